@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"chat-backend/internal/app"
+	"chat-backend/internal/batch"
+	"chat-backend/internal/handlers"
+	"chat-backend/internal/tenant"
+)
+
+const batchUsage = `Usage: chat-backend batch [flags] [file.jsonl ...]
+
+Runs each line of the JSONL files, or of stdin when no file is given,
+against the provider configured by the environment and writes a JSONL
+result line for each. Line numbers in the results count per file.
+
+Flags:
+`
+
+// batchCommand runs the batch subcommand and returns the exit code: 0 when
+// every item succeeded, 1 when any failed or the batch could not run, and
+// 2 for bad usage
+func batchCommand(args []string) int {
+	flags := flag.NewFlagSet("batch", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), batchUsage)
+		flags.PrintDefaults()
+	}
+	parallelism := flags.Int("parallelism", 4, "items to run at once")
+	output := flags.String("o", "", "write results to this file instead of stdout")
+	tenantID := flags.String("tenant", "", "run as this tenant, with its prompt and cache settings")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *parallelism < 1 {
+		fmt.Fprintln(os.Stderr, "-parallelism must be at least 1")
+		return 2
+	}
+
+	var out io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create output file: %v\n", err)
+			return 1
+		}
+		defer file.Close()
+		out = file
+	}
+
+	appCtx := app.BuildAppContext()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx = tenant.WithTenant(ctx, appCtx.Tenants.Lookup(*tenantID))
+
+	inputs := flags.Args()
+	if len(inputs) == 0 {
+		inputs = []string{"-"}
+	}
+
+	code := 0
+	for _, input := range inputs {
+		summary, err := runBatchFile(ctx, appCtx, input, out, *parallelism)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", input, err)
+			return 1
+		}
+
+		fmt.Fprintf(os.Stderr, "%s: %d items, %d succeeded, %d failed, %d tokens in %s\n",
+			input, summary.Items, summary.Succeeded, summary.Failed, summary.Usage.TotalTokens, summary.Duration.Round(1e6))
+		if summary.Failed > 0 {
+			code = 1
+		}
+	}
+	return code
+}
+
+// runBatchFile runs one input file, where "-" is stdin
+func runBatchFile(ctx context.Context, appCtx *app.AppContext, input string, out io.Writer, parallelism int) (batch.Summary, error) {
+	in := io.Reader(os.Stdin)
+	if input != "-" {
+		file, err := os.Open(input)
+		if err != nil {
+			return batch.Summary{}, err
+		}
+		defer file.Close()
+		in = file
+	}
+
+	return batch.Run(ctx, appCtx.ChatProvider, in, out, handlers.BatchOptions(appCtx, parallelism))
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"chat-backend/internal/app"
+	"chat-backend/internal/chat/ollama"
+	"chat-backend/internal/eval"
+)
+
+const evalUsage = `Usage: chat-backend eval -dataset file [flags]
+
+Asks every provider every question of the dataset and scores the answers
+against the expected ones. The dataset is a .jsonl file of
+{"id", "question", "expected"} lines, or a tab-separated file with
+Question and Answer columns like sample-data.tsv. Providers are given as
+name[:model], e.g. ollama:llama3.2,azure-openai, and read the same envs
+as the server.
+
+Flags:
+`
+
+// evalCommand runs the eval subcommand and returns the exit code: 0 when
+// the reports were written, 1 when the evaluation could not run, and 2 for
+// bad usage
+func evalCommand(args []string) int {
+	flags := flag.NewFlagSet("eval", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), evalUsage)
+		flags.PrintDefaults()
+	}
+	dataset := flags.String("dataset", "", "questions and expected answers to evaluate")
+	providers := flags.String("providers", "", "comma-separated providers to evaluate, as name[:model] (default CHAT_PROVIDER)")
+	metrics := flags.String("metrics", "exact,f1", "comma-separated metrics: exact, f1, embedding, judge")
+	judge := flags.String("judge", "", "provider that grades answers for the judge metric, as name[:model]")
+	embedModel := flags.String("embed-model", "", "Ollama model that embeds answers for the embedding metric")
+	parallelism := flags.Int("parallelism", 4, "questions to ask each provider at once")
+	system := flags.String("system", "", "system prompt sent ahead of every question")
+	output := flags.String("o", "eval-report", "write the report to this path with .json and .html extensions")
+	compare := flags.String("compare", "", "earlier JSON report to show next to this one")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *dataset == "" {
+		fmt.Fprintln(os.Stderr, "-dataset is required")
+		return 2
+	}
+	if *parallelism < 1 {
+		fmt.Fprintln(os.Stderr, "-parallelism must be at least 1")
+		return 2
+	}
+
+	scorers, err := evalScorers(*metrics, *judge, *embedModel)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	cases, err := eval.LoadDataset(*dataset)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", *dataset, err)
+		return 1
+	}
+
+	var previous *eval.Report
+	if *compare != "" {
+		if previous, err = eval.LoadReport(*compare); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	report := eval.Evaluate(ctx, eval.Config{
+		Dataset:      cases,
+		Targets:      evalTargets(*providers),
+		Scorers:      scorers,
+		SystemPrompt: *system,
+		Parallelism:  *parallelism,
+	})
+	report.Dataset = filepath.Base(*dataset)
+	if previous != nil {
+		report.Compare(previous, " (previous)")
+	}
+
+	if err := writeReport(*output+".json", report.WriteJSON); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if err := writeReport(*output+".html", report.WriteHTML); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	for _, run := range report.Runs {
+		scores := make([]string, 0, len(report.Metrics))
+		for _, metric := range report.Metrics {
+			scores = append(scores, fmt.Sprintf("%s %.3f", metric, run.Summary.Scores[metric]))
+		}
+		fmt.Fprintf(os.Stderr, "%s: %d cases, %d errors, %s\n", run.Name, run.Summary.Cases, run.Summary.Errors, strings.Join(scores, ", "))
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %s.json and %s.html\n", *output, *output)
+	return 0
+}
+
+// evalTargets builds the providers to evaluate from "name[:model]" specs.
+// Only the first colon splits, as Ollama model names contain one.
+func evalTargets(specs string) []eval.Target {
+	if strings.TrimSpace(specs) == "" {
+		specs = os.Getenv("CHAT_PROVIDER")
+		if specs == "" {
+			specs = "mock"
+		}
+	}
+
+	var targets []eval.Target
+	for _, spec := range strings.Split(specs, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		name, model, _ := strings.Cut(spec, ":")
+		provider, _ := app.NewProvider(name, model)
+		targets = append(targets, eval.Target{Name: spec, Provider: provider})
+	}
+	return targets
+}
+
+func evalScorers(metrics, judge, embedModel string) ([]eval.Scorer, error) {
+	var scorers []eval.Scorer
+	for _, metric := range strings.Split(metrics, ",") {
+		switch strings.TrimSpace(metric) {
+		case eval.MetricExact:
+			scorers = append(scorers, eval.ExactMatch{})
+		case eval.MetricF1:
+			scorers = append(scorers, eval.TokenF1{})
+		case eval.MetricEmbedding:
+			scorers = append(scorers, eval.NewEmbeddingSimilarity(ollama.NewEmbedder(os.Getenv("OLLAMA_BASE_URL"), embedModel)))
+		case eval.MetricJudge:
+			if judge == "" {
+				return nil, fmt.Errorf("the judge metric needs -judge")
+			}
+			name, model, _ := strings.Cut(judge, ":")
+			provider, _ := app.NewProvider(name, model)
+			scorers = append(scorers, eval.NewJudge(provider))
+		case "":
+		default:
+			return nil, fmt.Errorf("unknown metric %q", metric)
+		}
+	}
+	if len(scorers) == 0 {
+		return nil, fmt.Errorf("-metrics must name at least one metric")
+	}
+	return scorers, nil
+}
+
+func writeReport(path string, write func(w io.Writer) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return file.Close()
+}
@@ -0,0 +1,177 @@
+// Package analytics records answered chat requests for the admin reports:
+// what users ask, what goes unanswered, how long answers take and what
+// they cost.
+package analytics
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRetention is how long events are kept
+const DefaultRetention = 7 * 24 * time.Hour
+
+// maxEvents bounds the memory the store takes however busy the server is.
+// The oldest events go first.
+const maxEvents = 100_000
+
+// Event is one answered chat request
+type Event struct {
+	MessageID string
+	TenantID  string
+	Time      time.Time
+	Question  string
+	Answer    string
+	Provider  string
+	Model     string
+	// Latency runs from receiving the request to having the whole answer
+	Latency          time.Duration
+	PromptTokens     int
+	CompletionTokens int
+	// Unanswered is set for fallback answers given when the provider had
+	// none, and Cached for answers that did not reach the provider
+	Unanswered bool
+	Cached     bool
+	// Rating is the feedback on the answer, if any
+	Rating string
+}
+
+// Query picks the events a report covers. Empty fields match everything.
+type Query struct {
+	TenantID string
+	Since    time.Time
+	Until    time.Time
+}
+
+func (q Query) matches(e *Event) bool {
+	return (q.TenantID == "" || e.TenantID == q.TenantID) &&
+		(q.Since.IsZero() || !e.Time.Before(q.Since)) &&
+		(q.Until.IsZero() || e.Time.Before(q.Until))
+}
+
+// Price is what a model costs, in US dollars per million tokens
+type Price struct {
+	Input  float64
+	Output float64
+}
+
+// Prices are the prices of models by name
+type Prices map[string]Price
+
+// ParsePrices reads prices given as "model=input/output,other=input/output",
+// in US dollars per million tokens
+func ParsePrices(raw string) (Prices, error) {
+	prices := Prices{}
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		model, price, ok := strings.Cut(entry, "=")
+		input, output, found := strings.Cut(price, "/")
+		if !ok || !found || strings.TrimSpace(model) == "" {
+			return nil, fmt.Errorf("price %q must be model=input/output", entry)
+		}
+		in, err := strconv.ParseFloat(strings.TrimSpace(input), 64)
+		if err != nil || in < 0 {
+			return nil, fmt.Errorf("price %q has an invalid input price", entry)
+		}
+		out, err := strconv.ParseFloat(strings.TrimSpace(output), 64)
+		if err != nil || out < 0 {
+			return nil, fmt.Errorf("price %q has an invalid output price", entry)
+		}
+		prices[strings.TrimSpace(model)] = Price{Input: in, Output: out}
+	}
+	return prices, nil
+}
+
+// Store keeps events in memory, oldest first, for the retention period
+type Store struct {
+	retention time.Duration
+	prices    Prices
+	now       func() time.Time
+
+	mu     sync.Mutex
+	events []*Event
+	byID   map[string]*Event
+}
+
+func NewStore(retention time.Duration, prices Prices) *Store {
+	return &Store{
+		retention: retention,
+		prices:    prices,
+		now:       time.Now,
+		byID:      map[string]*Event{},
+	}
+}
+
+// Record adds an event, stamped with the current time when it has none
+func (s *Store) Record(event Event) {
+	if event.Time.IsZero() {
+		event.Time = s.now().UTC()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, &event)
+	if event.MessageID != "" {
+		s.byID[event.MessageID] = &event
+	}
+	s.pruneLocked()
+}
+
+// Rate notes the feedback on a tenant's answer. It is ignored for answers
+// the store no longer has.
+func (s *Store) Rate(tenantID, messageID, rating string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if event, ok := s.byID[messageID]; ok && event.TenantID == tenantID {
+		event.Rating = rating
+	}
+}
+
+// matching returns copies of the events that match q, oldest first
+func (s *Store) matching(q Query) []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneLocked()
+	var events []Event
+	for _, event := range s.events {
+		if q.matches(event) {
+			events = append(events, *event)
+		}
+	}
+	return events
+}
+
+// pruneLocked drops events past the retention period, and the oldest ones
+// beyond maxEvents. Callers hold the lock.
+func (s *Store) pruneLocked() {
+	drop := 0
+	if len(s.events) > maxEvents {
+		drop = len(s.events) - maxEvents
+	}
+	if s.retention > 0 {
+		cutoff := s.now().Add(-s.retention)
+		for drop < len(s.events) && s.events[drop].Time.Before(cutoff) {
+			drop++
+		}
+	}
+	if drop == 0 {
+		return
+	}
+
+	for i, event := range s.events[:drop] {
+		if s.byID[event.MessageID] == event {
+			delete(s.byID, event.MessageID)
+		}
+		s.events[i] = nil
+	}
+	s.events = s.events[drop:]
+}
@@ -0,0 +1,133 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestStore_TopQuestions(t *testing.T) {
+	store := NewStore(time.Hour, nil)
+	store.Record(Event{MessageID: "m1", Question: "How do I reset my password?"})
+	store.Record(Event{MessageID: "m2", Question: "how do I reset my password"})
+	store.Record(Event{MessageID: "m3", Question: "What are your opening hours?", Unanswered: true})
+	store.Rate("", "m1", "down")
+	store.Rate("globex", "m2", "up")
+
+	questions := store.TopQuestions(Query{}, 10)
+	if len(questions) != 2 {
+		t.Fatalf("Expected questions differing in case and punctuation to count as one, got %+v", questions)
+	}
+	top := questions[0]
+	if top.Question != "how do I reset my password" || top.Count != 2 || top.Down != 1 || top.Up != 0 {
+		t.Errorf("Expected the password question under its latest phrasing, rated by its own tenant only, got %+v", top)
+	}
+	if questions[1].Unanswered != 1 {
+		t.Errorf("Expected the unanswered question to be counted, got %+v", questions[1])
+	}
+
+	if questions := store.TopQuestions(Query{}, 1); len(questions) != 1 {
+		t.Errorf("Expected the limit to apply, got %+v", questions)
+	}
+}
+
+func TestStore_UnansweredClusters(t *testing.T) {
+	store := NewStore(time.Hour, nil)
+	for _, question := range []string{
+		"Do you ship to Canada?",
+		"Do you ship to Canada?",
+		"do you ship to canada and mexico",
+		"Can I pay with PayPal?",
+		"Do you ship to Canada?",
+	} {
+		store.Record(Event{Question: question, Unanswered: true})
+	}
+	store.Record(Event{Question: "Do you ship to France?"})
+
+	clusters := store.UnansweredClusters(Query{}, 10)
+	if len(clusters) != 2 {
+		t.Fatalf("Expected a shipping and a payment cluster, got %+v", clusters)
+	}
+	shipping := clusters[0]
+	if shipping.Question != "Do you ship to Canada?" || shipping.Count != 4 || len(shipping.Examples) != 2 {
+		t.Errorf("Expected the shipping questions under their most asked phrasing, got %+v", shipping)
+	}
+}
+
+func TestStore_Latency(t *testing.T) {
+	store := NewStore(time.Hour, nil)
+	for i := 1; i <= 100; i++ {
+		store.Record(Event{Provider: "ollama", Latency: time.Duration(i) * time.Millisecond})
+	}
+	store.Record(Event{Provider: "openai", Latency: 2 * time.Second})
+	store.Record(Event{Provider: "ollama", Latency: time.Microsecond, Cached: true})
+
+	report := store.Latency(Query{})
+	ollama := report.Providers["ollama"]
+	if ollama.Count != 100 || ollama.P50 != 50 || ollama.P90 != 90 || ollama.P99 != 99 || ollama.Max != 100 {
+		t.Errorf("Expected nearest-rank percentiles without the cached answer, got %+v", ollama)
+	}
+	if report.Overall.Count != 101 || report.Overall.Max != 2000 {
+		t.Errorf("Expected every provider in the overall percentiles, got %+v", report.Overall)
+	}
+}
+
+func TestStore_Cost(t *testing.T) {
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	store := NewStore(0, Prices{"gpt-4o": {Input: 2.5, Output: 10}})
+	store.Record(Event{Time: day.Add(time.Hour), Model: "gpt-4o", PromptTokens: 1000, CompletionTokens: 500})
+	store.Record(Event{Time: day.Add(2 * time.Hour), Model: "gpt-4o", PromptTokens: 1000, CompletionTokens: 500, Cached: true})
+	store.Record(Event{Time: day.Add(25 * time.Hour), Model: "llama3", PromptTokens: 200})
+
+	buckets := store.Cost(Query{}, 24*time.Hour)
+	if len(buckets) != 2 {
+		t.Fatalf("Expected a bucket for each day, got %+v", buckets)
+	}
+	first := buckets[0]
+	if !first.Start.Equal(day) || first.Requests != 2 || first.Cached != 1 || first.PromptTokens != 1000 {
+		t.Errorf("Expected the cached answer to be counted but not charged, got %+v", first)
+	}
+	if math.Abs(first.Cost-0.0075) > 1e-9 {
+		t.Errorf("Expected a cost of $0.0075, got %v", first.Cost)
+	}
+	if buckets[1].Unpriced != 1 || buckets[1].Cost != 0 {
+		t.Errorf("Expected the model without a price to be unpriced, got %+v", buckets[1])
+	}
+
+	if buckets := store.Cost(Query{Since: day.Add(24 * time.Hour)}, 24*time.Hour); len(buckets) != 1 {
+		t.Errorf("Expected since to leave out the first day, got %+v", buckets)
+	}
+}
+
+func TestStore_Retention(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	store := NewStore(time.Hour, nil)
+	store.now = func() time.Time { return now }
+
+	store.Record(Event{MessageID: "m1", Question: "Old"})
+	now = now.Add(2 * time.Hour)
+	store.Record(Event{MessageID: "m2", Question: "New"})
+
+	if questions := store.TopQuestions(Query{}, 10); len(questions) != 1 || questions[0].Question != "New" {
+		t.Errorf("Expected old events to be dropped, got %+v", questions)
+	}
+	if _, ok := store.byID["m1"]; ok {
+		t.Error("Expected dropped events to be unindexed")
+	}
+}
+
+func TestParsePrices(t *testing.T) {
+	prices, err := ParsePrices("gpt-4o=2.50/10, claude-sonnet-4 = 3/15")
+	if err != nil {
+		t.Fatalf("Failed to parse prices: %v", err)
+	}
+	if prices["gpt-4o"] != (Price{Input: 2.5, Output: 10}) || prices["claude-sonnet-4"] != (Price{Input: 3, Output: 15}) {
+		t.Errorf("Unexpected prices %+v", prices)
+	}
+
+	for _, raw := range []string{"gpt-4o=2.5", "gpt-4o=cheap/10", "=1/2", "gpt-4o=-1/2"} {
+		if _, err := ParsePrices(raw); err == nil {
+			t.Errorf("Expected %q to be refused", raw)
+		}
+	}
+}
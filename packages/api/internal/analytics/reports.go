@@ -0,0 +1,289 @@
+package analytics
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// clusterSimilarity is how much of their words two unanswered questions
+// must share to be clustered together
+const clusterSimilarity = 0.5
+
+// maxClusterExamples is how many phrasings a cluster lists
+const maxClusterExamples = 5
+
+// QuestionStats is how often a question was asked, and how it fared.
+// Questions that differ only in case, spacing and punctuation count as one,
+// under their latest phrasing.
+type QuestionStats struct {
+	Question   string    `json:"question"`
+	Count      int       `json:"count"`
+	Unanswered int       `json:"unanswered"`
+	Up         int       `json:"up"`
+	Down       int       `json:"down"`
+	LastAsked  time.Time `json:"last_asked"`
+}
+
+// Cluster is a group of unanswered questions that share most of their
+// words, under the phrasing asked most often
+type Cluster struct {
+	Question  string    `json:"question"`
+	Count     int       `json:"count"`
+	Examples  []string  `json:"examples"`
+	LastAsked time.Time `json:"last_asked"`
+}
+
+// Percentiles summarize latencies in milliseconds
+type Percentiles struct {
+	Count int   `json:"count"`
+	P50   int64 `json:"p50_ms"`
+	P90   int64 `json:"p90_ms"`
+	P95   int64 `json:"p95_ms"`
+	P99   int64 `json:"p99_ms"`
+	Max   int64 `json:"max_ms"`
+}
+
+// LatencyReport has the latency percentiles of all answers, and of each
+// provider's. Cached answers are left out, as they say little about the
+// providers.
+type LatencyReport struct {
+	Overall   Percentiles            `json:"overall"`
+	Providers map[string]Percentiles `json:"providers,omitempty"`
+}
+
+// CostBucket is the usage and cost of the answers in one interval. Cached
+// answers cost nothing, and answers from models without a price are
+// counted as unpriced.
+type CostBucket struct {
+	Start            time.Time `json:"start"`
+	Requests         int       `json:"requests"`
+	Cached           int       `json:"cached"`
+	Unpriced         int       `json:"unpriced"`
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+	Cost             float64   `json:"cost_usd"`
+}
+
+// TopQuestions returns the questions asked most often, up to limit
+func (s *Store) TopQuestions(q Query, limit int) []QuestionStats {
+	byKey := map[string]*QuestionStats{}
+	for _, event := range s.matching(q) {
+		key := strings.Join(words(event.Question), " ")
+		if key == "" {
+			continue
+		}
+		stats, ok := byKey[key]
+		if !ok {
+			stats = &QuestionStats{}
+			byKey[key] = stats
+		}
+		stats.Question = event.Question
+		stats.Count++
+		stats.LastAsked = event.Time
+		if event.Unanswered {
+			stats.Unanswered++
+		}
+		switch event.Rating {
+		case "up":
+			stats.Up++
+		case "down":
+			stats.Down++
+		}
+	}
+
+	questions := make([]QuestionStats, 0, len(byKey))
+	for _, stats := range byKey {
+		questions = append(questions, *stats)
+	}
+	slices.SortFunc(questions, func(a, b QuestionStats) int {
+		return cmp.Or(b.Count-a.Count, b.LastAsked.Compare(a.LastAsked), strings.Compare(a.Question, b.Question))
+	})
+	return truncate(questions, limit)
+}
+
+// UnansweredClusters groups the questions that got a fallback answer, and
+// returns the largest groups, up to limit. A question joins the first
+// cluster whose first question shares enough of its words.
+func (s *Store) UnansweredClusters(q Query, limit int) []Cluster {
+	type cluster struct {
+		words    []string
+		count    int
+		phrasing map[string]int
+		examples []string
+		last     time.Time
+	}
+
+	var clusters []*cluster
+	for _, event := range s.matching(q) {
+		if !event.Unanswered {
+			continue
+		}
+		w := words(event.Question)
+		if len(w) == 0 {
+			continue
+		}
+
+		var found *cluster
+		for _, c := range clusters {
+			if jaccard(c.words, w) >= clusterSimilarity {
+				found = c
+				break
+			}
+		}
+		if found == nil {
+			found = &cluster{words: w, phrasing: map[string]int{}}
+			clusters = append(clusters, found)
+		}
+		found.count++
+		found.last = event.Time
+		if found.phrasing[event.Question] == 0 && len(found.examples) < maxClusterExamples {
+			found.examples = append(found.examples, event.Question)
+		}
+		found.phrasing[event.Question]++
+	}
+
+	result := make([]Cluster, 0, len(clusters))
+	for _, c := range clusters {
+		question, most := "", 0
+		for phrasing, count := range c.phrasing {
+			if count > most || (count == most && phrasing < question) {
+				question, most = phrasing, count
+			}
+		}
+		result = append(result, Cluster{Question: question, Count: c.count, Examples: c.examples, LastAsked: c.last})
+	}
+	slices.SortFunc(result, func(a, b Cluster) int {
+		return cmp.Or(b.Count-a.Count, b.LastAsked.Compare(a.LastAsked), strings.Compare(a.Question, b.Question))
+	})
+	return truncate(result, limit)
+}
+
+// Latency returns the latency percentiles of the answers that were not
+// cached
+func (s *Store) Latency(q Query) LatencyReport {
+	var all []time.Duration
+	byProvider := map[string][]time.Duration{}
+	for _, event := range s.matching(q) {
+		if event.Cached {
+			continue
+		}
+		all = append(all, event.Latency)
+		byProvider[event.Provider] = append(byProvider[event.Provider], event.Latency)
+	}
+
+	report := LatencyReport{Overall: percentiles(all)}
+	for provider, latencies := range byProvider {
+		if report.Providers == nil {
+			report.Providers = map[string]Percentiles{}
+		}
+		report.Providers[provider] = percentiles(latencies)
+	}
+	return report
+}
+
+// Cost returns the usage and cost of answers in intervals of the given
+// length, oldest first. Intervals without answers are left out.
+func (s *Store) Cost(q Query, interval time.Duration) []CostBucket {
+	var buckets []CostBucket
+	for _, event := range s.matching(q) {
+		start := event.Time.Truncate(interval)
+		if n := len(buckets); n == 0 || !buckets[n-1].Start.Equal(start) {
+			buckets = append(buckets, CostBucket{Start: start})
+		}
+		bucket := &buckets[len(buckets)-1]
+
+		bucket.Requests++
+		if event.Cached {
+			bucket.Cached++
+			continue
+		}
+		bucket.PromptTokens += event.PromptTokens
+		bucket.CompletionTokens += event.CompletionTokens
+		price, ok := s.prices[event.Model]
+		if !ok {
+			bucket.Unpriced++
+			continue
+		}
+		bucket.Cost += (float64(event.PromptTokens)*price.Input + float64(event.CompletionTokens)*price.Output) / 1e6
+	}
+
+	// Events are recorded as they finish, so the odd one may be out of order
+	slices.SortStableFunc(buckets, func(a, b CostBucket) int { return a.Start.Compare(b.Start) })
+	merged := buckets[:0]
+	for _, bucket := range buckets {
+		if n := len(merged); n > 0 && merged[n-1].Start.Equal(bucket.Start) {
+			last := &merged[n-1]
+			last.Requests += bucket.Requests
+			last.Cached += bucket.Cached
+			last.Unpriced += bucket.Unpriced
+			last.PromptTokens += bucket.PromptTokens
+			last.CompletionTokens += bucket.CompletionTokens
+			last.Cost += bucket.Cost
+			continue
+		}
+		merged = append(merged, bucket)
+	}
+	return merged
+}
+
+// percentiles uses the nearest-rank method
+func percentiles(latencies []time.Duration) Percentiles {
+	if len(latencies) == 0 {
+		return Percentiles{}
+	}
+	slices.Sort(latencies)
+	rank := func(p int) int64 {
+		i := (p*len(latencies)+99)/100 - 1
+		return latencies[max(i, 0)].Milliseconds()
+	}
+	return Percentiles{
+		Count: len(latencies),
+		P50:   rank(50),
+		P90:   rank(90),
+		P95:   rank(95),
+		P99:   rank(99),
+		Max:   latencies[len(latencies)-1].Milliseconds(),
+	}
+}
+
+// words lowercases text and splits it into words, dropping punctuation
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// jaccard is the share of distinct words two questions have in common
+func jaccard(a, b []string) float64 {
+	set := map[string]bool{}
+	for _, word := range a {
+		set[word] = true
+	}
+	common, union := 0, len(set)
+	seen := map[string]bool{}
+	for _, word := range b {
+		if seen[word] {
+			continue
+		}
+		seen[word] = true
+		if set[word] {
+			common++
+		} else {
+			union++
+		}
+	}
+	if union == 0 {
+		return 0
+	}
+	return float64(common) / float64(union)
+}
+
+func truncate[T any](items []T, limit int) []T {
+	if limit > 0 && len(items) > limit {
+		return items[:limit]
+	}
+	return items
+}
@@ -4,20 +4,68 @@ import (
 	"log"
 	"log/slog"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"chat-backend/internal/analytics"
 	"chat-backend/internal/chat"
+	"chat-backend/internal/chat/anthropic"
 	"chat-backend/internal/chat/azure"
+	"chat-backend/internal/chat/azureopenai"
+	"chat-backend/internal/chat/cache"
+	"chat-backend/internal/chat/coalesce"
+	"chat-backend/internal/chat/contextwindow"
+	"chat-backend/internal/chat/limiter"
 	"chat-backend/internal/chat/mock"
 	"chat-backend/internal/chat/ollama"
+	"chat-backend/internal/chat/openai"
+	"chat-backend/internal/conversations"
+	"chat-backend/internal/feedback"
+	"chat-backend/internal/generations"
+	"chat-backend/internal/jobs"
+	"chat-backend/internal/prompts"
+	"chat-backend/internal/streams"
+	"chat-backend/internal/tenant"
 )
 
 type AppContext struct {
 	ChatProvider chat.ChatProvider
+	Prompts      *prompts.Store
+	Tenants      *tenant.Registry
+	Jobs         *jobs.Manager
+	Generations  *generations.Registry
+	Streams      *streams.Buffer
+	// Conversations keeps the chats that continue by ID, whose answers
+	// note the ProviderName and Model that generated them
+	Conversations *conversations.Store
+	ProviderName  string
+	Model         string
+	// Feedback keeps answers by message ID, and what users think of them
+	Feedback *feedback.Store
+	// Analytics records answered chat requests for the admin reports
+	Analytics *analytics.Store
+	// AdminAPIKey guards the management endpoints, which are closed without
+	// one unless AdminOpen is set for local development
+	AdminAPIKey string
+	AdminOpen   bool
+	// GRPCPort is where the gRPC API listens, and GRPCAPIKey guards it.
+	// The port is 0 when the gRPC API is off.
+	GRPCPort   int
+	GRPCAPIKey string
 }
 
 func NewAppContext(chatProvider chat.ChatProvider) *AppContext {
 	return &AppContext{
-		ChatProvider: chatProvider,
+		ChatProvider:  chatProvider,
+		Prompts:       prompts.NewStore(),
+		Tenants:       tenant.NewRegistry(),
+		Jobs:          jobs.NewManager(chatProvider, jobs.Config{}),
+		Generations:   generations.NewRegistry(),
+		Streams:       streams.NewBuffer(streams.DefaultTTL),
+		Conversations: conversations.NewStore(conversations.DefaultRetention),
+		Feedback:      feedback.NewStore(feedback.DefaultWindow),
+		Analytics:     analytics.NewStore(analytics.DefaultRetention, nil),
 	}
 }
 
@@ -29,18 +77,100 @@ func providerName() string {
 	return provider
 }
 
+// Parses extra upstream headers given as "Name=value,Other-Name=value"
+func parseHeaders(raw string) map[string]string {
+	headers := map[string]string{}
+	for _, pair := range strings.Split(raw, ",") {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			continue
+		}
+		headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return headers
+}
+
+// modelOr returns the override when one is given, and otherwise the model
+// named by env
+func modelOr(override, env string) string {
+	if override != "" {
+		return override
+	}
+	return os.Getenv(env)
+}
+
 // Builds context object containing app dependencies used by handlers
 // In particular, contains chat provider, depending on whichever provider
 // you chose to set with CHAT_PROVIDER env
 func BuildAppContext() *AppContext {
-	var chatProvider chat.ChatProvider
-
 	provider := providerName()
+	chatProvider, model := NewProvider(provider, "")
+
+	chatProvider = withLimiter(chatProvider)
+	chatProvider = withContextWindow(chatProvider, provider, model)
+	chatProvider = withSemanticCache(chatProvider, provider, model)
+	chatProvider = withCache(chatProvider, provider, model)
+	chatProvider = withCoalescing(chatProvider)
+
+	appCtx := NewAppContext(chatProvider)
+	appCtx.AdminAPIKey, appCtx.AdminOpen = buildAdminAuth()
+	appCtx.GRPCPort, appCtx.GRPCAPIKey = buildGRPC()
+	appCtx.Jobs = buildJobs(chatProvider)
+	appCtx.Streams = buildStreams()
+	appCtx.Conversations = buildConversations()
+	appCtx.Feedback = buildFeedback()
+	appCtx.Analytics = buildAnalytics()
+	appCtx.ProviderName, appCtx.Model = provider, model
+
+	if path := os.Getenv("PROMPTS_FILE"); path != "" {
+		store, err := prompts.LoadStore(path)
+		if err != nil {
+			log.Fatalf("Failed to load prompt templates: %v", err)
+		}
+		slog.Info("Using prompt templates file", "path", path, "templates", len(store.List()))
+		appCtx.Prompts = store
+	}
+
+	if path := os.Getenv("TENANTS_FILE"); path != "" {
+		registry, err := tenant.LoadFile(path)
+		if err != nil {
+			log.Fatalf("Failed to load tenants: %v", err)
+		}
+		slog.Info("Using tenants file", "path", path)
+		appCtx.Tenants = registry
+	}
+
+	return appCtx
+}
+
+// NewProvider builds the named chat provider from its envs, without any of
+// the layers BuildAppContext adds. A non-empty model overrides the model
+// or deployment the envs name. It also returns the model in use, which is
+// used to look up its context window.
+func NewProvider(name, model string) (chat.ChatProvider, string) {
+	var chatProvider chat.ChatProvider
 
-	switch provider {
+	switch name {
 	case "mock":
-		slog.Info("Using mock chat provider")
-		chatProvider = mock.NewMockChatProvider()
+		scenarioFile := os.Getenv("MOCK_SCENARIO_FILE")
+		if scenarioFile == "" {
+			slog.Info("Using mock chat provider")
+			chatProvider = mock.NewMockChatProvider()
+			break
+		}
+
+		scenario, err := mock.LoadScenarioFile(scenarioFile)
+		if err != nil {
+			log.Fatalf("Failed to load mock scenario: %v", err)
+		}
+
+		scripted, err := mock.NewScriptedChatProvider(scenario)
+		if err != nil {
+			log.Fatalf("Invalid mock scenario: %v", err)
+		}
+
+		slog.Info("Using scripted mock chat provider", "scenario", scenarioFile)
+		chatProvider = scripted
 
 	case "azure-qa":
 		endpoint := os.Getenv("AZURE_QNA_ENDPOINT")
@@ -52,19 +182,532 @@ func BuildAppContext() *AppContext {
 			log.Fatal("All required Azure envs must be set: AZURE_QNA_ENDPOINT, AZURE_QNA_API_KEY, AZURE_QNA_PROJECT_NAME, AZURE_QNA_DEPLOYMENT_NAME")
 		}
 
+		azureProvider := azure.NewAzureChatProvider(endpoint, apiKey, projectName, deploymentName)
+
+		if top := os.Getenv("AZURE_QNA_TOP"); top != "" {
+			n, err := strconv.Atoi(top)
+			if err != nil || n <= 0 {
+				log.Fatalf("Invalid AZURE_QNA_TOP: must be a positive number, got %q", top)
+			}
+			azureProvider.WithTop(n)
+		}
+
+		if threshold := os.Getenv("AZURE_QNA_CONFIDENCE_THRESHOLD"); threshold != "" {
+			f, err := strconv.ParseFloat(threshold, 64)
+			if err != nil || f < 0 || f > 1 {
+				log.Fatalf("Invalid AZURE_QNA_CONFIDENCE_THRESHOLD: must be a number from 0 to 1, got %q", threshold)
+			}
+			azureProvider.WithConfidenceThreshold(f)
+		}
+
 		slog.Info("Using Azure chat provider")
-		chatProvider = azure.NewAzureChatProvider(endpoint, apiKey, projectName, deploymentName)
+		chatProvider = azureProvider
 
 	case "ollama":
 		baseURL := os.Getenv("OLLAMA_BASE_URL")
-		model := os.Getenv("OLLAMA_MODEL")
+		model = modelOr(model, "OLLAMA_MODEL")
+		if model == "" {
+			model = ollama.DefaultModel
+		}
+
+		// Only an explicit context size is sent, since the model's full
+		// context window can take more memory than the host has
+		contextLength := maxContextTokens()
+		slog.Info("Using Ollama chat provider", "baseURL", baseURL, "model", model, "contextLength", contextLength)
+		chatProvider = ollama.NewOllamaChatProvider(baseURL, model).WithContextLength(contextLength)
+
+	case "azure-openai":
+		config := azureopenai.Config{
+			Endpoint:    os.Getenv("AZURE_OPENAI_ENDPOINT"),
+			Deployment:  modelOr(model, "AZURE_OPENAI_DEPLOYMENT"),
+			APIVersion:  os.Getenv("AZURE_OPENAI_API_VERSION"),
+			APIKey:      os.Getenv("AZURE_OPENAI_API_KEY"),
+			BearerToken: os.Getenv("AZURE_OPENAI_BEARER_TOKEN"),
+		}
+
+		if config.Endpoint == "" || config.Deployment == "" {
+			log.Fatal("All required Azure OpenAI envs must be set: AZURE_OPENAI_ENDPOINT, AZURE_OPENAI_DEPLOYMENT")
+		}
+
+		if config.APIKey == "" && config.BearerToken == "" {
+			log.Fatal("One of AZURE_OPENAI_API_KEY or AZURE_OPENAI_BEARER_TOKEN must be set")
+		}
+
+		if timeout := os.Getenv("AZURE_OPENAI_TIMEOUT"); timeout != "" {
+			d, err := time.ParseDuration(timeout)
+			if err != nil {
+				log.Fatalf("Invalid AZURE_OPENAI_TIMEOUT: %v", err)
+			}
+			config.Timeout = d
+		}
+
+		slog.Info("Using Azure OpenAI chat provider", "endpoint", config.Endpoint, "deployment", config.Deployment)
+		model = config.Deployment
+		chatProvider = azureopenai.NewAzureOpenAIChatProvider(config)
+
+	case "openai":
+		config := openai.Config{
+			BaseURL: os.Getenv("OPENAI_BASE_URL"),
+			Model:   modelOr(model, "OPENAI_MODEL"),
+			APIKey:  os.Getenv("OPENAI_API_KEY"),
+			Headers: parseHeaders(os.Getenv("OPENAI_HEADERS")),
+		}
+
+		if timeout := os.Getenv("OPENAI_TIMEOUT"); timeout != "" {
+			d, err := time.ParseDuration(timeout)
+			if err != nil {
+				log.Fatalf("Invalid OPENAI_TIMEOUT: %v", err)
+			}
+			config.Timeout = d
+		}
+
+		if config.Model == "" {
+			log.Fatal("OPENAI_MODEL must be set when using the openai provider")
+		}
+
+		slog.Info("Using OpenAI-compatible chat provider", "baseURL", config.BaseURL, "model", config.Model)
+		model = config.Model
+		chatProvider = openai.NewOpenAIChatProvider(config)
+
+	case "anthropic":
+		config := anthropic.Config{
+			BaseURL: os.Getenv("ANTHROPIC_BASE_URL"),
+			APIKey:  os.Getenv("ANTHROPIC_API_KEY"),
+			Model:   modelOr(model, "ANTHROPIC_MODEL"),
+		}
+
+		if config.APIKey == "" || config.Model == "" {
+			log.Fatal("All required Anthropic envs must be set: ANTHROPIC_API_KEY, ANTHROPIC_MODEL")
+		}
+
+		if maxTokens := os.Getenv("ANTHROPIC_MAX_TOKENS"); maxTokens != "" {
+			n, err := strconv.Atoi(maxTokens)
+			if err != nil {
+				log.Fatalf("Invalid ANTHROPIC_MAX_TOKENS: %v", err)
+			}
+			config.MaxTokens = n
+		}
+
+		slog.Info("Using Anthropic chat provider", "model", config.Model)
+		model = config.Model
+		chatProvider = anthropic.NewAnthropicChatProvider(config)
+
+	default:
+		log.Fatalf("Unknown CHAT_PROVIDER: %s. Supported values: mock, azure-qa, azure-openai, ollama, openai, anthropic", name)
+	}
+
+	return chatProvider, model
+}
+
+// Reads CONTEXT_MAX_TOKENS, the context size set for the model in tokens,
+// or 0 when it is not set
+func maxContextTokens() int {
+	value := os.Getenv("CONTEXT_MAX_TOKENS")
+	if value == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		log.Fatalf("Invalid CONTEXT_MAX_TOKENS: must be a positive number, got %q", value)
+	}
+	return n
+}
+
+// Wraps the provider so long conversations are trimmed to fit the model's
+// context window, as configured by the CONTEXT_* envs. Only LLM providers
+// are wrapped, since the others have no context window to fit.
+func withContextWindow(provider chat.ChatProvider, name, model string) chat.ChatProvider {
+	if name == "mock" || name == "azure-qa" {
+		return provider
+	}
+
+	config := contextwindow.Config{
+		Strategy: contextwindow.StrategyKeepRecent,
+		Model:    model,
+	}
+
+	if strategy := os.Getenv("CONTEXT_STRATEGY"); strategy != "" {
+		parsed, err := contextwindow.ParseStrategy(strategy)
+		if err != nil {
+			log.Fatalf("Invalid CONTEXT_STRATEGY: %v", err)
+		}
+		config.Strategy = parsed
+	}
+
+	if config.Strategy == contextwindow.StrategyNone {
+		return provider
+	}
+
+	// Without an explicit size, the model's known context window is used
+	config.MaxTokens = maxContextTokens()
+	for env, target := range map[string]*int{
+		"CONTEXT_RESERVE_TOKENS":  &config.ReserveTokens,
+		"CONTEXT_WINDOW_MESSAGES": &config.WindowMessages,
+	} {
+		value := os.Getenv(env)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			log.Fatalf("Invalid %s: %v", env, err)
+		}
+		*target = n
+	}
+
+	slog.Info("Using context window management", "strategy", config.Strategy, "model", model, "maxTokens", config.MaxTokens)
+	return contextwindow.NewContextWindowProvider(provider, config)
+}
+
+// Wraps the provider so repeated requests are answered from a cache, when
+// enabled with CACHE_ENABLED. CACHE_DIR keeps the cache on disk.
+func withCache(provider chat.ChatProvider, name, model string) chat.ChatProvider {
+	enabled, err := strconv.ParseBool(os.Getenv("CACHE_ENABLED"))
+	if err != nil || !enabled {
+		return provider
+	}
+
+	config := cache.Config{
+		Provider: name,
+		Model:    model,
+	}
+
+	if ttl := os.Getenv("CACHE_TTL"); ttl != "" {
+		d, err := time.ParseDuration(ttl)
+		if err != nil {
+			log.Fatalf("Invalid CACHE_TTL: %v", err)
+		}
+		config.TTL = d
+	}
+
+	if maxEntries := os.Getenv("CACHE_MAX_ENTRIES"); maxEntries != "" {
+		n, err := strconv.Atoi(maxEntries)
+		if err != nil {
+			log.Fatalf("Invalid CACHE_MAX_ENTRIES: %v", err)
+		}
+		config.MaxEntries = n
+	}
+
+	if dir := os.Getenv("CACHE_DIR"); dir != "" {
+		store, err := cache.NewDiskStore(dir, config.MaxEntries)
+		if err != nil {
+			log.Fatalf("Failed to open response cache: %v", err)
+		}
+		config.Store = store
+	}
 
-		slog.Info("Using Ollama chat provider", "baseURL", baseURL, "model", model)
-		chatProvider = ollama.NewOllamaChatProvider(baseURL, model)
+	slog.Info("Using response cache", "ttl", config.TTL, "maxEntries", config.MaxEntries, "dir", os.Getenv("CACHE_DIR"))
+	return cache.NewCacheProvider(provider, config)
+}
+
+// Wraps the provider so questions that mean the same as an earlier one are
+// answered from a cache, when enabled with SEMANTIC_CACHE_ENABLED. Questions
+// are embedded by the Ollama server at OLLAMA_BASE_URL, whichever provider
+// answers them.
+func withSemanticCache(provider chat.ChatProvider, name, model string) chat.ChatProvider {
+	enabled, err := strconv.ParseBool(os.Getenv("SEMANTIC_CACHE_ENABLED"))
+	if err != nil || !enabled {
+		return provider
+	}
+
+	embedModel := os.Getenv("SEMANTIC_CACHE_EMBED_MODEL")
+	config := cache.SemanticConfig{
+		Embedder: ollama.NewEmbedder(os.Getenv("OLLAMA_BASE_URL"), embedModel),
+		Provider: name,
+		Model:    model,
+	}
+
+	if threshold := os.Getenv("SEMANTIC_CACHE_THRESHOLD"); threshold != "" {
+		f, err := strconv.ParseFloat(threshold, 64)
+		if err != nil || f <= 0 || f > 1 {
+			log.Fatalf("Invalid SEMANTIC_CACHE_THRESHOLD: must be a number in (0, 1], got %q", threshold)
+		}
+		config.Threshold = f
+	}
+
+	if ttl := os.Getenv("SEMANTIC_CACHE_TTL"); ttl != "" {
+		d, err := time.ParseDuration(ttl)
+		if err != nil {
+			log.Fatalf("Invalid SEMANTIC_CACHE_TTL: %v", err)
+		}
+		config.TTL = d
+	}
+
+	if maxEntries := os.Getenv("SEMANTIC_CACHE_MAX_ENTRIES"); maxEntries != "" {
+		n, err := strconv.Atoi(maxEntries)
+		if err != nil {
+			log.Fatalf("Invalid SEMANTIC_CACHE_MAX_ENTRIES: %v", err)
+		}
+		config.MaxEntries = n
+	}
+
+	slog.Info("Using semantic cache", "embedModel", embedModel, "threshold", config.Threshold, "ttl", config.TTL)
+	return cache.NewSemanticCacheProvider(provider, config)
+}
+
+// Wraps the provider so identical requests in flight at the same time share
+// one upstream call, unless disabled with COALESCE_REQUESTS=false
+func withCoalescing(provider chat.ChatProvider) chat.ChatProvider {
+	if value := os.Getenv("COALESCE_REQUESTS"); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			log.Fatalf("Invalid COALESCE_REQUESTS: %v", err)
+		}
+		if !enabled {
+			return provider
+		}
+	}
+
+	slog.Info("Using request coalescing")
+	return coalesce.NewCoalescingProvider(provider)
+}
+
+// Wraps the provider so at most PROVIDER_MAX_CONCURRENT requests reach it
+// at once, queueing the rest as configured by the PROVIDER_QUEUE_* envs
+func withLimiter(provider chat.ChatProvider) chat.ChatProvider {
+	maxConcurrent := os.Getenv("PROVIDER_MAX_CONCURRENT")
+	if maxConcurrent == "" {
+		return provider
+	}
+
+	var config limiter.Config
+	n, err := strconv.Atoi(maxConcurrent)
+	if err != nil || n <= 0 {
+		log.Fatalf("Invalid PROVIDER_MAX_CONCURRENT: must be a positive integer, got %q", maxConcurrent)
+	}
+	config.MaxConcurrent = n
+
+	if length := os.Getenv("PROVIDER_QUEUE_LENGTH"); length != "" {
+		n, err := strconv.Atoi(length)
+		if err != nil {
+			log.Fatalf("Invalid PROVIDER_QUEUE_LENGTH: %v", err)
+		}
+		// Zero means no queue here, which the limiter spells as negative
+		if n == 0 {
+			n = -1
+		}
+		config.MaxQueue = n
+	}
+
+	if timeout := os.Getenv("PROVIDER_QUEUE_TIMEOUT"); timeout != "" {
+		d, err := time.ParseDuration(timeout)
+		if err != nil {
+			log.Fatalf("Invalid PROVIDER_QUEUE_TIMEOUT: %v", err)
+		}
+		config.QueueTimeout = d
+	}
+
+	if mode := os.Getenv("PROVIDER_QUEUE_MODE"); mode != "" {
+		parsed, err := limiter.ParseMode(mode)
+		if err != nil {
+			log.Fatalf("Invalid PROVIDER_QUEUE_MODE: %v", err)
+		}
+		config.Mode = parsed
+	}
+
+	// Priorities use the same "tier=priority,other=priority" format as
+	// OPENAI_HEADERS
+	if raw := os.Getenv("PROVIDER_TIER_PRIORITIES"); raw != "" {
+		config.TierPriorities = map[string]int{}
+		for tier, value := range parseHeaders(raw) {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				log.Fatalf("Invalid PROVIDER_TIER_PRIORITIES for tier %q: %v", tier, err)
+			}
+			config.TierPriorities[tier] = n
+		}
+	}
+
+	if config.Mode == limiter.ModePriority {
+		slog.Warn("Priority queueing trusts the X-Tenant-ID header, which must be set by a trusted proxy")
+	}
+
+	slog.Info("Using provider concurrency limit", "maxConcurrent", config.MaxConcurrent, "mode", config.Mode)
+	return limiter.NewLimiterProvider(provider, config)
+}
+
+// Builds the worker pool for asynchronous chat jobs, as configured by the
+// JOBS_* envs. JOBS_DIR keeps jobs on disk.
+func buildJobs(provider chat.ChatProvider) *jobs.Manager {
+	config := jobs.Config{
+		WebhookSecret: os.Getenv("JOBS_WEBHOOK_SECRET"),
+	}
+
+	if value := os.Getenv("JOBS_WEBHOOK_ALLOW_PRIVATE"); value != "" {
+		allow, err := strconv.ParseBool(value)
+		if err != nil {
+			log.Fatalf("Invalid JOBS_WEBHOOK_ALLOW_PRIVATE: %v", err)
+		}
+		config.AllowPrivateWebhooks = allow
+		if allow {
+			slog.Warn("Webhooks may be sent to private addresses")
+		}
+	}
+
+	for env, target := range map[string]*int{
+		"JOBS_WORKERS":      &config.Workers,
+		"JOBS_QUEUE_LENGTH": &config.QueueLength,
+	} {
+		value := os.Getenv(env)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			log.Fatalf("Invalid %s: must be a positive integer, got %q", env, value)
+		}
+		*target = n
+	}
+
+	for env, target := range map[string]*time.Duration{
+		"JOBS_RETENTION":       &config.Retention,
+		"JOBS_WEBHOOK_TIMEOUT": &config.WebhookTimeout,
+	} {
+		value := os.Getenv(env)
+		if value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			log.Fatalf("Invalid %s: %v", env, err)
+		}
+		*target = d
+	}
+
+	if dir := os.Getenv("JOBS_DIR"); dir != "" {
+		store, err := jobs.NewDirStore(dir)
+		if err != nil {
+			log.Fatalf("Failed to open jobs store: %v", err)
+		}
+		config.Store = store
+	}
+
+	slog.Info("Using chat jobs", "workers", config.Workers, "dir", os.Getenv("JOBS_DIR"), "webhooks", config.WebhookSecret != "")
+	return jobs.NewManager(provider, config)
+}
+
+// Builds the buffer that lets clients resume streams, kept for
+// STREAM_RESUME_TTL after they end. A TTL of 0 turns resuming off, and
+// streams then stop when their client disconnects.
+func buildStreams() *streams.Buffer {
+	ttl := streams.DefaultTTL
+	if value := os.Getenv("STREAM_RESUME_TTL"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			log.Fatalf("Invalid STREAM_RESUME_TTL: must be a duration of 0 or more, got %q", value)
+		}
+		ttl = d
+	}
 
+	slog.Info("Using resumable streams", "ttl", ttl)
+	return streams.NewBuffer(ttl)
+}
+
+// Reads ADMIN_API_KEY, which guards the management endpoints. Without a
+// key they are closed, unless ADMIN_API_OPEN=true opens them for local
+// development.
+func buildAdminAuth() (string, bool) {
+	key := os.Getenv("ADMIN_API_KEY")
+	open := false
+	if value := os.Getenv("ADMIN_API_OPEN"); value != "" {
+		var err error
+		open, err = strconv.ParseBool(value)
+		if err != nil {
+			log.Fatalf("Invalid ADMIN_API_OPEN: %v", err)
+		}
+	}
+
+	switch {
+	case key != "":
+	case open:
+		slog.Warn("Admin endpoints are open to anyone, as ADMIN_API_OPEN is set without ADMIN_API_KEY")
 	default:
-		log.Fatalf("Unknown CHAT_PROVIDER: %s. Supported values: mock, azure-qa, ollama", provider)
+		slog.Warn("Admin endpoints are disabled until ADMIN_API_KEY is set")
+	}
+	return key, open && key == ""
+}
+
+// Reads GRPC_PORT, where the gRPC API listens, and GRPC_API_KEY, which
+// every call must carry. The port defaults to 9090, and 0 turns the gRPC
+// API off. It also stays off without a key, since it would be open to
+// anyone who can reach the port.
+func buildGRPC() (int, string) {
+	port := 9090
+	if value := os.Getenv("GRPC_PORT"); value != "" {
+		var err error
+		port, err = strconv.Atoi(value)
+		if err != nil || port < 0 || port > 65535 {
+			log.Fatalf("Invalid GRPC_PORT: must be a port number, or 0 to disable, got %q", value)
+		}
+	}
+
+	key := os.Getenv("GRPC_API_KEY")
+	if port > 0 && key == "" {
+		slog.Warn("gRPC API is off until GRPC_API_KEY is set")
+		return 0, ""
+	}
+	return port, key
+}
+
+// Builds the store of conversations, which are kept in memory for
+// CONVERSATION_RETENTION after their last message
+func buildConversations() *conversations.Store {
+	retention := conversations.DefaultRetention
+	if value := os.Getenv("CONVERSATION_RETENTION"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			log.Fatalf("Invalid CONVERSATION_RETENTION: must be a positive duration, got %q", value)
+		}
+		retention = d
+	}
+
+	slog.Info("Using conversations", "retention", retention)
+	return conversations.NewStore(retention)
+}
+
+// Builds the store of answers and their feedback. Answers take feedback for
+// FEEDBACK_WINDOW, and feedback is appended to FEEDBACK_FILE when it is set.
+func buildFeedback() *feedback.Store {
+	window := feedback.DefaultWindow
+	if value := os.Getenv("FEEDBACK_WINDOW"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			log.Fatalf("Invalid FEEDBACK_WINDOW: must be a positive duration, got %q", value)
+		}
+		window = d
+	}
+
+	path := os.Getenv("FEEDBACK_FILE")
+	if path == "" {
+		slog.Info("Using feedback", "window", window)
+		return feedback.NewStore(window)
+	}
+	store, err := feedback.OpenStore(path, window)
+	if err != nil {
+		log.Fatalf("Failed to load feedback: %v", err)
+	}
+	slog.Info("Using feedback file", "path", path, "window", window)
+	return store
+}
+
+// Builds the store behind the analytics reports, which keeps events for
+// ANALYTICS_RETENTION. MODEL_PRICES prices the cost report, as
+// "model=input/output" pairs in US dollars per million tokens.
+func buildAnalytics() *analytics.Store {
+	retention := analytics.DefaultRetention
+	if value := os.Getenv("ANALYTICS_RETENTION"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			log.Fatalf("Invalid ANALYTICS_RETENTION: must be a positive duration, got %q", value)
+		}
+		retention = d
+	}
+
+	prices, err := analytics.ParsePrices(os.Getenv("MODEL_PRICES"))
+	if err != nil {
+		log.Fatalf("Invalid MODEL_PRICES: %v", err)
 	}
 
-	return NewAppContext(chatProvider)
+	slog.Info("Using analytics", "retention", retention, "priced_models", len(prices))
+	return analytics.NewStore(retention, prices)
 }
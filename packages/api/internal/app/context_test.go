@@ -4,9 +4,17 @@ import (
 	"os"
 	"testing"
 
+	"chat-backend/internal/chat"
+	"chat-backend/internal/chat/anthropic"
 	"chat-backend/internal/chat/azure"
+	"chat-backend/internal/chat/azureopenai"
+	"chat-backend/internal/chat/cache"
+	"chat-backend/internal/chat/coalesce"
+	"chat-backend/internal/chat/contextwindow"
+	"chat-backend/internal/chat/limiter"
 	"chat-backend/internal/chat/mock"
 	"chat-backend/internal/chat/ollama"
+	"chat-backend/internal/chat/openai"
 )
 
 func TestBuildAppContext_Mock(t *testing.T) {
@@ -25,7 +33,7 @@ func TestBuildAppContext_Mock(t *testing.T) {
 	}
 
 	// Check if it's a mock provider (we can't directly type assert due to interface)
-	if _, ok := ctx.ChatProvider.(*mock.MockChatProvider); !ok {
+	if _, ok := chat.Unwrap(ctx.ChatProvider).(*mock.MockChatProvider); !ok {
 		t.Error("expected mock chat provider for default case")
 	}
 }
@@ -40,7 +48,7 @@ func TestBuildAppContext_MockExplicit(t *testing.T) {
 		t.Fatal("expected context to be created")
 	}
 
-	if _, ok := ctx.ChatProvider.(*mock.MockChatProvider); !ok {
+	if _, ok := chat.Unwrap(ctx.ChatProvider).(*mock.MockChatProvider); !ok {
 		t.Error("expected mock chat provider when CHAT_PROVIDER=mock")
 	}
 }
@@ -55,7 +63,7 @@ func TestBuildAppContext_Ollama(t *testing.T) {
 		t.Fatal("expected context to be created")
 	}
 
-	if _, ok := ctx.ChatProvider.(*ollama.OllamaChatProvider); !ok {
+	if _, ok := chat.Unwrap(ctx.ChatProvider).(*ollama.OllamaChatProvider); !ok {
 		t.Error("expected ollama chat provider when CHAT_PROVIDER=ollama")
 	}
 }
@@ -76,7 +84,7 @@ func TestBuildAppContext_OllamaWithEnvs(t *testing.T) {
 		t.Fatal("expected context to be created")
 	}
 
-	if _, ok := ctx.ChatProvider.(*ollama.OllamaChatProvider); !ok {
+	if _, ok := chat.Unwrap(ctx.ChatProvider).(*ollama.OllamaChatProvider); !ok {
 		t.Error("expected ollama chat provider when CHAT_PROVIDER=ollama")
 	}
 }
@@ -101,11 +109,208 @@ func TestBuildAppContext_AzureQA(t *testing.T) {
 		t.Fatal("expected context to be created")
 	}
 
-	if _, ok := ctx.ChatProvider.(*azure.AzureChatProvider); !ok {
+	if _, ok := chat.Unwrap(ctx.ChatProvider).(*azure.AzureChatProvider); !ok {
 		t.Error("expected azure chat provider when CHAT_PROVIDER=azure-qa")
 	}
 }
 
+func TestBuildAppContext_AzureOpenAI(t *testing.T) {
+	os.Setenv("CHAT_PROVIDER", "azure-openai")
+	os.Setenv("AZURE_OPENAI_ENDPOINT", "https://test.openai.azure.com")
+	os.Setenv("AZURE_OPENAI_DEPLOYMENT", "gpt-4o")
+	os.Setenv("AZURE_OPENAI_API_KEY", "test-key")
+	defer func() {
+		os.Unsetenv("CHAT_PROVIDER")
+		os.Unsetenv("AZURE_OPENAI_ENDPOINT")
+		os.Unsetenv("AZURE_OPENAI_DEPLOYMENT")
+		os.Unsetenv("AZURE_OPENAI_API_KEY")
+	}()
+
+	ctx := BuildAppContext()
+
+	if ctx == nil {
+		t.Fatal("expected context to be created")
+	}
+
+	if _, ok := chat.Unwrap(ctx.ChatProvider).(*azureopenai.AzureOpenAIChatProvider); !ok {
+		t.Error("expected azure openai chat provider when CHAT_PROVIDER=azure-openai")
+	}
+}
+
+func TestBuildAppContext_OpenAI(t *testing.T) {
+	os.Setenv("CHAT_PROVIDER", "openai")
+	os.Setenv("OPENAI_BASE_URL", "http://localhost:8000/v1")
+	os.Setenv("OPENAI_MODEL", "llama-3.1-8b")
+	os.Setenv("OPENAI_TIMEOUT", "30s")
+	defer func() {
+		os.Unsetenv("CHAT_PROVIDER")
+		os.Unsetenv("OPENAI_BASE_URL")
+		os.Unsetenv("OPENAI_MODEL")
+		os.Unsetenv("OPENAI_TIMEOUT")
+	}()
+
+	ctx := BuildAppContext()
+
+	if ctx == nil {
+		t.Fatal("expected context to be created")
+	}
+
+	if _, ok := chat.Unwrap(ctx.ChatProvider).(*openai.OpenAIChatProvider); !ok {
+		t.Error("expected openai chat provider when CHAT_PROVIDER=openai")
+	}
+}
+
+func TestBuildAppContext_Anthropic(t *testing.T) {
+	os.Setenv("CHAT_PROVIDER", "anthropic")
+	os.Setenv("ANTHROPIC_API_KEY", "test-key")
+	os.Setenv("ANTHROPIC_MODEL", "claude-3-5-haiku-latest")
+	defer func() {
+		os.Unsetenv("CHAT_PROVIDER")
+		os.Unsetenv("ANTHROPIC_API_KEY")
+		os.Unsetenv("ANTHROPIC_MODEL")
+	}()
+
+	ctx := BuildAppContext()
+
+	if ctx == nil {
+		t.Fatal("expected context to be created")
+	}
+
+	if _, ok := chat.Unwrap(ctx.ChatProvider).(*anthropic.AnthropicChatProvider); !ok {
+		t.Error("expected anthropic chat provider when CHAT_PROVIDER=anthropic")
+	}
+}
+
+func TestBuildAppContext_ContextWindow(t *testing.T) {
+	os.Unsetenv("CHAT_PROVIDER")
+	defer os.Unsetenv("CHAT_PROVIDER")
+
+	ctx := BuildAppContext()
+
+	if _, ok := chat.Layer[*contextwindow.ContextWindowProvider](ctx.ChatProvider); ok {
+		t.Error("expected the mock provider to be left without context window management")
+	}
+
+	os.Setenv("CHAT_PROVIDER", "ollama")
+	ctx = BuildAppContext()
+
+	if _, ok := chat.Layer[*contextwindow.ContextWindowProvider](ctx.ChatProvider); !ok {
+		t.Error("expected LLM providers to be wrapped with context window management by default")
+	}
+
+	os.Setenv("CONTEXT_STRATEGY", "none")
+	defer os.Unsetenv("CONTEXT_STRATEGY")
+
+	ctx = BuildAppContext()
+
+	if _, ok := chat.Layer[*contextwindow.ContextWindowProvider](ctx.ChatProvider); ok {
+		t.Error("expected CONTEXT_STRATEGY=none to leave out context window management")
+	}
+}
+
+func TestBuildAppContext_Cache(t *testing.T) {
+	os.Unsetenv("CHAT_PROVIDER")
+	os.Setenv("CACHE_ENABLED", "true")
+	os.Setenv("CACHE_DIR", t.TempDir())
+	defer os.Unsetenv("CACHE_ENABLED")
+	defer os.Unsetenv("CACHE_DIR")
+
+	ctx := BuildAppContext()
+
+	if _, ok := chat.Layer[*cache.CacheProvider](ctx.ChatProvider); !ok {
+		t.Error("expected CACHE_ENABLED to wrap the provider with the response cache")
+	}
+
+	if _, ok := chat.Unwrap(ctx.ChatProvider).(*mock.MockChatProvider); !ok {
+		t.Error("expected the cache to wrap the configured provider")
+	}
+}
+
+func TestBuildAppContext_Coalescing(t *testing.T) {
+	os.Unsetenv("CHAT_PROVIDER")
+	defer os.Unsetenv("CHAT_PROVIDER")
+
+	ctx := BuildAppContext()
+
+	if _, ok := ctx.ChatProvider.(*coalesce.CoalescingProvider); !ok {
+		t.Error("expected requests to be coalesced in front of every other layer by default")
+	}
+
+	os.Setenv("COALESCE_REQUESTS", "false")
+	defer os.Unsetenv("COALESCE_REQUESTS")
+
+	ctx = BuildAppContext()
+
+	if _, ok := chat.Layer[*coalesce.CoalescingProvider](ctx.ChatProvider); ok {
+		t.Error("expected COALESCE_REQUESTS=false to disable coalescing")
+	}
+}
+
+func TestBuildAppContext_Limiter(t *testing.T) {
+	os.Unsetenv("CHAT_PROVIDER")
+	defer os.Unsetenv("CHAT_PROVIDER")
+
+	ctx := BuildAppContext()
+
+	if _, ok := chat.Layer[*limiter.LimiterProvider](ctx.ChatProvider); ok {
+		t.Error("expected no concurrency limit by default")
+	}
+
+	os.Setenv("PROVIDER_MAX_CONCURRENT", "4")
+	os.Setenv("PROVIDER_QUEUE_LENGTH", "0")
+	os.Setenv("PROVIDER_QUEUE_MODE", "priority")
+	defer os.Unsetenv("PROVIDER_MAX_CONCURRENT")
+	defer os.Unsetenv("PROVIDER_QUEUE_LENGTH")
+	defer os.Unsetenv("PROVIDER_QUEUE_MODE")
+
+	ctx = BuildAppContext()
+
+	limited, ok := chat.Layer[*limiter.LimiterProvider](ctx.ChatProvider)
+	if !ok {
+		t.Fatal("expected PROVIDER_MAX_CONCURRENT to enable the limiter")
+	}
+	if stats := limited.Stats(); stats.MaxConcurrent != 4 || stats.MaxQueue != 0 {
+		t.Errorf("expected 4 slots and no queue, got %+v", stats)
+	}
+	if _, ok := limited.Unwrap().(*mock.MockChatProvider); !ok {
+		t.Errorf("expected the limiter to sit right in front of the provider, got %T", limited.Unwrap())
+	}
+}
+
+func TestBuildAppContext_Jobs(t *testing.T) {
+	os.Unsetenv("CHAT_PROVIDER")
+	defer os.Unsetenv("CHAT_PROVIDER")
+
+	ctx := BuildAppContext()
+
+	if ctx.Jobs == nil || ctx.Jobs.WebhooksEnabled() {
+		t.Error("expected jobs without webhooks by default")
+	}
+
+	os.Setenv("JOBS_WEBHOOK_SECRET", "s3cret")
+	os.Setenv("JOBS_DIR", t.TempDir())
+	defer os.Unsetenv("JOBS_WEBHOOK_SECRET")
+	defer os.Unsetenv("JOBS_DIR")
+
+	ctx = BuildAppContext()
+
+	if !ctx.Jobs.WebhooksEnabled() {
+		t.Error("expected JOBS_WEBHOOK_SECRET to enable webhooks")
+	}
+}
+
+func TestParseHeaders(t *testing.T) {
+	headers := parseHeaders("X-Org = acme, X-Trace=on,invalid,")
+
+	if len(headers) != 2 {
+		t.Fatalf("expected 2 headers, got %d: %v", len(headers), headers)
+	}
+
+	if headers["X-Org"] != "acme" || headers["X-Trace"] != "on" {
+		t.Errorf("unexpected headers: %v", headers)
+	}
+}
+
 // Note: TestBuildAppContext_UnknownProvider and TestBuildAppContext_AzureMissingEnvs
 // are commented out because they call log.Fatal/log.Fatalf which terminates the process.
 // In a real test environment, you would need to refactor BuildAppContext to return
@@ -0,0 +1,278 @@
+package batch
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"chat-backend/internal/chat"
+)
+
+const (
+	defaultParallelism = 4
+	// Lines may carry base64 attachments, so allow more than the scanner's
+	// 64 KB default
+	maxLineBytes = 8 << 20
+)
+
+// Item is one line of batch input. Question is shorthand for a
+// conversation of a single user message.
+type Item struct {
+	ID       string         `json:"id,omitempty"`
+	Question string         `json:"question,omitempty"`
+	Messages []chat.Message `json:"messages,omitempty"`
+	Tools    []chat.Tool    `json:"tools,omitempty"`
+}
+
+// Failure describes why an item failed, in the same terms as the problem
+// details a single chat request would have been answered with
+type Failure struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Result is one line of batch output. Line is the item's line number in
+// the input, counting from 1.
+type Result struct {
+	Line         int               `json:"line"`
+	ID           string            `json:"id,omitempty"`
+	Response     string            `json:"response,omitempty"`
+	Answers      []chat.Answer     `json:"answers,omitempty"`
+	ToolCalls    []chat.ToolCall   `json:"tool_calls,omitempty"`
+	FinishReason string            `json:"finish_reason,omitempty"`
+	Usage        *chat.Usage       `json:"usage,omitempty"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+	Error        *Failure          `json:"error,omitempty"`
+	DurationMs   int64             `json:"duration_ms"`
+}
+
+// Summary totals a finished batch
+type Summary struct {
+	Items     int        `json:"items"`
+	Succeeded int        `json:"succeeded"`
+	Failed    int        `json:"failed"`
+	Usage     chat.Usage `json:"usage"`
+	Duration  time.Duration
+}
+
+// Options control a batch run. Prepare checks each item's request and
+// returns the one to send, such as with a system prompt added, along with
+// metadata for the item's result. Describe turns errors into the failures
+// reported per item. Both have plain defaults. MaxItems, when set, stops
+// a batch with an error at the first item past it.
+type Options struct {
+	Parallelism int
+	MaxItems    int
+	Prepare     func(context.Context, *chat.ChatRequest) (*chat.ChatRequest, map[string]string, error)
+	Describe    func(error) Failure
+}
+
+// Run sends every item read from in to the provider, at most Parallelism at
+// a time, and writes a result line to out for each. Results are written in
+// input order as soon as they and every earlier item are done, so output
+// can be streamed while later items still run.
+//
+// Items that fail get an error result and the batch carries on. Run only
+// returns an error when the input cannot be read, has more than MaxItems
+// items, or the output cannot be written.
+func Run(ctx context.Context, provider chat.ChatProvider, in io.Reader, out io.Writer, opts Options) (Summary, error) {
+	if opts.Parallelism <= 0 {
+		opts.Parallelism = defaultParallelism
+	}
+	if opts.Prepare == nil {
+		opts.Prepare = prepare
+	}
+	if opts.Describe == nil {
+		opts.Describe = describe
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Each item gets a slot that the writer waits on in input order. Items
+	// keep their place in the pipeline until written, which bounds both
+	// the requests in flight and the results held back by a slow item.
+	pipeline := make(chan chan Result, opts.Parallelism-1)
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(pipeline)
+		items := 0
+		var limitErr error
+		err := read(ctx, in, func(line int, data []byte) bool {
+			if opts.MaxItems > 0 && items == opts.MaxItems {
+				limitErr = chat.Errorf(chat.ErrInvalidRequest, "batch has more than %d items, stopped at line %d", opts.MaxItems, line)
+				return false
+			}
+			items++
+
+			slot := make(chan Result, 1)
+			select {
+			case pipeline <- slot:
+			case <-ctx.Done():
+				return false
+			}
+			go func() {
+				slot <- runItem(ctx, provider, line, data, opts)
+			}()
+			return true
+		})
+		if err == nil {
+			err = limitErr
+		}
+		readErr <- err
+	}()
+
+	started := time.Now()
+	var summary Summary
+	encoder := json.NewEncoder(out)
+	var writeErr error
+	for slot := range pipeline {
+		result := <-slot
+		if writeErr != nil {
+			continue
+		}
+
+		summary.add(result)
+		if err := encoder.Encode(&result); err != nil {
+			// Stop sending items nobody will see the results of
+			writeErr = fmt.Errorf("failed to write batch result: %w", err)
+			cancel()
+		}
+	}
+	summary.Duration = time.Since(started)
+
+	if writeErr != nil {
+		return summary, writeErr
+	}
+	if err := <-readErr; err != nil {
+		return summary, err
+	}
+	return summary, ctx.Err()
+}
+
+// read calls item with each non-blank line of in until it returns false
+func read(ctx context.Context, in io.Reader, item func(line int, data []byte) bool) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxLineBytes)
+
+	for line := 1; scanner.Scan(); line++ {
+		data := scanner.Bytes()
+		if len(strings.TrimSpace(string(data))) == 0 {
+			continue
+		}
+		// The scanner reuses its buffer, and items run concurrently
+		if !item(line, append([]byte(nil), data...)) {
+			return nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read batch input: %w", err)
+	}
+	return nil
+}
+
+func runItem(ctx context.Context, provider chat.ChatProvider, line int, data []byte, opts Options) Result {
+	started := time.Now()
+	result := Result{Line: line}
+
+	var metadata map[string]string
+	req, err := parseItem(data, &result)
+	if err == nil {
+		req, metadata, err = opts.Prepare(ctx, req)
+	}
+	if err == nil {
+		var resp *chat.ChatResponse
+		resp, err = provider.Chat(ctx, req)
+		if err == nil {
+			result.Response = resp.Content
+			result.Answers = resp.Answers
+			result.ToolCalls = resp.ToolCalls
+			result.FinishReason = resp.FinishReason
+			result.Usage = resp.Usage
+			result.Metadata = resp.Metadata
+			for key, value := range metadata {
+				if result.Metadata == nil {
+					result.Metadata = map[string]string{}
+				}
+				result.Metadata[key] = value
+			}
+		}
+	}
+
+	if err != nil {
+		failure := opts.Describe(err)
+		result.Error = &failure
+	}
+	result.DurationMs = time.Since(started).Milliseconds()
+	return result
+}
+
+// parseItem decodes a line into the request it stands for, setting the
+// result's ID as soon as it is known
+func parseItem(data []byte, result *Result) (*chat.ChatRequest, error) {
+	var item Item
+	if err := json.Unmarshal(data, &item); err != nil {
+		return nil, chat.Errorf(chat.ErrInvalidRequest, "invalid JSON: %v", err)
+	}
+	result.ID = item.ID
+
+	switch {
+	case item.Question != "" && len(item.Messages) > 0:
+		return nil, chat.Errorf(chat.ErrInvalidRequest, "give either question or messages, not both")
+	case item.Question != "":
+		item.Messages = []chat.Message{{Role: "user", Content: item.Question}}
+	case len(item.Messages) == 0:
+		return nil, chat.Errorf(chat.ErrInvalidRequest, "question or messages is required")
+	}
+
+	return &chat.ChatRequest{Messages: item.Messages, Tools: item.Tools}, nil
+}
+
+func (s *Summary) add(result Result) {
+	s.Items++
+	if result.Error != nil {
+		s.Failed++
+		return
+	}
+	s.Succeeded++
+	if result.Usage != nil {
+		s.Usage.PromptTokens += result.Usage.PromptTokens
+		s.Usage.CompletionTokens += result.Usage.CompletionTokens
+		s.Usage.TotalTokens += result.Usage.TotalTokens
+	}
+}
+
+// prepare only checks that the conversation ends with something to answer,
+// and sends the request as it is
+func prepare(_ context.Context, req *chat.ChatRequest) (*chat.ChatRequest, map[string]string, error) {
+	last := req.Messages[len(req.Messages)-1]
+	if last.Role != "user" && last.Role != "tool" {
+		return nil, nil, chat.Errorf(chat.ErrInvalidRequest, "the conversation must end with a user or tool message")
+	}
+	return req, nil, nil
+}
+
+// describe is the fallback when no describer is given. Unlike the HTTP
+// layer it reports the error text, since batch output goes to whoever ran
+// the batch.
+func describe(err error) Failure {
+	failure := Failure{
+		Type:   "about:blank",
+		Title:  http.StatusText(http.StatusInternalServerError),
+		Status: http.StatusInternalServerError,
+		Detail: err.Error(),
+	}
+	if errors.Is(err, chat.ErrInvalidRequest) {
+		failure.Title = http.StatusText(http.StatusBadRequest)
+		failure.Status = http.StatusBadRequest
+	}
+	return failure
+}
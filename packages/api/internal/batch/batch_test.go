@@ -0,0 +1,193 @@
+package batch
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"chat-backend/internal/chat"
+)
+
+// echoProvider answers with the question, after the delay its content asks
+// for, and records how many requests ran at once
+type echoProvider struct {
+	mu      sync.Mutex
+	current int
+	peak    int
+}
+
+func (p *echoProvider) Chat(ctx context.Context, req *chat.ChatRequest) (*chat.ChatResponse, error) {
+	p.mu.Lock()
+	p.current++
+	p.peak = max(p.peak, p.current)
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		p.current--
+		p.mu.Unlock()
+	}()
+
+	question := req.Messages[len(req.Messages)-1].Content
+	if question == "fail" {
+		return nil, chat.Errorf(chat.ErrRateLimited, "slow down")
+	}
+	if delay, err := time.ParseDuration(question); err == nil {
+		time.Sleep(delay)
+	}
+	return &chat.ChatResponse{
+		Content: "answer to " + question,
+		Usage:   &chat.Usage{PromptTokens: 2, CompletionTokens: 3, TotalTokens: 5},
+	}, nil
+}
+
+func (p *echoProvider) ChatStream(ctx context.Context, req *chat.ChatRequest, callback chat.StreamCallback) error {
+	return errors.New("batches never stream")
+}
+
+func decodeResults(t *testing.T, out *bytes.Buffer) []Result {
+	t.Helper()
+
+	var results []Result
+	scanner := bufio.NewScanner(out)
+	for scanner.Scan() {
+		var result Result
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+			t.Fatalf("Failed to decode result line '%s': %v", scanner.Text(), err)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func TestRun_KeepsInputOrder(t *testing.T) {
+	provider := &echoProvider{}
+	in := strings.NewReader(`{"id": "slow", "question": "40ms"}
+{"id": "fast", "question": "1ms"}
+{"id": "medium", "question": "10ms"}
+{"id": "quick", "question": "0s"}
+`)
+
+	var out bytes.Buffer
+	summary, err := Run(context.Background(), provider, in, &out, Options{Parallelism: 2})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	results := decodeResults(t, &out)
+	want := []string{"slow", "fast", "medium", "quick"}
+	if len(results) != len(want) {
+		t.Fatalf("Expected %d results, got %d", len(want), len(results))
+	}
+	for i, id := range want {
+		if results[i].ID != id || results[i].Line != i+1 {
+			t.Errorf("Expected result %d to be %s from line %d, got %+v", i, id, i+1, results[i])
+		}
+	}
+
+	if provider.peak > 2 {
+		t.Errorf("Expected at most 2 requests at once, got %d", provider.peak)
+	}
+	if summary.Items != 4 || summary.Succeeded != 4 || summary.Usage.TotalTokens != 20 {
+		t.Errorf("Unexpected summary: %+v", summary)
+	}
+}
+
+func TestRun_ReportsItemErrors(t *testing.T) {
+	in := strings.NewReader(`{"id": "ok", "question": "Hi"}
+
+not json
+{"id": "empty"}
+{"id": "both", "question": "Hi", "messages": [{"role": "user", "content": "Hi"}]}
+{"id": "assistant last", "messages": [{"role": "assistant", "content": "Hi"}]}
+{"id": "upstream", "question": "fail"}
+`)
+
+	var out bytes.Buffer
+	summary, err := Run(context.Background(), &echoProvider{}, in, &out, Options{})
+	if err != nil {
+		t.Fatalf("Expected item errors not to fail the batch, got: %v", err)
+	}
+
+	results := decodeResults(t, &out)
+	if len(results) != 6 {
+		t.Fatalf("Expected a result per non-blank line, got %d", len(results))
+	}
+	if results[0].Error != nil || results[0].Response != "answer to Hi" {
+		t.Errorf("Expected the first item to succeed, got %+v", results[0])
+	}
+	if results[1].Line != 3 {
+		t.Errorf("Expected blank lines to still count, got line %d", results[1].Line)
+	}
+	for _, result := range results[1:5] {
+		if result.Error == nil || result.Error.Status != 400 {
+			t.Errorf("Expected line %d to be rejected, got %+v", result.Line, result.Error)
+		}
+	}
+	if failure := results[5].Error; failure == nil || failure.Status != 500 || !strings.Contains(failure.Detail, "slow down") {
+		t.Errorf("Expected the upstream failure to be described, got %+v", failure)
+	}
+	if summary.Succeeded != 1 || summary.Failed != 5 {
+		t.Errorf("Unexpected summary: %+v", summary)
+	}
+}
+
+func TestRun_UsesOptions(t *testing.T) {
+	in := strings.NewReader(`{"question": "Hi"}` + "\n")
+
+	var out bytes.Buffer
+	Run(context.Background(), &echoProvider{}, in, &out, Options{
+		Prepare: func(context.Context, *chat.ChatRequest) (*chat.ChatRequest, map[string]string, error) {
+			return nil, nil, errors.New("rejected")
+		},
+		Describe: func(err error) Failure { return Failure{Type: "custom", Detail: err.Error()} },
+	})
+
+	results := decodeResults(t, &out)
+	if len(results) != 1 || results[0].Error == nil || results[0].Error.Type != "custom" {
+		t.Errorf("Expected the custom preparer and describer to be used, got %+v", results)
+	}
+}
+
+func TestRun_MaxItems(t *testing.T) {
+	in := strings.NewReader(strings.Repeat(`{"question": "Hi"}`+"\n", 5))
+
+	var out bytes.Buffer
+	summary, err := Run(context.Background(), &echoProvider{}, in, &out, Options{MaxItems: 3})
+	if !errors.Is(err, chat.ErrInvalidRequest) || !strings.Contains(err.Error(), "line 4") {
+		t.Errorf("Expected the batch to stop at the fourth item, got: %v", err)
+	}
+	if results := decodeResults(t, &out); len(results) != 3 || summary.Items != 3 {
+		t.Errorf("Expected the first 3 items to run, got %+v", results)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("client went away")
+}
+
+func TestRun_StopsWhenOutputFails(t *testing.T) {
+	in := strings.NewReader(strings.Repeat(`{"question": "1ms"}`+"\n", 50))
+	provider := &echoProvider{}
+
+	_, err := Run(context.Background(), provider, in, failingWriter{}, Options{Parallelism: 2})
+	if err == nil || !strings.Contains(err.Error(), "client went away") {
+		t.Errorf("Expected the write error, got: %v", err)
+	}
+}
+
+func TestRun_LineTooLong(t *testing.T) {
+	in := strings.NewReader(`{"question": "` + strings.Repeat("a", maxLineBytes) + `"}`)
+
+	var out bytes.Buffer
+	if _, err := Run(context.Background(), &echoProvider{}, in, &out, Options{}); err == nil {
+		t.Error("Expected an over-long line to fail the batch")
+	}
+}
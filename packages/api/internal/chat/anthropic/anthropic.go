@@ -0,0 +1,122 @@
+package anthropic
+
+import (
+	"context"
+	"strings"
+
+	"chat-backend/internal/chat"
+)
+
+type AnthropicChatProvider struct {
+	client AnthropicClient
+}
+
+func NewAnthropicChatProvider(config Config) *AnthropicChatProvider {
+	return &AnthropicChatProvider{
+		client: NewClient(config),
+	}
+}
+
+func (p *AnthropicChatProvider) Chat(ctx context.Context, req *chat.ChatRequest) (*chat.ChatResponse, error) {
+	messagesReq, err := toMessagesRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := p.client.Messages(ctx, messagesReq)
+	if err != nil {
+		return nil, err
+	}
+
+	var content strings.Builder
+	for _, block := range message.Content {
+		if block.Type == "text" {
+			content.WriteString(block.Text)
+		}
+	}
+
+	return &chat.ChatResponse{
+		Content: content.String(),
+		Usage:   toChatUsage(message.Usage),
+	}, nil
+}
+
+func (p *AnthropicChatProvider) ChatStream(ctx context.Context, req *chat.ChatRequest, callback chat.StreamCallback) error {
+	messagesReq, err := toMessagesRequest(req)
+	if err != nil {
+		return err
+	}
+
+	// Input tokens are reported once in message_start, output tokens
+	// accumulate and are reported in message_delta
+	var usage Usage
+
+	// Create a callback that converts stream events to chat responses
+	eventCallback := func(event *StreamEvent) error {
+		switch event.Type {
+		case "message_start":
+			if event.Message != nil {
+				usage = event.Message.Usage
+			}
+
+		case "content_block_delta":
+			if event.Delta != nil && event.Delta.Type == "text_delta" && event.Delta.Text != "" {
+				return callback(&chat.ChatResponse{Content: event.Delta.Text})
+			}
+
+		case "message_delta":
+			if event.Usage != nil {
+				usage.OutputTokens = event.Usage.OutputTokens
+				return callback(&chat.ChatResponse{Usage: toChatUsage(usage)})
+			}
+		}
+		return nil
+	}
+
+	return p.client.MessagesStream(ctx, messagesReq, eventCallback)
+}
+
+// toMessagesRequest converts chat messages to the Messages API format, which
+// takes the system prompt as a top-level field rather than a message
+func toMessagesRequest(req *chat.ChatRequest) (*MessagesRequest, error) {
+	if len(req.Messages) == 0 {
+		return nil, chat.Errorf(chat.ErrInvalidRequest, "no messages provided")
+	}
+
+	if err := chat.RejectAttachments(req); err != nil {
+		return nil, err
+	}
+
+	var system []string
+	var messages []Message
+	for _, msg := range req.Messages {
+		switch msg.Role {
+		case "system":
+			system = append(system, msg.Content)
+		case "user", "assistant":
+			messages = append(messages, Message{
+				Role:    msg.Role,
+				Content: msg.Content,
+			})
+		default:
+			return nil, chat.Errorf(chat.ErrInvalidRequest, "unsupported message role %q", msg.Role)
+		}
+	}
+
+	if len(messages) == 0 {
+		return nil, chat.Errorf(chat.ErrInvalidRequest, "no user message found in conversation")
+	}
+
+	return &MessagesRequest{
+		System:   strings.Join(system, "\n\n"),
+		Messages: messages,
+	}, nil
+}
+
+func toChatUsage(usage Usage) *chat.Usage {
+	return &chat.Usage{
+		PromptTokens:     usage.InputTokens,
+		CompletionTokens: usage.OutputTokens,
+		TotalTokens:      usage.InputTokens + usage.OutputTokens,
+	}
+}
@@ -0,0 +1,219 @@
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"chat-backend/internal/chat"
+)
+
+// fixtureServer replays a recorded API response from testdata and records
+// the last request it received
+type fixtureServer struct {
+	t           *testing.T
+	status      int
+	fixture     string
+	lastHeaders http.Header
+	lastRequest MessagesRequest
+}
+
+func (f *fixtureServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/v1/messages" {
+		f.t.Errorf("Unexpected path: %s", r.URL.Path)
+	}
+
+	f.lastHeaders = r.Header.Clone()
+	if err := json.NewDecoder(r.Body).Decode(&f.lastRequest); err != nil {
+		f.t.Errorf("Failed to decode request: %v", err)
+	}
+
+	body, err := os.ReadFile(filepath.Join("testdata", f.fixture))
+	if err != nil {
+		f.t.Fatalf("Failed to read fixture: %v", err)
+	}
+
+	if filepath.Ext(f.fixture) == ".sse" {
+		w.Header().Set("Content-Type", "text/event-stream")
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.WriteHeader(f.status)
+	w.Write(body)
+}
+
+func newTestProvider(t *testing.T, status int, fixture string) (*AnthropicChatProvider, *fixtureServer) {
+	t.Helper()
+
+	fake := &fixtureServer{t: t, status: status, fixture: fixture}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	provider := NewAnthropicChatProvider(Config{
+		BaseURL: server.URL,
+		APIKey:  "test-key",
+		Model:   "claude-3-5-haiku-latest",
+	})
+	return provider, fake
+}
+
+func TestAnthropicChatProvider_Chat(t *testing.T) {
+	provider, fake := newTestProvider(t, http.StatusOK, "message.json")
+
+	resp, err := provider.Chat(context.Background(), &chat.ChatRequest{
+		Messages: []chat.Message{
+			{Role: "system", Content: "You are a Star Wars expert."},
+			{Role: "user", Content: "Who is Luke's father?"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if resp.Content != "Darth Vader is Luke Skywalker's father." {
+		t.Errorf("Unexpected content: '%s'", resp.Content)
+	}
+
+	if resp.Usage == nil || resp.Usage.PromptTokens != 24 || resp.Usage.CompletionTokens != 11 || resp.Usage.TotalTokens != 35 {
+		t.Errorf("Unexpected usage: %+v", resp.Usage)
+	}
+
+	if fake.lastRequest.System != "You are a Star Wars expert." {
+		t.Errorf("Expected system prompt to be lifted, got '%s'", fake.lastRequest.System)
+	}
+
+	if len(fake.lastRequest.Messages) != 1 || fake.lastRequest.Messages[0].Role != "user" {
+		t.Errorf("Expected only the user message, got %+v", fake.lastRequest.Messages)
+	}
+
+	if fake.lastRequest.MaxTokens != defaultMaxTokens {
+		t.Errorf("Expected default max_tokens, got %d", fake.lastRequest.MaxTokens)
+	}
+
+	if fake.lastHeaders.Get("x-api-key") != "test-key" || fake.lastHeaders.Get("anthropic-version") != defaultVersion {
+		t.Errorf("Expected auth and version headers, got %v", fake.lastHeaders)
+	}
+}
+
+func TestAnthropicChatProvider_ChatStream(t *testing.T) {
+	provider, fake := newTestProvider(t, http.StatusOK, "stream.sse")
+
+	var content string
+	var usage *chat.Usage
+	err := provider.ChatStream(context.Background(), &chat.ChatRequest{
+		Messages: []chat.Message{{Role: "user", Content: "Who is Luke's father?"}},
+	}, func(chunk *chat.ChatResponse) error {
+		content += chunk.Content
+		if chunk.Usage != nil {
+			usage = chunk.Usage
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if content != "Darth Vader is Luke's father." {
+		t.Errorf("Unexpected content: '%s'", content)
+	}
+
+	if usage == nil || usage.PromptTokens != 25 || usage.CompletionTokens != 9 || usage.TotalTokens != 34 {
+		t.Errorf("Unexpected usage: %+v", usage)
+	}
+
+	if !fake.lastRequest.Stream {
+		t.Error("Expected stream to be requested")
+	}
+}
+
+func TestAnthropicChatProvider_StreamErrorEvent(t *testing.T) {
+	provider, _ := newTestProvider(t, http.StatusOK, "stream_overloaded.sse")
+
+	var content string
+	err := provider.ChatStream(context.Background(), &chat.ChatRequest{
+		Messages: []chat.Message{{Role: "user", Content: "Who is Luke's father?"}},
+	}, func(chunk *chat.ChatResponse) error {
+		content += chunk.Content
+		return nil
+	})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Type != "overloaded_error" {
+		t.Fatalf("Expected overloaded APIError, got: %v", err)
+	}
+
+	if !errors.Is(err, chat.ErrOverloaded) {
+		t.Error("Expected overloaded error to match chat.ErrOverloaded")
+	}
+
+	if content != "Darth" {
+		t.Errorf("Expected partial content before the error, got '%s'", content)
+	}
+}
+
+func TestAnthropicChatProvider_StreamCutOff(t *testing.T) {
+	provider, _ := newTestProvider(t, http.StatusOK, "stream_truncated.sse")
+
+	var content string
+	err := provider.ChatStream(context.Background(), &chat.ChatRequest{
+		Messages: []chat.Message{{Role: "user", Content: "Who is Luke's father?"}},
+	}, func(chunk *chat.ChatResponse) error {
+		content += chunk.Content
+		return nil
+	})
+
+	if !errors.Is(err, chat.ErrMalformedResponse) {
+		t.Fatalf("Expected a stream without message_stop to fail, got: %v", err)
+	}
+
+	if content != "Darth Vader is Luke's father." {
+		t.Errorf("Expected the content before the cut, got '%s'", content)
+	}
+}
+
+func TestAnthropicChatProvider_ErrorMapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		fixture string
+		want    error
+	}{
+		{"invalid request", http.StatusBadRequest, "error_invalid_request.json", chat.ErrInvalidRequest},
+		{"rate limited", http.StatusTooManyRequests, "error_rate_limit.json", chat.ErrRateLimited},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, _ := newTestProvider(t, tt.status, tt.fixture)
+
+			_, err := provider.Chat(context.Background(), &chat.ChatRequest{
+				Messages: []chat.Message{{Role: "user", Content: "Hello"}},
+			})
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status {
+				t.Fatalf("Expected APIError with status %d, got: %v", tt.status, err)
+			}
+
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Expected error to match %v, got: %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestAnthropicChatProvider_RejectsUnsupportedRoles(t *testing.T) {
+	provider, _ := newTestProvider(t, http.StatusOK, "message.json")
+
+	_, err := provider.Chat(context.Background(), &chat.ChatRequest{
+		Messages: []chat.Message{{Role: "system", Content: "Only a system prompt"}},
+	})
+	if !errors.Is(err, chat.ErrInvalidRequest) {
+		t.Errorf("Expected ErrInvalidRequest without user messages, got: %v", err)
+	}
+}
@@ -0,0 +1,230 @@
+package anthropic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"chat-backend/internal/chat"
+	"chat-backend/internal/chat/sse"
+)
+
+const (
+	defaultBaseURL   = "https://api.anthropic.com"
+	defaultVersion   = "2023-06-01"
+	defaultMaxTokens = 1024
+)
+
+type StreamCallback func(event *StreamEvent) error
+
+type AnthropicClient interface {
+	Messages(ctx context.Context, req *MessagesRequest) (*MessagesResponse, error)
+	MessagesStream(ctx context.Context, req *MessagesRequest, callback StreamCallback) error
+}
+
+type Config struct {
+	BaseURL   string
+	APIKey    string
+	Model     string
+	MaxTokens int
+}
+
+type anthropicHttpClient struct {
+	config     Config
+	httpClient *http.Client
+}
+
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type MessagesRequest struct {
+	Model     string    `json:"model"`
+	MaxTokens int       `json:"max_tokens"`
+	System    string    `json:"system,omitempty"`
+	Messages  []Message `json:"messages"`
+	Stream    bool      `json:"stream,omitempty"`
+}
+
+type ContentBlock struct {
+	Type string `json:"type"`
+	Text string `json:"text,omitempty"`
+}
+
+type MessagesResponse struct {
+	ID         string         `json:"id"`
+	Model      string         `json:"model"`
+	Content    []ContentBlock `json:"content"`
+	StopReason string         `json:"stop_reason"`
+	Usage      Usage          `json:"usage"`
+}
+
+type Usage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+// StreamEvent is one event of the Messages streaming protocol. Only the
+// fields relevant to its Type are populated.
+type StreamEvent struct {
+	Type    string            `json:"type"`
+	Message *MessagesResponse `json:"message,omitempty"`
+	Index   int               `json:"index"`
+	Delta   *StreamDelta      `json:"delta,omitempty"`
+	Usage   *Usage            `json:"usage,omitempty"`
+	Error   *ErrorDetail      `json:"error,omitempty"`
+}
+
+type StreamDelta struct {
+	Type       string `json:"type,omitempty"`
+	Text       string `json:"text,omitempty"`
+	StopReason string `json:"stop_reason,omitempty"`
+}
+
+type ErrorDetail struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+type errorResponse struct {
+	Type  string      `json:"type"`
+	Error ErrorDetail `json:"error"`
+}
+
+func NewClient(config Config) AnthropicClient {
+	if config.BaseURL == "" {
+		config.BaseURL = defaultBaseURL
+	}
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+	if config.MaxTokens <= 0 {
+		config.MaxTokens = defaultMaxTokens
+	}
+
+	return &anthropicHttpClient{
+		config:     config,
+		httpClient: &http.Client{},
+	}
+}
+
+func (c *anthropicHttpClient) Messages(ctx context.Context, req *MessagesRequest) (*MessagesResponse, error) {
+	req.Stream = false
+
+	resp, err := c.post(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var message MessagesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&message); err != nil {
+		return nil, chat.Errorf(chat.ErrMalformedResponse, "failed to decode response: %w", err)
+	}
+
+	return &message, nil
+}
+
+func (c *anthropicHttpClient) MessagesStream(ctx context.Context, req *MessagesRequest, callback StreamCallback) error {
+	req.Stream = true
+
+	resp, err := c.post(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	reader := sse.NewReader(resp.Body)
+	for {
+		event, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			// A stream that ends without message_stop was cut off, so the
+			// answer may be incomplete
+			return chat.Errorf(chat.ErrMalformedResponse, "stream ended before message_stop")
+		}
+		if err != nil {
+			return chat.TransportError(fmt.Errorf("failed to read stream: %w", err))
+		}
+
+		var streamEvent StreamEvent
+		if err := json.Unmarshal([]byte(event.Data), &streamEvent); err != nil {
+			return chat.Errorf(chat.ErrMalformedResponse, "failed to decode stream event: %w", err)
+		}
+
+		// Errors can arrive mid-stream, after a 200 status
+		if streamEvent.Type == "error" && streamEvent.Error != nil {
+			return &APIError{
+				Type:    streamEvent.Error.Type,
+				Message: streamEvent.Error.Message,
+			}
+		}
+
+		if err := callback(&streamEvent); err != nil {
+			return err
+		}
+
+		if streamEvent.Type == "message_stop" {
+			return nil
+		}
+	}
+}
+
+func (c *anthropicHttpClient) post(ctx context.Context, req *MessagesRequest) (*http.Response, error) {
+	if req.Model == "" {
+		req.Model = c.config.Model
+	}
+	if req.MaxTokens <= 0 {
+		req.MaxTokens = c.config.MaxTokens
+	}
+
+	reqBody, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/v1/messages", c.config.BaseURL)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-api-key", c.config.APIKey)
+	httpReq.Header.Set("anthropic-version", defaultVersion)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, chat.TransportError(fmt.Errorf("failed to send request: %w", err))
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, parseErrorResponse(resp)
+	}
+
+	return resp, nil
+}
+
+func parseErrorResponse(resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Message:    http.StatusText(resp.StatusCode),
+	}
+
+	var errResp errorResponse
+	if err := json.Unmarshal(body, &errResp); err != nil || errResp.Error.Type == "" {
+		slog.Error("Anthropic API returned unparseable error", "status", resp.StatusCode, "body", string(body))
+		return apiErr
+	}
+
+	apiErr.Type = errResp.Error.Type
+	apiErr.Message = errResp.Error.Message
+	return apiErr
+}
@@ -0,0 +1,47 @@
+package anthropic
+
+import (
+	"fmt"
+	"strings"
+
+	"chat-backend/internal/chat"
+)
+
+// APIError is an error returned by the Messages API, either as a non-200
+// response or as an error event in the middle of a stream
+type APIError struct {
+	StatusCode int
+	Type       string
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.StatusCode == 0 {
+		return fmt.Sprintf("anthropic API stream error (%s): %s", e.Type, e.Message)
+	}
+	return fmt.Sprintf("anthropic API returned status %d (%s): %s", e.StatusCode, e.Type, e.Message)
+}
+
+// Unwrap maps Anthropic error types onto chat errors
+func (e *APIError) Unwrap() error {
+	switch e.Type {
+	case "invalid_request_error":
+		if strings.Contains(e.Message, "prompt is too long") {
+			return chat.ErrContextLength
+		}
+		return chat.ErrInvalidRequest
+	case "not_found_error", "request_too_large":
+		return chat.ErrInvalidRequest
+	case "authentication_error", "permission_error":
+		return chat.ErrUnauthorized
+	case "rate_limit_error":
+		return chat.ErrRateLimited
+	case "timeout_error":
+		return chat.ErrTimeout
+	case "overloaded_error":
+		return chat.ErrOverloaded
+	case "api_error":
+		return chat.ErrProviderUnavailable
+	}
+	return chat.StatusKind(e.StatusCode)
+}
@@ -0,0 +1,58 @@
+package azure
+
+import (
+	"container/list"
+	"sync"
+)
+
+// maxRememberedAnswers caps the answers whose QnA IDs are remembered. A
+// knowledge base has a fixed set of answers, so this only evicts anything
+// for the largest ones.
+const maxRememberedAnswers = 10_000
+
+// answerCache maps answer texts to their QnA IDs, evicting the least
+// recently used answer once full. The zero value is ready to use.
+type answerCache struct {
+	mu    sync.Mutex
+	order *list.List // of *answerEntry, most recently used first
+	items map[string]*list.Element
+}
+
+type answerEntry struct {
+	answer string
+	qnaID  int
+}
+
+func (c *answerCache) put(answer string, qnaID int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.items == nil {
+		c.order = list.New()
+		c.items = map[string]*list.Element{}
+	}
+	if elem, ok := c.items[answer]; ok {
+		elem.Value.(*answerEntry).qnaID = qnaID
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[answer] = c.order.PushFront(&answerEntry{answer: answer, qnaID: qnaID})
+	if c.order.Len() > maxRememberedAnswers {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*answerEntry).answer)
+	}
+}
+
+func (c *answerCache) get(answer string) (int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[answer]
+	if !ok {
+		return 0, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*answerEntry).qnaID, true
+}
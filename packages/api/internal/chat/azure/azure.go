@@ -2,56 +2,195 @@ package azure
 
 import (
 	"context"
-	"fmt"
+	"strings"
 
 	"chat-backend/internal/chat"
 )
 
+const (
+	defaultTop                      = 3
+	defaultConfidenceScoreThreshold = 0.2
+	defaultStreamChunkWords         = 4
+)
+
 type AzureChatProvider struct {
-	client AzureQuestionAnsweringClient
+	client    AzureQuestionAnsweringClient
+	top       int
+	threshold float64
+
+	// Maps answers we have returned to their QnA IDs so a follow-up
+	// question can pass the previous answer as context. Answers in a
+	// knowledge base map to a single QnA pair, so the text is a stable key.
+	answerIDs answerCache
 }
 
 func NewAzureChatProvider(endpoint, apiKey, projectName, deploymentName string) *AzureChatProvider {
 	return &AzureChatProvider{
-		client: NewClient(endpoint, apiKey, projectName, deploymentName),
+		client:    NewClient(endpoint, apiKey, projectName, deploymentName),
+		top:       defaultTop,
+		threshold: defaultConfidenceScoreThreshold,
+	}
+}
+
+// WithTop sets how many ranked answers are requested from the knowledge base
+func (p *AzureChatProvider) WithTop(top int) *AzureChatProvider {
+	if top > 0 {
+		p.top = top
 	}
+	return p
+}
+
+// WithConfidenceThreshold sets the minimum score for answers to be returned
+func (p *AzureChatProvider) WithConfidenceThreshold(threshold float64) *AzureChatProvider {
+	if threshold >= 0 && threshold <= 1 {
+		p.threshold = threshold
+	}
+	return p
 }
 
 func (p *AzureChatProvider) Chat(ctx context.Context, req *chat.ChatRequest) (*chat.ChatResponse, error) {
 	if len(req.Messages) == 0 {
-		return nil, fmt.Errorf("no messages provided")
+		return nil, chat.Errorf(chat.ErrInvalidRequest, "no messages provided")
+	}
+
+	if err := chat.RejectAttachments(req); err != nil {
+		return nil, err
 	}
 
 	// Find the last user message to use as the question
-	var question string
+	questionIndex := -1
 	for i := len(req.Messages) - 1; i >= 0; i-- {
 		if req.Messages[i].Role == "user" {
-			question = req.Messages[i].Content
+			questionIndex = i
 			break
 		}
 	}
 
-	if question == "" {
-		return nil, fmt.Errorf("no user message found in conversation")
+	if questionIndex < 0 || req.Messages[questionIndex].Content == "" {
+		return nil, chat.Errorf(chat.ErrInvalidRequest, "no user message found in conversation")
 	}
 
-	queryResp, err := p.client.Query(ctx, question)
+	queryReq := &QueryRequest{
+		Question:                 req.Messages[questionIndex].Content,
+		ConfidenceScoreThreshold: p.threshold,
+		Top:                      p.topOrDefault(),
+		Context:                  p.previousContext(req.Messages[:questionIndex]),
+	}
+
+	queryResp, err := p.client.Query(ctx, queryReq)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(queryResp.Answers) == 0 {
+	answers := make([]chat.Answer, 0, len(queryResp.Answers))
+	for _, answer := range queryResp.Answers {
+		// The service reports "no answer" as a placeholder with ID -1
+		if answer.QnaID < 0 || answer.Answer == "" {
+			continue
+		}
+		answers = append(answers, chat.Answer{
+			Content:  answer.Answer,
+			Score:    answer.ConfidenceScore,
+			Source:   answer.Source,
+			Metadata: answer.Metadata,
+		})
+		p.answerIDs.put(answer.Answer, answer.QnaID)
+	}
+
+	if len(answers) == 0 {
 		return &chat.ChatResponse{
-			Content: "I don't have an answer for that question.",
+			Content:      "I don't have an answer for that question.",
+			FinishReason: chat.FinishReasonNoAnswer,
 		}, nil
 	}
 
 	return &chat.ChatResponse{
-		Content: queryResp.Answers[0].Answer,
+		Content: answers[0].Content,
+		Answers: answers,
 	}, nil
 }
 
+// ChatStream emulates streaming for the question answering service, which
+// only returns complete answers, by sending the best answer a few words at a
+// time. The final chunk carries the ranked answers and the finish reason.
 func (p *AzureChatProvider) ChatStream(ctx context.Context, req *chat.ChatRequest, callback chat.StreamCallback) error {
-	return fmt.Errorf("streaming not supported by azure provider")
+	resp, err := p.Chat(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	chunks := chunkWords(resp.Content, defaultStreamChunkWords)
+	for i, chunk := range chunks {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		chunkResp := &chat.ChatResponse{Content: chunk}
+		if i == len(chunks)-1 {
+			chunkResp.Answers = resp.Answers
+			chunkResp.FinishReason = resp.FinishReason
+		}
+		if err := callback(chunkResp); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *AzureChatProvider) topOrDefault() int {
+	if p.top <= 0 {
+		return defaultTop
+	}
+	return p.top
+}
+
+// previousContext builds the follow-up context from the most recent
+// assistant answer and the user question that preceded it
+func (p *AzureChatProvider) previousContext(history []chat.Message) *QueryContext {
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Role != "assistant" {
+			continue
+		}
+
+		qnaID, ok := p.answerIDs.get(history[i].Content)
+		if !ok {
+			return nil
+		}
+
+		queryCtx := &QueryContext{PreviousQnaID: qnaID}
+		for j := i - 1; j >= 0; j-- {
+			if history[j].Role == "user" {
+				queryCtx.PreviousUserQuery = history[j].Content
+				break
+			}
+		}
+		return queryCtx
+	}
+
+	return nil
 }
 
+// chunkWords splits text into chunks of n words, keeping the whitespace
+// so that concatenating the chunks reproduces the original text
+func chunkWords(text string, n int) []string {
+	var chunks []string
+	var current strings.Builder
+	words := 0
+
+	for _, field := range strings.SplitAfter(text, " ") {
+		current.WriteString(field)
+		words++
+		if words == n {
+			chunks = append(chunks, current.String())
+			current.Reset()
+			words = 0
+		}
+	}
+
+	if current.Len() > 0 || len(chunks) == 0 {
+		chunks = append(chunks, current.String())
+	}
+
+	return chunks
+}
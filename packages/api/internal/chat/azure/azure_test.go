@@ -2,19 +2,22 @@ package azure
 
 import (
 	"context"
+	"strconv"
 	"testing"
 
 	"chat-backend/internal/chat"
 )
 
 type mockAzureClient struct {
-	response *QueryResponse
-	err      error
-	lastQuery string
+	response    *QueryResponse
+	err         error
+	lastQuery   string
+	lastRequest *QueryRequest
 }
 
-func (m *mockAzureClient) Query(ctx context.Context, query string) (*QueryResponse, error) {
-	m.lastQuery = query
+func (m *mockAzureClient) Query(ctx context.Context, req *QueryRequest) (*QueryResponse, error) {
+	m.lastQuery = req.Question
+	m.lastRequest = req
 	if m.err != nil {
 		return nil, m.err
 	}
@@ -111,4 +114,161 @@ func TestAzureChatProvider_FindsLastUserMessage(t *testing.T) {
 	if mockClient.lastQuery != expectedQuery {
 		t.Errorf("Expected query '%s', got '%s'", expectedQuery, mockClient.lastQuery)
 	}
-}
\ No newline at end of file
+}
+
+func TestAzureChatProvider_ReturnsRankedAnswers(t *testing.T) {
+	mockClient := &mockAzureClient{
+		response: &QueryResponse{
+			Answers: []QueryAnswer{
+				{QnaID: 4, Answer: "Best answer", ConfidenceScore: 0.9, Source: "faq.tsv", Metadata: map[string]string{"topic": "jedi"}},
+				{QnaID: 7, Answer: "Second answer", ConfidenceScore: 0.4, Source: "manual.pdf"},
+				{QnaID: -1, Answer: "No answer found"},
+			},
+		},
+	}
+
+	provider := &AzureChatProvider{client: mockClient, top: 5, threshold: 0.3}
+
+	resp, err := provider.Chat(context.Background(), &chat.ChatRequest{
+		Messages: []chat.Message{{Role: "user", Content: "Who trains Jedi?"}},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if mockClient.lastRequest.Top != 5 || mockClient.lastRequest.ConfidenceScoreThreshold != 0.3 {
+		t.Errorf("Expected top 5 and threshold 0.3, got %d and %v", mockClient.lastRequest.Top, mockClient.lastRequest.ConfidenceScoreThreshold)
+	}
+
+	if resp.Content != "Best answer" {
+		t.Errorf("Expected best answer as content, got '%s'", resp.Content)
+	}
+
+	if len(resp.Answers) != 2 {
+		t.Fatalf("Expected 2 answers (placeholder dropped), got %d", len(resp.Answers))
+	}
+
+	if resp.Answers[0].Source != "faq.tsv" || resp.Answers[0].Score != 0.9 || resp.Answers[0].Metadata["topic"] != "jedi" {
+		t.Errorf("Expected source, score and metadata to be kept, got %+v", resp.Answers[0])
+	}
+}
+
+func TestAzureChatProvider_MarksNoAnswer(t *testing.T) {
+	mockClient := &mockAzureClient{
+		response: &QueryResponse{Answers: []QueryAnswer{{QnaID: -1, Answer: "No answer found"}}},
+	}
+	provider := &AzureChatProvider{client: mockClient}
+
+	resp, err := provider.Chat(context.Background(), &chat.ChatRequest{
+		Messages: []chat.Message{{Role: "user", Content: "Who trains Sith?"}},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if resp.FinishReason != chat.FinishReasonNoAnswer || len(resp.Answers) != 0 {
+		t.Errorf("Expected the fallback answer to be marked, got %+v", resp)
+	}
+}
+
+func TestAzureChatProvider_SendsFollowUpContext(t *testing.T) {
+	mockClient := &mockAzureClient{
+		response: &QueryResponse{
+			Answers: []QueryAnswer{{QnaID: 12, Answer: "Use the reset link on the login page."}},
+		},
+	}
+
+	provider := &AzureChatProvider{client: mockClient}
+
+	_, err := provider.Chat(context.Background(), &chat.ChatRequest{
+		Messages: []chat.Message{{Role: "user", Content: "How do I reset my password?"}},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if mockClient.lastRequest.Context != nil {
+		t.Errorf("Expected no context on first turn, got %+v", mockClient.lastRequest.Context)
+	}
+
+	_, err = provider.Chat(context.Background(), &chat.ChatRequest{
+		Messages: []chat.Message{
+			{Role: "user", Content: "How do I reset my password?"},
+			{Role: "assistant", Content: "Use the reset link on the login page."},
+			{Role: "user", Content: "What if I don't get the email?"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	queryCtx := mockClient.lastRequest.Context
+	if queryCtx == nil {
+		t.Fatal("Expected follow-up context to be sent")
+	}
+
+	if queryCtx.PreviousQnaID != 12 || queryCtx.PreviousUserQuery != "How do I reset my password?" {
+		t.Errorf("Unexpected context: %+v", queryCtx)
+	}
+}
+
+func TestAzureChatProvider_ChatStreamChunksAnswer(t *testing.T) {
+	answer := "A lightsaber is an elegant weapon for a more civilized age."
+	mockClient := &mockAzureClient{
+		response: &QueryResponse{
+			Answers: []QueryAnswer{{QnaID: 1, Answer: answer, Source: "faq.tsv"}},
+		},
+	}
+
+	provider := &AzureChatProvider{client: mockClient}
+
+	var chunks []*chat.ChatResponse
+	err := provider.ChatStream(context.Background(), &chat.ChatRequest{
+		Messages: []chat.Message{{Role: "user", Content: "What is a lightsaber?"}},
+	}, func(chunk *chat.ChatResponse) error {
+		chunks = append(chunks, chunk)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(chunks) < 2 {
+		t.Fatalf("Expected answer to be split into several chunks, got %d", len(chunks))
+	}
+
+	var content string
+	for _, chunk := range chunks {
+		content += chunk.Content
+	}
+	if content != answer {
+		t.Errorf("Expected chunks to reassemble the answer, got '%s'", content)
+	}
+
+	last := chunks[len(chunks)-1]
+	if len(last.Answers) != 1 || last.Answers[0].Source != "faq.tsv" {
+		t.Errorf("Expected final chunk to carry answers, got %+v", last.Answers)
+	}
+}
+
+func TestAnswerCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	var cache answerCache
+	cache.put("first", 1)
+	for i := 0; i < maxRememberedAnswers-1; i++ {
+		cache.put(strconv.Itoa(i), i)
+	}
+
+	// Using the first answer keeps it over the next oldest one
+	cache.get("first")
+	cache.put("new", 2)
+
+	if id, ok := cache.get("first"); !ok || id != 1 {
+		t.Errorf("Expected the recently used answer to be kept, got %d, %v", id, ok)
+	}
+	if _, ok := cache.get("0"); ok {
+		t.Error("Expected the least recently used answer to be evicted")
+	}
+	if id, ok := cache.get("new"); !ok || id != 2 {
+		t.Errorf("Expected the new answer to be remembered, got %d, %v", id, ok)
+	}
+}
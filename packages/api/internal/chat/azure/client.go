@@ -9,10 +9,12 @@ import (
 	"log/slog"
 	"net/http"
 	"net/url"
+
+	"chat-backend/internal/chat"
 )
 
 type AzureQuestionAnsweringClient interface {
-	Query(ctx context.Context, question string) (*QueryResponse, error)
+	Query(ctx context.Context, req *QueryRequest) (*QueryResponse, error)
 }
 
 type azureHttpClient struct {
@@ -24,9 +26,17 @@ type azureHttpClient struct {
 }
 
 type QueryRequest struct {
-	Question                 string  `json:"question"`
-	ConfidenceScoreThreshold float64 `json:"confidenceScoreThreshold"`
-	Top                      int     `json:"top"`
+	Question                 string        `json:"question"`
+	ConfidenceScoreThreshold float64       `json:"confidenceScoreThreshold"`
+	Top                      int           `json:"top"`
+	Context                  *QueryContext `json:"context,omitempty"`
+}
+
+// QueryContext links a question to the previous answer so the service can
+// resolve follow-up prompts in multi-turn conversations
+type QueryContext struct {
+	PreviousQnaID     int    `json:"previousQnaId"`
+	PreviousUserQuery string `json:"previousUserQuery,omitempty"`
 }
 
 type QueryResponse struct {
@@ -34,6 +44,7 @@ type QueryResponse struct {
 }
 
 type QueryAnswer struct {
+	QnaID           int               `json:"id"`
 	Answer          string            `json:"answer"`
 	ConfidenceScore float64           `json:"confidenceScore"`
 	Source          string            `json:"source"`
@@ -59,13 +70,7 @@ func (c *azureHttpClient) getQueryURL() string {
 	)
 }
 
-func (c *azureHttpClient) Query(ctx context.Context, question string) (*QueryResponse, error) {
-	queryReq := QueryRequest{
-		Question:                 question,
-		ConfidenceScoreThreshold: 0.2,
-		Top:                      1,
-	}
-
+func (c *azureHttpClient) Query(ctx context.Context, queryReq *QueryRequest) (*QueryResponse, error) {
 	url := c.getQueryURL()
 
 	jsonData, err := json.Marshal(queryReq)
@@ -91,25 +96,25 @@ func (c *azureHttpClient) Query(ctx context.Context, question string) (*QueryRes
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
 		slog.Error("Failed to make request to Azure", "error", err, "url", url)
-		return nil, fmt.Errorf("failed to connect to Azure service")
+		return nil, chat.TransportError(fmt.Errorf("failed to connect to Azure service: %w", err))
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		slog.Error("Failed to read Azure response body", "error", err)
-		return nil, fmt.Errorf("failed to read response")
+		return nil, chat.TransportError(fmt.Errorf("failed to read response: %w", err))
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		slog.Error("Azure API returned error", "status", resp.StatusCode, "body", string(body))
-		return nil, fmt.Errorf("Azure service error")
+		return nil, chat.Errorf(chat.StatusKind(resp.StatusCode), "Azure service error: status %d", resp.StatusCode)
 	}
 
 	var queryResp QueryResponse
 	if err := json.Unmarshal(body, &queryResp); err != nil {
 		slog.Error("Failed to unmarshal Azure response", "error", err, "body", string(body))
-		return nil, fmt.Errorf("failed to parse response")
+		return nil, chat.Errorf(chat.ErrMalformedResponse, "failed to parse response")
 	}
 
 	return &queryResp, nil
@@ -0,0 +1,32 @@
+package azureopenai
+
+import (
+	"context"
+
+	"chat-backend/internal/chat"
+	"chat-backend/internal/chat/openai"
+)
+
+// AzureOpenAIChatProvider answers through an Azure OpenAI deployment, which
+// speaks the OpenAI chat completions API under its own URLs and auth
+type AzureOpenAIChatProvider struct {
+	provider *openai.OpenAIChatProvider
+}
+
+func NewAzureOpenAIChatProvider(config Config) *AzureOpenAIChatProvider {
+	return &AzureOpenAIChatProvider{
+		provider: openai.NewOpenAIChatProvider(config.openAIConfig()),
+	}
+}
+
+func (p *AzureOpenAIChatProvider) Chat(ctx context.Context, req *chat.ChatRequest) (*chat.ChatResponse, error) {
+	resp, err := p.provider.Chat(ctx, req)
+	if err != nil {
+		return nil, contentFilterError(err)
+	}
+	return resp, nil
+}
+
+func (p *AzureOpenAIChatProvider) ChatStream(ctx context.Context, req *chat.ChatRequest, callback chat.StreamCallback) error {
+	return contentFilterError(p.provider.ChatStream(ctx, req, callback))
+}
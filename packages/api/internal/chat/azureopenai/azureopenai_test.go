@@ -0,0 +1,145 @@
+package azureopenai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"chat-backend/internal/chat"
+)
+
+// serve answers every request with the given status and body, and passes
+// the request to inspect, if not nil
+func serve(t *testing.T, status int, body string, inspect func(r *http.Request, body []byte)) string {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if inspect != nil {
+			data, _ := io.ReadAll(r.Body)
+			inspect(r, data)
+		}
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func userRequest(content string) *chat.ChatRequest {
+	return &chat.ChatRequest{
+		Messages: []chat.Message{{Role: "user", Content: content}},
+	}
+}
+
+func TestAzureOpenAIChatProvider_Chat(t *testing.T) {
+	var path, apiVersion, apiKey string
+	var sent map[string]any
+	endpoint := serve(t, http.StatusOK, `{
+		"choices": [{"index": 0, "finish_reason": "tool_calls", "message": {"role": "assistant", "content": "", "tool_calls": [
+			{"id": "call_1", "type": "function", "function": {"name": "lookup", "arguments": "{}"}}
+		]}}],
+		"usage": {"prompt_tokens": 9, "completion_tokens": 2, "total_tokens": 11}
+	}`, func(r *http.Request, body []byte) {
+		path, apiVersion, apiKey = r.URL.Path, r.URL.Query().Get("api-version"), r.Header.Get("api-key")
+		json.Unmarshal(body, &sent)
+	})
+	provider := NewAzureOpenAIChatProvider(Config{Endpoint: endpoint + "/", Deployment: "gpt-4o", APIKey: "secret", APIVersion: "2024-06-01"})
+
+	req := userRequest("Where is my order?")
+	req.Tools = []chat.Tool{{Name: "lookup", Parameters: json.RawMessage(`{"type":"object"}`)}}
+	resp, err := provider.Chat(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(resp.ToolCalls) != 1 || resp.ToolCalls[0].Name != "lookup" || resp.Usage == nil || resp.Usage.TotalTokens != 11 {
+		t.Errorf("Expected the tool call and usage, got %+v", resp)
+	}
+	if path != "/openai/deployments/gpt-4o/chat/completions" || apiVersion != "2024-06-01" || apiKey != "secret" {
+		t.Errorf("Expected the deployment URL and api-key, got %s?api-version=%s with key '%s'", path, apiVersion, apiKey)
+	}
+	if _, ok := sent["model"]; ok || sent["tools"] == nil {
+		t.Errorf("Expected the tools and no model to be sent, got %v", sent)
+	}
+}
+
+func TestAzureOpenAIChatProvider_BearerAuth(t *testing.T) {
+	var header http.Header
+	var apiVersion string
+	endpoint := serve(t, http.StatusOK, `{"choices": [{"message": {"role": "assistant", "content": "ok"}}]}`, func(r *http.Request, _ []byte) {
+		header, apiVersion = r.Header, r.URL.Query().Get("api-version")
+	})
+	provider := NewAzureOpenAIChatProvider(Config{Endpoint: endpoint, Deployment: "gpt-4o", BearerToken: "entra-token"})
+
+	if _, err := provider.Chat(context.Background(), userRequest("Hi")); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if header.Get("Authorization") != "Bearer entra-token" || header.Get("api-key") != "" {
+		t.Errorf("Expected only the bearer token, got %v", header)
+	}
+	if apiVersion != defaultAPIVersion {
+		t.Errorf("Expected default api-version, got '%s'", apiVersion)
+	}
+}
+
+func TestAzureOpenAIChatProvider_PromptContentFilter(t *testing.T) {
+	endpoint := serve(t, http.StatusBadRequest, `{"error": {
+		"code": "content_filter",
+		"message": "The response was filtered",
+		"innererror": {
+			"code": "ResponsibleAIPolicyViolation",
+			"content_filter_result": {
+				"hate": {"filtered": false, "severity": "safe"},
+				"violence": {"filtered": true, "severity": "high"}
+			}
+		}
+	}}`, nil)
+	provider := NewAzureOpenAIChatProvider(Config{Endpoint: endpoint, Deployment: "gpt-4o", APIKey: "secret"})
+
+	_, err := provider.Chat(context.Background(), userRequest("something nasty"))
+
+	var filterErr *ContentFilterError
+	if !errors.As(err, &filterErr) {
+		t.Fatalf("Expected ContentFilterError, got: %v", err)
+	}
+	if filterErr.Stage != FilterStagePrompt {
+		t.Errorf("Expected prompt stage, got '%s'", filterErr.Stage)
+	}
+	if categories := filterErr.Results.FilteredCategories(); len(categories) != 1 || categories[0] != "violence" {
+		t.Errorf("Expected violence category, got %v", categories)
+	}
+	if !errors.Is(err, chat.ErrContentFiltered) {
+		t.Error("Expected error to match chat.ErrContentFiltered")
+	}
+}
+
+func TestAzureOpenAIChatProvider_CompletionContentFilter(t *testing.T) {
+	endpoint := serve(t, http.StatusOK,
+		"data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Partial\"}}]}\n\n"+
+			"data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"delta\":{},\"finish_reason\":\"content_filter\",\"content_filter_results\":{\"self_harm\":{\"filtered\":true,\"severity\":\"medium\"}}}]}\n\n"+
+			"data: [DONE]\n\n", nil)
+	provider := NewAzureOpenAIChatProvider(Config{Endpoint: endpoint, Deployment: "gpt-4o", APIKey: "secret"})
+
+	var content string
+	err := provider.ChatStream(context.Background(), userRequest("Hi"), func(chunk *chat.ChatResponse) error {
+		content += chunk.Content
+		return nil
+	})
+
+	var filterErr *ContentFilterError
+	if !errors.As(err, &filterErr) {
+		t.Fatalf("Expected ContentFilterError, got: %v", err)
+	}
+	if filterErr.Stage != FilterStageCompletion || filterErr.Error() != "completion blocked by content filter: self_harm" {
+		t.Errorf("Expected the completion stage and its category, got '%v'", filterErr)
+	}
+	if content != "Partial" {
+		t.Errorf("Expected partial content before the filter, got '%s'", content)
+	}
+}
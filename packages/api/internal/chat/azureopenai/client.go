@@ -0,0 +1,71 @@
+package azureopenai
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"chat-backend/internal/chat/openai"
+)
+
+const (
+	defaultAPIVersion = "2024-10-21"
+	defaultTimeout    = 60 * time.Second
+)
+
+// Config holds the connection settings for an Azure OpenAI deployment.
+// Either APIKey or BearerToken (a Microsoft Entra ID token) must be set.
+type Config struct {
+	Endpoint    string
+	Deployment  string
+	APIVersion  string
+	APIKey      string
+	BearerToken string
+	// Timeout bounds a whole answer, but only the wait for the response
+	// headers of a stream. It defaults to a minute.
+	Timeout time.Duration
+}
+
+// ContentFilterResults maps a category (hate, self_harm, sexual, violence,
+// jailbreak, ...) to the verdict of Azure's content filter
+type ContentFilterResults map[string]ContentFilterResult
+
+type ContentFilterResult struct {
+	Filtered bool   `json:"filtered"`
+	Severity string `json:"severity,omitempty"`
+	Detected *bool  `json:"detected,omitempty"`
+}
+
+// FilteredCategories returns the categories that caused content to be filtered
+func (r ContentFilterResults) FilteredCategories() []string {
+	var categories []string
+	for category, result := range r {
+		if result.Filtered {
+			categories = append(categories, category)
+		}
+	}
+	return categories
+}
+
+// openAIConfig points an OpenAI client at the deployment. Azure names the
+// model by the deployment in the URL, so none is sent.
+func (c Config) openAIConfig() openai.Config {
+	config := openai.Config{
+		BaseURL: strings.TrimRight(c.Endpoint, "/") + "/openai/deployments/" + url.PathEscape(c.Deployment),
+		Query:   url.Values{"api-version": {c.APIVersion}},
+		Timeout: c.Timeout,
+	}
+	if config.Query.Get("api-version") == "" {
+		config.Query.Set("api-version", defaultAPIVersion)
+	}
+	if config.Timeout == 0 {
+		config.Timeout = defaultTimeout
+	}
+
+	if c.BearerToken != "" {
+		config.APIKey = c.BearerToken
+	} else {
+		config.APIKey, config.AuthHeader = c.APIKey, "api-key"
+	}
+	return config
+}
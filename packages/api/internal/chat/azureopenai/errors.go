@@ -0,0 +1,66 @@
+package azureopenai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"chat-backend/internal/chat"
+	"chat-backend/internal/chat/openai"
+)
+
+const (
+	FilterStagePrompt     = "prompt"
+	FilterStageCompletion = "completion"
+)
+
+// ContentFilterError reports that Azure's content filter blocked the prompt
+// or cut the completion short
+type ContentFilterError struct {
+	Stage   string
+	Results ContentFilterResults
+}
+
+func (e *ContentFilterError) Error() string {
+	categories := e.Results.FilteredCategories()
+	sort.Strings(categories)
+	if len(categories) == 0 {
+		return fmt.Sprintf("%s blocked by content filter", e.Stage)
+	}
+	return fmt.Sprintf("%s blocked by content filter: %s", e.Stage, strings.Join(categories, ", "))
+}
+
+func (e *ContentFilterError) Unwrap() error {
+	return chat.ErrContentFiltered
+}
+
+// contentFilterError turns the content filter errors of the OpenAI client
+// into a ContentFilterError listing the filtered categories. Prompts are
+// rejected with a 400 whose inner error has the results, and completions
+// finish early with the results on the choice.
+func contentFilterError(err error) error {
+	var completionErr *openai.ContentFilterError
+	if errors.As(err, &completionErr) {
+		filterErr := &ContentFilterError{Stage: FilterStageCompletion}
+		if len(completionErr.Results) > 0 {
+			json.Unmarshal(completionErr.Results, &filterErr.Results)
+		}
+		return filterErr
+	}
+
+	var apiErr *openai.APIError
+	if errors.As(err, &apiErr) && apiErr.Code == "content_filter" {
+		filterErr := &ContentFilterError{Stage: FilterStagePrompt}
+		var inner struct {
+			ContentFilterResults ContentFilterResults `json:"content_filter_result"`
+		}
+		if len(apiErr.InnerError) > 0 && json.Unmarshal(apiErr.InnerError, &inner) == nil {
+			filterErr.Results = inner.ContentFilterResults
+		}
+		return filterErr
+	}
+
+	return err
+}
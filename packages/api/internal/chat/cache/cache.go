@@ -0,0 +1,278 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"chat-backend/internal/chat"
+	"chat-backend/internal/tenant"
+)
+
+// MetadataCache is the response metadata key reporting whether the answer
+// came from the cache
+const MetadataCache = "cache"
+
+// Values of MetadataCache
+const (
+	StatusHit    = "hit"
+	StatusMiss   = "miss"
+	StatusBypass = "bypass"
+)
+
+const (
+	defaultTTL        = time.Hour
+	defaultMaxEntries = 1000
+	// Cached answers are replayed to streaming clients in chunks of about
+	// this many bytes, split on word boundaries
+	replayChunkBytes = 32
+)
+
+// Config controls the cache. Provider and Model are part of every key so
+// answers are never shared between different backends.
+type Config struct {
+	Provider   string
+	Model      string
+	TTL        time.Duration
+	MaxEntries int
+	// Store defaults to an in-memory LRU of MaxEntries entries
+	Store Store
+}
+
+// CacheProvider answers repeated requests from a cache instead of the
+// wrapped provider. Only successful responses are stored.
+type CacheProvider struct {
+	provider chat.ChatProvider
+	config   Config
+}
+
+func NewCacheProvider(provider chat.ChatProvider, config Config) *CacheProvider {
+	if config.TTL <= 0 {
+		config.TTL = defaultTTL
+	}
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = defaultMaxEntries
+	}
+	if config.Store == nil {
+		config.Store = NewMemoryStore(config.MaxEntries)
+	}
+
+	return &CacheProvider{
+		provider: provider,
+		config:   config,
+	}
+}
+
+func (p *CacheProvider) Unwrap() chat.ChatProvider {
+	return p.provider
+}
+
+func (p *CacheProvider) Chat(ctx context.Context, req *chat.ChatRequest) (*chat.ChatResponse, error) {
+	if tenant.FromContext(ctx).DisableCache {
+		return p.bypass(p.provider.Chat(ctx, req))
+	}
+
+	key, err := p.key(req)
+	if err != nil {
+		slog.Warn("Failed to compute cache key", "error", err)
+		return p.bypass(p.provider.Chat(ctx, req))
+	}
+
+	if cached, ok := p.lookup(key); ok {
+		cached.Metadata = mergeMetadata(cached.Metadata, map[string]string{MetadataCache: StatusHit})
+		return cached, nil
+	}
+
+	resp, err := p.provider.Chat(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	p.store(key, resp)
+	reportStatus(resp, StatusMiss)
+	return resp, nil
+}
+
+func (p *CacheProvider) ChatStream(ctx context.Context, req *chat.ChatRequest, callback chat.StreamCallback) error {
+	if tenant.FromContext(ctx).DisableCache {
+		return p.provider.ChatStream(ctx, req, withMetadata(callback, StatusBypass))
+	}
+
+	key, err := p.key(req)
+	if err != nil {
+		slog.Warn("Failed to compute cache key", "error", err)
+		return p.provider.ChatStream(ctx, req, withMetadata(callback, StatusBypass))
+	}
+
+	if cached, ok := p.lookup(key); ok {
+		return replay(cached, callback)
+	}
+
+	// Collect the streamed answer so it can be stored once it completes
+	var collected chat.ChatResponse
+	stream := withMetadata(callback, StatusMiss)
+	err = p.provider.ChatStream(ctx, req, func(chunk *chat.ChatResponse) error {
+		accumulate(&collected, chunk)
+		return stream(chunk)
+	})
+	if err != nil {
+		return err
+	}
+
+	p.store(key, &collected)
+	return nil
+}
+
+func (p *CacheProvider) bypass(resp *chat.ChatResponse, err error) (*chat.ChatResponse, error) {
+	if err != nil {
+		return nil, err
+	}
+	reportStatus(resp, StatusBypass)
+	return resp, nil
+}
+
+// lookup decodes a cached response. Each hit gets a fresh copy, so callers
+// may modify it freely.
+func (p *CacheProvider) lookup(key string) (*chat.ChatResponse, bool) {
+	data, ok := p.config.Store.Get(key)
+	if !ok {
+		return nil, false
+	}
+
+	var resp chat.ChatResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		slog.Warn("Discarding unreadable cache entry", "key", key, "error", err)
+		return nil, false
+	}
+	return &resp, true
+}
+
+func (p *CacheProvider) store(key string, resp *chat.ChatResponse) {
+	data, err := encode(resp)
+	if err != nil {
+		slog.Warn("Failed to encode response for the cache", "error", err)
+		return
+	}
+	p.config.Store.Set(key, data, p.config.TTL)
+}
+
+// key scopes the request's fingerprint to the provider and model
+func (p *CacheProvider) key(req *chat.ChatRequest) (string, error) {
+	fingerprint, err := chat.Fingerprint(req)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(p.config.Provider + "\x00" + p.config.Model + "\x00" + fingerprint))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// encode serializes a response for storage, leaving out the status that
+// cache layers add on the way out
+func encode(resp *chat.ChatResponse) ([]byte, error) {
+	stored := *resp
+	stored.Metadata = nil
+	for key, value := range resp.Metadata {
+		if key == MetadataCache || key == MetadataSimilarity {
+			continue
+		}
+		stored.Metadata = mergeMetadata(stored.Metadata, map[string]string{key: value})
+	}
+	return json.Marshal(&stored)
+}
+
+// reportStatus sets the cache status of a response, unless a cache layer
+// closer to the provider already reported one. With both the exact and the
+// semantic cache enabled, a semantic hit then shows as a hit.
+func reportStatus(resp *chat.ChatResponse, status string) {
+	if resp.Metadata[MetadataCache] == "" {
+		resp.Metadata = mergeMetadata(resp.Metadata, map[string]string{MetadataCache: status})
+	}
+}
+
+// withMetadata reports the cache status on the first chunk of a stream
+func withMetadata(callback chat.StreamCallback, status string) chat.StreamCallback {
+	reported := false
+	return func(chunk *chat.ChatResponse) error {
+		if !reported {
+			reportStatus(chunk, status)
+			reported = true
+		}
+		return callback(chunk)
+	}
+}
+
+// accumulate folds a streamed chunk into the full response
+func accumulate(resp *chat.ChatResponse, chunk *chat.ChatResponse) {
+	resp.Content += chunk.Content
+	resp.Answers = append(resp.Answers, chunk.Answers...)
+	resp.ToolCalls = append(resp.ToolCalls, chunk.ToolCalls...)
+	if chunk.Usage != nil {
+		usage := *chunk.Usage
+		resp.Usage = &usage
+	}
+	if chunk.FinishReason != "" {
+		resp.FinishReason = chunk.FinishReason
+	}
+	resp.Metadata = mergeMetadata(resp.Metadata, chunk.Metadata)
+}
+
+// replay streams a cached response as a series of text chunks. Metadata
+// rides on the first chunk; answers, tool calls, usage and the finish
+// reason on the last, as providers send them.
+func replay(resp *chat.ChatResponse, callback chat.StreamCallback) error {
+	chunks := splitContent(resp.Content)
+	if len(chunks) == 0 {
+		chunks = []string{""}
+	}
+
+	for i, content := range chunks {
+		chunk := &chat.ChatResponse{Content: content}
+		if i == 0 {
+			chunk.Metadata = mergeMetadata(resp.Metadata, map[string]string{MetadataCache: StatusHit})
+		}
+		if i == len(chunks)-1 {
+			chunk.Answers = resp.Answers
+			chunk.ToolCalls = resp.ToolCalls
+			chunk.Usage = resp.Usage
+			chunk.FinishReason = resp.FinishReason
+		}
+		if err := callback(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitContent cuts text into chunks of about replayChunkBytes, breaking
+// only after whitespace so words are never split
+func splitContent(content string) []string {
+	var chunks []string
+	start := 0
+	for i, r := range content {
+		if i-start >= replayChunkBytes && (r == ' ' || r == '\n') {
+			chunks = append(chunks, content[start:i+1])
+			start = i + 1
+		}
+	}
+	if start < len(content) {
+		chunks = append(chunks, content[start:])
+	}
+	return chunks
+}
+
+func mergeMetadata(dst, src map[string]string) map[string]string {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string]string, len(src))
+	}
+	for key, value := range src {
+		dst[key] = value
+	}
+	return dst
+}
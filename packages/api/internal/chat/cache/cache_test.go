@@ -0,0 +1,222 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"chat-backend/internal/chat"
+	"chat-backend/internal/chat/mock"
+	"chat-backend/internal/tenant"
+)
+
+func newScriptedProvider(t *testing.T, scenario string) *mock.ScriptedChatProvider {
+	t.Helper()
+
+	parsed, err := mock.ParseScenario([]byte(scenario))
+	if err != nil {
+		t.Fatalf("Failed to parse scenario: %v", err)
+	}
+	provider, err := mock.NewScriptedChatProvider(parsed)
+	if err != nil {
+		t.Fatalf("Failed to build scripted provider: %v", err)
+	}
+	return provider
+}
+
+func question(content string) *chat.ChatRequest {
+	return &chat.ChatRequest{Messages: []chat.Message{{Role: "user", Content: content}}}
+}
+
+func TestCacheProvider_Chat(t *testing.T) {
+	inner := newScriptedProvider(t, `default: {response: "Open 9 to 5."}`)
+	provider := NewCacheProvider(inner, Config{Provider: "mock"})
+
+	first, err := provider.Chat(context.Background(), question("When are you open?"))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if first.Metadata[MetadataCache] != StatusMiss {
+		t.Errorf("Expected a miss, got %v", first.Metadata)
+	}
+
+	// Whitespace differences normalize to the same key
+	second, err := provider.Chat(context.Background(), question("  When are   you open? "))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if second.Metadata[MetadataCache] != StatusHit || second.Content != "Open 9 to 5." {
+		t.Errorf("Expected a cached answer, got %+v", second)
+	}
+
+	if got := len(inner.Requests()); got != 1 {
+		t.Errorf("Expected 1 upstream request, got %d", got)
+	}
+
+	provider.Chat(context.Background(), question("Where are you?"))
+	if got := len(inner.Requests()); got != 2 {
+		t.Errorf("Expected a different question to miss, got %d upstream requests", got)
+	}
+}
+
+func TestCacheProvider_KeyIncludesModel(t *testing.T) {
+	inner := newScriptedProvider(t, `default: {response: "ok"}`)
+	store := NewMemoryStore(10)
+
+	NewCacheProvider(inner, Config{Model: "mistral", Store: store}).Chat(context.Background(), question("Hi"))
+	NewCacheProvider(inner, Config{Model: "llama3", Store: store}).Chat(context.Background(), question("Hi"))
+
+	if got := len(inner.Requests()); got != 2 {
+		t.Errorf("Expected models not to share entries, got %d upstream requests", got)
+	}
+}
+
+func TestCacheProvider_DoesNotCacheErrors(t *testing.T) {
+	inner := newScriptedProvider(t, `
+rules:
+  - error: unavailable
+    times: 1
+default: {response: "ok"}
+`)
+	provider := NewCacheProvider(inner, Config{})
+
+	if _, err := provider.Chat(context.Background(), question("Hi")); err == nil {
+		t.Fatal("Expected the scripted error")
+	}
+
+	resp, err := provider.Chat(context.Background(), question("Hi"))
+	if err != nil || resp.Metadata[MetadataCache] != StatusMiss {
+		t.Errorf("Expected a miss after a failure, got %+v (%v)", resp, err)
+	}
+}
+
+func TestCacheProvider_ReplaysStream(t *testing.T) {
+	content := "Our support desk is open from nine to five on weekdays, and closed on public holidays."
+	inner := newScriptedProvider(t, `default: {chunks: ["Our support desk is open from nine to five on weekdays, ", "and closed on public holidays."]}`)
+	provider := NewCacheProvider(inner, Config{})
+
+	var missed []*chat.ChatResponse
+	err := provider.ChatStream(context.Background(), question("Hours?"), func(chunk *chat.ChatResponse) error {
+		missed = append(missed, chunk)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if missed[0].Metadata[MetadataCache] != StatusMiss {
+		t.Errorf("Expected a miss on the first chunk, got %v", missed[0].Metadata)
+	}
+
+	// A streamed answer also serves plain requests
+	resp, _ := provider.Chat(context.Background(), question("Hours?"))
+	if resp.Content != content || resp.Metadata[MetadataCache] != StatusHit {
+		t.Errorf("Expected the streamed answer to be cached, got %+v", resp)
+	}
+
+	var replayed []string
+	err = provider.ChatStream(context.Background(), question("Hours?"), func(chunk *chat.ChatResponse) error {
+		if len(replayed) == 0 && chunk.Metadata[MetadataCache] != StatusHit {
+			t.Errorf("Expected a hit on the first chunk, got %v", chunk.Metadata)
+		}
+		replayed = append(replayed, chunk.Content)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(replayed) < 2 {
+		t.Errorf("Expected the answer to be replayed in several chunks, got %q", replayed)
+	}
+	if got := strings.Join(replayed, ""); got != content {
+		t.Errorf("Expected replayed content '%s', got '%s'", content, got)
+	}
+	if got := len(inner.Requests()); got != 1 {
+		t.Errorf("Expected 1 upstream request, got %d", got)
+	}
+}
+
+func TestCacheProvider_TenantOptOut(t *testing.T) {
+	inner := newScriptedProvider(t, `default: {response: "ok"}`)
+	provider := NewCacheProvider(inner, Config{})
+	ctx := tenant.WithTenant(context.Background(), tenant.Tenant{ID: "acme", DisableCache: true})
+
+	for i := 0; i < 2; i++ {
+		resp, err := provider.Chat(ctx, question("Hi"))
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if resp.Metadata[MetadataCache] != StatusBypass {
+			t.Errorf("Expected the cache to be bypassed, got %v", resp.Metadata)
+		}
+	}
+
+	if got := len(inner.Requests()); got != 2 {
+		t.Errorf("Expected every request to go upstream, got %d", got)
+	}
+}
+
+func TestMemoryStore_EvictsLeastRecentlyUsed(t *testing.T) {
+	store := NewMemoryStore(2)
+	store.Set("a", []byte("1"), time.Minute)
+	store.Set("b", []byte("2"), time.Minute)
+	store.Get("a")
+	store.Set("c", []byte("3"), time.Minute)
+
+	if _, ok := store.Get("b"); ok {
+		t.Error("Expected the least recently used entry to be evicted")
+	}
+	if _, ok := store.Get("a"); !ok {
+		t.Error("Expected the recently used entry to be kept")
+	}
+}
+
+func TestMemoryStore_Expires(t *testing.T) {
+	store := NewMemoryStore(2)
+	store.Set("a", []byte("1"), -time.Second)
+
+	if _, ok := store.Get("a"); ok {
+		t.Error("Expected the expired entry to be dropped")
+	}
+	if store.Len() != 0 {
+		t.Errorf("Expected the expired entry to be removed, got %d entries", store.Len())
+	}
+}
+
+func TestDiskStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewDiskStore(dir, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	store.Set("expired", []byte("x"), -time.Second)
+	if _, ok := store.Get("expired"); ok {
+		t.Error("Expected the expired entry to be dropped")
+	}
+
+	store.Set("a", []byte("1"), time.Minute)
+	store.Set("b", []byte("2"), time.Minute)
+
+	// Entries survive reopening the store
+	reopened, err := NewDiskStore(dir, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if value, ok := reopened.Get("b"); !ok || string(value) != "2" {
+		t.Errorf("Expected the entry to be persisted, got '%s' (%v)", value, ok)
+	}
+
+	// Make "a" clearly the least recently used before adding a third entry
+	time.Sleep(10 * time.Millisecond)
+	reopened.Get("b")
+	reopened.Set("c", []byte("3"), time.Minute)
+
+	if _, ok := reopened.Get("a"); ok {
+		t.Error("Expected the least recently used entry to be evicted")
+	}
+	if _, ok := reopened.Get("c"); !ok {
+		t.Error("Expected the new entry to be kept")
+	}
+}
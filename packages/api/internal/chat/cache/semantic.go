@@ -0,0 +1,312 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"chat-backend/internal/chat"
+	"chat-backend/internal/tenant"
+)
+
+// MetadataSimilarity reports how close a semantic cache hit was to the
+// question it was cached for
+const MetadataSimilarity = "cache_similarity"
+
+const defaultThreshold = 0.9
+
+// Embedder turns text into a vector whose direction captures its meaning
+type Embedder interface {
+	Embed(ctx context.Context, text string) ([]float32, error)
+}
+
+// SemanticConfig controls the semantic cache. A cached answer is reused
+// when the cosine similarity between the new and the cached question is at
+// least Threshold.
+type SemanticConfig struct {
+	Embedder   Embedder
+	Provider   string
+	Model      string
+	Threshold  float64
+	TTL        time.Duration
+	MaxEntries int
+}
+
+// Stats reports how well a cache is doing
+type Stats struct {
+	Entries   int     `json:"entries"`
+	Hits      uint64  `json:"hits"`
+	Misses    uint64  `json:"misses"`
+	Evictions uint64  `json:"evictions"`
+	HitRate   float64 `json:"hit_rate"`
+}
+
+// SemanticCacheProvider answers questions that mean the same as one asked
+// before, such as "how do I reset my password" and "password reset?".
+//
+// Only the opening question of a conversation is cached, since follow-ups
+// depend on the turns before them. Entries are scoped by tenant and system
+// prompt, so the same question asked under a different prompt is a miss.
+type SemanticCacheProvider struct {
+	provider chat.ChatProvider
+	config   SemanticConfig
+
+	mu     sync.Mutex
+	order  *list.List
+	scopes map[string]map[*list.Element]struct{}
+	stats  Stats
+}
+
+type semanticEntry struct {
+	scope     string
+	vector    []float32
+	norm      float64
+	response  []byte
+	expiresAt time.Time
+}
+
+func NewSemanticCacheProvider(provider chat.ChatProvider, config SemanticConfig) *SemanticCacheProvider {
+	if config.Threshold <= 0 {
+		config.Threshold = defaultThreshold
+	}
+	if config.TTL <= 0 {
+		config.TTL = defaultTTL
+	}
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = defaultMaxEntries
+	}
+
+	return &SemanticCacheProvider{
+		provider: provider,
+		config:   config,
+		order:    list.New(),
+		scopes:   map[string]map[*list.Element]struct{}{},
+	}
+}
+
+func (p *SemanticCacheProvider) Unwrap() chat.ChatProvider {
+	return p.provider
+}
+
+// Stats returns the hit, miss and eviction counts since startup
+func (p *SemanticCacheProvider) Stats() Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := p.stats
+	stats.Entries = p.order.Len()
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(total)
+	}
+	return stats
+}
+
+func (p *SemanticCacheProvider) Chat(ctx context.Context, req *chat.ChatRequest) (*chat.ChatResponse, error) {
+	if tenant.FromContext(ctx).DisableCache {
+		return p.provider.Chat(ctx, req)
+	}
+
+	scope, vector, ok := p.embed(ctx, req)
+	if !ok {
+		return p.provider.Chat(ctx, req)
+	}
+
+	if cached, similarity, ok := p.lookup(scope, vector); ok {
+		cached.Metadata = mergeMetadata(cached.Metadata, hitMetadata(similarity))
+		return cached, nil
+	}
+
+	resp, err := p.provider.Chat(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	p.store(scope, vector, resp)
+	reportStatus(resp, StatusMiss)
+	return resp, nil
+}
+
+func (p *SemanticCacheProvider) ChatStream(ctx context.Context, req *chat.ChatRequest, callback chat.StreamCallback) error {
+	if tenant.FromContext(ctx).DisableCache {
+		return p.provider.ChatStream(ctx, req, callback)
+	}
+
+	scope, vector, ok := p.embed(ctx, req)
+	if !ok {
+		return p.provider.ChatStream(ctx, req, callback)
+	}
+
+	if cached, similarity, ok := p.lookup(scope, vector); ok {
+		cached.Metadata = mergeMetadata(cached.Metadata, hitMetadata(similarity))
+		return replay(cached, callback)
+	}
+
+	var collected chat.ChatResponse
+	stream := withMetadata(callback, StatusMiss)
+	err := p.provider.ChatStream(ctx, req, func(chunk *chat.ChatResponse) error {
+		accumulate(&collected, chunk)
+		return stream(chunk)
+	})
+	if err != nil {
+		return err
+	}
+
+	p.store(scope, vector, &collected)
+	return nil
+}
+
+// embed returns the scope and embedding of a cacheable request. Requests
+// that carry more than an opening question, attachments or tools are not
+// cacheable, and neither is anything when the embedder fails.
+func (p *SemanticCacheProvider) embed(ctx context.Context, req *chat.ChatRequest) (string, []float32, bool) {
+	if len(req.Tools) > 0 {
+		return "", nil, false
+	}
+
+	var system []string
+	var question *chat.Message
+	for i := range req.Messages {
+		msg := &req.Messages[i]
+		if msg.Role == "system" {
+			system = append(system, msg.Content)
+			continue
+		}
+		if question != nil || msg.Role != "user" || len(msg.Attachments) > 0 {
+			return "", nil, false
+		}
+		question = msg
+	}
+	if question == nil || strings.TrimSpace(question.Content) == "" {
+		return "", nil, false
+	}
+
+	vector, err := p.config.Embedder.Embed(ctx, question.Content)
+	if err != nil {
+		slog.Warn("Failed to embed question for the semantic cache", "error", err)
+		return "", nil, false
+	}
+
+	data, _ := json.Marshal([]string{
+		p.config.Provider,
+		p.config.Model,
+		tenant.FromContext(ctx).ID,
+		strings.Join(system, "\n"),
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), vector, true
+}
+
+// lookup finds the most similar cached question in scope. Expired entries
+// met along the way are dropped.
+func (p *SemanticCacheProvider) lookup(scope string, vector []float32) (*chat.ChatResponse, float64, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	norm := magnitude(vector)
+	now := time.Now()
+
+	var best *list.Element
+	bestSimilarity := 0.0
+	for elem := range p.scopes[scope] {
+		entry := elem.Value.(*semanticEntry)
+		if now.After(entry.expiresAt) {
+			p.remove(elem)
+			continue
+		}
+		similarity := cosine(vector, norm, entry.vector, entry.norm)
+		if similarity >= p.config.Threshold && similarity > bestSimilarity {
+			best, bestSimilarity = elem, similarity
+		}
+	}
+
+	if best == nil {
+		p.stats.Misses++
+		return nil, 0, false
+	}
+
+	var resp chat.ChatResponse
+	if err := json.Unmarshal(best.Value.(*semanticEntry).response, &resp); err != nil {
+		p.remove(best)
+		p.stats.Misses++
+		return nil, 0, false
+	}
+
+	p.order.MoveToFront(best)
+	p.stats.Hits++
+	return &resp, bestSimilarity, true
+}
+
+func (p *SemanticCacheProvider) store(scope string, vector []float32, resp *chat.ChatResponse) {
+	data, err := encode(resp)
+	if err != nil {
+		slog.Warn("Failed to encode response for the semantic cache", "error", err)
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	elem := p.order.PushFront(&semanticEntry{
+		scope:     scope,
+		vector:    vector,
+		norm:      magnitude(vector),
+		response:  data,
+		expiresAt: time.Now().Add(p.config.TTL),
+	})
+	if p.scopes[scope] == nil {
+		p.scopes[scope] = map[*list.Element]struct{}{}
+	}
+	p.scopes[scope][elem] = struct{}{}
+
+	for p.order.Len() > p.config.MaxEntries {
+		p.remove(p.order.Back())
+		p.stats.Evictions++
+	}
+}
+
+// remove drops an entry. Callers hold the lock.
+func (p *SemanticCacheProvider) remove(elem *list.Element) {
+	entry := elem.Value.(*semanticEntry)
+	p.order.Remove(elem)
+	delete(p.scopes[entry.scope], elem)
+	if len(p.scopes[entry.scope]) == 0 {
+		delete(p.scopes, entry.scope)
+	}
+}
+
+func hitMetadata(similarity float64) map[string]string {
+	return map[string]string{
+		MetadataCache:      StatusHit,
+		MetadataSimilarity: strconv.FormatFloat(similarity, 'f', 4, 64),
+	}
+}
+
+func magnitude(v []float32) float64 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	return math.Sqrt(sum)
+}
+
+// cosine returns the cosine similarity of two vectors given their
+// magnitudes. Vectors of different lengths come from different models and
+// never match.
+func cosine(a []float32, normA float64, b []float32, normB float64) float64 {
+	if len(a) != len(b) || normA == 0 || normB == 0 {
+		return 0
+	}
+	var dot float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+	}
+	return dot / (normA * normB)
+}
@@ -0,0 +1,163 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"chat-backend/internal/chat"
+	"chat-backend/internal/tenant"
+)
+
+// fakeEmbedder maps known questions to fixed vectors
+type fakeEmbedder map[string][]float32
+
+func (e fakeEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	if vector, ok := e[text]; ok {
+		return vector, nil
+	}
+	return nil, errors.New("unknown text")
+}
+
+var passwordQuestions = fakeEmbedder{
+	"how do I reset my password": {1, 0.1, 0},
+	"password reset?":            {0.95, 0.15, 0.05},
+	"what are your hours":        {0, 0.2, 1},
+}
+
+func withSystem(system, question string) *chat.ChatRequest {
+	return &chat.ChatRequest{Messages: []chat.Message{
+		{Role: "system", Content: system},
+		{Role: "user", Content: question},
+	}}
+}
+
+func TestSemanticCacheProvider_ParaphraseHits(t *testing.T) {
+	inner := newScriptedProvider(t, `default: {response: "Use the forgot password link."}`)
+	provider := NewSemanticCacheProvider(inner, SemanticConfig{Embedder: passwordQuestions, Threshold: 0.95})
+
+	first, err := provider.Chat(context.Background(), question("how do I reset my password"))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if first.Metadata[MetadataCache] != StatusMiss {
+		t.Errorf("Expected a miss, got %v", first.Metadata)
+	}
+
+	second, err := provider.Chat(context.Background(), question("password reset?"))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if second.Metadata[MetadataCache] != StatusHit || second.Content != "Use the forgot password link." {
+		t.Errorf("Expected the paraphrase to hit, got %+v", second)
+	}
+	if second.Metadata[MetadataSimilarity] == "" {
+		t.Error("Expected the similarity to be reported")
+	}
+
+	third, _ := provider.Chat(context.Background(), question("what are your hours"))
+	if third.Metadata[MetadataCache] != StatusMiss {
+		t.Errorf("Expected an unrelated question to miss, got %v", third.Metadata)
+	}
+
+	stats := provider.Stats()
+	if stats.Hits != 1 || stats.Misses != 2 || stats.Entries != 2 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	if stats.HitRate < 0.33 || stats.HitRate > 0.34 {
+		t.Errorf("Expected a hit rate of 1/3, got %f", stats.HitRate)
+	}
+}
+
+func TestSemanticCacheProvider_Scopes(t *testing.T) {
+	inner := newScriptedProvider(t, `default: {response: "ok"}`)
+	provider := NewSemanticCacheProvider(inner, SemanticConfig{Embedder: passwordQuestions})
+	acme := tenant.WithTenant(context.Background(), tenant.Tenant{ID: "acme"})
+	globex := tenant.WithTenant(context.Background(), tenant.Tenant{ID: "globex"})
+
+	provider.Chat(acme, withSystem("You are Acme support.", "how do I reset my password"))
+	provider.Chat(globex, withSystem("You are Acme support.", "how do I reset my password"))
+	provider.Chat(acme, withSystem("You are Acme sales.", "how do I reset my password"))
+
+	if got := len(inner.Requests()); got != 3 {
+		t.Errorf("Expected tenants and system prompts not to share entries, got %d upstream requests", got)
+	}
+
+	resp, _ := provider.Chat(acme, withSystem("You are Acme support.", "password reset?"))
+	if resp.Metadata[MetadataCache] != StatusHit {
+		t.Errorf("Expected a hit within the same scope, got %v", resp.Metadata)
+	}
+}
+
+func TestSemanticCacheProvider_SkipsFollowUps(t *testing.T) {
+	inner := newScriptedProvider(t, `default: {response: "ok"}`)
+	provider := NewSemanticCacheProvider(inner, SemanticConfig{Embedder: passwordQuestions})
+
+	followUp := &chat.ChatRequest{Messages: []chat.Message{
+		{Role: "user", Content: "hi"},
+		{Role: "assistant", Content: "hello"},
+		{Role: "user", Content: "how do I reset my password"},
+	}}
+	for i := 0; i < 2; i++ {
+		resp, err := provider.Chat(context.Background(), followUp)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if resp.Metadata[MetadataCache] != "" {
+			t.Errorf("Expected follow-ups to skip the cache, got %v", resp.Metadata)
+		}
+	}
+
+	// An embedder failure falls through to the provider
+	resp, err := provider.Chat(context.Background(), question("unknown question"))
+	if err != nil || resp.Content != "ok" {
+		t.Errorf("Expected the provider to answer, got %+v (%v)", resp, err)
+	}
+}
+
+func TestSemanticCacheProvider_Evicts(t *testing.T) {
+	inner := newScriptedProvider(t, `default: {response: "ok"}`)
+	provider := NewSemanticCacheProvider(inner, SemanticConfig{Embedder: passwordQuestions, MaxEntries: 1})
+
+	provider.Chat(context.Background(), question("how do I reset my password"))
+	provider.Chat(context.Background(), question("what are your hours"))
+
+	resp, _ := provider.Chat(context.Background(), question("password reset?"))
+	if resp.Metadata[MetadataCache] != StatusMiss {
+		t.Errorf("Expected the evicted answer to miss, got %v", resp.Metadata)
+	}
+	if stats := provider.Stats(); stats.Evictions != 2 || stats.Entries != 1 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestSemanticCacheProvider_UnderExactCache(t *testing.T) {
+	inner := newScriptedProvider(t, `default: {chunks: ["Use the forgot ", "password link."]}`)
+	semantic := NewSemanticCacheProvider(inner, SemanticConfig{Embedder: passwordQuestions})
+	provider := NewCacheProvider(semantic, Config{})
+
+	provider.Chat(context.Background(), question("how do I reset my password"))
+
+	var first *chat.ChatResponse
+	var content string
+	err := provider.ChatStream(context.Background(), question("password reset?"), func(chunk *chat.ChatResponse) error {
+		if first == nil {
+			first = chunk
+		}
+		content += chunk.Content
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if first.Metadata[MetadataCache] != StatusHit {
+		t.Errorf("Expected the semantic hit to be reported through the exact cache, got %v", first.Metadata)
+	}
+	if content != "Use the forgot password link." {
+		t.Errorf("Unexpected content '%s'", content)
+	}
+	if got := len(inner.Requests()); got != 1 {
+		t.Errorf("Expected 1 upstream request, got %d", got)
+	}
+}
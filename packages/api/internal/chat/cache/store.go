@@ -0,0 +1,229 @@
+package cache
+
+import (
+	"bytes"
+	"container/list"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Store holds encoded responses by key. Implementations drop entries once
+// their TTL has passed, and evict the least recently used entries to stay
+// within their size limit.
+type Store interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration)
+}
+
+// MemoryStore is an in-process LRU store
+type MemoryStore struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List
+	entries    map[string]*list.Element
+}
+
+type memoryEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewMemoryStore returns a store holding at most maxEntries entries, or a
+// default number when maxEntries is not positive
+func NewMemoryStore(maxEntries int) *MemoryStore {
+	if maxEntries <= 0 {
+		maxEntries = defaultMaxEntries
+	}
+	return &MemoryStore{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    map[string]*list.Element{},
+	}
+}
+
+func (s *MemoryStore) Get(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*memoryEntry)
+	if time.Now().After(entry.expiresAt) {
+		s.order.Remove(elem)
+		delete(s.entries, key)
+		return nil, false
+	}
+
+	s.order.MoveToFront(elem)
+	return entry.value, true
+}
+
+func (s *MemoryStore) Set(key string, value []byte, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := &memoryEntry{key: key, value: value, expiresAt: time.Now().Add(ttl)}
+	if elem, ok := s.entries[key]; ok {
+		elem.Value = entry
+		s.order.MoveToFront(elem)
+		return
+	}
+
+	s.entries[key] = s.order.PushFront(entry)
+	for s.order.Len() > s.maxEntries {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*memoryEntry).key)
+	}
+}
+
+// Len returns the number of entries, including any that have expired but
+// not been looked up since
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
+
+// DiskStore keeps one file per entry in a directory, so cached answers
+// survive restarts. A file's modification time records when it was last
+// used, which drives LRU eviction.
+type DiskStore struct {
+	mu         sync.Mutex
+	dir        string
+	maxEntries int
+	count      int
+}
+
+const diskSuffix = ".cache"
+
+// NewDiskStore opens or creates a store in dir. Like NewMemoryStore, a
+// maxEntries that is not positive means the default size.
+func NewDiskStore(dir string, maxEntries int) (*DiskStore, error) {
+	if maxEntries <= 0 {
+		maxEntries = defaultMaxEntries
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"+diskSuffix))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	return &DiskStore{dir: dir, maxEntries: maxEntries, count: len(files)}, nil
+}
+
+// Entries are stored as the expiry time in unix nanoseconds on the first
+// line, followed by the value
+func (s *DiskStore) Get(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := s.path(key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	header, value, ok := bytes.Cut(data, []byte("\n"))
+	expiresAt, err := strconv.ParseInt(string(header), 10, 64)
+	if !ok || err != nil || time.Now().UnixNano() > expiresAt {
+		s.remove(path)
+		return nil, false
+	}
+
+	now := time.Now()
+	os.Chtimes(path, now, now)
+	return value, true
+}
+
+func (s *DiskStore) Set(key string, value []byte, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := s.path(key)
+	_, statErr := os.Stat(path)
+
+	var data bytes.Buffer
+	data.WriteString(strconv.FormatInt(time.Now().Add(ttl).UnixNano(), 10))
+	data.WriteByte('\n')
+	data.Write(value)
+
+	// Write to a temporary file first so readers never see a torn entry
+	tmp, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data.Bytes())
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil || os.Rename(tmp.Name(), path) != nil {
+		return
+	}
+
+	if statErr != nil {
+		s.count++
+	}
+	if s.count > s.maxEntries {
+		s.evict()
+	}
+}
+
+// evict removes the least recently used entries until the store is within
+// its size limit. Callers hold the lock.
+func (s *DiskStore) evict() {
+	files, err := filepath.Glob(filepath.Join(s.dir, "*"+diskSuffix))
+	if err != nil {
+		return
+	}
+
+	type file struct {
+		path    string
+		modTime time.Time
+	}
+	entries := make([]file, 0, len(files))
+	for _, path := range files {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, file{path: path, modTime: info.ModTime()})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].modTime.Before(entries[j].modTime)
+	})
+
+	s.count = len(entries)
+	for _, entry := range entries {
+		if s.count <= s.maxEntries {
+			break
+		}
+		s.remove(entry.path)
+	}
+}
+
+func (s *DiskStore) remove(path string) {
+	if os.Remove(path) == nil {
+		s.count--
+	}
+}
+
+// Keys are hex digests, but guard against path separators anyway
+func (s *DiskStore) path(key string) string {
+	return filepath.Join(s.dir, strings.ReplaceAll(key, string(filepath.Separator), "_")+diskSuffix)
+}
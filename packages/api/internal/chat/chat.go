@@ -2,33 +2,83 @@ package chat
 
 import (
 	"context"
-	"errors"
-)
-
-var (
-	ErrProviderUnavailable = errors.New("chat provider unavailable")
-	ErrInvalidRequest      = errors.New("invalid request")
+	"encoding/json"
+	"strings"
 )
 
 type Message struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role        string       `json:"role"`
+	Content     string       `json:"content"`
+	ToolCalls   []ToolCall   `json:"tool_calls,omitempty"`
+	ToolCallID  string       `json:"tool_call_id,omitempty"`
+	Attachments []Attachment `json:"attachments,omitempty"`
+}
+
+// Attachment is a file sent alongside a message, such as an image for a
+// vision model. Data is base64 encoded when marshalled to JSON.
+type Attachment struct {
+	Filename string `json:"filename,omitempty"`
+	MimeType string `json:"mime_type"`
+	Data     []byte `json:"data"`
+}
+
+func (a Attachment) IsImage() bool {
+	return strings.HasPrefix(a.MimeType, "image/")
 }
 
 type ChatRequest struct {
 	Messages  []Message `json:"messages"`
 	Streaming bool      `json:"streaming,omitempty"`
+	Tools     []Tool    `json:"tools,omitempty"`
 }
 
 type ChatResponse struct {
-	Content string `json:"content"`
-	Usage   *Usage `json:"usage,omitempty"`
+	Content      string     `json:"content"`
+	Usage        *Usage     `json:"usage,omitempty"`
+	Answers      []Answer   `json:"answers,omitempty"`
+	ToolCalls    []ToolCall `json:"tool_calls,omitempty"`
+	FinishReason string     `json:"finish_reason,omitempty"`
+	// Metadata carries notes from the layers between the handler and the
+	// provider, such as how the conversation was trimmed to fit the context
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// FinishReasonNoAnswer marks a fallback answer given when the provider had
+// none, such as a knowledge base without a matching question
+const FinishReasonNoAnswer = "no_answer"
+
+// Tool is a function the model may ask the caller to invoke. Parameters is
+// a JSON schema describing the arguments.
+type Tool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
 }
 
+// ToolCall is a request from the model to invoke a tool. Arguments holds
+// the raw JSON arguments produced by the model.
+type ToolCall struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// Answer is one candidate answer from providers that rank several,
+// such as a question answering knowledge base
+type Answer struct {
+	Content  string            `json:"content"`
+	Score    float64           `json:"score"`
+	Source   string            `json:"source,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Usage is the token count of a generation. Estimated is set when the
+// provider did not report it, such as for a generation cancelled part way.
 type Usage struct {
-	PromptTokens     int `json:"prompt_tokens"`
-	CompletionTokens int `json:"completion_tokens"`
-	TotalTokens      int `json:"total_tokens"`
+	PromptTokens     int  `json:"prompt_tokens"`
+	CompletionTokens int  `json:"completion_tokens"`
+	TotalTokens      int  `json:"total_tokens"`
+	Estimated        bool `json:"estimated,omitempty"`
 }
 
 type StreamCallback func(chunk *ChatResponse) error
@@ -36,4 +86,32 @@ type StreamCallback func(chunk *ChatResponse) error
 type ChatProvider interface {
 	Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error)
 	ChatStream(ctx context.Context, req *ChatRequest, callback StreamCallback) error
-}
\ No newline at end of file
+}
+
+// Unwrap returns the provider at the bottom of a chain of decorators, such
+// as the context window layer, that wrap another provider
+func Unwrap(provider ChatProvider) ChatProvider {
+	for {
+		wrapper, ok := provider.(interface{ Unwrap() ChatProvider })
+		if !ok {
+			return provider
+		}
+		provider = wrapper.Unwrap()
+	}
+}
+
+// Layer returns the first provider of type T in a chain of decorators,
+// starting with provider itself
+func Layer[T ChatProvider](provider ChatProvider) (T, bool) {
+	for {
+		if layer, ok := provider.(T); ok {
+			return layer, true
+		}
+		wrapper, ok := provider.(interface{ Unwrap() ChatProvider })
+		if !ok {
+			var zero T
+			return zero, false
+		}
+		provider = wrapper.Unwrap()
+	}
+}
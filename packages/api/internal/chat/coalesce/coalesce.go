@@ -0,0 +1,233 @@
+package coalesce
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"chat-backend/internal/chat"
+	"chat-backend/internal/tenant"
+)
+
+// MetadataCoalesced is set on responses that were shared from another
+// request's upstream call rather than fetched for this request
+const MetadataCoalesced = "coalesced"
+
+// CoalescingProvider lets identical requests that are in flight at the
+// same time share one upstream call. Requests are identical when they come
+// from the same tenant and have the same chat.Fingerprint.
+//
+// The shared call outlives whichever request started it, and is only
+// cancelled once every request waiting on it has gone away.
+type CoalescingProvider struct {
+	provider chat.ChatProvider
+
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+// call is one upstream call and everything it has produced so far. Chunks
+// are kept until the call completes so late joiners can catch up.
+type call struct {
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	waiters  int
+	chunks   []*chat.ChatResponse
+	response *chat.ChatResponse
+	err      error
+	done     bool
+	// changed is closed and replaced whenever the call makes progress
+	changed chan struct{}
+}
+
+func NewCoalescingProvider(provider chat.ChatProvider) *CoalescingProvider {
+	return &CoalescingProvider{
+		provider: provider,
+		calls:    map[string]*call{},
+	}
+}
+
+func (p *CoalescingProvider) Unwrap() chat.ChatProvider {
+	return p.provider
+}
+
+func (p *CoalescingProvider) Chat(ctx context.Context, req *chat.ChatRequest) (*chat.ChatResponse, error) {
+	key, err := p.key(ctx, "chat", req)
+	if err != nil {
+		return p.provider.Chat(ctx, req)
+	}
+
+	c, shared := p.join(ctx, key, func(ctx context.Context, c *call) {
+		resp, err := p.provider.Chat(ctx, req)
+		c.finish(resp, err)
+	})
+	defer p.leave(key, c)
+
+	for {
+		c.mu.Lock()
+		done, resp, err, changed := c.done, c.response, c.err, c.changed
+		c.mu.Unlock()
+
+		if done {
+			if err != nil {
+				return nil, err
+			}
+			return clone(resp, shared), nil
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (p *CoalescingProvider) ChatStream(ctx context.Context, req *chat.ChatRequest, callback chat.StreamCallback) error {
+	key, err := p.key(ctx, "stream", req)
+	if err != nil {
+		return p.provider.ChatStream(ctx, req, callback)
+	}
+
+	c, shared := p.join(ctx, key, func(ctx context.Context, c *call) {
+		err := p.provider.ChatStream(ctx, req, func(chunk *chat.ChatResponse) error {
+			c.publish(chunk)
+			return nil
+		})
+		c.finish(nil, err)
+	})
+	defer p.leave(key, c)
+
+	// Every subscriber reads the call's chunks from the start, so late
+	// joiners get the ones they missed replayed before the live ones
+	next := 0
+	for {
+		c.mu.Lock()
+		chunks, done, err, changed := c.chunks[next:], c.done, c.err, c.changed
+		c.mu.Unlock()
+
+		for _, chunk := range chunks {
+			if err := callback(clone(chunk, shared && next == 0)); err != nil {
+				return err
+			}
+			next++
+		}
+
+		if done {
+			return err
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// key identifies identical requests. Streamed and plain requests are kept
+// apart since they produce different kinds of results.
+func (p *CoalescingProvider) key(ctx context.Context, kind string, req *chat.ChatRequest) (string, error) {
+	fingerprint, err := chat.Fingerprint(req)
+	if err != nil {
+		slog.Warn("Failed to fingerprint request for coalescing", "error", err)
+		return "", err
+	}
+	return kind + "/" + tenant.FromContext(ctx).ID + "/" + fingerprint, nil
+}
+
+// join returns the in-flight call for key, starting it with run when there
+// is none. shared reports whether the call was started by another request.
+func (p *CoalescingProvider) join(ctx context.Context, key string, run func(context.Context, *call)) (c *call, shared bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if c, ok := p.calls[key]; ok {
+		c.mu.Lock()
+		c.waiters++
+		c.mu.Unlock()
+		return c, true
+	}
+
+	// The upstream call keeps the request's values, such as its tenant,
+	// but not its cancellation
+	upstream, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	c = &call{
+		cancel:  cancel,
+		waiters: 1,
+		changed: make(chan struct{}),
+	}
+	p.calls[key] = c
+
+	go func() {
+		run(upstream, c)
+
+		p.mu.Lock()
+		if p.calls[key] == c {
+			delete(p.calls, key)
+		}
+		p.mu.Unlock()
+		cancel()
+	}()
+
+	return c, false
+}
+
+// publish records a streamed chunk and wakes every subscriber
+func (c *call) publish(chunk *chat.ChatResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.chunks = append(c.chunks, chunk)
+	c.notify()
+}
+
+func (c *call) finish(resp *chat.ChatResponse, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.response, c.err, c.done = resp, err, true
+	c.notify()
+}
+
+// notify wakes everyone waiting on the call. Callers hold the lock.
+func (c *call) notify() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// leave drops a waiter. When nobody is left to receive the result, the
+// upstream call is cancelled and forgotten, so the next identical request
+// starts afresh instead of joining a cancelled call.
+func (p *CoalescingProvider) leave(key string, c *call) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.waiters--
+	if c.waiters == 0 && !c.done {
+		c.cancel()
+		if p.calls[key] == c {
+			delete(p.calls, key)
+		}
+	}
+}
+
+// clone copies a shared response so each request can add its own metadata
+// without affecting the others
+func clone(resp *chat.ChatResponse, coalesced bool) *chat.ChatResponse {
+	copied := *resp
+	copied.Metadata = nil
+	if len(resp.Metadata) > 0 || coalesced {
+		copied.Metadata = make(map[string]string, len(resp.Metadata)+1)
+	}
+	for key, value := range resp.Metadata {
+		copied.Metadata[key] = value
+	}
+	if coalesced {
+		copied.Metadata[MetadataCoalesced] = "true"
+	}
+	return &copied
+}
@@ -0,0 +1,253 @@
+package coalesce
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"chat-backend/internal/chat"
+	"chat-backend/internal/tenant"
+)
+
+// gatedProvider sends its first chunk straight away, then holds the rest of
+// the answer until release is closed
+type gatedProvider struct {
+	calls     atomic.Int32
+	release   chan struct{}
+	cancelled chan struct{}
+}
+
+func newGatedProvider() *gatedProvider {
+	return &gatedProvider{release: make(chan struct{}), cancelled: make(chan struct{}, 1)}
+}
+
+func (p *gatedProvider) wait(ctx context.Context) error {
+	select {
+	case <-p.release:
+		return nil
+	case <-ctx.Done():
+		p.cancelled <- struct{}{}
+		return ctx.Err()
+	}
+}
+
+func (p *gatedProvider) Chat(ctx context.Context, req *chat.ChatRequest) (*chat.ChatResponse, error) {
+	p.calls.Add(1)
+	if err := p.wait(ctx); err != nil {
+		return nil, err
+	}
+	return &chat.ChatResponse{Content: "shared answer"}, nil
+}
+
+func (p *gatedProvider) ChatStream(ctx context.Context, req *chat.ChatRequest, callback chat.StreamCallback) error {
+	p.calls.Add(1)
+	if err := callback(&chat.ChatResponse{Content: "shared "}); err != nil {
+		return err
+	}
+	if err := p.wait(ctx); err != nil {
+		return err
+	}
+	return callback(&chat.ChatResponse{Content: "answer"})
+}
+
+func question() *chat.ChatRequest {
+	return &chat.ChatRequest{Messages: []chat.Message{{Role: "user", Content: "What is new?"}}}
+}
+
+// waitForWaiters blocks until n requests are waiting on in-flight calls
+func waitForWaiters(t *testing.T, p *CoalescingProvider, n int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		p.mu.Lock()
+		waiters := 0
+		for _, c := range p.calls {
+			c.mu.Lock()
+			waiters += c.waiters
+			c.mu.Unlock()
+		}
+		p.mu.Unlock()
+
+		if waiters == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("Timed out waiting for %d waiters", n)
+}
+
+func TestCoalescingProvider_Chat(t *testing.T) {
+	inner := newGatedProvider()
+	provider := NewCoalescingProvider(inner)
+
+	const requests = 5
+	responses := make([]*chat.ChatResponse, requests)
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp, err := provider.Chat(context.Background(), question())
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			responses[i] = resp
+		}(i)
+	}
+
+	waitForWaiters(t, provider, requests)
+	close(inner.release)
+	wg.Wait()
+
+	if got := inner.calls.Load(); got != 1 {
+		t.Errorf("Expected 1 upstream call, got %d", got)
+	}
+
+	coalesced := 0
+	for _, resp := range responses {
+		if resp == nil || resp.Content != "shared answer" {
+			t.Fatalf("Expected every request to get the answer, got %+v", resp)
+		}
+		if resp.Metadata[MetadataCoalesced] == "true" {
+			coalesced++
+		}
+	}
+	if coalesced != requests-1 {
+		t.Errorf("Expected %d coalesced responses, got %d", requests-1, coalesced)
+	}
+
+	// Once complete, the next request starts a new call
+	inner.release = make(chan struct{})
+	close(inner.release)
+	provider.Chat(context.Background(), question())
+	if got := inner.calls.Load(); got != 2 {
+		t.Errorf("Expected a new upstream call after completion, got %d calls", got)
+	}
+}
+
+func TestCoalescingProvider_KeepsTenantsApart(t *testing.T) {
+	inner := newGatedProvider()
+	close(inner.release)
+	provider := NewCoalescingProvider(inner)
+
+	var wg sync.WaitGroup
+	for _, id := range []string{"acme", "globex"} {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			provider.Chat(tenant.WithTenant(context.Background(), tenant.Tenant{ID: id}), question())
+		}(id)
+	}
+	wg.Wait()
+
+	if got := inner.calls.Load(); got != 2 {
+		t.Errorf("Expected one upstream call per tenant, got %d", got)
+	}
+}
+
+func TestCoalescingProvider_StreamLateJoiner(t *testing.T) {
+	inner := newGatedProvider()
+	provider := NewCoalescingProvider(inner)
+
+	first := make(chan struct{})
+	var early, late strings.Builder
+	var lateFirst *chat.ChatResponse
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		provider.ChatStream(context.Background(), question(), func(chunk *chat.ChatResponse) error {
+			if early.Len() == 0 {
+				close(first)
+			}
+			early.WriteString(chunk.Content)
+			return nil
+		})
+	}()
+
+	// Join after the first chunk has already gone out
+	<-first
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		provider.ChatStream(context.Background(), question(), func(chunk *chat.ChatResponse) error {
+			if lateFirst == nil {
+				lateFirst = chunk
+			}
+			late.WriteString(chunk.Content)
+			return nil
+		})
+	}()
+
+	waitForWaiters(t, provider, 2)
+	close(inner.release)
+	wg.Wait()
+
+	if early.String() != "shared answer" || late.String() != "shared answer" {
+		t.Errorf("Expected both subscribers to get the whole answer, got '%s' and '%s'", early.String(), late.String())
+	}
+	if lateFirst.Metadata[MetadataCoalesced] != "true" {
+		t.Errorf("Expected the late joiner to be marked coalesced, got %v", lateFirst.Metadata)
+	}
+	if got := inner.calls.Load(); got != 1 {
+		t.Errorf("Expected 1 upstream call, got %d", got)
+	}
+}
+
+func TestCoalescingProvider_LeaderCancelled(t *testing.T) {
+	inner := newGatedProvider()
+	provider := NewCoalescingProvider(inner)
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := provider.Chat(leaderCtx, question())
+		leaderErr <- err
+	}()
+	waitForWaiters(t, provider, 1)
+
+	followerResp := make(chan *chat.ChatResponse, 1)
+	go func() {
+		resp, _ := provider.Chat(context.Background(), question())
+		followerResp <- resp
+	}()
+	waitForWaiters(t, provider, 2)
+
+	cancelLeader()
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the leader to see its cancellation, got: %v", err)
+	}
+
+	close(inner.release)
+	if resp := <-followerResp; resp == nil || resp.Content != "shared answer" {
+		t.Errorf("Expected the follower to still get the answer, got %+v", resp)
+	}
+}
+
+func TestCoalescingProvider_CancelsWhenEveryoneLeaves(t *testing.T) {
+	inner := newGatedProvider()
+	provider := NewCoalescingProvider(inner)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		provider.Chat(ctx, question())
+		close(done)
+	}()
+	waitForWaiters(t, provider, 1)
+
+	cancel()
+	<-done
+
+	select {
+	case <-inner.cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the upstream call to be cancelled")
+	}
+}
@@ -0,0 +1,374 @@
+package contextwindow
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"chat-backend/internal/chat"
+)
+
+type Strategy string
+
+const (
+	// StrategyNone forwards conversations untouched
+	StrategyNone Strategy = "none"
+	// StrategyKeepRecent keeps system messages plus the most recent turns
+	// that fit the context window
+	StrategyKeepRecent Strategy = "keep-recent"
+	// StrategySlidingWindow keeps system messages plus at most a fixed
+	// number of recent messages, then trims further if they do not fit
+	StrategySlidingWindow Strategy = "sliding-window"
+	// StrategySummarize replaces the turns that do not fit with a summary
+	// written by the provider itself
+	StrategySummarize Strategy = "summarize"
+)
+
+// Keys of the response metadata reporting what was done to the conversation
+const (
+	MetadataStrategy        = "context_strategy"
+	MetadataDroppedMessages = "context_dropped_messages"
+	MetadataEstimatedTokens = "context_estimated_tokens"
+)
+
+const (
+	defaultReserveTokens  = 512
+	defaultWindowMessages = 20
+
+	summaryPrompt = "Summarize the following conversation in a few sentences. " +
+		"Keep names, facts, decisions and open questions needed to continue it. " +
+		"Reply with the summary only."
+)
+
+func ParseStrategy(s string) (Strategy, error) {
+	switch strategy := Strategy(s); strategy {
+	case StrategyNone, StrategyKeepRecent, StrategySlidingWindow, StrategySummarize:
+		return strategy, nil
+	}
+	return "", fmt.Errorf("unknown context strategy %q", s)
+}
+
+// Config controls how conversations are fitted into the model's context.
+// MaxTokens defaults to the known limit of Model, and ReserveTokens is left
+// free for the completion.
+type Config struct {
+	Strategy       Strategy
+	Model          string
+	MaxTokens      int
+	ReserveTokens  int
+	WindowMessages int
+}
+
+// ContextWindowProvider trims conversations that would overflow the context
+// window before passing them to the wrapped provider
+type ContextWindowProvider struct {
+	provider  chat.ChatProvider
+	config    Config
+	summaries summaryCache
+}
+
+func NewContextWindowProvider(provider chat.ChatProvider, config Config) *ContextWindowProvider {
+	if config.Strategy == "" {
+		config.Strategy = StrategyKeepRecent
+	}
+	if config.MaxTokens <= 0 {
+		config.MaxTokens = LimitForModel(config.Model)
+	}
+	if config.ReserveTokens <= 0 {
+		config.ReserveTokens = defaultReserveTokens
+	}
+	if config.WindowMessages <= 0 {
+		config.WindowMessages = defaultWindowMessages
+	}
+
+	return &ContextWindowProvider{
+		provider: provider,
+		config:   config,
+	}
+}
+
+func (p *ContextWindowProvider) Unwrap() chat.ChatProvider {
+	return p.provider
+}
+
+func (p *ContextWindowProvider) Chat(ctx context.Context, req *chat.ChatRequest) (*chat.ChatResponse, error) {
+	fitted, metadata, err := p.fit(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.provider.Chat(ctx, fitted)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Metadata = mergeMetadata(resp.Metadata, metadata)
+	return resp, nil
+}
+
+func (p *ContextWindowProvider) ChatStream(ctx context.Context, req *chat.ChatRequest, callback chat.StreamCallback) error {
+	fitted, metadata, err := p.fit(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	// Report the strategy once, on the first chunk
+	reported := metadata == nil
+	return p.provider.ChatStream(ctx, fitted, func(chunk *chat.ChatResponse) error {
+		if !reported {
+			chunk.Metadata = mergeMetadata(chunk.Metadata, metadata)
+			reported = true
+		}
+		return callback(chunk)
+	})
+}
+
+func (p *ContextWindowProvider) budget() int {
+	return max(p.config.MaxTokens-p.config.ReserveTokens, 0)
+}
+
+// fit returns the request to send upstream and, when the conversation had
+// to be changed, metadata describing how
+func (p *ContextWindowProvider) fit(ctx context.Context, req *chat.ChatRequest) (*chat.ChatRequest, map[string]string, error) {
+	budget := p.budget()
+	messages := req.Messages
+
+	if p.config.Strategy == StrategyNone {
+		return req, nil, nil
+	}
+
+	tooLong := p.config.Strategy == StrategySlidingWindow && countConversation(messages) > p.config.WindowMessages
+	if !tooLong && EstimateTotal(messages) <= budget {
+		return req, nil, nil
+	}
+
+	var (
+		kept    []chat.Message
+		dropped int
+		err     error
+	)
+	applied := p.config.Strategy
+
+	switch p.config.Strategy {
+	case StrategyKeepRecent:
+		kept, dropped, err = keepRecent(messages, budget)
+	case StrategySlidingWindow:
+		kept, dropped, err = slidingWindow(messages, p.config.WindowMessages, budget)
+	case StrategySummarize:
+		kept, dropped, err = p.summarize(ctx, messages, budget)
+		if err != nil && ctx.Err() == nil {
+			slog.Warn("Failed to summarize conversation, dropping older turns instead", "error", err)
+			applied = StrategyKeepRecent
+			kept, dropped, err = keepRecent(messages, budget)
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fitted := *req
+	fitted.Messages = kept
+
+	metadata := map[string]string{
+		MetadataStrategy:        string(applied),
+		MetadataDroppedMessages: strconv.Itoa(dropped),
+		MetadataEstimatedTokens: strconv.Itoa(EstimateTotal(kept)),
+	}
+	slog.Info("Fitted conversation into context window", "strategy", applied, "dropped", dropped, "budget", budget)
+
+	return &fitted, metadata, nil
+}
+
+// splitSystem separates system messages, which are always kept, from the
+// rest of the conversation
+func splitSystem(messages []chat.Message) (system, rest []chat.Message) {
+	for _, msg := range messages {
+		if msg.Role == "system" {
+			system = append(system, msg)
+		} else {
+			rest = append(rest, msg)
+		}
+	}
+	return system, rest
+}
+
+func countConversation(messages []chat.Message) int {
+	_, rest := splitSystem(messages)
+	return len(rest)
+}
+
+// recentStart returns the index of the oldest message in rest that can be
+// kept within budget. It always lands on a user message, since an assistant
+// reply or tool result without what it answers confuses models.
+func recentStart(rest []chat.Message, budget int) (int, error) {
+	start := len(rest)
+	used := 0
+	for i := len(rest) - 1; i >= 0; i-- {
+		used += EstimateTokens(rest[i])
+		if used > budget {
+			break
+		}
+		start = i
+	}
+
+	for start < len(rest) && rest[start].Role != "user" {
+		start++
+	}
+
+	if start == len(rest) {
+		return 0, chat.Errorf(chat.ErrContextLength, "latest turn does not fit in the %d token context window", budget)
+	}
+	return start, nil
+}
+
+func keepRecent(messages []chat.Message, budget int) ([]chat.Message, int, error) {
+	system, rest := splitSystem(messages)
+
+	start, err := recentStart(rest, budget-EstimateTotal(system))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return append(system, rest[start:]...), start, nil
+}
+
+func slidingWindow(messages []chat.Message, window, budget int) ([]chat.Message, int, error) {
+	system, rest := splitSystem(messages)
+
+	skipped := 0
+	if len(rest) > window {
+		skipped = len(rest) - window
+	}
+
+	kept, dropped, err := keepRecent(append(system, rest[skipped:]...), budget)
+	if err != nil {
+		return nil, 0, err
+	}
+	return kept, skipped + dropped, nil
+}
+
+func (p *ContextWindowProvider) summarize(ctx context.Context, messages []chat.Message, budget int) ([]chat.Message, int, error) {
+	system, rest := splitSystem(messages)
+
+	// Leave a quarter of the budget for the summary itself
+	start, err := recentStart(rest, budget*3/4-EstimateTotal(system))
+	if err != nil {
+		return nil, 0, err
+	}
+	if start == 0 {
+		return keepRecent(messages, budget)
+	}
+
+	summary, err := p.summary(ctx, rest[:start])
+	if err != nil {
+		return nil, 0, err
+	}
+
+	system = append(system, chat.Message{
+		Role:    "system",
+		Content: "Summary of the earlier conversation: " + summary,
+	})
+
+	// Recent turns are kept as long as the summary turned out short enough
+	kept, dropped, err := keepRecent(append(system, rest[start:]...), budget)
+	if err != nil {
+		return nil, 0, err
+	}
+	return kept, start + dropped, nil
+}
+
+// summary asks the wrapped provider to summarize older turns. Turns that
+// do not fit in one request are summarized a part at a time, each request
+// carrying the summary so far. Summaries are remembered since the same
+// prefix is sent again with every new turn.
+func (p *ContextWindowProvider) summary(ctx context.Context, older []chat.Message) (string, error) {
+	key, err := summaryKey(older)
+	if err != nil {
+		return "", err
+	}
+
+	if summary, ok := p.summaries.get(key); ok {
+		return summary, nil
+	}
+
+	summary := ""
+	for len(older) > 0 {
+		prefix := ""
+		if summary != "" {
+			prefix = "Summary so far: " + summary + "\n\n"
+		}
+		room := p.budget() - EstimateTotal([]chat.Message{{Content: summaryPrompt}, {Content: prefix}})
+		if room <= 0 {
+			return "", fmt.Errorf("summary leaves no room in the %d token context window", p.budget())
+		}
+
+		part, n := transcript(older, room)
+		older = older[n:]
+
+		resp, err := p.provider.Chat(ctx, &chat.ChatRequest{
+			Messages: []chat.Message{
+				{Role: "system", Content: summaryPrompt},
+				{Role: "user", Content: prefix + part},
+			},
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to summarize conversation: %w", err)
+		}
+
+		summary = strings.TrimSpace(resp.Content)
+		if summary == "" {
+			return "", fmt.Errorf("provider returned an empty summary")
+		}
+	}
+
+	p.summaries.put(key, summary)
+	return summary, nil
+}
+
+// transcript writes the leading messages that fit in room tokens as
+// "role: content" lines, and returns how many it took. A first message too
+// long on its own is cut short rather than left out.
+func transcript(messages []chat.Message, room int) (string, int) {
+	var b strings.Builder
+	n := 0
+	for _, msg := range messages {
+		line := fmt.Sprintf("%s: %s\n", msg.Role, msg.Content)
+		if (b.Len()+len(line)+charsPerToken-1)/charsPerToken > room {
+			if n == 0 {
+				b.WriteString(strings.ToValidUTF8(line[:room*charsPerToken-1], "") + "\n")
+				n = 1
+			}
+			break
+		}
+		b.WriteString(line)
+		n++
+	}
+	return b.String(), n
+}
+
+func summaryKey(messages []chat.Message) (string, error) {
+	data, err := json.Marshal(messages)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func mergeMetadata(dst, src map[string]string) map[string]string {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string]string, len(src))
+	}
+	for key, value := range src {
+		dst[key] = value
+	}
+	return dst
+}
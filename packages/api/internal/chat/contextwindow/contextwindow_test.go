@@ -0,0 +1,272 @@
+package contextwindow
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"chat-backend/internal/chat"
+	"chat-backend/internal/chat/mock"
+)
+
+func newScriptedProvider(t *testing.T, scenario string) *mock.ScriptedChatProvider {
+	t.Helper()
+
+	parsed, err := mock.ParseScenario([]byte(scenario))
+	if err != nil {
+		t.Fatalf("Failed to parse scenario: %v", err)
+	}
+	provider, err := mock.NewScriptedChatProvider(parsed)
+	if err != nil {
+		t.Fatalf("Failed to build scripted provider: %v", err)
+	}
+	return provider
+}
+
+// turn is a message of roughly 100 tokens
+func turn(role string, label string) chat.Message {
+	return chat.Message{Role: role, Content: label + " " + strings.Repeat("word ", 80)}
+}
+
+func longConversation() []chat.Message {
+	return []chat.Message{
+		{Role: "system", Content: "You are a helpful assistant."},
+		turn("user", "first question"),
+		turn("assistant", "first answer"),
+		turn("user", "second question"),
+		turn("assistant", "second answer"),
+		turn("user", "third question"),
+	}
+}
+
+func TestContextWindowProvider_PassesShortConversationsThrough(t *testing.T) {
+	inner := newScriptedProvider(t, `default: {response: "ok"}`)
+	provider := NewContextWindowProvider(inner, Config{MaxTokens: 4096})
+
+	resp, err := provider.Chat(context.Background(), &chat.ChatRequest{Messages: longConversation()})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if resp.Metadata != nil {
+		t.Errorf("Expected no metadata when nothing was trimmed, got %v", resp.Metadata)
+	}
+
+	if got := len(inner.Requests()[0].Messages); got != 6 {
+		t.Errorf("Expected all 6 messages forwarded, got %d", got)
+	}
+}
+
+func TestContextWindowProvider_KeepRecent(t *testing.T) {
+	inner := newScriptedProvider(t, `default: {response: "ok"}`)
+	provider := NewContextWindowProvider(inner, Config{
+		Strategy:      StrategyKeepRecent,
+		MaxTokens:     450,
+		ReserveTokens: 100,
+	})
+
+	resp, err := provider.Chat(context.Background(), &chat.ChatRequest{Messages: longConversation()})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	forwarded := inner.Requests()[0].Messages
+	if len(forwarded) != 4 {
+		t.Fatalf("Expected system message plus the last turns, got %d messages", len(forwarded))
+	}
+	if forwarded[0].Role != "system" || !strings.HasPrefix(forwarded[1].Content, "second question") {
+		t.Errorf("Expected system message followed by the second question, got %+v", forwarded[:2])
+	}
+
+	if resp.Metadata[MetadataStrategy] != "keep-recent" || resp.Metadata[MetadataDroppedMessages] != "2" {
+		t.Errorf("Unexpected metadata: %v", resp.Metadata)
+	}
+}
+
+func TestContextWindowProvider_SlidingWindow(t *testing.T) {
+	inner := newScriptedProvider(t, `default: {response: "ok"}`)
+	provider := NewContextWindowProvider(inner, Config{
+		Strategy:       StrategySlidingWindow,
+		MaxTokens:      100000,
+		WindowMessages: 2,
+	})
+
+	resp, err := provider.Chat(context.Background(), &chat.ChatRequest{Messages: longConversation()})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// The window starts at an assistant message, so it advances to the
+	// next user message
+	forwarded := inner.Requests()[0].Messages
+	if len(forwarded) != 2 || !strings.HasPrefix(forwarded[1].Content, "third question") {
+		t.Errorf("Expected system message and the last question, got %+v", forwarded)
+	}
+
+	if resp.Metadata[MetadataStrategy] != "sliding-window" || resp.Metadata[MetadataDroppedMessages] != "4" {
+		t.Errorf("Unexpected metadata: %v", resp.Metadata)
+	}
+}
+
+func TestContextWindowProvider_Summarize(t *testing.T) {
+	inner := newScriptedProvider(t, `
+rules:
+  - name: summary
+    match: "first question"
+    response: "The user asked a first question."
+default:
+  response: "ok"
+`)
+	provider := NewContextWindowProvider(inner, Config{
+		Strategy:      StrategySummarize,
+		MaxTokens:     500,
+		ReserveTokens: 100,
+	})
+
+	req := &chat.ChatRequest{Messages: longConversation()}
+	resp, err := provider.Chat(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// The older turns do not fit in one request, so the summary of the
+	// first part goes along with the second
+	requests := inner.Requests()
+	if len(requests) != 3 || requests[0].Rule != "summary" || requests[1].Rule != "summary" {
+		t.Fatalf("Expected two summary requests followed by the chat request, got %+v", requests)
+	}
+	for _, summaryReq := range requests[:2] {
+		if tokens := EstimateTotal(summaryReq.Messages); tokens > 400 {
+			t.Errorf("Expected each summary request to fit the 400 token budget, got %d", tokens)
+		}
+	}
+	if !strings.HasPrefix(requests[1].Messages[1].Content, "Summary so far: The user asked a first question.") {
+		t.Errorf("Expected the second part to carry the summary so far, got %q", requests[1].Messages[1].Content)
+	}
+
+	forwarded := requests[2].Messages
+	if forwarded[1].Role != "system" || !strings.Contains(forwarded[1].Content, "The user asked a first question.") {
+		t.Errorf("Expected the summary after the system message, got %+v", forwarded[1])
+	}
+
+	if resp.Metadata[MetadataStrategy] != "summarize" {
+		t.Errorf("Unexpected metadata: %v", resp.Metadata)
+	}
+
+	// The same history is summarized once
+	if _, err := provider.Chat(context.Background(), req); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(inner.Requests()) != 4 {
+		t.Errorf("Expected the summary to be reused, got %d upstream requests", len(inner.Requests()))
+	}
+}
+
+func TestContextWindowProvider_SummarizeFallsBack(t *testing.T) {
+	inner := newScriptedProvider(t, `
+rules:
+  - match: "^user: first question"
+    error: unavailable
+default:
+  response: "ok"
+`)
+	provider := NewContextWindowProvider(inner, Config{
+		Strategy:      StrategySummarize,
+		MaxTokens:     400,
+		ReserveTokens: 100,
+	})
+
+	resp, err := provider.Chat(context.Background(), &chat.ChatRequest{Messages: longConversation()})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if resp.Metadata[MetadataStrategy] != "keep-recent" {
+		t.Errorf("Expected fallback to keep-recent, got %v", resp.Metadata)
+	}
+}
+
+func TestContextWindowProvider_LatestTurnTooLong(t *testing.T) {
+	inner := newScriptedProvider(t, `default: {response: "ok"}`)
+	provider := NewContextWindowProvider(inner, Config{MaxTokens: 150, ReserveTokens: 100})
+
+	_, err := provider.Chat(context.Background(), &chat.ChatRequest{Messages: longConversation()})
+	if !errors.Is(err, chat.ErrContextLength) {
+		t.Errorf("Expected ErrContextLength, got: %v", err)
+	}
+
+	if len(inner.Requests()) != 0 {
+		t.Error("Expected the provider not to be called")
+	}
+}
+
+func TestContextWindowProvider_StreamReportsStrategyOnce(t *testing.T) {
+	inner := newScriptedProvider(t, `default: {chunks: ["a", "b"]}`)
+	provider := NewContextWindowProvider(inner, Config{MaxTokens: 400, ReserveTokens: 100})
+
+	var reported int
+	err := provider.ChatStream(context.Background(), &chat.ChatRequest{Messages: longConversation()}, func(chunk *chat.ChatResponse) error {
+		if chunk.Metadata[MetadataStrategy] != "" {
+			reported++
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if reported != 1 {
+		t.Errorf("Expected the strategy on exactly one chunk, got %d", reported)
+	}
+}
+
+func TestLimitForModel(t *testing.T) {
+	tests := map[string]int{
+		"gemma3:1b":               32768,
+		"gemma3:27b":              131072,
+		"llama3.1:8b":             131072,
+		"llama3:8b":               8192,
+		"claude-3-5-haiku-latest": 200000,
+		"some-unknown-model":      defaultContextTokens,
+	}
+
+	for model, want := range tests {
+		if got := LimitForModel(model); got != want {
+			t.Errorf("LimitForModel(%q) = %d, want %d", model, got, want)
+		}
+	}
+}
+
+func TestTranscript_CutsLongFirstMessage(t *testing.T) {
+	messages := []chat.Message{turn("user", "long"), turn("assistant", "reply")}
+
+	text, n := transcript(messages, 50)
+	if n != 1 || len(text) > 50*charsPerToken || !strings.HasPrefix(text, "user: long") {
+		t.Errorf("Expected the first message cut to fit, got %d messages: %q", n, text)
+	}
+
+	if _, n := transcript(messages, 1000); n != 2 {
+		t.Errorf("Expected both messages to fit, got %d", n)
+	}
+}
+
+func TestSummaryCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	var cache summaryCache
+	for i := range maxRememberedSummaries {
+		cache.put(strconv.Itoa(i), "summary")
+	}
+	cache.get("0")
+	cache.put("new", "summary")
+
+	if _, ok := cache.get("0"); !ok {
+		t.Error("Expected the recently used summary to be kept")
+	}
+	if _, ok := cache.get("1"); ok {
+		t.Error("Expected the least recently used summary to be evicted")
+	}
+	if _, ok := cache.get("new"); !ok {
+		t.Error("Expected the new summary to be kept")
+	}
+}
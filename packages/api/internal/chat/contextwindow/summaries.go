@@ -0,0 +1,57 @@
+package contextwindow
+
+import (
+	"container/list"
+	"sync"
+)
+
+// maxRememberedSummaries caps the summaries kept for conversations that
+// come back with the same older turns
+const maxRememberedSummaries = 1000
+
+// summaryCache maps the key of older turns to their summary, evicting the
+// least recently used summary once full. The zero value is ready to use.
+type summaryCache struct {
+	mu    sync.Mutex
+	order *list.List // of *summaryEntry, most recently used first
+	items map[string]*list.Element
+}
+
+type summaryEntry struct {
+	key     string
+	summary string
+}
+
+func (c *summaryCache) put(key, summary string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.items == nil {
+		c.order = list.New()
+		c.items = map[string]*list.Element{}
+	}
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*summaryEntry).summary = summary
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&summaryEntry{key: key, summary: summary})
+	if c.order.Len() > maxRememberedSummaries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*summaryEntry).key)
+	}
+}
+
+func (c *summaryCache) get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return "", false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*summaryEntry).summary, true
+}
@@ -0,0 +1,85 @@
+package contextwindow
+
+import (
+	"strings"
+
+	"chat-backend/internal/chat"
+)
+
+const (
+	// Roughly four characters per token for English text with the BPE
+	// tokenizers used by current models
+	charsPerToken = 4
+
+	// Role markers and separators each message costs in the prompt template
+	perMessageTokens = 4
+
+	// Vision models turn an image into a fixed number of patch tokens
+	// regardless of its file size
+	perImageTokens = 512
+
+	defaultContextTokens = 4096
+)
+
+// modelLimits maps model name prefixes to their context window in tokens.
+// Longer prefixes are listed before shorter ones that would also match.
+var modelLimits = []struct {
+	prefix string
+	tokens int
+}{
+	{"llama3.1", 131072},
+	{"llama3.2", 131072},
+	{"llama3", 8192},
+	{"llama2", 4096},
+	{"gemma3:1b", 32768},
+	{"gemma3", 131072},
+	{"gemma2", 8192},
+	{"mistral", 32768},
+	{"mixtral", 32768},
+	{"qwen2.5", 32768},
+	{"phi3", 4096},
+	{"llava", 4096},
+	{"gpt-4o", 128000},
+	{"gpt-4.1", 1047576},
+	{"gpt-4", 8192},
+	{"gpt-3.5-turbo", 16385},
+	{"claude", 200000},
+}
+
+// LimitForModel returns the context window of model in tokens, falling back
+// to a conservative default for models it does not know
+func LimitForModel(model string) int {
+	model = strings.ToLower(model)
+	for _, limit := range modelLimits {
+		if strings.HasPrefix(model, limit.prefix) {
+			return limit.tokens
+		}
+	}
+	return defaultContextTokens
+}
+
+// EstimateTokens approximates how many prompt tokens msg will use. It errs
+// on the high side so trimmed conversations still fit.
+func EstimateTokens(msg chat.Message) int {
+	chars := len(msg.Content)
+	for _, call := range msg.ToolCalls {
+		chars += len(call.Name) + len(call.Arguments)
+	}
+
+	tokens := perMessageTokens + (chars+charsPerToken-1)/charsPerToken
+	for _, attachment := range msg.Attachments {
+		if attachment.IsImage() {
+			tokens += perImageTokens
+		}
+	}
+	return tokens
+}
+
+// EstimateTotal sums EstimateTokens over messages
+func EstimateTotal(messages []chat.Message) int {
+	total := 0
+	for _, msg := range messages {
+		total += EstimateTokens(msg)
+	}
+	return total
+}
@@ -0,0 +1,100 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// Error kinds. Providers wrap upstream failures so that errors.Is matches
+// one of these, which the HTTP layer maps onto a status code.
+var (
+	ErrInvalidRequest      = errors.New("invalid request")
+	ErrUnauthorized        = errors.New("unauthorized by upstream provider")
+	ErrRateLimited         = errors.New("rate limited by upstream provider")
+	ErrContextLength       = errors.New("context length exceeded")
+	ErrContentFiltered     = errors.New("content filtered")
+	ErrTimeout             = errors.New("chat provider timed out")
+	ErrProviderUnavailable = errors.New("chat provider unavailable")
+	ErrOverloaded          = errors.New("chat provider overloaded")
+	ErrMalformedResponse   = errors.New("malformed provider response")
+	ErrCancelled           = errors.New("generation cancelled")
+
+	ErrAttachmentsNotSupported = fmt.Errorf("attachments not supported by provider: %w", ErrInvalidRequest)
+)
+
+// Error attaches an error kind to an underlying error while keeping its
+// message, so both errors.Is(err, kind) and errors.As on the cause work
+type Error struct {
+	Kind error
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() []error {
+	if e.Kind == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// Errorf formats an error like fmt.Errorf and classifies it as kind
+func Errorf(kind error, format string, args ...any) error {
+	return &Error{Kind: kind, Err: fmt.Errorf(format, args...)}
+}
+
+// TransportError classifies a failure to reach a provider: timeouts become
+// ErrTimeout and everything else ErrProviderUnavailable. Cancellation by the
+// caller is returned as is.
+func TransportError(err error) error {
+	if errors.Is(err, context.Canceled) {
+		return err
+	}
+	if IsTimeout(err) {
+		return &Error{Kind: ErrTimeout, Err: err}
+	}
+	return &Error{Kind: ErrProviderUnavailable, Err: err}
+}
+
+// IsTimeout reports whether err is a deadline or network timeout
+func IsTimeout(err error) bool {
+	if errors.Is(err, ErrTimeout) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// RejectAttachments is used by text-only providers to refuse requests that
+// carry attachments they cannot forward
+func RejectAttachments(req *ChatRequest) error {
+	for _, msg := range req.Messages {
+		if len(msg.Attachments) > 0 {
+			return ErrAttachmentsNotSupported
+		}
+	}
+	return nil
+}
+
+// StatusKind maps an upstream HTTP status onto an error kind. Providers use it
+// as the fallback when the response body carries nothing more specific.
+func StatusKind(status int) error {
+	switch {
+	case status == http.StatusUnauthorized, status == http.StatusForbidden:
+		return ErrUnauthorized
+	case status == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case status == http.StatusRequestTimeout, status == http.StatusGatewayTimeout:
+		return ErrTimeout
+	case status >= 500:
+		return ErrProviderUnavailable
+	case status >= 400:
+		return ErrInvalidRequest
+	}
+	return nil
+}
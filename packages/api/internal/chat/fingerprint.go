@@ -0,0 +1,70 @@
+package chat
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+)
+
+// fingerprintMessage is the normalized form of a message. Runs of
+// whitespace are collapsed so trivially different spellings of the same
+// question match, and attachments are reduced to digests.
+type fingerprintMessage struct {
+	Role        string     `json:"role"`
+	Content     string     `json:"content"`
+	ToolCalls   []ToolCall `json:"tool_calls,omitempty"`
+	ToolCallID  string     `json:"tool_call_id,omitempty"`
+	Attachments []string   `json:"attachments,omitempty"`
+}
+
+type fingerprintTool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+}
+
+// Fingerprint hashes everything in a request that can change the answer,
+// so requests with equal fingerprints can share one. Whether the request is
+// streamed does not change the answer and is left out.
+func Fingerprint(req *ChatRequest) (string, error) {
+	messages := make([]fingerprintMessage, 0, len(req.Messages))
+	for _, msg := range req.Messages {
+		normalized := fingerprintMessage{
+			Role:       strings.ToLower(strings.TrimSpace(msg.Role)),
+			Content:    strings.Join(strings.Fields(msg.Content), " "),
+			ToolCalls:  msg.ToolCalls,
+			ToolCallID: msg.ToolCallID,
+		}
+		for _, attachment := range msg.Attachments {
+			sum := sha256.Sum256(attachment.Data)
+			normalized.Attachments = append(normalized.Attachments, attachment.MimeType+":"+hex.EncodeToString(sum[:]))
+		}
+		messages = append(messages, normalized)
+	}
+
+	tools := make([]fingerprintTool, 0, len(req.Tools))
+	for _, tool := range req.Tools {
+		normalized := fingerprintTool{Name: tool.Name, Description: tool.Description}
+		if len(tool.Parameters) > 0 {
+			var compact bytes.Buffer
+			if err := json.Compact(&compact, tool.Parameters); err != nil {
+				return "", err
+			}
+			normalized.Parameters = compact.Bytes()
+		}
+		tools = append(tools, normalized)
+	}
+
+	data, err := json.Marshal(struct {
+		Tools    []fingerprintTool    `json:"tools"`
+		Messages []fingerprintMessage `json:"messages"`
+	}{tools, messages})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
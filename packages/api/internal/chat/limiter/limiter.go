@@ -0,0 +1,330 @@
+package limiter
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"chat-backend/internal/chat"
+	"chat-backend/internal/tenant"
+)
+
+type Mode string
+
+const (
+	// ModeFIFO serves queued requests in arrival order
+	ModeFIFO Mode = "fifo"
+	// ModePriority serves requests from higher priority tenant tiers first,
+	// and requests of equal priority in arrival order
+	ModePriority Mode = "priority"
+)
+
+var (
+	ErrQueueFull    = errors.New("request queue is full")
+	ErrQueueTimeout = errors.New("timed out waiting in the request queue")
+)
+
+const (
+	defaultMaxQueue     = 100
+	defaultQueueTimeout = 30 * time.Second
+	// Assumed duration of a request until one has completed
+	defaultHoldEstimate = 5 * time.Second
+)
+
+func ParseMode(s string) (Mode, error) {
+	switch mode := Mode(s); mode {
+	case ModeFIFO, ModePriority:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown queue mode %q", s)
+}
+
+// Config bounds how many requests reach the provider at once. Requests
+// beyond MaxConcurrent wait in a queue of at most MaxQueue requests, for at
+// most QueueTimeout. A MaxQueue of 0 means the default length, and a
+// negative one turns queueing off. TierPriorities maps tenant tiers to
+// priorities used in ModePriority; higher goes first and unknown tiers
+// get 0.
+type Config struct {
+	MaxConcurrent  int
+	MaxQueue       int
+	QueueTimeout   time.Duration
+	Mode           Mode
+	TierPriorities map[string]int
+}
+
+// QueueError is returned when a request could not get a slot. It matches
+// chat.ErrOverloaded and either ErrQueueFull or ErrQueueTimeout.
+type QueueError struct {
+	Reason error
+	// Retry estimates when a slot is likely to be free
+	Retry time.Duration
+}
+
+func (e *QueueError) Error() string {
+	return fmt.Sprintf("%v, retry in %s", e.Reason, e.Retry.Round(time.Second))
+}
+
+func (e *QueueError) Unwrap() []error {
+	return []error{e.Reason, chat.ErrOverloaded}
+}
+
+func (e *QueueError) RetryAfter() time.Duration {
+	return e.Retry
+}
+
+// Stats reports the limiter's current load and history since startup
+type Stats struct {
+	MaxConcurrent int     `json:"max_concurrent"`
+	InFlight      int     `json:"in_flight"`
+	QueueDepth    int     `json:"queue_depth"`
+	MaxQueue      int     `json:"max_queue"`
+	Served        uint64  `json:"served"`
+	Rejected      uint64  `json:"rejected"`
+	TimedOut      uint64  `json:"timed_out"`
+	AvgWaitMs     float64 `json:"avg_wait_ms"`
+	OldestWaitMs  float64 `json:"oldest_wait_ms"`
+}
+
+// LimiterProvider lets at most MaxConcurrent requests through to the
+// wrapped provider at a time, queueing the rest. A streamed request holds
+// its slot until the stream ends.
+type LimiterProvider struct {
+	provider chat.ChatProvider
+	config   Config
+
+	mu       sync.Mutex
+	inFlight int
+	queue    waitQueue
+	seq      uint64
+	// hold is a moving average of how long requests keep their slot
+	hold time.Duration
+
+	served    uint64
+	rejected  uint64
+	timedOut  uint64
+	totalWait time.Duration
+}
+
+func NewLimiterProvider(provider chat.ChatProvider, config Config) *LimiterProvider {
+	if config.MaxConcurrent <= 0 {
+		config.MaxConcurrent = 1
+	}
+	if config.MaxQueue < 0 {
+		config.MaxQueue = 0
+	} else if config.MaxQueue == 0 {
+		config.MaxQueue = defaultMaxQueue
+	}
+	if config.QueueTimeout <= 0 {
+		config.QueueTimeout = defaultQueueTimeout
+	}
+	if config.Mode == "" {
+		config.Mode = ModeFIFO
+	}
+
+	return &LimiterProvider{
+		provider: provider,
+		config:   config,
+		hold:     defaultHoldEstimate,
+	}
+}
+
+func (p *LimiterProvider) Unwrap() chat.ChatProvider {
+	return p.provider
+}
+
+func (p *LimiterProvider) Chat(ctx context.Context, req *chat.ChatRequest) (*chat.ChatResponse, error) {
+	if err := p.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer p.release(time.Now())
+
+	return p.provider.Chat(ctx, req)
+}
+
+func (p *LimiterProvider) ChatStream(ctx context.Context, req *chat.ChatRequest, callback chat.StreamCallback) error {
+	if err := p.acquire(ctx); err != nil {
+		return err
+	}
+	defer p.release(time.Now())
+
+	return p.provider.ChatStream(ctx, req, callback)
+}
+
+// Stats returns a snapshot of the limiter's load
+func (p *LimiterProvider) Stats() Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := Stats{
+		MaxConcurrent: p.config.MaxConcurrent,
+		InFlight:      p.inFlight,
+		QueueDepth:    p.queue.Len(),
+		MaxQueue:      p.config.MaxQueue,
+		Served:        p.served,
+		Rejected:      p.rejected,
+		TimedOut:      p.timedOut,
+	}
+	if p.served > 0 {
+		stats.AvgWaitMs = float64(p.totalWait.Milliseconds()) / float64(p.served)
+	}
+	for _, w := range p.queue {
+		if wait := time.Since(w.enqueued); float64(wait.Milliseconds()) > stats.OldestWaitMs {
+			stats.OldestWaitMs = float64(wait.Milliseconds())
+		}
+	}
+	return stats
+}
+
+// acquire takes a slot, waiting in the queue if none is free
+func (p *LimiterProvider) acquire(ctx context.Context) error {
+	p.mu.Lock()
+
+	if p.inFlight < p.config.MaxConcurrent && p.queue.Len() == 0 {
+		p.inFlight++
+		p.served++
+		p.mu.Unlock()
+		return nil
+	}
+
+	if p.queue.Len() >= p.config.MaxQueue {
+		p.rejected++
+		err := &QueueError{Reason: ErrQueueFull, Retry: p.estimate(p.queue.Len() + 1)}
+		p.mu.Unlock()
+		return err
+	}
+
+	w := &waiter{
+		priority: p.priority(ctx),
+		seq:      p.seq,
+		enqueued: time.Now(),
+		ready:    make(chan struct{}),
+	}
+	p.seq++
+	heap.Push(&p.queue, w)
+	p.mu.Unlock()
+
+	timer := time.NewTimer(p.config.QueueTimeout)
+	defer timer.Stop()
+
+	var err error
+	select {
+	case <-w.ready:
+		return nil
+	case <-timer.C:
+		err = ErrQueueTimeout
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if w.index < 0 {
+		// The slot was handed over while giving up, so pass it on
+		p.releaseLocked()
+		return p.abandon(err)
+	}
+
+	heap.Remove(&p.queue, w.index)
+	return p.abandon(err)
+}
+
+// abandon builds the error for a request that stopped waiting. Callers hold
+// the lock.
+func (p *LimiterProvider) abandon(err error) error {
+	if err != ErrQueueTimeout {
+		return err
+	}
+	p.timedOut++
+	return &QueueError{Reason: ErrQueueTimeout, Retry: p.estimate(p.queue.Len() + 1)}
+}
+
+// release frees the slot taken at start, handing it straight to the next
+// queued request if there is one
+func (p *LimiterProvider) release(start time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Weight recent requests more, so the estimate follows load changes
+	p.hold = (p.hold*4 + time.Since(start)) / 5
+	p.releaseLocked()
+}
+
+func (p *LimiterProvider) releaseLocked() {
+	if p.queue.Len() == 0 {
+		p.inFlight--
+		return
+	}
+
+	w := heap.Pop(&p.queue).(*waiter)
+	p.served++
+	p.totalWait += time.Since(w.enqueued)
+	close(w.ready)
+}
+
+// estimate guesses how long until the request at position in the queue
+// gets a slot, assuming every slot turns over in the average hold time.
+// Callers hold the lock.
+func (p *LimiterProvider) estimate(position int) time.Duration {
+	rounds := (position + p.config.MaxConcurrent - 1) / p.config.MaxConcurrent
+	return time.Duration(rounds) * p.hold
+}
+
+// priority is the queue priority of the tenant's tier. The tenant comes
+// from the X-Tenant-ID header, which clients can set to anything, so tiers
+// only mean something behind a trusted proxy that sets the header itself.
+func (p *LimiterProvider) priority(ctx context.Context) int {
+	if p.config.Mode != ModePriority {
+		return 0
+	}
+	return p.config.TierPriorities[tenant.FromContext(ctx).Tier]
+}
+
+type waiter struct {
+	priority int
+	seq      uint64
+	enqueued time.Time
+	ready    chan struct{}
+	// index is the waiter's position in the heap, or -1 once it has been
+	// given a slot
+	index int
+}
+
+// waitQueue is a heap of waiters, highest priority first and then oldest
+// first
+type waitQueue []*waiter
+
+func (q waitQueue) Len() int { return len(q) }
+
+func (q waitQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority > q[j].priority
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q waitQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *waitQueue) Push(x any) {
+	w := x.(*waiter)
+	w.index = len(*q)
+	*q = append(*q, w)
+}
+
+func (q *waitQueue) Pop() any {
+	old := *q
+	n := len(old)
+	w := old[n-1]
+	old[n-1] = nil
+	w.index = -1
+	*q = old[:n-1]
+	return w
+}
@@ -0,0 +1,200 @@
+package limiter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"chat-backend/internal/chat"
+	"chat-backend/internal/tenant"
+)
+
+// blockingProvider holds every request until release is closed, and records
+// the order requests got through
+type blockingProvider struct {
+	release chan struct{}
+
+	mu      sync.Mutex
+	current int
+	peak    int
+	order   []string
+}
+
+func newBlockingProvider() *blockingProvider {
+	return &blockingProvider{release: make(chan struct{})}
+}
+
+func (p *blockingProvider) Chat(ctx context.Context, req *chat.ChatRequest) (*chat.ChatResponse, error) {
+	p.mu.Lock()
+	p.current++
+	p.peak = max(p.peak, p.current)
+	p.order = append(p.order, req.Messages[0].Content)
+	p.mu.Unlock()
+
+	<-p.release
+
+	p.mu.Lock()
+	p.current--
+	p.mu.Unlock()
+	return &chat.ChatResponse{Content: "ok"}, nil
+}
+
+func (p *blockingProvider) ChatStream(ctx context.Context, req *chat.ChatRequest, callback chat.StreamCallback) error {
+	resp, err := p.Chat(ctx, req)
+	if err != nil {
+		return err
+	}
+	return callback(resp)
+}
+
+func request(content string) *chat.ChatRequest {
+	return &chat.ChatRequest{Messages: []chat.Message{{Role: "user", Content: content}}}
+}
+
+// waitFor polls the limiter until cond holds
+func waitFor(t *testing.T, p *LimiterProvider, cond func(Stats) bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if cond(p.Stats()) {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("Timed out waiting for limiter state, last: %+v", p.Stats())
+}
+
+func TestLimiterProvider_BoundsConcurrency(t *testing.T) {
+	inner := newBlockingProvider()
+	provider := NewLimiterProvider(inner, Config{MaxConcurrent: 2})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := provider.Chat(context.Background(), request("q")); err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+		}()
+	}
+
+	waitFor(t, provider, func(s Stats) bool { return s.InFlight == 2 && s.QueueDepth == 3 })
+	close(inner.release)
+	wg.Wait()
+
+	if inner.peak != 2 {
+		t.Errorf("Expected at most 2 concurrent requests, got %d", inner.peak)
+	}
+
+	stats := provider.Stats()
+	if stats.InFlight != 0 || stats.QueueDepth != 0 || stats.Served != 5 {
+		t.Errorf("Unexpected stats after draining: %+v", stats)
+	}
+}
+
+func TestLimiterProvider_QueueFull(t *testing.T) {
+	inner := newBlockingProvider()
+	defer close(inner.release)
+	provider := NewLimiterProvider(inner, Config{MaxConcurrent: 1, MaxQueue: 1})
+
+	go provider.Chat(context.Background(), request("running"))
+	waitFor(t, provider, func(s Stats) bool { return s.InFlight == 1 })
+	go provider.Chat(context.Background(), request("queued"))
+	waitFor(t, provider, func(s Stats) bool { return s.QueueDepth == 1 })
+
+	_, err := provider.Chat(context.Background(), request("rejected"))
+
+	var queueErr *QueueError
+	if !errors.As(err, &queueErr) || !errors.Is(err, ErrQueueFull) || !errors.Is(err, chat.ErrOverloaded) {
+		t.Fatalf("Expected a queue full error, got: %v", err)
+	}
+	if queueErr.RetryAfter() <= 0 {
+		t.Errorf("Expected a retry estimate, got %s", queueErr.RetryAfter())
+	}
+	if provider.Stats().Rejected != 1 {
+		t.Errorf("Expected the rejection to be counted, got %+v", provider.Stats())
+	}
+}
+
+func TestLimiterProvider_QueueTimeout(t *testing.T) {
+	inner := newBlockingProvider()
+	defer close(inner.release)
+	provider := NewLimiterProvider(inner, Config{MaxConcurrent: 1, QueueTimeout: 20 * time.Millisecond})
+
+	go provider.Chat(context.Background(), request("running"))
+	waitFor(t, provider, func(s Stats) bool { return s.InFlight == 1 })
+
+	err := provider.ChatStream(context.Background(), request("waiting"), func(*chat.ChatResponse) error { return nil })
+	if !errors.Is(err, ErrQueueTimeout) || !errors.Is(err, chat.ErrOverloaded) {
+		t.Fatalf("Expected a queue timeout, got: %v", err)
+	}
+
+	stats := provider.Stats()
+	if stats.TimedOut != 1 || stats.QueueDepth != 0 {
+		t.Errorf("Expected the timed out request to leave the queue, got %+v", stats)
+	}
+}
+
+func TestLimiterProvider_TierPriority(t *testing.T) {
+	inner := newBlockingProvider()
+	provider := NewLimiterProvider(inner, Config{
+		MaxConcurrent:  1,
+		Mode:           ModePriority,
+		TierPriorities: map[string]int{"enterprise": 10},
+	})
+
+	var wg sync.WaitGroup
+	send := func(content, tier string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx := tenant.WithTenant(context.Background(), tenant.Tenant{ID: content, Tier: tier})
+			provider.Chat(ctx, request(content))
+		}()
+	}
+
+	send("running", "")
+	waitFor(t, provider, func(s Stats) bool { return s.InFlight == 1 })
+	send("free", "free")
+	waitFor(t, provider, func(s Stats) bool { return s.QueueDepth == 1 })
+	send("enterprise", "enterprise")
+	waitFor(t, provider, func(s Stats) bool { return s.QueueDepth == 2 })
+
+	close(inner.release)
+	wg.Wait()
+
+	want := []string{"running", "enterprise", "free"}
+	for i, content := range want {
+		if inner.order[i] != content {
+			t.Fatalf("Expected service order %v, got %v", want, inner.order)
+		}
+	}
+}
+
+func TestLimiterProvider_CancelledWhileQueued(t *testing.T) {
+	inner := newBlockingProvider()
+	provider := NewLimiterProvider(inner, Config{MaxConcurrent: 1})
+
+	go provider.Chat(context.Background(), request("running"))
+	waitFor(t, provider, func(s Stats) bool { return s.InFlight == 1 })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		_, err := provider.Chat(ctx, request("cancelled"))
+		errs <- err
+	}()
+	waitFor(t, provider, func(s Stats) bool { return s.QueueDepth == 1 })
+
+	cancel()
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the cancellation to be returned, got: %v", err)
+	}
+
+	close(inner.release)
+	waitFor(t, provider, func(s Stats) bool { return s.InFlight == 0 && s.QueueDepth == 0 })
+}
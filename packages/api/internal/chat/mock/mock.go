@@ -57,7 +57,11 @@ func (m *MockChatProvider) loadTSVData() {
 
 func (m *MockChatProvider) Chat(ctx context.Context, req *chat.ChatRequest) (*chat.ChatResponse, error) {
 	if len(req.Messages) == 0 {
-		return nil, fmt.Errorf("no messages provided")
+		return nil, chat.Errorf(chat.ErrInvalidRequest, "no messages provided")
+	}
+
+	if err := chat.RejectAttachments(req); err != nil {
+		return nil, err
 	}
 
 	question := req.Messages[len(req.Messages)-1].Content
@@ -90,7 +94,8 @@ func (m *MockChatProvider) Chat(ctx context.Context, req *chat.ChatRequest) (*ch
 	}
 
 	return &chat.ChatResponse{
-		Content: "I don't have an answer for that question in my knowledge base.",
+		Content:      "I don't have an answer for that question in my knowledge base.",
+		FinishReason: chat.FinishReasonNoAnswer,
 	}, nil
 }
 
@@ -121,7 +126,7 @@ func (m *MockChatProvider) findBestMatch(userQuestion string) *QAPair {
 }
 
 func (m *MockChatProvider) ChatStream(ctx context.Context, req *chat.ChatRequest, callback chat.StreamCallback) error {
-	return fmt.Errorf("streaming not supported by mock provider")
+	return chat.Errorf(chat.ErrInvalidRequest, "streaming not supported by mock provider")
 }
 
 func longestCommonSubstring(s1, s2 string) int {
@@ -0,0 +1,282 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"chat-backend/internal/chat"
+)
+
+// Scenario describes a scripted conversation used to drive the mock provider
+// deterministically in tests. Rules are evaluated in order against the last
+// message of each request; the first rule that matches (and has not used up
+// its Times budget) decides the outcome. Scenarios are written in YAML, and
+// since JSON is a subset of YAML the same loader accepts JSON files.
+type Scenario struct {
+	Rules   []Rule `yaml:"rules" json:"rules"`
+	Default *Rule  `yaml:"default,omitempty" json:"default,omitempty"`
+}
+
+// Rule is a single scripted behaviour. Error may be one of "unavailable",
+// "invalid_request", "timeout" or "malformed". When both Chunks and Error are
+// set, streaming requests receive the chunks before the error is returned,
+// which simulates an upstream failing mid-stream.
+type Rule struct {
+	Name     string   `yaml:"name,omitempty" json:"name,omitempty"`
+	Match    string   `yaml:"match,omitempty" json:"match,omitempty"`
+	Response string   `yaml:"response,omitempty" json:"response,omitempty"`
+	Chunks   []string `yaml:"chunks,omitempty" json:"chunks,omitempty"`
+	Error    string   `yaml:"error,omitempty" json:"error,omitempty"`
+	Latency  string   `yaml:"latency,omitempty" json:"latency,omitempty"`
+	Times    int      `yaml:"times,omitempty" json:"times,omitempty"`
+}
+
+// RecordedRequest is a request observed by the ScriptedChatProvider
+type RecordedRequest struct {
+	Time      time.Time
+	Streaming bool
+	Messages  []chat.Message
+	Tools     []chat.Tool
+	Rule      string
+}
+
+type compiledRule struct {
+	Rule
+	pattern *regexp.Regexp
+	latency time.Duration
+	hits    int
+}
+
+// ScriptedChatProvider is a chat.ChatProvider whose behaviour is driven by a
+// Scenario. It records every request it receives so tests can assert on them.
+type ScriptedChatProvider struct {
+	mu       sync.Mutex
+	rules    []*compiledRule
+	fallback *compiledRule
+	requests []RecordedRequest
+}
+
+// LoadScenarioFile reads a YAML or JSON scenario from disk
+func LoadScenarioFile(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario file: %w", err)
+	}
+	return ParseScenario(data)
+}
+
+// ParseScenario decodes a YAML or JSON scenario
+func ParseScenario(data []byte) (*Scenario, error) {
+	var scenario Scenario
+	if err := yaml.Unmarshal(data, &scenario); err != nil {
+		return nil, fmt.Errorf("failed to parse scenario: %w", err)
+	}
+	return &scenario, nil
+}
+
+func NewScriptedChatProvider(scenario *Scenario) (*ScriptedChatProvider, error) {
+	provider := &ScriptedChatProvider{}
+
+	for i, rule := range scenario.Rules {
+		compiled, err := compileRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i, err)
+		}
+		provider.rules = append(provider.rules, compiled)
+	}
+
+	if scenario.Default != nil {
+		compiled, err := compileRule(*scenario.Default)
+		if err != nil {
+			return nil, fmt.Errorf("default rule: %w", err)
+		}
+		provider.fallback = compiled
+	}
+
+	return provider, nil
+}
+
+func compileRule(rule Rule) (*compiledRule, error) {
+	compiled := &compiledRule{Rule: rule}
+
+	if rule.Match != "" {
+		pattern, err := regexp.Compile(rule.Match)
+		if err != nil {
+			return nil, fmt.Errorf("invalid match pattern %q: %w", rule.Match, err)
+		}
+		compiled.pattern = pattern
+	}
+
+	if rule.Latency != "" {
+		latency, err := time.ParseDuration(rule.Latency)
+		if err != nil {
+			return nil, fmt.Errorf("invalid latency %q: %w", rule.Latency, err)
+		}
+		compiled.latency = latency
+	}
+
+	switch rule.Error {
+	case "", "unavailable", "invalid_request", "timeout", "malformed":
+	default:
+		return nil, fmt.Errorf("unknown error kind %q", rule.Error)
+	}
+
+	return compiled, nil
+}
+
+// Requests returns a copy of every request received so far
+func (p *ScriptedChatProvider) Requests() []RecordedRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	requests := make([]RecordedRequest, len(p.requests))
+	copy(requests, p.requests)
+	return requests
+}
+
+// Reset clears recorded requests and rule hit counters
+func (p *ScriptedChatProvider) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.requests = nil
+	for _, rule := range p.rules {
+		rule.hits = 0
+	}
+}
+
+func (p *ScriptedChatProvider) Chat(ctx context.Context, req *chat.ChatRequest) (*chat.ChatResponse, error) {
+	rule := p.record(req, false)
+	if rule == nil {
+		return nil, chat.Errorf(chat.ErrInvalidRequest, "no scripted rule matched request")
+	}
+
+	if err := rule.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := rule.err(ctx); err != nil {
+		return nil, err
+	}
+
+	return &chat.ChatResponse{
+		Content: rule.content(),
+	}, nil
+}
+
+func (p *ScriptedChatProvider) ChatStream(ctx context.Context, req *chat.ChatRequest, callback chat.StreamCallback) error {
+	rule := p.record(req, true)
+	if rule == nil {
+		return chat.Errorf(chat.ErrInvalidRequest, "no scripted rule matched request")
+	}
+
+	if err := rule.wait(ctx); err != nil {
+		return err
+	}
+
+	chunks := rule.Chunks
+	if len(chunks) == 0 && rule.Error == "" {
+		chunks = []string{rule.Response}
+	}
+
+	for _, chunk := range chunks {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := callback(&chat.ChatResponse{Content: chunk}); err != nil {
+			return err
+		}
+	}
+
+	return rule.err(ctx)
+}
+
+// record stores the request and returns the rule that should answer it
+func (p *ScriptedChatProvider) record(req *chat.ChatRequest, streaming bool) *compiledRule {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	messages := make([]chat.Message, len(req.Messages))
+	copy(messages, req.Messages)
+
+	var last string
+	if len(messages) > 0 {
+		last = messages[len(messages)-1].Content
+	}
+
+	matched := p.fallback
+	for _, rule := range p.rules {
+		if rule.Times > 0 && rule.hits >= rule.Times {
+			continue
+		}
+		if rule.pattern != nil && !rule.pattern.MatchString(last) {
+			continue
+		}
+		rule.hits++
+		matched = rule
+		break
+	}
+
+	recorded := RecordedRequest{
+		Time:      time.Now(),
+		Streaming: streaming,
+		Messages:  messages,
+		Tools:     req.Tools,
+	}
+	if matched != nil {
+		recorded.Rule = matched.Name
+	}
+	p.requests = append(p.requests, recorded)
+
+	return matched
+}
+
+func (r *compiledRule) wait(ctx context.Context) error {
+	if r.latency <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(r.latency)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *compiledRule) err(ctx context.Context) error {
+	switch r.Error {
+	case "unavailable":
+		return fmt.Errorf("scripted failure: %w", chat.ErrProviderUnavailable)
+	case "invalid_request":
+		return fmt.Errorf("scripted failure: %w", chat.ErrInvalidRequest)
+	case "malformed":
+		return fmt.Errorf("scripted failure: %w", chat.ErrMalformedResponse)
+	case "timeout":
+		// Block until the caller gives up, the way a hung upstream would.
+		// Without a deadline that would hang forever, so fail straight away.
+		if _, ok := ctx.Deadline(); !ok {
+			return fmt.Errorf("scripted failure: %w", context.DeadlineExceeded)
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (r *compiledRule) content() string {
+	if r.Response == "" && len(r.Chunks) > 0 {
+		return strings.Join(r.Chunks, "")
+	}
+	return r.Response
+}
@@ -0,0 +1,160 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"chat-backend/internal/chat"
+)
+
+const testScenario = `
+rules:
+  - name: outage-once
+    match: "(?i)outage"
+    error: unavailable
+    times: 1
+  - name: slow
+    match: "slow"
+    latency: 50ms
+    response: "eventually"
+  - name: broken-stream
+    match: "broken"
+    chunks: ["partial ", "answer"]
+    error: malformed
+  - name: hang
+    match: "hang"
+    error: timeout
+default:
+  name: fallback
+  response: "default answer"
+`
+
+func newTestProvider(t *testing.T, source string) *ScriptedChatProvider {
+	t.Helper()
+
+	scenario, err := ParseScenario([]byte(source))
+	if err != nil {
+		t.Fatalf("Failed to parse scenario: %v", err)
+	}
+
+	provider, err := NewScriptedChatProvider(scenario)
+	if err != nil {
+		t.Fatalf("Failed to build provider: %v", err)
+	}
+	return provider
+}
+
+func userRequest(content string) *chat.ChatRequest {
+	return &chat.ChatRequest{
+		Messages: []chat.Message{{Role: "user", Content: content}},
+	}
+}
+
+func TestScriptedChatProvider_ErrorThenRecovery(t *testing.T) {
+	provider := newTestProvider(t, testScenario)
+
+	_, err := provider.Chat(context.Background(), userRequest("is there an outage?"))
+	if !errors.Is(err, chat.ErrProviderUnavailable) {
+		t.Fatalf("Expected ErrProviderUnavailable, got: %v", err)
+	}
+
+	resp, err := provider.Chat(context.Background(), userRequest("is there an outage?"))
+	if err != nil {
+		t.Fatalf("Expected no error on retry, got: %v", err)
+	}
+	if resp.Content != "default answer" {
+		t.Errorf("Expected fallback answer, got '%s'", resp.Content)
+	}
+
+	requests := provider.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("Expected 2 recorded requests, got %d", len(requests))
+	}
+	if requests[0].Rule != "outage-once" || requests[1].Rule != "fallback" {
+		t.Errorf("Unexpected rules recorded: '%s', '%s'", requests[0].Rule, requests[1].Rule)
+	}
+}
+
+func TestScriptedChatProvider_Latency(t *testing.T) {
+	provider := newTestProvider(t, testScenario)
+
+	start := time.Now()
+	resp, err := provider.Chat(context.Background(), userRequest("be slow"))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Expected at least 50ms latency, got %s", elapsed)
+	}
+	if resp.Content != "eventually" {
+		t.Errorf("Expected 'eventually', got '%s'", resp.Content)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := provider.Chat(ctx, userRequest("be slow")); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got: %v", err)
+	}
+}
+
+func TestScriptedChatProvider_StreamFailsMidway(t *testing.T) {
+	provider := newTestProvider(t, testScenario)
+
+	var chunks []string
+	err := provider.ChatStream(context.Background(), userRequest("broken please"), func(chunk *chat.ChatResponse) error {
+		chunks = append(chunks, chunk.Content)
+		return nil
+	})
+
+	if !errors.Is(err, chat.ErrMalformedResponse) {
+		t.Fatalf("Expected ErrMalformedResponse, got: %v", err)
+	}
+	if len(chunks) != 2 {
+		t.Errorf("Expected 2 chunks before failure, got %d", len(chunks))
+	}
+	if !provider.Requests()[0].Streaming {
+		t.Error("Expected request to be recorded as streaming")
+	}
+}
+
+func TestScriptedChatProvider_Timeout(t *testing.T) {
+	provider := newTestProvider(t, testScenario)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := provider.Chat(ctx, userRequest("hang forever")); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got: %v", err)
+	}
+}
+
+func TestScriptedChatProvider_JSONScenario(t *testing.T) {
+	provider := newTestProvider(t, `{"rules": [{"match": "^hi$", "response": "hello"}]}`)
+
+	resp, err := provider.Chat(context.Background(), userRequest("hi"))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if resp.Content != "hello" {
+		t.Errorf("Expected 'hello', got '%s'", resp.Content)
+	}
+
+	if _, err := provider.Chat(context.Background(), userRequest("unmatched")); !errors.Is(err, chat.ErrInvalidRequest) {
+		t.Errorf("Expected ErrInvalidRequest without a default rule, got: %v", err)
+	}
+}
+
+func TestNewScriptedChatProvider_InvalidRule(t *testing.T) {
+	scenario := &Scenario{Rules: []Rule{{Match: "("}}}
+	if _, err := NewScriptedChatProvider(scenario); err == nil {
+		t.Error("Expected error for invalid regex")
+	}
+
+	scenario = &Scenario{Rules: []Rule{{Error: "explode"}}}
+	if _, err := NewScriptedChatProvider(scenario); err == nil {
+		t.Error("Expected error for unknown error kind")
+	}
+}
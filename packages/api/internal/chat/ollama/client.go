@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"strings"
+	"time"
+
+	"chat-backend/internal/chat"
 )
 
 type StreamCallback func(chunk *ChatResponse) error
@@ -24,19 +26,44 @@ type ollamaHttpClient struct {
 }
 
 type OllamaMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role    string   `json:"role"`
+	Content string   `json:"content"`
+	Images  []string `json:"images,omitempty"`
 }
 
 type ChatRequest struct {
 	Model    string          `json:"model"`
 	Messages []OllamaMessage `json:"messages"`
 	Stream   bool            `json:"stream"`
+	Options  *Options        `json:"options,omitempty"`
+}
+
+// Options are the model parameters sent with a request. NumCtx is the
+// context size, without which Ollama runs at its small default and
+// silently drops the start of longer prompts.
+type Options struct {
+	NumCtx int `json:"num_ctx,omitempty"`
 }
 
+// DefaultModel is used when no model is configured
+const DefaultModel = "mistral"
+
+// ChatResponse is a single response object. When streaming, every frame
+// carries a fragment of the message and the final frame, with Done set,
+// carries the done reason and generation statistics.
 type ChatResponse struct {
-	Message OllamaMessage `json:"message"`
-	Done    bool          `json:"done"`
+	Model      string        `json:"model"`
+	CreatedAt  time.Time     `json:"created_at"`
+	Message    OllamaMessage `json:"message"`
+	Done       bool          `json:"done"`
+	DoneReason string        `json:"done_reason,omitempty"`
+
+	TotalDuration      time.Duration `json:"total_duration,omitempty"`
+	LoadDuration       time.Duration `json:"load_duration,omitempty"`
+	PromptEvalCount    int           `json:"prompt_eval_count,omitempty"`
+	PromptEvalDuration time.Duration `json:"prompt_eval_duration,omitempty"`
+	EvalCount          int           `json:"eval_count,omitempty"`
+	EvalDuration       time.Duration `json:"eval_duration,omitempty"`
 }
 
 func NewClient(baseURL, model string) OllamaClient {
@@ -44,7 +71,7 @@ func NewClient(baseURL, model string) OllamaClient {
 		baseURL = "http://localhost:11434"
 	}
 	if model == "" {
-		model = "mistral"
+		model = DefaultModel
 	}
 
 	return &ollamaHttpClient{
@@ -55,128 +82,150 @@ func NewClient(baseURL, model string) OllamaClient {
 }
 
 func (c *ollamaHttpClient) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
-	req.Model = c.model
+	req.Stream = false
 
-	reqBody, err := json.Marshal(req)
+	body, err := c.do(ctx, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+		return nil, err
 	}
+	defer body.Close()
 
-	url := fmt.Sprintf("%s/api/chat", c.baseURL)
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(reqBody))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	var ollamaResp ChatResponse
+	if err := decodeFrame(json.NewDecoder(body), &ollamaResp); err != nil {
+		if err == io.EOF {
+			return nil, &MalformedResponseError{Reason: "empty response body"}
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, err
 	}
 
-	httpReq.Header.Set("Content-Type", "application/json")
+	return &ollamaResp, nil
+}
 
-	resp, err := c.httpClient.Do(httpReq)
+func (c *ollamaHttpClient) ChatStream(ctx context.Context, req *ChatRequest, callback StreamCallback) error {
+	req.Stream = true
+
+	body, err := c.do(ctx, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return err
 	}
-	defer resp.Body.Close()
+	defer body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("ollama API returned status %d", resp.StatusCode)
-	}
+	decoder := newStreamDecoder(body)
+	for {
+		// Frames already buffered would still decode after the request is
+		// cancelled, so stop before each one
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 
-	if req.Stream {
-		return c.handleStreamingResponse(resp.Body)
-	}
+		frame, err := decoder.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			// A cancelled request surfaces as a failed read, report the cause
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			return err
+		}
 
-	return c.handleNonStreamingResponse(resp.Body)
+		if err := callback(frame); err != nil {
+			return fmt.Errorf("callback error: %w", err)
+		}
+	}
 }
 
-func (c *ollamaHttpClient) ChatStream(ctx context.Context, req *ChatRequest, callback StreamCallback) error {
+// do sends a chat request through the request pipeline
+func (c *ollamaHttpClient) do(ctx context.Context, req *ChatRequest) (io.ReadCloser, error) {
 	req.Model = c.model
-	req.Stream = true
+	return c.post(ctx, "/api/chat", req)
+}
 
-	reqBody, err := json.Marshal(req)
+// post is the single request pipeline shared by every Ollama endpoint. It
+// sends the payload and returns the response body, or an *APIError carrying
+// Ollama's error message when the status is not 200.
+func (c *ollamaHttpClient) post(ctx context.Context, path string, payload any) (io.ReadCloser, error) {
+	reqBody, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("failed to marshal request: %w", err)
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	url := fmt.Sprintf("%s/api/chat", c.baseURL)
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(reqBody))
+	url := c.baseURL + path
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(reqBody))
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
 
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
+		return nil, chat.TransportError(fmt.Errorf("failed to send request: %w", err))
 	}
-	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("ollama API returned status %d", resp.StatusCode)
+		defer resp.Body.Close()
+		return nil, parseErrorResponse(resp, c.model)
 	}
 
-	return c.handleStreamingResponseWithCallback(resp.Body, callback)
+	return resp.Body, nil
 }
 
-func (c *ollamaHttpClient) handleStreamingResponseWithCallback(body io.Reader, callback StreamCallback) error {
-	decoder := json.NewDecoder(body)
+// streamDecoder reads the newline-delimited JSON frames of a streamed chat
+type streamDecoder struct {
+	decoder *json.Decoder
+	done    bool
+}
 
-	for {
-		var ollamaResp ChatResponse
-		if err := decoder.Decode(&ollamaResp); err != nil {
-			if err == io.EOF {
-				break
-			}
-			return fmt.Errorf("failed to decode streaming response: %w", err)
-		}
+func newStreamDecoder(body io.Reader) *streamDecoder {
+	return &streamDecoder{decoder: json.NewDecoder(body)}
+}
 
-		if err := callback(&ollamaResp); err != nil {
-			return fmt.Errorf("callback error: %w", err)
-		}
+// Next returns the next frame. After the final frame (Done set) it returns
+// io.EOF. A body that ends before the final frame is reported as malformed,
+// since the generation was cut off.
+func (d *streamDecoder) Next() (*ChatResponse, error) {
+	if d.done {
+		return nil, io.EOF
+	}
 
-		if ollamaResp.Done {
-			break
+	var frame ChatResponse
+	if err := decodeFrame(d.decoder, &frame); err != nil {
+		if err == io.EOF {
+			return nil, &MalformedResponseError{Reason: "stream ended before the final frame"}
 		}
+		return nil, err
 	}
 
-	return nil
+	d.done = frame.Done
+	return &frame, nil
 }
 
-// TODO: this isn't right, not actually streaming. Let's fix.
-func (c *ollamaHttpClient) handleStreamingResponse(body io.Reader) (*ChatResponse, error) {
-	var fullContent strings.Builder
-	decoder := json.NewDecoder(body)
-
-	for {
-		var ollamaResp ChatResponse
-		if err := decoder.Decode(&ollamaResp); err != nil {
-			if err == io.EOF {
-				break
-			}
-			return nil, fmt.Errorf("failed to decode streaming response: %w", err)
-		}
-
-		fullContent.WriteString(ollamaResp.Message.Content)
-
-		if ollamaResp.Done {
-			break
+// decodeFrame decodes one response object, turning the {"error": "..."}
+// objects Ollama sends when a generation fails part way into an *APIError
+func decodeFrame(decoder *json.Decoder, frame *ChatResponse) error {
+	var raw json.RawMessage
+	if err := decoder.Decode(&raw); err != nil {
+		if err == io.EOF {
+			return io.EOF
 		}
+		return &MalformedResponseError{Reason: err.Error()}
 	}
 
-	return &ChatResponse{
-		Message: OllamaMessage{
-			Role:    "assistant",
-			Content: fullContent.String(),
-		},
-		Done: true,
-	}, nil
-}
+	var errFrame struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(raw, &errFrame); err == nil && errFrame.Error != "" {
+		return &APIError{StatusCode: http.StatusOK, Message: errFrame.Error}
+	}
 
-func (c *ollamaHttpClient) handleNonStreamingResponse(body io.Reader) (*ChatResponse, error) {
-	var ollamaResp ChatResponse
-	if err := json.NewDecoder(body).Decode(&ollamaResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := json.Unmarshal(raw, frame); err != nil {
+		return &MalformedResponseError{Reason: err.Error()}
 	}
 
-	return &ollamaResp, nil
+	return nil
 }
-
@@ -0,0 +1,278 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"chat-backend/internal/chat"
+)
+
+func newTestClient(t *testing.T, status int, body string) (OllamaClient, *ChatRequest) {
+	t.Helper()
+
+	var received ChatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+
+	return NewClient(server.URL, "gemma3:1b"), &received
+}
+
+func TestOllamaClient_Chat(t *testing.T) {
+	client, received := newTestClient(t, http.StatusOK, `{
+		"model": "gemma3:1b",
+		"message": {"role": "assistant", "content": "Hello!"},
+		"done": true,
+		"done_reason": "stop",
+		"prompt_eval_count": 12,
+		"eval_count": 3,
+		"total_duration": 5000000
+	}`)
+
+	resp, err := client.Chat(context.Background(), &ChatRequest{
+		Messages: []OllamaMessage{{Role: "user", Content: "Hi"}},
+		Stream:   true,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if received.Stream {
+		t.Error("Expected Chat to request a non-streaming response")
+	}
+
+	if received.Model != "gemma3:1b" {
+		t.Errorf("Expected model to be set, got '%s'", received.Model)
+	}
+
+	if resp.Message.Content != "Hello!" || resp.DoneReason != "stop" || resp.EvalCount != 3 {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+}
+
+func TestOllamaClient_ChatStream(t *testing.T) {
+	client, received := newTestClient(t, http.StatusOK,
+		`{"message": {"role": "assistant", "content": "Hel"}, "done": false}`+"\n"+
+			`{"message": {"role": "assistant", "content": "lo"}, "done": false}`+"\n"+
+			`{"message": {"role": "assistant", "content": ""}, "done": true, "done_reason": "length", "prompt_eval_count": 7, "eval_count": 2}`+"\n")
+
+	var frames []*ChatResponse
+	err := client.ChatStream(context.Background(), &ChatRequest{
+		Messages: []OllamaMessage{{Role: "user", Content: "Hi"}},
+	}, func(frame *ChatResponse) error {
+		frames = append(frames, frame)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if !received.Stream {
+		t.Error("Expected ChatStream to request streaming")
+	}
+
+	if len(frames) != 3 {
+		t.Fatalf("Expected 3 frames, got %d", len(frames))
+	}
+
+	final := frames[2]
+	if !final.Done || final.DoneReason != "length" || final.PromptEvalCount != 7 || final.EvalCount != 2 {
+		t.Errorf("Unexpected final frame: %+v", final)
+	}
+}
+
+func TestOllamaClient_StreamCutOff(t *testing.T) {
+	client, _ := newTestClient(t, http.StatusOK,
+		`{"message": {"role": "assistant", "content": "Hel"}, "done": false}`+"\n")
+
+	err := client.ChatStream(context.Background(), &ChatRequest{}, func(frame *ChatResponse) error {
+		return nil
+	})
+
+	var malformed *MalformedResponseError
+	if !errors.As(err, &malformed) {
+		t.Fatalf("Expected MalformedResponseError, got: %v", err)
+	}
+
+	if !errors.Is(err, chat.ErrMalformedResponse) {
+		t.Error("Expected error to match chat.ErrMalformedResponse")
+	}
+}
+
+func TestOllamaClient_StreamErrorFrame(t *testing.T) {
+	client, _ := newTestClient(t, http.StatusOK,
+		`{"message": {"role": "assistant", "content": "Hel"}, "done": false}`+"\n"+
+			`{"error": "an error was encountered while running the model: unexpected EOF"}`+"\n")
+
+	var content string
+	err := client.ChatStream(context.Background(), &ChatRequest{}, func(frame *ChatResponse) error {
+		content += frame.Message.Content
+		return nil
+	})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected APIError, got: %v", err)
+	}
+
+	if apiErr.Message != "an error was encountered while running the model: unexpected EOF" {
+		t.Errorf("Expected upstream message to be kept, got '%s'", apiErr.Message)
+	}
+
+	if content != "Hel" {
+		t.Errorf("Expected frames before the error to be delivered, got '%s'", content)
+	}
+}
+
+func TestOllamaClient_StreamCancelled(t *testing.T) {
+	// All frames arrive in one read, so only the context check stops them
+	client, _ := newTestClient(t, http.StatusOK,
+		`{"message": {"role": "assistant", "content": "Hel"}, "done": false}`+"\n"+
+			`{"message": {"role": "assistant", "content": "lo"}, "done": false}`+"\n"+
+			`{"message": {"role": "assistant", "content": ""}, "done": true}`+"\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	frames := 0
+	err := client.ChatStream(ctx, &ChatRequest{}, func(frame *ChatResponse) error {
+		frames++
+		cancel()
+		return nil
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the cancellation, got: %v", err)
+	}
+	if frames != 1 {
+		t.Errorf("Expected decoding to stop once cancelled, got %d frames", frames)
+	}
+}
+
+func TestOllamaClient_ErrorMapping(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   []error
+	}{
+		{
+			name:   "model not found",
+			status: http.StatusNotFound,
+			body:   `{"error": "model \"gemma3:1b\" not found, try pulling it first"}`,
+			want:   []error{ErrModelNotFound, chat.ErrProviderUnavailable},
+		},
+		{
+			name:   "context too long",
+			status: http.StatusBadRequest,
+			body:   `{"error": "input length exceeds the context length"}`,
+			want:   []error{chat.ErrContextLength},
+		},
+		{
+			name:   "bad request",
+			status: http.StatusBadRequest,
+			body:   `{"error": "invalid message format"}`,
+			want:   []error{chat.ErrInvalidRequest},
+		},
+		{
+			name:   "server error without json",
+			status: http.StatusInternalServerError,
+			body:   "llama runner process has terminated",
+			want:   []error{chat.ErrProviderUnavailable},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := newTestClient(t, tt.status, tt.body)
+
+			_, err := client.Chat(context.Background(), &ChatRequest{})
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Expected APIError, got: %v", err)
+			}
+
+			if apiErr.StatusCode != tt.status || apiErr.Message == "" {
+				t.Errorf("Expected status %d with a message, got %+v", tt.status, apiErr)
+			}
+
+			for _, want := range tt.want {
+				if !errors.Is(err, want) {
+					t.Errorf("Expected error to match %v, got: %v", want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestOllamaClient_Unreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	client := NewClient(server.URL, "gemma3:1b")
+
+	_, err := client.Chat(context.Background(), &ChatRequest{})
+	if !errors.Is(err, chat.ErrProviderUnavailable) {
+		t.Errorf("Expected ErrProviderUnavailable, got: %v", err)
+	}
+}
+
+func TestOllamaClient_CancelledBeforeSending(t *testing.T) {
+	client, _ := newTestClient(t, http.StatusOK, `{"message": {"role": "assistant", "content": "Hi"}, "done": true}`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.Chat(ctx, &ChatRequest{})
+	if !errors.Is(err, context.Canceled) || errors.Is(err, chat.ErrProviderUnavailable) {
+		t.Errorf("Expected the cancellation to pass through as is, got: %v", err)
+	}
+}
+
+func TestEmbedder_Embed(t *testing.T) {
+	var received EmbedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/embed" {
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+		fmt.Fprint(w, `{"model": "nomic-embed-text", "embeddings": [[0.1, 0.2, 0.3]]}`)
+	}))
+	defer server.Close()
+
+	vector, err := NewEmbedder(server.URL, "").Embed(context.Background(), "password reset?")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if received.Model != defaultEmbeddingModel || len(received.Input) != 1 || received.Input[0] != "password reset?" {
+		t.Errorf("Unexpected request: %+v", received)
+	}
+	if len(vector) != 3 || vector[2] != 0.3 {
+		t.Errorf("Unexpected embedding: %v", vector)
+	}
+}
+
+func TestEmbedder_EmptyResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"embeddings": []}`)
+	}))
+	defer server.Close()
+
+	_, err := NewEmbedder(server.URL, "").Embed(context.Background(), "hi")
+	if !errors.Is(err, chat.ErrMalformedResponse) {
+		t.Errorf("Expected ErrMalformedResponse, got: %v", err)
+	}
+}
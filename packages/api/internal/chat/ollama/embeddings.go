@@ -0,0 +1,66 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+const defaultEmbeddingModel = "nomic-embed-text"
+
+type EmbedRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type EmbedResponse struct {
+	Model      string      `json:"model"`
+	Embeddings [][]float32 `json:"embeddings"`
+}
+
+// Embedder turns text into vectors with an Ollama embedding model, through
+// the same request pipeline as chat
+type Embedder struct {
+	client *ollamaHttpClient
+}
+
+func NewEmbedder(baseURL, model string) *Embedder {
+	if baseURL == "" {
+		baseURL = "http://localhost:11434"
+	}
+	if model == "" {
+		model = defaultEmbeddingModel
+	}
+
+	return &Embedder{
+		client: &ollamaHttpClient{
+			baseURL:    baseURL,
+			model:      model,
+			httpClient: &http.Client{},
+		},
+	}
+}
+
+func (e *Embedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	body, err := e.client.post(ctx, "/api/embed", &EmbedRequest{
+		Model: e.client.model,
+		Input: []string{text},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var resp EmbedResponse
+	if err := json.NewDecoder(body).Decode(&resp); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, &MalformedResponseError{Reason: err.Error()}
+	}
+
+	if len(resp.Embeddings) == 0 || len(resp.Embeddings[0]) == 0 {
+		return nil, &MalformedResponseError{Reason: "no embedding returned"}
+	}
+	return resp.Embeddings[0], nil
+}
@@ -0,0 +1,84 @@
+package ollama
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"chat-backend/internal/chat"
+)
+
+var ErrModelNotFound = errors.New("ollama model not found")
+
+// APIError is an error reported by Ollama, either as a non-200 response or
+// as an error frame in the middle of a stream
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("ollama API returned status %d: %s", e.StatusCode, e.Message)
+}
+
+// Unwrap classifies the error by status and message, since Ollama reports
+// most failures as plain strings
+func (e *APIError) Unwrap() []error {
+	message := strings.ToLower(e.Message)
+
+	switch {
+	case e.StatusCode == http.StatusNotFound, strings.Contains(message, "not found, try pulling"):
+		// The model is configured server side, so a missing model is an
+		// outage from the client's point of view
+		return []error{ErrModelNotFound, chat.ErrProviderUnavailable}
+	case strings.Contains(message, "context length"), strings.Contains(message, "context window"):
+		return []error{chat.ErrContextLength}
+	}
+	if kind := chat.StatusKind(e.StatusCode); kind != nil {
+		return []error{kind}
+	}
+	return nil
+}
+
+// MalformedResponseError is a response body that could not be decoded, or a
+// stream that ended before the final frame
+type MalformedResponseError struct {
+	Reason string
+}
+
+func (e *MalformedResponseError) Error() string {
+	return fmt.Sprintf("malformed ollama response: %s", e.Reason)
+}
+
+func (e *MalformedResponseError) Unwrap() error {
+	return chat.ErrMalformedResponse
+}
+
+func parseErrorResponse(resp *http.Response, model string) error {
+	body, _ := io.ReadAll(resp.Body)
+
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Message:    strings.TrimSpace(string(body)),
+	}
+
+	var errResp struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error != "" {
+		apiErr.Message = errResp.Error
+	}
+
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+
+	if resp.StatusCode == http.StatusNotFound && !strings.Contains(apiErr.Message, model) {
+		apiErr.Message = fmt.Sprintf("%s (model %q)", apiErr.Message, model)
+	}
+
+	return apiErr
+}
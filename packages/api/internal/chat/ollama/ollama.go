@@ -2,6 +2,7 @@ package ollama
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 
 	"chat-backend/internal/chat"
@@ -9,6 +10,7 @@ import (
 
 type OllamaChatProvider struct {
 	client OllamaClient
+	numCtx int
 }
 
 func NewOllamaChatProvider(baseURL, model string) *OllamaChatProvider {
@@ -17,22 +19,34 @@ func NewOllamaChatProvider(baseURL, model string) *OllamaChatProvider {
 	}
 }
 
+// WithContextLength sets the context size Ollama runs the model with, in
+// tokens. It should match the size conversations are trimmed to.
+func (p *OllamaChatProvider) WithContextLength(tokens int) *OllamaChatProvider {
+	p.numCtx = tokens
+	return p
+}
+
+// options returns the model parameters of each request
+func (p *OllamaChatProvider) options() *Options {
+	if p.numCtx <= 0 {
+		return nil
+	}
+	return &Options{NumCtx: p.numCtx}
+}
+
 func (p *OllamaChatProvider) Chat(ctx context.Context, req *chat.ChatRequest) (*chat.ChatResponse, error) {
 	if len(req.Messages) == 0 {
-		return nil, fmt.Errorf("no messages provided")
+		return nil, chat.Errorf(chat.ErrInvalidRequest, "no messages provided")
 	}
 
-	ollamaMessages := make([]OllamaMessage, len(req.Messages))
-	for i, msg := range req.Messages {
-		ollamaMessages[i] = OllamaMessage{
-			Role:    msg.Role,
-			Content: msg.Content,
-		}
+	ollamaMessages, err := toOllamaMessages(req.Messages)
+	if err != nil {
+		return nil, err
 	}
 
 	ollamaReq := &ChatRequest{
 		Messages: ollamaMessages,
-		Stream:   req.Streaming,
+		Options:  p.options(),
 	}
 
 	ollamaResp, err := p.client.Chat(ctx, ollamaReq)
@@ -41,35 +55,74 @@ func (p *OllamaChatProvider) Chat(ctx context.Context, req *chat.ChatRequest) (*
 	}
 
 	return &chat.ChatResponse{
-		Content: ollamaResp.Message.Content,
+		Content:      ollamaResp.Message.Content,
+		Usage:        toChatUsage(ollamaResp),
+		FinishReason: ollamaResp.DoneReason,
 	}, nil
 }
 
 func (p *OllamaChatProvider) ChatStream(ctx context.Context, req *chat.ChatRequest, callback chat.StreamCallback) error {
 	if len(req.Messages) == 0 {
-		return fmt.Errorf("no messages provided")
+		return chat.Errorf(chat.ErrInvalidRequest, "no messages provided")
 	}
 
-	ollamaMessages := make([]OllamaMessage, len(req.Messages))
-	for i, msg := range req.Messages {
-		ollamaMessages[i] = OllamaMessage{
-			Role:    msg.Role,
-			Content: msg.Content,
-		}
+	ollamaMessages, err := toOllamaMessages(req.Messages)
+	if err != nil {
+		return err
 	}
 
 	ollamaReq := &ChatRequest{
 		Messages: ollamaMessages,
 		Stream:   true,
+		Options:  p.options(),
 	}
 
-	// Create a callback that converts ollama responses to chat responses
+	// Create a callback that converts ollama responses to chat responses.
+	// The final frame carries the statistics, which become the usage.
 	ollamaCallback := func(ollamaResp *ChatResponse) error {
 		chatResp := &chat.ChatResponse{
 			Content: ollamaResp.Message.Content,
 		}
+		if ollamaResp.Done {
+			chatResp.Usage = toChatUsage(ollamaResp)
+			chatResp.FinishReason = ollamaResp.DoneReason
+		} else if chatResp.Content == "" {
+			return nil
+		}
 		return callback(chatResp)
 	}
 
 	return p.client.ChatStream(ctx, ollamaReq, ollamaCallback)
-}
\ No newline at end of file
+}
+
+// toOllamaMessages converts chat messages to Ollama's format. Image
+// attachments go in the images field, which vision models such as llava
+// read; any other attachment type is rejected.
+func toOllamaMessages(messages []chat.Message) ([]OllamaMessage, error) {
+	ollamaMessages := make([]OllamaMessage, len(messages))
+	for i, msg := range messages {
+		ollamaMessages[i] = OllamaMessage{
+			Role:    msg.Role,
+			Content: msg.Content,
+		}
+
+		for _, attachment := range msg.Attachments {
+			if !attachment.IsImage() {
+				return nil, fmt.Errorf("%s: %w", attachment.MimeType, chat.ErrAttachmentsNotSupported)
+			}
+			ollamaMessages[i].Images = append(ollamaMessages[i].Images, base64.StdEncoding.EncodeToString(attachment.Data))
+		}
+	}
+	return ollamaMessages, nil
+}
+
+func toChatUsage(resp *ChatResponse) *chat.Usage {
+	if !resp.Done {
+		return nil
+	}
+	return &chat.Usage{
+		PromptTokens:     resp.PromptEvalCount,
+		CompletionTokens: resp.EvalCount,
+		TotalTokens:      resp.PromptEvalCount + resp.EvalCount,
+	}
+}
@@ -0,0 +1,138 @@
+package ollama
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"chat-backend/internal/chat"
+)
+
+type mockOllamaClient struct {
+	response    *ChatResponse
+	err         error
+	lastRequest *ChatRequest
+}
+
+func (m *mockOllamaClient) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	m.lastRequest = req
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.response, nil
+}
+
+func (m *mockOllamaClient) ChatStream(ctx context.Context, req *ChatRequest, callback StreamCallback) error {
+	m.lastRequest = req
+	if m.err != nil {
+		return m.err
+	}
+	return callback(m.response)
+}
+
+func TestOllamaChatProvider_SendsImages(t *testing.T) {
+	mockClient := &mockOllamaClient{
+		response: &ChatResponse{Message: OllamaMessage{Role: "assistant", Content: "A cat"}, Done: true},
+	}
+	provider := &OllamaChatProvider{client: mockClient}
+
+	image := []byte("\x89PNG\r\n\x1a\nimage-bytes")
+	_, err := provider.Chat(context.Background(), &chat.ChatRequest{
+		Messages: []chat.Message{{
+			Role:        "user",
+			Content:     "What is this?",
+			Attachments: []chat.Attachment{{MimeType: "image/png", Data: image}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	images := mockClient.lastRequest.Messages[0].Images
+	if len(images) != 1 {
+		t.Fatalf("Expected one image, got %d", len(images))
+	}
+
+	if images[0] != base64.StdEncoding.EncodeToString(image) {
+		t.Errorf("Expected base64 encoded image, got '%s'", images[0])
+	}
+}
+
+func TestOllamaChatProvider_RejectsNonImageAttachments(t *testing.T) {
+	mockClient := &mockOllamaClient{}
+	provider := &OllamaChatProvider{client: mockClient}
+
+	err := provider.ChatStream(context.Background(), &chat.ChatRequest{
+		Messages: []chat.Message{{
+			Role:        "user",
+			Content:     "Summarise this",
+			Attachments: []chat.Attachment{{MimeType: "application/pdf", Data: []byte("%PDF-1.7")}},
+		}},
+	}, func(chunk *chat.ChatResponse) error { return nil })
+
+	if !errors.Is(err, chat.ErrAttachmentsNotSupported) {
+		t.Errorf("Expected ErrAttachmentsNotSupported, got: %v", err)
+	}
+
+	if mockClient.lastRequest != nil {
+		t.Error("Expected the request not to reach the client")
+	}
+}
+
+func TestOllamaChatProvider_ChatMapsUsage(t *testing.T) {
+	mockClient := &mockOllamaClient{
+		response: &ChatResponse{
+			Message:         OllamaMessage{Role: "assistant", Content: "Hi"},
+			Done:            true,
+			DoneReason:      "stop",
+			PromptEvalCount: 10,
+			EvalCount:       4,
+		},
+	}
+	provider := &OllamaChatProvider{client: mockClient}
+
+	resp, err := provider.Chat(context.Background(), &chat.ChatRequest{
+		Messages: []chat.Message{{Role: "user", Content: "Hello"}},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if resp.Usage == nil || resp.Usage.PromptTokens != 10 || resp.Usage.CompletionTokens != 4 || resp.Usage.TotalTokens != 14 {
+		t.Errorf("Unexpected usage: %+v", resp.Usage)
+	}
+
+	if resp.FinishReason != "stop" {
+		t.Errorf("Expected finish reason 'stop', got '%s'", resp.FinishReason)
+	}
+}
+
+func TestOllamaChatProvider_SendsContextLength(t *testing.T) {
+	mockClient := &mockOllamaClient{response: &ChatResponse{Done: true}}
+	provider := &OllamaChatProvider{client: mockClient}
+
+	req := &chat.ChatRequest{Messages: []chat.Message{{Role: "user", Content: "Hi"}}}
+	if _, err := provider.Chat(context.Background(), req); err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+	if mockClient.lastRequest.Options != nil {
+		t.Errorf("Expected no options without a context length, got %+v", mockClient.lastRequest.Options)
+	}
+
+	provider.WithContextLength(32768)
+	for _, stream := range []bool{false, true} {
+		var err error
+		if stream {
+			err = provider.ChatStream(context.Background(), req, func(*chat.ChatResponse) error { return nil })
+		} else {
+			_, err = provider.Chat(context.Background(), req)
+		}
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		if options := mockClient.lastRequest.Options; options == nil || options.NumCtx != 32768 {
+			t.Errorf("Expected num_ctx 32768 (stream=%v), got %+v", stream, options)
+		}
+	}
+}
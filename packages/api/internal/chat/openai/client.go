@@ -0,0 +1,321 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"chat-backend/internal/chat"
+	"chat-backend/internal/chat/sse"
+)
+
+const defaultBaseURL = "https://api.openai.com/v1"
+
+type StreamCallback func(chunk *ChatCompletionChunk) error
+
+type OpenAIClient interface {
+	ChatCompletion(ctx context.Context, req *ChatCompletionRequest) (*ChatCompletionResponse, error)
+	ChatCompletionStream(ctx context.Context, req *ChatCompletionRequest, callback StreamCallback) error
+}
+
+// Config holds the settings for an OpenAI-compatible server such as
+// llama.cpp server, vLLM or LM Studio. BaseURL includes the version prefix,
+// e.g. http://localhost:8000/v1. APIKey is optional for local runtimes.
+type Config struct {
+	BaseURL string
+	Model   string
+	APIKey  string
+	// AuthHeader carries the API key as is, e.g. Azure's api-key, instead
+	// of as an Authorization bearer token
+	AuthHeader string
+	// Query is added to the completions URL, e.g. Azure's api-version
+	Query   url.Values
+	Headers map[string]string
+	// Timeout bounds a whole answer, but only the wait for the response
+	// headers of a stream, which may run for longer
+	Timeout time.Duration
+}
+
+type openAIHttpClient struct {
+	config     Config
+	httpClient *http.Client
+}
+
+type ChatMessage struct {
+	Role       string     `json:"role"`
+	Content    string     `json:"content"`
+	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string     `json:"tool_call_id,omitempty"`
+}
+
+type Tool struct {
+	Type     string       `json:"type"`
+	Function FunctionSpec `json:"function"`
+}
+
+type FunctionSpec struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+}
+
+type ToolCall struct {
+	Index    int          `json:"index"`
+	ID       string       `json:"id,omitempty"`
+	Type     string       `json:"type,omitempty"`
+	Function FunctionCall `json:"function"`
+}
+
+type FunctionCall struct {
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments"`
+}
+
+type StreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+type ChatCompletionRequest struct {
+	Model         string         `json:"model,omitempty"`
+	Messages      []ChatMessage  `json:"messages"`
+	Tools         []Tool         `json:"tools,omitempty"`
+	Stream        bool           `json:"stream,omitempty"`
+	StreamOptions *StreamOptions `json:"stream_options,omitempty"`
+}
+
+type ChatCompletionResponse struct {
+	ID      string   `json:"id"`
+	Model   string   `json:"model"`
+	Choices []Choice `json:"choices"`
+	Usage   *Usage   `json:"usage,omitempty"`
+}
+
+type ChatCompletionChunk struct {
+	ID      string        `json:"id"`
+	Model   string        `json:"model"`
+	Choices []ChunkChoice `json:"choices"`
+	Usage   *Usage        `json:"usage,omitempty"`
+}
+
+// Choice is a completion. Servers with a content filter, such as Azure
+// OpenAI, say what it found in ContentFilterResults.
+type Choice struct {
+	Index                int             `json:"index"`
+	Message              ChatMessage     `json:"message"`
+	FinishReason         string          `json:"finish_reason"`
+	ContentFilterResults json.RawMessage `json:"content_filter_results,omitempty"`
+}
+
+type ChunkChoice struct {
+	Index                int             `json:"index"`
+	Delta                ChatMessage     `json:"delta"`
+	FinishReason         string          `json:"finish_reason"`
+	ContentFilterResults json.RawMessage `json:"content_filter_results,omitempty"`
+}
+
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// APIError is a non-200 response from the upstream server. InnerError
+// holds the details some servers add, such as Azure's content filter
+// results.
+type APIError struct {
+	StatusCode int
+	Type       string
+	Code       string
+	Message    string
+	InnerError json.RawMessage
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("openai API returned status %d: %s", e.StatusCode, e.Message)
+}
+
+func (e *APIError) Unwrap() error {
+	if e.Code == "context_length_exceeded" {
+		return chat.ErrContextLength
+	}
+	return chat.StatusKind(e.StatusCode)
+}
+
+// ContentFilterError reports that the server's content filter cut a
+// completion short, with the results it sent
+type ContentFilterError struct {
+	Results json.RawMessage
+}
+
+func (e *ContentFilterError) Error() string {
+	return "completion stopped by content filter"
+}
+
+func (e *ContentFilterError) Unwrap() error {
+	return chat.ErrContentFiltered
+}
+
+type errorResponse struct {
+	Error struct {
+		Message    string          `json:"message"`
+		Type       string          `json:"type"`
+		Code       any             `json:"code"`
+		InnerError json.RawMessage `json:"innererror,omitempty"`
+	} `json:"error"`
+}
+
+func NewClient(config Config) OpenAIClient {
+	if config.BaseURL == "" {
+		config.BaseURL = defaultBaseURL
+	}
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+
+	// http.Client.Timeout would also cut streams off part way, so the
+	// timeout is applied per request instead
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = config.Timeout
+
+	return &openAIHttpClient{
+		config:     config,
+		httpClient: &http.Client{Transport: transport},
+	}
+}
+
+func (c *openAIHttpClient) ChatCompletion(ctx context.Context, req *ChatCompletionRequest) (*ChatCompletionResponse, error) {
+	req.Stream = false
+	req.StreamOptions = nil
+
+	if c.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.config.Timeout)
+		defer cancel()
+	}
+
+	resp, err := c.post(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var completion ChatCompletionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&completion); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, chat.TransportError(ctxErr)
+		}
+		return nil, chat.Errorf(chat.ErrMalformedResponse, "failed to decode response: %w", err)
+	}
+
+	return &completion, nil
+}
+
+func (c *openAIHttpClient) ChatCompletionStream(ctx context.Context, req *ChatCompletionRequest, callback StreamCallback) error {
+	req.Stream = true
+	req.StreamOptions = &StreamOptions{IncludeUsage: true}
+
+	resp, err := c.post(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	reader := sse.NewReader(resp.Body)
+	for {
+		event, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return chat.TransportError(fmt.Errorf("failed to read stream: %w", err))
+		}
+
+		if event.Data == "[DONE]" {
+			return nil
+		}
+
+		var chunk ChatCompletionChunk
+		if err := json.Unmarshal([]byte(event.Data), &chunk); err != nil {
+			return chat.Errorf(chat.ErrMalformedResponse, "failed to decode stream chunk: %w", err)
+		}
+
+		if err := callback(&chunk); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *openAIHttpClient) post(ctx context.Context, req *ChatCompletionRequest) (*http.Response, error) {
+	if req.Model == "" {
+		req.Model = c.config.Model
+	}
+
+	reqBody, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/chat/completions", c.config.BaseURL)
+	if len(c.config.Query) > 0 {
+		url += "?" + c.config.Query.Encode()
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	for name, value := range c.config.Headers {
+		httpReq.Header.Set(name, value)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	switch {
+	case c.config.APIKey == "":
+	case c.config.AuthHeader != "":
+		httpReq.Header.Set(c.config.AuthHeader, c.config.APIKey)
+	default:
+		httpReq.Header.Set("Authorization", "Bearer "+c.config.APIKey)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, chat.TransportError(fmt.Errorf("failed to send request: %w", err))
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, parseErrorResponse(resp)
+	}
+
+	return resp, nil
+}
+
+func parseErrorResponse(resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Message:    http.StatusText(resp.StatusCode),
+	}
+
+	var errResp errorResponse
+	if err := json.Unmarshal(body, &errResp); err != nil || errResp.Error.Message == "" {
+		slog.Error("OpenAI-compatible server returned unparseable error", "status", resp.StatusCode, "body", string(body))
+		return apiErr
+	}
+
+	apiErr.Type = errResp.Error.Type
+	apiErr.Message = errResp.Error.Message
+	apiErr.InnerError = errResp.Error.InnerError
+	// Some servers send the code as a number, others as a string
+	if errResp.Error.Code != nil {
+		apiErr.Code = fmt.Sprint(errResp.Error.Code)
+	}
+
+	return apiErr
+}
@@ -0,0 +1,183 @@
+package openai
+
+import (
+	"context"
+	"sort"
+
+	"chat-backend/internal/chat"
+)
+
+type OpenAIChatProvider struct {
+	client OpenAIClient
+}
+
+func NewOpenAIChatProvider(config Config) *OpenAIChatProvider {
+	return &OpenAIChatProvider{
+		client: NewClient(config),
+	}
+}
+
+func (p *OpenAIChatProvider) Chat(ctx context.Context, req *chat.ChatRequest) (*chat.ChatResponse, error) {
+	if len(req.Messages) == 0 {
+		return nil, chat.Errorf(chat.ErrInvalidRequest, "no messages provided")
+	}
+
+	if err := chat.RejectAttachments(req); err != nil {
+		return nil, err
+	}
+
+	completion, err := p.client.ChatCompletion(ctx, toCompletionRequest(req))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(completion.Choices) == 0 {
+		return nil, chat.Errorf(chat.ErrMalformedResponse, "completion returned no choices")
+	}
+
+	choice := completion.Choices[0]
+	if choice.FinishReason == "content_filter" {
+		return nil, &ContentFilterError{Results: choice.ContentFilterResults}
+	}
+
+	message := choice.Message
+	return &chat.ChatResponse{
+		Content:   message.Content,
+		Usage:     toChatUsage(completion.Usage),
+		ToolCalls: toChatToolCalls(message.ToolCalls),
+	}, nil
+}
+
+func (p *OpenAIChatProvider) ChatStream(ctx context.Context, req *chat.ChatRequest, callback chat.StreamCallback) error {
+	if len(req.Messages) == 0 {
+		return chat.Errorf(chat.ErrInvalidRequest, "no messages provided")
+	}
+
+	if err := chat.RejectAttachments(req); err != nil {
+		return err
+	}
+
+	// Tool calls arrive as fragments keyed by index, with the arguments
+	// spread over many chunks, so they are assembled and sent at the end
+	toolCalls := map[int]*ToolCall{}
+
+	// Create a callback that converts completion chunks to chat responses
+	chunkCallback := func(chunk *ChatCompletionChunk) error {
+		if len(chunk.Choices) == 0 {
+			if chunk.Usage != nil {
+				return callback(&chat.ChatResponse{Usage: toChatUsage(chunk.Usage)})
+			}
+			return nil
+		}
+
+		choice := chunk.Choices[0]
+		if choice.FinishReason == "content_filter" {
+			return &ContentFilterError{Results: choice.ContentFilterResults}
+		}
+
+		delta := choice.Delta
+		for _, fragment := range delta.ToolCalls {
+			call, ok := toolCalls[fragment.Index]
+			if !ok {
+				call = &ToolCall{Index: fragment.Index}
+				toolCalls[fragment.Index] = call
+			}
+			if fragment.ID != "" {
+				call.ID = fragment.ID
+			}
+			if fragment.Function.Name != "" {
+				call.Function.Name = fragment.Function.Name
+			}
+			call.Function.Arguments += fragment.Function.Arguments
+		}
+
+		if delta.Content == "" {
+			return nil
+		}
+
+		return callback(&chat.ChatResponse{Content: delta.Content})
+	}
+
+	if err := p.client.ChatCompletionStream(ctx, toCompletionRequest(req), chunkCallback); err != nil {
+		return err
+	}
+
+	if len(toolCalls) == 0 {
+		return nil
+	}
+
+	indexes := make([]int, 0, len(toolCalls))
+	for index := range toolCalls {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+
+	calls := make([]ToolCall, len(indexes))
+	for i, index := range indexes {
+		calls[i] = *toolCalls[index]
+	}
+
+	return callback(&chat.ChatResponse{ToolCalls: toChatToolCalls(calls)})
+}
+
+func toCompletionRequest(req *chat.ChatRequest) *ChatCompletionRequest {
+	messages := make([]ChatMessage, len(req.Messages))
+	for i, msg := range req.Messages {
+		messages[i] = ChatMessage{
+			Role:       msg.Role,
+			Content:    msg.Content,
+			ToolCallID: msg.ToolCallID,
+		}
+		for j, call := range msg.ToolCalls {
+			messages[i].ToolCalls = append(messages[i].ToolCalls, ToolCall{
+				Index: j,
+				ID:    call.ID,
+				Type:  "function",
+				Function: FunctionCall{
+					Name:      call.Name,
+					Arguments: call.Arguments,
+				},
+			})
+		}
+	}
+
+	var tools []Tool
+	for _, tool := range req.Tools {
+		tools = append(tools, Tool{
+			Type: "function",
+			Function: FunctionSpec{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  tool.Parameters,
+			},
+		})
+	}
+
+	return &ChatCompletionRequest{
+		Messages: messages,
+		Tools:    tools,
+	}
+}
+
+func toChatToolCalls(calls []ToolCall) []chat.ToolCall {
+	var toolCalls []chat.ToolCall
+	for _, call := range calls {
+		toolCalls = append(toolCalls, chat.ToolCall{
+			ID:        call.ID,
+			Name:      call.Function.Name,
+			Arguments: call.Function.Arguments,
+		})
+	}
+	return toolCalls
+}
+
+func toChatUsage(usage *Usage) *chat.Usage {
+	if usage == nil {
+		return nil
+	}
+	return &chat.Usage{
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+	}
+}
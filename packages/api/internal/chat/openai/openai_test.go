@@ -0,0 +1,272 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"chat-backend/internal/chat"
+)
+
+// fakeOpenAI serves canned chat completion responses and records the last
+// request it received
+type fakeOpenAI struct {
+	t           *testing.T
+	status      int
+	body        string
+	delay       time.Duration
+	lastPath    string
+	lastHeaders http.Header
+	lastRequest ChatCompletionRequest
+}
+
+func (f *fakeOpenAI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lastPath = r.URL.Path
+	f.lastHeaders = r.Header.Clone()
+	if err := json.NewDecoder(r.Body).Decode(&f.lastRequest); err != nil {
+		f.t.Errorf("Failed to decode request: %v", err)
+	}
+
+	if f.delay > 0 {
+		time.Sleep(f.delay)
+	}
+
+	w.WriteHeader(f.status)
+	fmt.Fprint(w, f.body)
+}
+
+func newTestProvider(t *testing.T, fake *fakeOpenAI, config Config) *OpenAIChatProvider {
+	t.Helper()
+
+	fake.t = t
+	if fake.status == 0 {
+		fake.status = http.StatusOK
+	}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	config.BaseURL = server.URL + "/v1"
+	if config.Model == "" {
+		config.Model = "local-model"
+	}
+	return NewOpenAIChatProvider(config)
+}
+
+func userRequest(content string) *chat.ChatRequest {
+	return &chat.ChatRequest{
+		Messages: []chat.Message{{Role: "user", Content: content}},
+	}
+}
+
+func TestOpenAIChatProvider_Chat(t *testing.T) {
+	fake := &fakeOpenAI{
+		body: `{
+			"id": "chatcmpl-1",
+			"model": "local-model",
+			"choices": [{"index": 0, "finish_reason": "stop", "message": {"role": "assistant", "content": "Hi!"}}],
+			"usage": {"prompt_tokens": 3, "completion_tokens": 1, "total_tokens": 4}
+		}`,
+	}
+	provider := newTestProvider(t, fake, Config{
+		APIKey:  "sk-test",
+		Headers: map[string]string{"X-Org": "acme"},
+	})
+
+	resp, err := provider.Chat(context.Background(), userRequest("Hello"))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if resp.Content != "Hi!" {
+		t.Errorf("Expected 'Hi!', got '%s'", resp.Content)
+	}
+
+	if resp.Usage == nil || resp.Usage.TotalTokens != 4 {
+		t.Errorf("Expected usage to be mapped, got %+v", resp.Usage)
+	}
+
+	if fake.lastPath != "/v1/chat/completions" {
+		t.Errorf("Unexpected path: %s", fake.lastPath)
+	}
+
+	if fake.lastRequest.Model != "local-model" {
+		t.Errorf("Expected model to be sent, got '%s'", fake.lastRequest.Model)
+	}
+
+	if fake.lastHeaders.Get("Authorization") != "Bearer sk-test" {
+		t.Errorf("Expected bearer auth header, got '%s'", fake.lastHeaders.Get("Authorization"))
+	}
+
+	if fake.lastHeaders.Get("X-Org") != "acme" {
+		t.Errorf("Expected custom header to be sent")
+	}
+}
+
+func TestOpenAIChatProvider_NoAPIKey(t *testing.T) {
+	fake := &fakeOpenAI{
+		body: `{"choices": [{"message": {"role": "assistant", "content": "ok"}}]}`,
+	}
+	provider := newTestProvider(t, fake, Config{})
+
+	if _, err := provider.Chat(context.Background(), userRequest("Hello")); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if fake.lastHeaders.Get("Authorization") != "" {
+		t.Error("Expected no Authorization header without an API key")
+	}
+}
+
+func TestOpenAIChatProvider_Tools(t *testing.T) {
+	fake := &fakeOpenAI{
+		body: `{"choices": [{"finish_reason": "tool_calls", "message": {
+			"role": "assistant",
+			"content": "",
+			"tool_calls": [{"id": "call_1", "type": "function", "function": {"name": "get_weather", "arguments": "{\"city\":\"Paris\"}"}}]
+		}}]}`,
+	}
+	provider := newTestProvider(t, fake, Config{})
+
+	req := userRequest("Weather in Paris?")
+	req.Tools = []chat.Tool{{
+		Name:        "get_weather",
+		Description: "Look up the weather",
+		Parameters:  json.RawMessage(`{"type":"object","properties":{"city":{"type":"string"}}}`),
+	}}
+
+	resp, err := provider.Chat(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(fake.lastRequest.Tools) != 1 || fake.lastRequest.Tools[0].Type != "function" || fake.lastRequest.Tools[0].Function.Name != "get_weather" {
+		t.Errorf("Expected tool to be sent as a function, got %+v", fake.lastRequest.Tools)
+	}
+
+	if len(resp.ToolCalls) != 1 {
+		t.Fatalf("Expected 1 tool call, got %d", len(resp.ToolCalls))
+	}
+
+	call := resp.ToolCalls[0]
+	if call.ID != "call_1" || call.Name != "get_weather" || call.Arguments != `{"city":"Paris"}` {
+		t.Errorf("Unexpected tool call: %+v", call)
+	}
+}
+
+func TestOpenAIChatProvider_ChatStream(t *testing.T) {
+	fake := &fakeOpenAI{
+		body: "data: {\"choices\":[{\"index\":0,\"delta\":{\"role\":\"assistant\",\"content\":\"Let me \"}}]}\n\n" +
+			": keepalive\n\n" +
+			"data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"check.\"}}]}\n\n" +
+			"data: {\"choices\":[{\"index\":0,\"delta\":{\"tool_calls\":[{\"index\":0,\"id\":\"call_1\",\"type\":\"function\",\"function\":{\"name\":\"get_weather\",\"arguments\":\"\"}}]}}]}\n\n" +
+			"data: {\"choices\":[{\"index\":0,\"delta\":{\"tool_calls\":[{\"index\":0,\"function\":{\"arguments\":\"{\\\"city\\\":\"}}]}}]}\n\n" +
+			"data: {\"choices\":[{\"index\":0,\"delta\":{\"tool_calls\":[{\"index\":0,\"function\":{\"arguments\":\"\\\"Paris\\\"}\"}}]}}]}\n\n" +
+			"data: {\"choices\":[{\"index\":0,\"delta\":{},\"finish_reason\":\"tool_calls\"}]}\n\n" +
+			"data: {\"choices\":[],\"usage\":{\"prompt_tokens\":10,\"completion_tokens\":5,\"total_tokens\":15}}\n\n" +
+			"data: [DONE]\n\n",
+	}
+	provider := newTestProvider(t, fake, Config{})
+
+	var content string
+	var usage *chat.Usage
+	var toolCalls []chat.ToolCall
+	err := provider.ChatStream(context.Background(), userRequest("Weather in Paris?"), func(chunk *chat.ChatResponse) error {
+		content += chunk.Content
+		if chunk.Usage != nil {
+			usage = chunk.Usage
+		}
+		toolCalls = append(toolCalls, chunk.ToolCalls...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if content != "Let me check." {
+		t.Errorf("Expected 'Let me check.', got '%s'", content)
+	}
+
+	if usage == nil || usage.TotalTokens != 15 {
+		t.Errorf("Expected usage, got %+v", usage)
+	}
+
+	if len(toolCalls) != 1 || toolCalls[0].Arguments != `{"city":"Paris"}` || toolCalls[0].ID != "call_1" {
+		t.Errorf("Expected assembled tool call, got %+v", toolCalls)
+	}
+}
+
+func TestOpenAIChatProvider_APIError(t *testing.T) {
+	fake := &fakeOpenAI{
+		status: http.StatusNotFound,
+		body:   `{"error": {"message": "model 'missing' not found", "type": "invalid_request_error", "code": 404}}`,
+	}
+	provider := newTestProvider(t, fake, Config{})
+
+	_, err := provider.Chat(context.Background(), userRequest("Hello"))
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected APIError, got: %v", err)
+	}
+
+	if apiErr.Message != "model 'missing' not found" || apiErr.Code != "404" {
+		t.Errorf("Unexpected API error: %+v", apiErr)
+	}
+
+	if !errors.Is(err, chat.ErrInvalidRequest) {
+		t.Error("Expected 404 to match chat.ErrInvalidRequest")
+	}
+}
+
+func TestOpenAIChatProvider_Timeout(t *testing.T) {
+	fake := &fakeOpenAI{
+		delay: 100 * time.Millisecond,
+		body:  `{"choices": [{"message": {"role": "assistant", "content": "late"}}]}`,
+	}
+	provider := newTestProvider(t, fake, Config{Timeout: 20 * time.Millisecond})
+
+	_, err := provider.Chat(context.Background(), userRequest("Hello"))
+	if !errors.Is(err, chat.ErrTimeout) {
+		t.Fatalf("Expected timeout error, got %v", err)
+	}
+}
+
+func TestOpenAIChatProvider_StreamOutlivesTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, word := range []string{"Hello", " world"} {
+			fmt.Fprintf(w, "data: {\"choices\": [{\"delta\": {\"content\": %q}}]}\n\n", word)
+			w.(http.Flusher).Flush()
+			time.Sleep(50 * time.Millisecond)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIChatProvider(Config{BaseURL: server.URL, Model: "local-model", Timeout: 20 * time.Millisecond})
+
+	var content strings.Builder
+	err := provider.ChatStream(context.Background(), userRequest("Hi"), func(chunk *chat.ChatResponse) error {
+		content.WriteString(chunk.Content)
+		return nil
+	})
+	if err != nil || content.String() != "Hello world" {
+		t.Errorf("Expected the whole stream despite the timeout, got %q, %v", content.String(), err)
+	}
+}
+
+func TestOpenAIChatProvider_StreamHeaderTimeout(t *testing.T) {
+	fake := &fakeOpenAI{delay: 100 * time.Millisecond, body: "data: [DONE]\n\n"}
+	provider := newTestProvider(t, fake, Config{Timeout: 20 * time.Millisecond})
+
+	err := provider.ChatStream(context.Background(), userRequest("Hi"), func(*chat.ChatResponse) error { return nil })
+	if !errors.Is(err, chat.ErrTimeout) {
+		t.Errorf("Expected a stream that never starts to time out, got %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package sse
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// Event is a single server-sent event
+type Event struct {
+	ID    string
+	Event string
+	Data  string
+}
+
+// Reader decodes a text/event-stream body into events
+type Reader struct {
+	scanner *bufio.Scanner
+}
+
+func NewReader(r io.Reader) *Reader {
+	scanner := bufio.NewScanner(r)
+	// Completion chunks can be large, allow up to 1MB per line
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	return &Reader{scanner: scanner}
+}
+
+// Next returns the next event, or io.EOF once the stream is exhausted
+func (r *Reader) Next() (*Event, error) {
+	var event Event
+	var data []string
+	seen := false
+
+	for r.scanner.Scan() {
+		line := r.scanner.Text()
+
+		// A blank line dispatches the event
+		if line == "" {
+			if !seen {
+				continue
+			}
+			event.Data = strings.Join(data, "\n")
+			return &event, nil
+		}
+
+		// Lines starting with a colon are comments, used as keepalives
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		seen = true
+
+		switch field {
+		case "data":
+			data = append(data, value)
+		case "event":
+			event.Event = value
+		case "id":
+			event.ID = value
+		}
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// Flush a trailing event that was not followed by a blank line
+	if seen {
+		event.Data = strings.Join(data, "\n")
+		return &event, nil
+	}
+
+	return nil, io.EOF
+}
@@ -0,0 +1,96 @@
+package sse
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReader(t *testing.T) {
+	tests := []struct {
+		name   string
+		stream string
+		events []Event
+	}{
+		{
+			name:   "single data line",
+			stream: "data: hello\n\n",
+			events: []Event{{Data: "hello"}},
+		},
+		{
+			name:   "multi-line data",
+			stream: "data: first\ndata: second\ndata:third\n\n",
+			events: []Event{{Data: "first\nsecond\nthird"}},
+		},
+		{
+			name:   "event and id fields",
+			stream: "id: 7\nevent: content_block_delta\ndata: {}\n\n",
+			events: []Event{{ID: "7", Event: "content_block_delta", Data: "{}"}},
+		},
+		{
+			name:   "comments are skipped",
+			stream: ": keepalive\n\n: another\ndata: hello\n: in between\ndata: world\n\n",
+			events: []Event{{Data: "hello\nworld"}},
+		},
+		{
+			name:   "CRLF line endings",
+			stream: "event: message\r\ndata: hello\r\ndata: world\r\n\r\ndata: [DONE]\r\n\r\n",
+			events: []Event{{Event: "message", Data: "hello\nworld"}, {Data: "[DONE]"}},
+		},
+		{
+			name:   "trailing event without a blank line",
+			stream: "data: first\n\ndata: last",
+			events: []Event{{Data: "first"}, {Data: "last"}},
+		},
+		{
+			name:   "extra blank lines between events",
+			stream: "\n\ndata: one\n\n\n\ndata: two\n\n",
+			events: []Event{{Data: "one"}, {Data: "two"}},
+		},
+		{
+			name:   "only one leading space is stripped",
+			stream: "data:  indented\n\n",
+			events: []Event{{Data: " indented"}},
+		},
+		{
+			name:   "empty stream",
+			stream: "",
+		},
+		{
+			name:   "only comments",
+			stream: ": ping\n\n: ping\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := NewReader(strings.NewReader(tt.stream))
+
+			var events []Event
+			for {
+				event, err := reader.Next()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				events = append(events, *event)
+			}
+
+			if !reflect.DeepEqual(events, tt.events) {
+				t.Errorf("Expected %+v, got %+v", tt.events, events)
+			}
+		})
+	}
+}
+
+func TestReader_LineTooLong(t *testing.T) {
+	reader := NewReader(strings.NewReader("data: " + strings.Repeat("x", 2<<20) + "\n\n"))
+
+	if _, err := reader.Next(); err == nil || errors.Is(err, io.EOF) {
+		t.Errorf("Expected an error for a line over the limit, got %v", err)
+	}
+}
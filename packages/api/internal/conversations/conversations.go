@@ -0,0 +1,175 @@
+// Package conversations keeps chat histories on the server, so that a chat
+// can go on from its ID and be exported as a transcript. IDs are generated,
+// and hard to guess, as they are all it takes to read a conversation.
+package conversations
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"chat-backend/internal/chat"
+	"chat-backend/internal/ids"
+)
+
+// DefaultRetention is how long a conversation is kept after its last message
+const DefaultRetention = 24 * time.Hour
+
+var ErrNotFound = errors.New("conversation not found")
+
+// Message is a message of a conversation with when it was recorded. Answers
+// also have the ID feedback on them is given by, and note the provider and
+// model that generated them, and their usage.
+type Message struct {
+	ID         string          `json:"id,omitempty"`
+	Role       string          `json:"role"`
+	Content    string          `json:"content"`
+	ToolCalls  []chat.ToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string          `json:"tool_call_id,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Provider   string          `json:"provider,omitempty"`
+	Model      string          `json:"model,omitempty"`
+	Usage      *chat.Usage     `json:"usage,omitempty"`
+}
+
+type Conversation struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Messages  []Message `json:"messages"`
+}
+
+// ChatMessages returns the conversation's messages as provider input
+func (c Conversation) ChatMessages() []chat.Message {
+	messages := make([]chat.Message, 0, len(c.Messages))
+	for _, msg := range c.Messages {
+		messages = append(messages, chat.Message{
+			Role:       msg.Role,
+			Content:    msg.Content,
+			ToolCalls:  msg.ToolCalls,
+			ToolCallID: msg.ToolCallID,
+		})
+	}
+	return messages
+}
+
+// Store keeps conversations in memory, by tenant and ID, until they have
+// been left alone for the retention period
+type Store struct {
+	retention time.Duration
+	now       func() time.Time
+
+	mu            sync.Mutex
+	conversations map[key]*Conversation
+}
+
+type key struct {
+	tenantID string
+	id       string
+}
+
+func NewStore(retention time.Duration) *Store {
+	return &Store{
+		retention:     retention,
+		now:           time.Now,
+		conversations: map[key]*Conversation{},
+	}
+}
+
+// Get returns a copy of a tenant's conversation. Conversations of other
+// tenants are reported as not found, like expired ones.
+func (s *Store) Get(tenantID, id string) (Conversation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.lookupLocked(key{tenantID: tenantID, id: id})
+	if !ok {
+		return Conversation{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return clone(c), nil
+}
+
+// Create starts an empty conversation for a tenant
+func (s *Store) Create(tenantID string) Conversation {
+	return s.Import(tenantID, Conversation{})
+}
+
+// Append records messages at the end of a tenant's conversation. Messages
+// without a time are stamped with the current one.
+func (s *Store) Append(tenantID, id string, messages ...Message) (Conversation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now().UTC()
+	c, ok := s.lookupLocked(key{tenantID: tenantID, id: id})
+	if !ok {
+		return Conversation{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+
+	for _, msg := range messages {
+		if msg.CreatedAt.IsZero() {
+			msg.CreatedAt = now
+		}
+		c.Messages = append(c.Messages, msg)
+	}
+	c.UpdatedAt = now
+	s.pruneLocked()
+	return clone(c), nil
+}
+
+// Import adds a whole conversation for a tenant, such as one exported
+// earlier, under a new ID. Any ID it had is replaced.
+func (s *Store) Import(tenantID string, c Conversation) Conversation {
+	c = clone(&c)
+	c.ID = ids.New()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := key{tenantID: tenantID, id: c.ID}
+	now := s.now().UTC()
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = now
+	}
+	// Messages without a time, such as OpenAI-style ones, count as sent
+	// when they were imported
+	for i := range c.Messages {
+		if c.Messages[i].CreatedAt.IsZero() {
+			c.Messages[i].CreatedAt = now
+		}
+	}
+	c.UpdatedAt = now
+	s.conversations[k] = &c
+	s.pruneLocked()
+	return clone(&c)
+}
+
+// lookupLocked returns a conversation that has not expired. Callers hold
+// the lock.
+func (s *Store) lookupLocked(k key) (*Conversation, bool) {
+	c, ok := s.conversations[k]
+	if !ok || s.expired(c) {
+		return nil, false
+	}
+	return c, true
+}
+
+// pruneLocked drops expired conversations. Callers hold the lock.
+func (s *Store) pruneLocked() {
+	for k, c := range s.conversations {
+		if s.expired(c) {
+			delete(s.conversations, k)
+		}
+	}
+}
+
+func (s *Store) expired(c *Conversation) bool {
+	return s.retention > 0 && s.now().Sub(c.UpdatedAt) > s.retention
+}
+
+func clone(c *Conversation) Conversation {
+	copied := *c
+	copied.Messages = append([]Message(nil), c.Messages...)
+	return copied
+}
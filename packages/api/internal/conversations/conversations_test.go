@@ -0,0 +1,155 @@
+package conversations
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"chat-backend/internal/chat"
+)
+
+func TestStore_Append(t *testing.T) {
+	store := NewStore(time.Hour)
+	id := store.Create("acme").ID
+	store.Append("acme", id, Message{Role: "user", Content: "Hi"})
+	store.Append("acme", id, Message{Role: "assistant", Content: "Hello"})
+
+	c, err := store.Get("acme", id)
+	if err != nil {
+		t.Fatalf("Expected the conversation, got: %v", err)
+	}
+	if len(c.Messages) != 2 || c.Messages[1].Content != "Hello" {
+		t.Errorf("Expected both messages in order, got %+v", c.Messages)
+	}
+	if c.Messages[0].CreatedAt.IsZero() {
+		t.Error("Expected messages to be stamped with a time")
+	}
+
+	if _, err := store.Get("globex", id); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected another tenant's conversation to be not found, got: %v", err)
+	}
+	if _, err := store.Append("acme", "support-123", Message{Role: "user", Content: "Hi"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected appending to start no conversation, got: %v", err)
+	}
+}
+
+func TestStore_GetReturnsCopy(t *testing.T) {
+	store := NewStore(time.Hour)
+	id := store.Import("", Conversation{Messages: []Message{{Role: "user", Content: "Hi"}}}).ID
+
+	c, _ := store.Get("", id)
+	c.Messages[0].Content = "changed"
+
+	if c, _ := store.Get("", id); c.Messages[0].Content != "Hi" {
+		t.Error("Expected the stored conversation to be unaffected by changes to a copy")
+	}
+}
+
+func TestStore_Import(t *testing.T) {
+	store := NewStore(time.Hour)
+
+	imported := store.Import("", Conversation{ID: "support-123", Messages: []Message{{Role: "user", Content: "Hi"}}})
+	if imported.ID == "" || imported.ID == "support-123" {
+		t.Fatalf("Expected an ID to be generated, got %+v", imported)
+	}
+	if imported.Messages[0].CreatedAt.IsZero() {
+		t.Error("Expected messages without a time to be stamped")
+	}
+}
+
+func TestStore_Retention(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	store := NewStore(time.Hour)
+	store.now = func() time.Time { return now }
+
+	id := store.Create("").ID
+	store.Append("", id, Message{Role: "user", Content: "Hi"})
+	now = now.Add(2 * time.Hour)
+
+	if _, err := store.Get("", id); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected the conversation to expire, got: %v", err)
+	}
+	if _, err := store.Append("", id, Message{Role: "user", Content: "Again"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected an expired conversation to take no more messages, got: %v", err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		id      string
+		content []string
+		call    string
+	}{
+		{
+			name:    "export",
+			data:    `{"id": "t1", "messages": [{"role": "user", "content": "Hi", "created_at": "2026-10-19T12:00:00Z"}, {"role": "assistant", "content": "Hello", "provider": "ollama", "usage": {"total_tokens": 5}}]}`,
+			id:      "t1",
+			content: []string{"Hi", "Hello"},
+		},
+		{
+			name:    "openai array",
+			data:    `[{"role": "user", "content": [{"type": "text", "text": "What is"}, {"type": "image_url"}, {"type": "text", "text": "this?"}]}]`,
+			content: []string{"What is\nthis?"},
+		},
+		{
+			name:    "openai tool calls",
+			data:    `{"messages": [{"role": "assistant", "content": null, "tool_calls": [{"id": "c1", "type": "function", "function": {"name": "weather", "arguments": "{}"}}]}]}`,
+			content: []string{""},
+			call:    "weather",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Parse([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("Failed to parse: %v", err)
+			}
+			if c.ID != tt.id || len(c.Messages) != len(tt.content) {
+				t.Fatalf("Unexpected conversation %+v", c)
+			}
+			for i, content := range tt.content {
+				if c.Messages[i].Content != content {
+					t.Errorf("Expected message %d to be %q, got %q", i, content, c.Messages[i].Content)
+				}
+			}
+			if tt.call != "" && (len(c.Messages[0].ToolCalls) != 1 || c.Messages[0].ToolCalls[0].Name != tt.call) {
+				t.Errorf("Expected a call to %s, got %+v", tt.call, c.Messages[0].ToolCalls)
+			}
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, data := range []string{``, `[]`, `{"messages": []}`, `[{"role": "user", "content": 42}]`, `{`} {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("Expected %q to be refused", data)
+		}
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	sent := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	c := Conversation{ID: "t1", Messages: []Message{
+		{Role: "user", Content: "Weather?", CreatedAt: sent},
+		{Role: "assistant", ToolCalls: []chat.ToolCall{{ID: "c1", Name: "weather", Arguments: `{"city":"Paris"}`}}, CreatedAt: sent, Provider: "openai", Model: "gpt-4o", Usage: &chat.Usage{TotalTokens: 12, Estimated: true}},
+	}}
+
+	var b strings.Builder
+	if err := WriteMarkdown(&b, c); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+
+	for _, want := range []string{
+		"# Conversation t1\n",
+		"### User · 2026-10-19 12:00:00 UTC\n\nWeather?\n",
+		"Called `weather` with `{\"city\":\"Paris\"}`",
+		"_openai · gpt-4o · about 12 tokens_",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("Expected the transcript to contain %q, got:\n%s", want, b.String())
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package conversations
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"chat-backend/internal/chat"
+)
+
+// timeFormat is how transcripts show when messages were sent
+const timeFormat = "2006-01-02 15:04:05 UTC"
+
+// WriteMarkdown writes a conversation as a Markdown transcript, with a
+// heading for each message
+func WriteMarkdown(w io.Writer, c Conversation) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Conversation %s\n", c.ID)
+
+	for _, msg := range c.Messages {
+		fmt.Fprintf(&b, "\n### %s · %s\n\n", roleTitle(msg.Role), msg.CreatedAt.UTC().Format(timeFormat))
+		if msg.Content != "" {
+			fmt.Fprintf(&b, "%s\n", msg.Content)
+		}
+		for _, call := range msg.ToolCalls {
+			fmt.Fprintf(&b, "\nCalled `%s` with `%s`\n", call.Name, call.Arguments)
+		}
+		if details := answerDetails(msg); details != "" {
+			fmt.Fprintf(&b, "\n_%s_\n", details)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteText writes a conversation as a plain text transcript
+func WriteText(w io.Writer, c Conversation) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Conversation %s\n", c.ID)
+
+	for _, msg := range c.Messages {
+		fmt.Fprintf(&b, "\n[%s] %s", msg.CreatedAt.UTC().Format(timeFormat), roleTitle(msg.Role))
+		if details := answerDetails(msg); details != "" {
+			fmt.Fprintf(&b, " (%s)", details)
+		}
+		b.WriteString(":\n")
+		if msg.Content != "" {
+			fmt.Fprintf(&b, "%s\n", msg.Content)
+		}
+		for _, call := range msg.ToolCalls {
+			fmt.Fprintf(&b, "Called %s with %s\n", call.Name, call.Arguments)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func roleTitle(role string) string {
+	if role == "" {
+		return role
+	}
+	return strings.ToUpper(role[:1]) + role[1:]
+}
+
+// answerDetails describes what generated an answer, such as
+// "ollama · gemma3:1b · 258 tokens"
+func answerDetails(msg Message) string {
+	var details []string
+	if msg.Provider != "" {
+		details = append(details, msg.Provider)
+	}
+	if msg.Model != "" {
+		details = append(details, msg.Model)
+	}
+	if msg.Usage != nil {
+		tokens := fmt.Sprintf("%d tokens", msg.Usage.TotalTokens)
+		if msg.Usage.Estimated {
+			tokens = "about " + tokens
+		}
+		details = append(details, tokens)
+	}
+	return strings.Join(details, " · ")
+}
+
+// importMessage accepts both the export format and OpenAI-style messages,
+// whose content may be a list of parts and whose tool calls nest the
+// function
+type importMessage struct {
+	Role       string           `json:"role"`
+	Content    json.RawMessage  `json:"content"`
+	ToolCalls  []importToolCall `json:"tool_calls"`
+	ToolCallID string           `json:"tool_call_id"`
+	CreatedAt  time.Time        `json:"created_at"`
+	Provider   string           `json:"provider"`
+	Model      string           `json:"model"`
+	Usage      *chat.Usage      `json:"usage"`
+}
+
+type importToolCall struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+	Function  *struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+type importConversation struct {
+	ID        string          `json:"id"`
+	CreatedAt time.Time       `json:"created_at"`
+	Messages  []importMessage `json:"messages"`
+}
+
+// Parse reads a conversation to import: either the JSON export format, an
+// object with OpenAI-style messages, or a bare array of them
+func Parse(data []byte) (Conversation, error) {
+	var in importConversation
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("[")) {
+		if err := json.Unmarshal(data, &in.Messages); err != nil {
+			return Conversation{}, fmt.Errorf("invalid messages: %w", err)
+		}
+	} else if err := json.Unmarshal(data, &in); err != nil {
+		return Conversation{}, fmt.Errorf("invalid conversation: %w", err)
+	}
+
+	if len(in.Messages) == 0 {
+		return Conversation{}, fmt.Errorf("conversation has no messages")
+	}
+
+	c := Conversation{ID: in.ID, CreatedAt: in.CreatedAt}
+	for i, msg := range in.Messages {
+		content, err := parseContent(msg.Content)
+		if err != nil {
+			return Conversation{}, fmt.Errorf("message %d: %w", i, err)
+		}
+
+		message := Message{
+			Role:       msg.Role,
+			Content:    content,
+			ToolCallID: msg.ToolCallID,
+			CreatedAt:  msg.CreatedAt,
+			Provider:   msg.Provider,
+			Model:      msg.Model,
+			Usage:      msg.Usage,
+		}
+		for _, call := range msg.ToolCalls {
+			toolCall := chat.ToolCall{ID: call.ID, Name: call.Name, Arguments: call.Arguments}
+			if call.Function != nil {
+				toolCall.Name, toolCall.Arguments = call.Function.Name, call.Function.Arguments
+			}
+			message.ToolCalls = append(message.ToolCalls, toolCall)
+		}
+		c.Messages = append(c.Messages, message)
+	}
+	return c, nil
+}
+
+// parseContent reads message content given as a string, or as OpenAI
+// content parts, whose text parts are joined
+func parseContent(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", nil
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text, nil
+	}
+
+	var parts []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(raw, &parts); err != nil {
+		return "", fmt.Errorf("content must be a string or a list of parts")
+	}
+	var texts []string
+	for _, part := range parts {
+		if part.Type == "text" {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.Join(texts, "\n"), nil
+}
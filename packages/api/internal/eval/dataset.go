@@ -0,0 +1,127 @@
+package eval
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Case is one question of a dataset with the answer it should get
+type Case struct {
+	ID       string `json:"id"`
+	Question string `json:"question"`
+	Expected string `json:"expected"`
+}
+
+// LoadDataset reads a dataset file. Files ending in .jsonl hold one case
+// per line; anything else is read as a tab-separated file with a header
+// row, in the format of the mock provider's sample-data.tsv.
+func LoadDataset(path string) ([]Case, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dataset: %w", err)
+	}
+	defer file.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".jsonl") {
+		return ParseJSONL(file)
+	}
+	return ParseTSV(file)
+}
+
+// ParseTSV reads tab-separated cases. The header names the Question and
+// Answer columns, in any case and order, and may add an ID column. Cases
+// without an ID are numbered by their row.
+func ParseTSV(r io.Reader) ([]Case, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = '\t'
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse dataset: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("dataset is empty")
+	}
+
+	columns := map[string]int{}
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	question, hasQuestion := columns["question"]
+	answer, hasAnswer := columns["answer"]
+	if !hasQuestion || !hasAnswer {
+		return nil, fmt.Errorf("dataset header must name Question and Answer columns")
+	}
+	id, hasID := columns["id"]
+
+	var cases []Case
+	for row, record := range records[1:] {
+		if len(record) <= max(question, answer) {
+			return nil, fmt.Errorf("dataset row %d: expected at least %d columns", row+2, max(question, answer)+1)
+		}
+
+		c := Case{
+			ID:       strconv.Itoa(row + 1),
+			Question: strings.TrimSpace(record[question]),
+			Expected: strings.TrimSpace(record[answer]),
+		}
+		if hasID && id < len(record) && record[id] != "" {
+			c.ID = record[id]
+		}
+		cases = append(cases, c)
+	}
+	return cases, validate(cases)
+}
+
+// ParseJSONL reads one {"id", "question", "expected"} object per line
+func ParseJSONL(r io.Reader) ([]Case, error) {
+	var cases []Case
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), 1<<20)
+
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		var c Case
+		if err := json.Unmarshal(scanner.Bytes(), &c); err != nil {
+			return nil, fmt.Errorf("dataset line %d: %w", line, err)
+		}
+		if c.ID == "" {
+			c.ID = strconv.Itoa(line)
+		}
+		cases = append(cases, c)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read dataset: %w", err)
+	}
+	return cases, validate(cases)
+}
+
+func validate(cases []Case) error {
+	if len(cases) == 0 {
+		return fmt.Errorf("dataset has no cases")
+	}
+
+	seen := map[string]bool{}
+	for _, c := range cases {
+		if c.Question == "" || c.Expected == "" {
+			return fmt.Errorf("case %s: question and expected answer are required", c.ID)
+		}
+		if seen[c.ID] {
+			return fmt.Errorf("case %s appears twice", c.ID)
+		}
+		seen[c.ID] = true
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package eval
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseTSV(t *testing.T) {
+	cases, err := ParseTSV(strings.NewReader("answer\tQuestion\tID\n" +
+		"Paris\tWhat is the capital of France?\tcapital\n" +
+		"4\tWhat is 2 + 2?\t\n"))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(cases) != 2 {
+		t.Fatalf("Expected 2 cases, got %d", len(cases))
+	}
+	if cases[0] != (Case{ID: "capital", Question: "What is the capital of France?", Expected: "Paris"}) {
+		t.Errorf("Unexpected first case: %+v", cases[0])
+	}
+	if cases[1].ID != "2" {
+		t.Errorf("Expected a case without an ID to be numbered by its row, got '%s'", cases[1].ID)
+	}
+}
+
+func TestParseTSV_MissingColumns(t *testing.T) {
+	if _, err := ParseTSV(strings.NewReader("Prompt\tAnswer\nHi\tHello\n")); err == nil {
+		t.Error("Expected a header without a Question column to be rejected")
+	}
+}
+
+func TestParseJSONL(t *testing.T) {
+	cases, err := ParseJSONL(strings.NewReader(`{"id": "a", "question": "Hi?", "expected": "Hello"}
+
+{"question": "Bye?", "expected": "Goodbye"}
+`))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(cases) != 2 || cases[0].ID != "a" || cases[1].ID != "3" {
+		t.Errorf("Unexpected cases: %+v", cases)
+	}
+}
+
+func TestParseJSONL_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"empty":            "",
+		"not json":         "question?\n",
+		"missing expected": `{"id": "a", "question": "Hi?"}`,
+		"duplicate id": `{"id": "a", "question": "Hi?", "expected": "Hello"}
+{"id": "a", "question": "Bye?", "expected": "Goodbye"}`,
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseJSONL(strings.NewReader(input)); err == nil {
+				t.Error("Expected the dataset to be rejected")
+			}
+		})
+	}
+}
+
+func TestLoadDataset_SampleData(t *testing.T) {
+	cases, err := LoadDataset(filepath.Join("..", "chat", "mock", "sample-data.tsv"))
+	if err != nil {
+		t.Fatalf("Expected the mock provider's sample data to load, got: %v", err)
+	}
+	if len(cases) == 0 {
+		t.Error("Expected sample data cases")
+	}
+}
+
+func TestLoadDataset_JSONL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cases.jsonl")
+	if err := os.WriteFile(path, []byte(`{"question": "Hi?", "expected": "Hello"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cases, err := LoadDataset(path)
+	if err != nil || len(cases) != 1 {
+		t.Errorf("Expected one case, got %+v, %v", cases, err)
+	}
+}
@@ -0,0 +1,208 @@
+package eval
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"chat-backend/internal/chat"
+)
+
+const defaultParallelism = 4
+
+// Target is a provider to evaluate, under the name it is reported as
+type Target struct {
+	Name     string
+	Provider chat.ChatProvider
+}
+
+// Config describes an evaluation. SystemPrompt, when set, is sent ahead of
+// every question.
+type Config struct {
+	Dataset      []Case
+	Targets      []Target
+	Scorers      []Scorer
+	SystemPrompt string
+	Parallelism  int
+}
+
+// Report holds the results of every target on the same dataset
+type Report struct {
+	CreatedAt time.Time `json:"created_at"`
+	Dataset   string    `json:"dataset,omitempty"`
+	Metrics   []string  `json:"metrics"`
+	Runs      []Run     `json:"runs"`
+}
+
+// Run is one target's results
+type Run struct {
+	Name    string       `json:"name"`
+	Summary Summary      `json:"summary"`
+	Results []CaseResult `json:"results"`
+}
+
+// Summary averages a run's scores over its cases. Cases the provider
+// failed score 0 on every metric, while metrics that could not be computed
+// for a case are left out of that metric's average and counted in
+// ScoreErrors.
+type Summary struct {
+	Cases        int                `json:"cases"`
+	Errors       int                `json:"errors"`
+	Scores       map[string]float64 `json:"scores"`
+	ScoreErrors  map[string]int     `json:"score_errors,omitempty"`
+	AvgLatencyMs float64            `json:"avg_latency_ms"`
+	TotalTokens  int                `json:"total_tokens"`
+}
+
+// CaseResult is a target's answer to one case and how it scored
+type CaseResult struct {
+	CaseID    string             `json:"case_id"`
+	Question  string             `json:"question"`
+	Expected  string             `json:"expected"`
+	Answer    string             `json:"answer"`
+	Error     string             `json:"error,omitempty"`
+	LatencyMs int64              `json:"latency_ms"`
+	Usage     *chat.Usage        `json:"usage,omitempty"`
+	Scores    map[string]float64 `json:"scores,omitempty"`
+	Notes     map[string]string  `json:"notes,omitempty"`
+}
+
+// Evaluate asks every target every question and scores the answers. Failures
+// of single questions or scores are recorded in the report rather than
+// stopping the evaluation.
+func Evaluate(ctx context.Context, config Config) *Report {
+	if config.Parallelism <= 0 {
+		config.Parallelism = defaultParallelism
+	}
+
+	report := &Report{CreatedAt: time.Now().UTC()}
+	for _, scorer := range config.Scorers {
+		report.Metrics = append(report.Metrics, scorer.Name())
+	}
+
+	for _, target := range config.Targets {
+		slog.Info("Evaluating target", "target", target.Name, "cases", len(config.Dataset))
+		results := evaluateTarget(ctx, config, target)
+		report.Runs = append(report.Runs, Run{
+			Name:    target.Name,
+			Summary: summarize(results, report.Metrics),
+			Results: results,
+		})
+	}
+	return report
+}
+
+func evaluateTarget(ctx context.Context, config Config, target Target) []CaseResult {
+	results := make([]CaseResult, len(config.Dataset))
+	slots := make(chan struct{}, config.Parallelism)
+	var wg sync.WaitGroup
+
+	for i, c := range config.Dataset {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			results[i] = evaluateCase(ctx, config, target, c)
+		}()
+	}
+
+	wg.Wait()
+	return results
+}
+
+func evaluateCase(ctx context.Context, config Config, target Target, c Case) CaseResult {
+	result := CaseResult{
+		CaseID:   c.ID,
+		Question: c.Question,
+		Expected: c.Expected,
+		Scores:   map[string]float64{},
+	}
+
+	var messages []chat.Message
+	if config.SystemPrompt != "" {
+		messages = append(messages, chat.Message{Role: "system", Content: config.SystemPrompt})
+	}
+	messages = append(messages, chat.Message{Role: "user", Content: c.Question})
+
+	started := time.Now()
+	resp, err := target.Provider.Chat(ctx, &chat.ChatRequest{Messages: messages})
+	result.LatencyMs = time.Since(started).Milliseconds()
+
+	if err != nil {
+		slog.Warn("Evaluation question failed", "target", target.Name, "case", c.ID, "error", err)
+		result.Error = err.Error()
+		for _, scorer := range config.Scorers {
+			result.Scores[scorer.Name()] = 0
+		}
+		return result
+	}
+	result.Answer = resp.Content
+	result.Usage = resp.Usage
+
+	for _, scorer := range config.Scorers {
+		score, err := scorer.Score(ctx, c, resp.Content)
+		if err != nil {
+			slog.Warn("Failed to score answer", "target", target.Name, "case", c.ID, "metric", scorer.Name(), "error", err)
+			result.Notes = setNote(result.Notes, scorer.Name(), "error: "+err.Error())
+			continue
+		}
+		result.Scores[scorer.Name()] = score.Value
+		if score.Note != "" {
+			result.Notes = setNote(result.Notes, scorer.Name(), score.Note)
+		}
+	}
+	return result
+}
+
+func setNote(notes map[string]string, metric, note string) map[string]string {
+	if notes == nil {
+		notes = map[string]string{}
+	}
+	notes[metric] = note
+	return notes
+}
+
+func summarize(results []CaseResult, metrics []string) Summary {
+	summary := Summary{
+		Cases:  len(results),
+		Scores: map[string]float64{},
+	}
+
+	totals := map[string]float64{}
+	counts := map[string]int{}
+	var latency int64
+	for _, result := range results {
+		latency += result.LatencyMs
+		if result.Error != "" {
+			summary.Errors++
+		}
+		if result.Usage != nil {
+			summary.TotalTokens += result.Usage.TotalTokens
+		}
+
+		for _, metric := range metrics {
+			value, ok := result.Scores[metric]
+			if !ok {
+				if summary.ScoreErrors == nil {
+					summary.ScoreErrors = map[string]int{}
+				}
+				summary.ScoreErrors[metric]++
+				continue
+			}
+			totals[metric] += value
+			counts[metric]++
+		}
+	}
+
+	for _, metric := range metrics {
+		if counts[metric] > 0 {
+			summary.Scores[metric] = totals[metric] / float64(counts[metric])
+		}
+	}
+	if len(results) > 0 {
+		summary.AvgLatencyMs = float64(latency) / float64(len(results))
+	}
+	return summary
+}
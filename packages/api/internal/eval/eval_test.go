@@ -0,0 +1,193 @@
+package eval
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"chat-backend/internal/chat"
+)
+
+// answerProvider answers from a map of questions, failing on the others
+type answerProvider struct {
+	answers map[string]string
+}
+
+func (p *answerProvider) Chat(ctx context.Context, req *chat.ChatRequest) (*chat.ChatResponse, error) {
+	question := req.Messages[len(req.Messages)-1].Content
+	answer, ok := p.answers[question]
+	if !ok {
+		return nil, chat.Errorf(chat.ErrProviderUnavailable, "no answer")
+	}
+	return &chat.ChatResponse{Content: answer, Usage: &chat.Usage{TotalTokens: 10}}, nil
+}
+
+func (p *answerProvider) ChatStream(ctx context.Context, req *chat.ChatRequest, callback chat.StreamCallback) error {
+	return errors.New("evaluations never stream")
+}
+
+// failingScorer cannot score one answer
+type failingScorer struct{}
+
+func (failingScorer) Name() string { return "flaky" }
+
+func (failingScorer) Score(ctx context.Context, c Case, answer string) (Score, error) {
+	if answer == "Blue" {
+		return Score{}, errors.New("cannot score")
+	}
+	return Score{Value: 1}, nil
+}
+
+var testDataset = []Case{
+	{ID: "sky", Question: "What color is the sky?", Expected: "Blue"},
+	{ID: "grass", Question: "What color is grass?", Expected: "Green"},
+	{ID: "sea", Question: "What color is the sea?", Expected: "Blue"},
+	{ID: "sun", Question: "What color is the sun?", Expected: "Yellow"},
+}
+
+func TestEvaluate(t *testing.T) {
+	good := &answerProvider{answers: map[string]string{
+		"What color is the sky?": "Blue",
+		"What color is grass?":   "Green",
+		"What color is the sea?": "Deep blue",
+		"What color is the sun?": "Yellow",
+	}}
+	bad := &answerProvider{answers: map[string]string{
+		"What color is the sky?": "Red",
+	}}
+
+	report := Evaluate(context.Background(), Config{
+		Dataset:     testDataset,
+		Targets:     []Target{{Name: "good", Provider: good}, {Name: "bad", Provider: bad}},
+		Scorers:     []Scorer{ExactMatch{}, TokenF1{}, failingScorer{}},
+		Parallelism: 2,
+	})
+
+	if len(report.Runs) != 2 || strings.Join(report.Metrics, ",") != "exact,f1,flaky" {
+		t.Fatalf("Unexpected report: %+v", report)
+	}
+
+	goodRun := report.Runs[0]
+	for i, result := range goodRun.Results {
+		if result.CaseID != testDataset[i].ID {
+			t.Errorf("Expected results in dataset order, got %s at %d", result.CaseID, i)
+		}
+	}
+	if goodRun.Summary.Scores[MetricExact] != 0.75 {
+		t.Errorf("Expected 3 of 4 exact matches, got %v", goodRun.Summary.Scores[MetricExact])
+	}
+	if goodRun.Summary.Scores["flaky"] != 1 || goodRun.Summary.ScoreErrors["flaky"] != 1 {
+		t.Errorf("Expected the unscored answer to be left out of the mean, got %+v", goodRun.Summary)
+	}
+	if goodRun.Results[0].Notes["flaky"] == "" {
+		t.Error("Expected the scoring failure to be noted on the case")
+	}
+	if goodRun.Summary.TotalTokens != 40 {
+		t.Errorf("Expected 40 tokens, got %d", goodRun.Summary.TotalTokens)
+	}
+
+	badRun := report.Runs[1]
+	if badRun.Summary.Errors != 3 {
+		t.Errorf("Expected 3 failed questions, got %d", badRun.Summary.Errors)
+	}
+	if badRun.Summary.Scores[MetricF1] != 0 || badRun.Results[1].Error == "" {
+		t.Errorf("Expected failed questions to score 0, got %+v", badRun.Summary)
+	}
+}
+
+func TestEvaluate_SystemPrompt(t *testing.T) {
+	var got []chat.Message
+	provider := &recordingProvider{record: func(messages []chat.Message) { got = messages }}
+
+	Evaluate(context.Background(), Config{
+		Dataset:      testDataset[:1],
+		Targets:      []Target{{Name: "recording", Provider: provider}},
+		Scorers:      []Scorer{ExactMatch{}},
+		SystemPrompt: "Answer with one word",
+	})
+
+	if len(got) != 2 || got[0].Role != "system" || got[0].Content != "Answer with one word" {
+		t.Errorf("Expected the system prompt ahead of the question, got %+v", got)
+	}
+}
+
+type recordingProvider struct {
+	record func([]chat.Message)
+}
+
+func (p *recordingProvider) Chat(ctx context.Context, req *chat.ChatRequest) (*chat.ChatResponse, error) {
+	p.record(req.Messages)
+	return &chat.ChatResponse{Content: "Blue"}, nil
+}
+
+func (p *recordingProvider) ChatStream(ctx context.Context, req *chat.ChatRequest, callback chat.StreamCallback) error {
+	return errors.New("evaluations never stream")
+}
+
+func TestReport_JSONRoundTripAndCompare(t *testing.T) {
+	provider := &answerProvider{answers: map[string]string{"What color is the sky?": "Blue"}}
+	previous := Evaluate(context.Background(), Config{
+		Dataset: testDataset[:2],
+		Targets: []Target{{Name: "ollama", Provider: provider}},
+		Scorers: []Scorer{ExactMatch{}},
+	})
+
+	path := filepath.Join(t.TempDir(), "previous.json")
+	var buf bytes.Buffer
+	if err := previous.WriteJSON(&buf); err != nil {
+		t.Fatalf("Failed to write JSON: %v", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadReport(path)
+	if err != nil {
+		t.Fatalf("Failed to load report: %v", err)
+	}
+
+	current := Evaluate(context.Background(), Config{
+		Dataset: testDataset[:2],
+		Targets: []Target{{Name: "ollama", Provider: provider}},
+		Scorers: []Scorer{ExactMatch{}, TokenF1{}},
+	})
+	current.Compare(loaded, " (previous)")
+
+	if len(current.Runs) != 2 || current.Runs[1].Name != "ollama (previous)" {
+		t.Fatalf("Expected the previous run to be appended, got %+v", current.Runs)
+	}
+	if current.Runs[1].Summary.Scores[MetricExact] != 0.5 {
+		t.Errorf("Expected the previous scores to survive the round trip, got %+v", current.Runs[1].Summary)
+	}
+}
+
+func TestReport_WriteHTML(t *testing.T) {
+	report := Evaluate(context.Background(), Config{
+		Dataset: testDataset[:2],
+		Targets: []Target{
+			{Name: "first", Provider: &answerProvider{answers: map[string]string{"What color is the sky?": "Blue"}}},
+			{Name: "<second>", Provider: &answerProvider{answers: map[string]string{}}},
+		},
+		Scorers: []Scorer{ExactMatch{}},
+	})
+
+	var buf bytes.Buffer
+	if err := report.WriteHTML(&buf); err != nil {
+		t.Fatalf("Failed to write HTML: %v", err)
+	}
+	html := buf.String()
+
+	if !strings.Contains(html, `<td class="num best">50.0%</td>`) {
+		t.Error("Expected the best score to be highlighted")
+	}
+	if !strings.Contains(html, "&lt;second&gt;") || strings.Contains(html, "<second>") {
+		t.Error("Expected run names to be escaped")
+	}
+	if !strings.Contains(html, "What color is grass?") || !strings.Contains(html, `class="error"`) {
+		t.Error("Expected every case and failed answer to be listed")
+	}
+}
@@ -0,0 +1,112 @@
+package eval
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"slices"
+)
+
+//go:embed report.html
+var reportTemplate string
+
+var reportHTML = template.Must(template.New("report").Funcs(template.FuncMap{
+	"pct": percent,
+	"score": func(result *CaseResult, metric string) string {
+		if value, ok := result.Scores[metric]; ok {
+			return percent(value)
+		}
+		return "-"
+	},
+}).Parse(reportTemplate))
+
+func percent(value float64) string {
+	return fmt.Sprintf("%.1f%%", value*100)
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// LoadReport reads a report written by WriteJSON
+func LoadReport(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report: %w", err)
+	}
+
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse report %s: %w", path, err)
+	}
+	return &report, nil
+}
+
+// Compare appends the runs of an earlier report, renamed with the given
+// suffix, so that both are shown side by side
+func (r *Report) Compare(previous *Report, suffix string) {
+	for _, run := range previous.Runs {
+		run.Name += suffix
+		r.Runs = append(r.Runs, run)
+	}
+	for _, metric := range previous.Metrics {
+		if !slices.Contains(r.Metrics, metric) {
+			r.Metrics = append(r.Metrics, metric)
+		}
+	}
+}
+
+// WriteHTML writes the report as a standalone page with a summary table
+// and the answers of every run to every case
+func (r *Report) WriteHTML(w io.Writer) error {
+	return reportHTML.Execute(w, r.view())
+}
+
+type reportView struct {
+	*Report
+	Best  map[string]float64
+	Cases []caseView
+}
+
+type caseView struct {
+	Case
+	Results []*CaseResult
+}
+
+// view arranges the report for the template: the best mean of every metric
+// and the results grouped by case, in dataset order
+func (r *Report) view() reportView {
+	view := reportView{Report: r, Best: map[string]float64{}}
+
+	for _, metric := range r.Metrics {
+		for _, run := range r.Runs {
+			if score, ok := run.Summary.Scores[metric]; ok && score > view.Best[metric] {
+				view.Best[metric] = score
+			}
+		}
+	}
+
+	index := map[string]int{}
+	for i, run := range r.Runs {
+		for j := range run.Results {
+			result := &r.Runs[i].Results[j]
+			position, ok := index[result.CaseID]
+			if !ok {
+				position = len(view.Cases)
+				index[result.CaseID] = position
+				view.Cases = append(view.Cases, caseView{
+					Case:    Case{ID: result.CaseID, Question: result.Question, Expected: result.Expected},
+					Results: make([]*CaseResult, len(r.Runs)),
+				})
+			}
+			view.Cases[position].Results[i] = result
+		}
+	}
+	return view
+}
@@ -0,0 +1,218 @@
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+
+	"chat-backend/internal/chat"
+)
+
+// Names of the built in metrics
+const (
+	MetricExact     = "exact"
+	MetricF1        = "f1"
+	MetricEmbedding = "embedding"
+	MetricJudge     = "judge"
+)
+
+// Score is a metric's value for one answer, from 0 to 1 for every built
+// in metric, with an optional explanation
+type Score struct {
+	Value float64
+	Note  string
+}
+
+// Scorer rates an answer against the expected one
+type Scorer interface {
+	Name() string
+	Score(ctx context.Context, c Case, answer string) (Score, error)
+}
+
+// ExactMatch scores 1 when the answer equals the expected one after
+// normalization, and 0 otherwise
+type ExactMatch struct{}
+
+func (ExactMatch) Name() string { return MetricExact }
+
+func (ExactMatch) Score(ctx context.Context, c Case, answer string) (Score, error) {
+	if strings.Join(tokens(answer), " ") == strings.Join(tokens(c.Expected), " ") {
+		return Score{Value: 1}, nil
+	}
+	return Score{}, nil
+}
+
+// TokenF1 scores the overlap of normalized words between the answer and
+// the expected one, as the harmonic mean of precision and recall
+type TokenF1 struct{}
+
+func (TokenF1) Name() string { return MetricF1 }
+
+func (TokenF1) Score(ctx context.Context, c Case, answer string) (Score, error) {
+	return Score{Value: f1(tokens(answer), tokens(c.Expected))}, nil
+}
+
+func f1(got, want []string) float64 {
+	if len(got) == 0 || len(want) == 0 {
+		if len(got) == len(want) {
+			return 1
+		}
+		return 0
+	}
+
+	counts := map[string]int{}
+	for _, token := range want {
+		counts[token]++
+	}
+	common := 0
+	for _, token := range got {
+		if counts[token] > 0 {
+			counts[token]--
+			common++
+		}
+	}
+	if common == 0 {
+		return 0
+	}
+
+	precision := float64(common) / float64(len(got))
+	recall := float64(common) / float64(len(want))
+	return 2 * precision * recall / (precision + recall)
+}
+
+// tokens lowercases text and splits it into words, dropping punctuation
+// and articles so that phrasing differences do not count
+func tokens(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	kept := words[:0]
+	for _, word := range words {
+		if word != "a" && word != "an" && word != "the" {
+			kept = append(kept, word)
+		}
+	}
+	return kept
+}
+
+// Embedder turns text into a vector. ollama.Embedder is one.
+type Embedder interface {
+	Embed(ctx context.Context, text string) ([]float32, error)
+}
+
+// EmbeddingSimilarity scores the cosine similarity of the answer's and the
+// expected answer's embeddings, which rewards answers that mean the same
+// in other words
+type EmbeddingSimilarity struct {
+	embedder Embedder
+
+	// Expected answers are embedded once however many runs score them
+	mu       sync.Mutex
+	expected map[string][]float32
+}
+
+func NewEmbeddingSimilarity(embedder Embedder) *EmbeddingSimilarity {
+	return &EmbeddingSimilarity{embedder: embedder, expected: map[string][]float32{}}
+}
+
+func (s *EmbeddingSimilarity) Name() string { return MetricEmbedding }
+
+func (s *EmbeddingSimilarity) Score(ctx context.Context, c Case, answer string) (Score, error) {
+	s.mu.Lock()
+	want, ok := s.expected[c.Expected]
+	s.mu.Unlock()
+
+	if !ok {
+		var err error
+		want, err = s.embedder.Embed(ctx, c.Expected)
+		if err != nil {
+			return Score{}, fmt.Errorf("failed to embed expected answer: %w", err)
+		}
+		s.mu.Lock()
+		s.expected[c.Expected] = want
+		s.mu.Unlock()
+	}
+
+	got, err := s.embedder.Embed(ctx, answer)
+	if err != nil {
+		return Score{}, fmt.Errorf("failed to embed answer: %w", err)
+	}
+	if len(got) != len(want) {
+		return Score{}, fmt.Errorf("embeddings have different dimensions: %d and %d", len(got), len(want))
+	}
+	return Score{Value: max(cosine(got, want), 0)}, nil
+}
+
+func cosine(a, b []float32) float64 {
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+const judgePrompt = `You grade answers given by an assistant against a reference answer.
+Rate how well the answer agrees with the reference on a scale of 1 to 5:
+5 means it says the same, 3 means it is partly right or incomplete, and 1
+means it is wrong or unrelated. Ignore differences in wording and length.
+Reply with JSON only, like {"score": 4, "reason": "one short sentence"}.`
+
+// Judge asks a model to grade the answer against the expected one. Its
+// grade of 1 to 5 is scaled to 0 to 1, with the model's reason as the note.
+type Judge struct {
+	provider chat.ChatProvider
+}
+
+func NewJudge(provider chat.ChatProvider) *Judge {
+	return &Judge{provider: provider}
+}
+
+func (j *Judge) Name() string { return MetricJudge }
+
+func (j *Judge) Score(ctx context.Context, c Case, answer string) (Score, error) {
+	resp, err := j.provider.Chat(ctx, &chat.ChatRequest{
+		Messages: []chat.Message{
+			{Role: "system", Content: judgePrompt},
+			{Role: "user", Content: fmt.Sprintf("Question: %s\n\nReference answer: %s\n\nAnswer to grade: %s", c.Question, c.Expected, answer)},
+		},
+	})
+	if err != nil {
+		return Score{}, fmt.Errorf("judge failed: %w", err)
+	}
+
+	grade, reason, err := parseGrade(resp.Content)
+	if err != nil {
+		return Score{}, err
+	}
+	return Score{Value: (grade - 1) / 4, Note: reason}, nil
+}
+
+// parseGrade reads the judge's JSON verdict, tolerating text around it
+func parseGrade(content string) (float64, string, error) {
+	start, end := strings.Index(content, "{"), strings.LastIndex(content, "}")
+	if start < 0 || end < start {
+		return 0, "", fmt.Errorf("judge did not reply with JSON: %q", content)
+	}
+
+	var verdict struct {
+		Score  float64 `json:"score"`
+		Reason string  `json:"reason"`
+	}
+	if err := json.Unmarshal([]byte(content[start:end+1]), &verdict); err != nil {
+		return 0, "", fmt.Errorf("failed to parse judge verdict: %w", err)
+	}
+	if verdict.Score < 1 || verdict.Score > 5 {
+		return 0, "", fmt.Errorf("judge score %v is not between 1 and 5", verdict.Score)
+	}
+	return verdict.Score, verdict.Reason, nil
+}
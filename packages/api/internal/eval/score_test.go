@@ -0,0 +1,125 @@
+package eval
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"chat-backend/internal/chat"
+)
+
+func TestExactMatch(t *testing.T) {
+	c := Case{Expected: "The Eiffel Tower."}
+	tests := map[string]float64{
+		"the eiffel tower":          1,
+		"Eiffel  Tower!":            1,
+		"The Eiffel Tower in Paris": 0,
+	}
+
+	for answer, want := range tests {
+		score, _ := ExactMatch{}.Score(context.Background(), c, answer)
+		if score.Value != want {
+			t.Errorf("Expected '%s' to score %v, got %v", answer, want, score.Value)
+		}
+	}
+}
+
+func TestTokenF1(t *testing.T) {
+	c := Case{Expected: "Darth Vader is Luke's father"}
+	tests := map[string]float64{
+		"Darth Vader is Luke's father": 1,
+		"Luke's father is Darth Vader": 1,
+		"It is Darth Vader":            2 * 0.75 * 0.5 / 1.25,
+		"Obi-Wan":                      0,
+		"":                             0,
+	}
+
+	for answer, want := range tests {
+		score, _ := TokenF1{}.Score(context.Background(), c, answer)
+		if math.Abs(score.Value-want) > 1e-9 {
+			t.Errorf("Expected '%s' to score %v, got %v", answer, want, score.Value)
+		}
+	}
+}
+
+// fakeEmbedder embeds text as a fixed vector and counts its calls
+type fakeEmbedder struct {
+	vectors map[string][]float32
+	calls   int
+}
+
+func (e *fakeEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	e.calls++
+	vector, ok := e.vectors[text]
+	if !ok {
+		return nil, errors.New("no embedding")
+	}
+	return vector, nil
+}
+
+func TestEmbeddingSimilarity(t *testing.T) {
+	embedder := &fakeEmbedder{vectors: map[string][]float32{
+		"expected": {1, 0},
+		"same":     {2, 0},
+		"diagonal": {1, 1},
+		"opposite": {-1, 0},
+	}}
+	scorer := NewEmbeddingSimilarity(embedder)
+	c := Case{Expected: "expected"}
+
+	tests := map[string]float64{
+		"same":     1,
+		"diagonal": math.Sqrt2 / 2,
+		"opposite": 0,
+	}
+	for answer, want := range tests {
+		score, err := scorer.Score(context.Background(), c, answer)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if math.Abs(score.Value-want) > 1e-6 {
+			t.Errorf("Expected '%s' to score %v, got %v", answer, want, score.Value)
+		}
+	}
+
+	if embedder.calls != 4 {
+		t.Errorf("Expected the expected answer to be embedded once, got %d calls", embedder.calls)
+	}
+	if _, err := scorer.Score(context.Background(), c, "unknown"); err == nil {
+		t.Error("Expected an embedding failure to be returned")
+	}
+}
+
+// judgeProvider replies with a fixed verdict
+type judgeProvider struct {
+	reply string
+}
+
+func (p *judgeProvider) Chat(ctx context.Context, req *chat.ChatRequest) (*chat.ChatResponse, error) {
+	return &chat.ChatResponse{Content: p.reply}, nil
+}
+
+func (p *judgeProvider) ChatStream(ctx context.Context, req *chat.ChatRequest, callback chat.StreamCallback) error {
+	return errors.New("judges never stream")
+}
+
+func TestJudge(t *testing.T) {
+	judge := NewJudge(&judgeProvider{reply: "Sure! ```json\n{\"score\": 4, \"reason\": \"Mostly right\"}\n```"})
+
+	score, err := judge.Score(context.Background(), Case{Question: "Q", Expected: "A"}, "a")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if score.Value != 0.75 || score.Note != "Mostly right" {
+		t.Errorf("Expected a grade of 4 to score 0.75 with its reason, got %+v", score)
+	}
+}
+
+func TestParseGrade_Invalid(t *testing.T) {
+	for _, reply := range []string{"Four", `{"score": "four"}`, `{"score": 9}`, `{"reason": "no score"}`} {
+		if _, _, err := parseGrade(reply); err == nil {
+			t.Errorf("Expected '%s' to be rejected", reply)
+		}
+	}
+}
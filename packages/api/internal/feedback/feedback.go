@@ -0,0 +1,236 @@
+// Package feedback collects what users think of answers. Answers are kept
+// with their prompt for a while so that feedback on them can be stored
+// alongside, and exported to build evaluation datasets.
+package feedback
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"chat-backend/internal/chat"
+)
+
+// DefaultWindow is how long an answer accepts feedback
+const DefaultWindow = 24 * time.Hour
+
+// Ratings
+const (
+	RatingUp   = "up"
+	RatingDown = "down"
+)
+
+var ErrNotFound = errors.New("message not found")
+
+type Feedback struct {
+	Rating    string    `json:"rating"`
+	Category  string    `json:"category,omitempty"`
+	Comment   string    `json:"comment,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Record is an answer with the prompt it was generated from, and the
+// feedback on it once there is some
+type Record struct {
+	MessageID      string         `json:"message_id"`
+	TenantID       string         `json:"tenant_id,omitempty"`
+	ConversationID string         `json:"conversation_id,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	Prompt         []chat.Message `json:"prompt"`
+	Answer         string         `json:"answer"`
+	Provider       string         `json:"provider,omitempty"`
+	Model          string         `json:"model,omitempty"`
+	Feedback       *Feedback      `json:"feedback,omitempty"`
+}
+
+// Question returns the last user message of the prompt
+func (r Record) Question() string {
+	for i := len(r.Prompt) - 1; i >= 0; i-- {
+		if r.Prompt[i].Role == "user" {
+			return r.Prompt[i].Content
+		}
+	}
+	return ""
+}
+
+// Filter picks records with feedback. Empty fields match everything.
+type Filter struct {
+	TenantID string
+	Rating   string
+	Category string
+	Since    time.Time
+}
+
+func (f Filter) matches(r *Record) bool {
+	return r.Feedback != nil &&
+		(f.TenantID == "" || r.TenantID == f.TenantID) &&
+		(f.Rating == "" || r.Feedback.Rating == f.Rating) &&
+		(f.Category == "" || r.Feedback.Category == f.Category) &&
+		(f.Since.IsZero() || !r.Feedback.CreatedAt.Before(f.Since))
+}
+
+// Store keeps answers for the feedback window. Answers with feedback are
+// kept for good and, when the store has a file, appended to it so that
+// they survive a restart.
+type Store struct {
+	window time.Duration
+	path   string
+	now    func() time.Time
+
+	mu      sync.Mutex
+	records map[string]*Record
+	pruned  time.Time
+}
+
+// NewStore returns a store that keeps everything in memory
+func NewStore(window time.Duration) *Store {
+	return &Store{window: window, now: time.Now, records: map[string]*Record{}}
+}
+
+// OpenStore returns a store that appends feedback to a JSON Lines file,
+// loading what the file already holds. Later lines for a message replace
+// earlier ones, as feedback can be changed.
+func OpenStore(path string, window time.Duration) (*Store, error) {
+	s := NewStore(window)
+	s.path = path
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open feedback file: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64<<10), 16<<20)
+	for line := 1; scanner.Scan(); line++ {
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("feedback file line %d: %w", line, err)
+		}
+		s.records[record.MessageID] = &record
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read feedback file: %w", err)
+	}
+	return s, nil
+}
+
+// Add keeps an answer so that it can get feedback. The data of prompt
+// attachments is dropped.
+func (s *Store) Add(record Record) {
+	record.Prompt = withoutAttachmentData(record.Prompt)
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = s.now().UTC()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Pruning goes through every record, so it is only done now and then
+	if now := s.now(); now.Sub(s.pruned) > time.Minute {
+		s.pruneLocked()
+		s.pruned = now
+	}
+	s.records[record.MessageID] = &record
+}
+
+// Submit stores feedback on a tenant's answer, replacing any it already
+// had. Answers of other tenants, and those past the window without
+// feedback, are reported as not found.
+func (s *Store) Submit(tenantID, messageID string, feedback Feedback) (Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[messageID]
+	if !ok || record.TenantID != tenantID || (record.Feedback == nil && s.expired(record)) {
+		return Record{}, fmt.Errorf("%w: %s", ErrNotFound, messageID)
+	}
+
+	if feedback.CreatedAt.IsZero() {
+		feedback.CreatedAt = s.now().UTC()
+	}
+	updated := *record
+	updated.Feedback = &feedback
+	if err := s.appendLocked(&updated); err != nil {
+		return Record{}, err
+	}
+	s.records[messageID] = &updated
+	return updated, nil
+}
+
+// List returns the records with feedback that match filter, oldest first
+func (s *Store) List(filter Filter) []Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var records []Record
+	for _, record := range s.records {
+		if filter.matches(record) {
+			records = append(records, *record)
+		}
+	}
+	slices.SortFunc(records, func(a, b Record) int {
+		return a.Feedback.CreatedAt.Compare(b.Feedback.CreatedAt)
+	})
+	return records
+}
+
+func (s *Store) appendLocked(record *Record) error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode feedback: %w", err)
+	}
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to save feedback: %w", err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to save feedback: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to save feedback: %w", err)
+	}
+	return nil
+}
+
+// pruneLocked drops answers that got no feedback in the window. Callers
+// hold the lock.
+func (s *Store) pruneLocked() {
+	for id, record := range s.records {
+		if record.Feedback == nil && s.expired(record) {
+			delete(s.records, id)
+		}
+	}
+}
+
+func (s *Store) expired(record *Record) bool {
+	return s.window > 0 && s.now().Sub(record.CreatedAt) > s.window
+}
+
+func withoutAttachmentData(messages []chat.Message) []chat.Message {
+	stripped := make([]chat.Message, len(messages))
+	for i, msg := range messages {
+		stripped[i] = msg
+		if len(msg.Attachments) == 0 {
+			continue
+		}
+		stripped[i].Attachments = make([]chat.Attachment, len(msg.Attachments))
+		for j, attachment := range msg.Attachments {
+			stripped[i].Attachments[j] = chat.Attachment{Filename: attachment.Filename, MimeType: attachment.MimeType}
+		}
+	}
+	return stripped
+}
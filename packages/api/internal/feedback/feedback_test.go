@@ -0,0 +1,107 @@
+package feedback
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"chat-backend/internal/chat"
+)
+
+func answer(tenantID, messageID string) Record {
+	return Record{
+		MessageID: messageID,
+		TenantID:  tenantID,
+		Prompt:    []chat.Message{{Role: "user", Content: "What is the capital of France?"}},
+		Answer:    "Paris.",
+	}
+}
+
+func TestStore_Submit(t *testing.T) {
+	store := NewStore(time.Hour)
+	store.Add(answer("acme", "m1"))
+
+	record, err := store.Submit("acme", "m1", Feedback{Rating: RatingUp, Comment: "Spot on"})
+	if err != nil {
+		t.Fatalf("Failed to submit feedback: %v", err)
+	}
+	if record.Answer != "Paris." || record.Feedback.Rating != RatingUp || record.Feedback.CreatedAt.IsZero() {
+		t.Errorf("Expected the answer with its feedback, got %+v", record)
+	}
+	if record.Question() != "What is the capital of France?" {
+		t.Errorf("Expected the question of the prompt, got '%s'", record.Question())
+	}
+
+	if _, err := store.Submit("globex", "m1", Feedback{Rating: RatingDown}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected another tenant's answer to be not found, got: %v", err)
+	}
+	if _, err := store.Submit("acme", "unknown", Feedback{Rating: RatingDown}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected an unknown answer to be not found, got: %v", err)
+	}
+}
+
+func TestStore_Window(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	store := NewStore(time.Hour)
+	store.now = func() time.Time { return now }
+
+	store.Add(answer("", "rated"))
+	store.Add(answer("", "unrated"))
+	store.Submit("", "rated", Feedback{Rating: RatingUp})
+	now = now.Add(2 * time.Hour)
+
+	if _, err := store.Submit("", "unrated", Feedback{Rating: RatingUp}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected feedback past the window to be refused, got: %v", err)
+	}
+	// Feedback already given can still be changed
+	if _, err := store.Submit("", "rated", Feedback{Rating: RatingDown}); err != nil {
+		t.Errorf("Expected rated answers to be kept, got: %v", err)
+	}
+}
+
+func TestStore_List(t *testing.T) {
+	store := NewStore(time.Hour)
+	for _, id := range []string{"m1", "m2", "m3", "m4"} {
+		store.Add(answer("acme", id))
+	}
+	store.Submit("acme", "m1", Feedback{Rating: RatingDown, Category: "wrong"})
+	store.Submit("acme", "m2", Feedback{Rating: RatingUp})
+	store.Submit("acme", "m3", Feedback{Rating: RatingDown, Category: "incomplete"})
+
+	if records := store.List(Filter{}); len(records) != 3 || records[0].MessageID != "m1" {
+		t.Errorf("Expected the rated answers in the order they were rated, got %+v", records)
+	}
+	if records := store.List(Filter{Rating: RatingDown, Category: "wrong"}); len(records) != 1 || records[0].MessageID != "m1" {
+		t.Errorf("Expected the filter to pick m1, got %+v", records)
+	}
+	if records := store.List(Filter{TenantID: "globex"}); len(records) != 0 {
+		t.Errorf("Expected no records for another tenant, got %+v", records)
+	}
+}
+
+func TestOpenStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feedback.jsonl")
+	store, err := OpenStore(path, time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+
+	record := answer("", "m1")
+	record.Prompt[0].Attachments = []chat.Attachment{{Filename: "map.png", MimeType: "image/png", Data: []byte("png")}}
+	store.Add(record)
+	store.Submit("", "m1", Feedback{Rating: RatingUp})
+	store.Submit("", "m1", Feedback{Rating: RatingDown, Comment: "Changed my mind"})
+
+	reopened, err := OpenStore(path, time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to reopen store: %v", err)
+	}
+	records := reopened.List(Filter{})
+	if len(records) != 1 || records[0].Feedback.Rating != RatingDown {
+		t.Fatalf("Expected the latest feedback to be loaded, got %+v", records)
+	}
+	if attachment := records[0].Prompt[0].Attachments[0]; attachment.Filename != "map.png" || len(attachment.Data) != 0 {
+		t.Errorf("Expected attachments to be saved without their data, got %+v", attachment)
+	}
+}
@@ -0,0 +1,81 @@
+// Package generations tracks the chat generations in progress so that
+// clients can stop one by its ID from another request.
+package generations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"chat-backend/internal/chat"
+	"chat-backend/internal/ids"
+)
+
+var ErrNotFound = errors.New("generation not found")
+
+// Registry holds the cancel functions of the generations in progress,
+// keyed by tenant and ID so that tenants cannot reach each other's
+type Registry struct {
+	mu     sync.Mutex
+	active map[key]context.CancelCauseFunc
+}
+
+type key struct {
+	tenantID string
+	id       string
+}
+
+func NewRegistry() *Registry {
+	return &Registry{active: map[key]context.CancelCauseFunc{}}
+}
+
+// Start registers a generation for the tenant under a new random ID. IDs
+// are never chosen by clients: tenants are often shared, so only an ID
+// that cannot be guessed keeps one user from cancelling, or resuming the
+// stream of, another's generation. The returned context is cancelled with
+// chat.ErrCancelled as its cause when the generation is cancelled, and
+// finish must be called once the generation is over.
+func (r *Registry) Start(ctx context.Context, tenantID string) (string, context.Context, func()) {
+	id := ids.New()
+	k := key{tenantID: tenantID, id: id}
+
+	generationCtx, cancel := context.WithCancelCause(ctx)
+	r.mu.Lock()
+	r.active[k] = cancel
+	r.mu.Unlock()
+
+	finish := func() {
+		r.mu.Lock()
+		delete(r.active, k)
+		r.mu.Unlock()
+		cancel(nil)
+	}
+	return id, generationCtx, finish
+}
+
+// Cancel stops a generation of the tenant
+func (r *Registry) Cancel(tenantID, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cancel, ok := r.active[key{tenantID: tenantID, id: id}]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	cancel(chat.ErrCancelled)
+	return nil
+}
+
+// Active returns how many generations are in progress
+func (r *Registry) Active() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.active)
+}
+
+// Cancelled reports whether ctx, or the generation it belongs to, was
+// cancelled through Cancel rather than by the client going away
+func Cancelled(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), chat.ErrCancelled)
+}
@@ -0,0 +1,77 @@
+package generations
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"chat-backend/internal/chat"
+)
+
+func TestRegistry_Cancel(t *testing.T) {
+	registry := NewRegistry()
+	id, ctx, finish := registry.Start(context.Background(), "acme")
+	defer finish()
+
+	if err := registry.Cancel("acme", id); err != nil {
+		t.Fatalf("Expected the generation to be cancelled, got: %v", err)
+	}
+	if ctx.Err() == nil || !Cancelled(ctx) {
+		t.Error("Expected the generation's context to be cancelled by Cancel")
+	}
+	if !errors.Is(context.Cause(ctx), chat.ErrCancelled) {
+		t.Errorf("Expected chat.ErrCancelled as the cause, got %v", context.Cause(ctx))
+	}
+}
+
+func TestRegistry_CancelOtherTenant(t *testing.T) {
+	registry := NewRegistry()
+	id, ctx, finish := registry.Start(context.Background(), "acme")
+	defer finish()
+
+	if err := registry.Cancel("globex", id); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected another tenant's generation to be not found, got: %v", err)
+	}
+	if ctx.Err() != nil {
+		t.Error("Expected the generation to keep running")
+	}
+}
+
+func TestRegistry_Finish(t *testing.T) {
+	registry := NewRegistry()
+	id, ctx, finish := registry.Start(context.Background(), "")
+	finish()
+
+	if registry.Active() != 0 {
+		t.Errorf("Expected no active generations, got %d", registry.Active())
+	}
+	if err := registry.Cancel("", id); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected a finished generation to be not found, got: %v", err)
+	}
+	if Cancelled(ctx) {
+		t.Error("Expected a finished generation not to count as cancelled")
+	}
+}
+
+func TestCancelled_ClientGone(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	_, ctx, finish := NewRegistry().Start(parent, "")
+	defer finish()
+
+	cancel()
+	if ctx.Err() == nil || Cancelled(ctx) {
+		t.Error("Expected a client going away not to count as a cancel")
+	}
+}
+
+func TestRegistry_GeneratesIDs(t *testing.T) {
+	registry := NewRegistry()
+	first, _, finishFirst := registry.Start(context.Background(), "")
+	second, _, finishSecond := registry.Start(context.Background(), "")
+	defer finishFirst()
+	defer finishSecond()
+
+	if len(first) != 32 || first == second {
+		t.Errorf("Expected distinct random IDs, got '%s' and '%s'", first, second)
+	}
+}
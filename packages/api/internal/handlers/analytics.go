@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"chat-backend/internal/analytics"
+	"chat-backend/internal/app"
+)
+
+const (
+	defaultReportLimit = 20
+	maxReportLimit     = 500
+)
+
+type TopQuestionsResponse struct {
+	Questions []analytics.QuestionStats `json:"questions"`
+}
+
+type UnansweredResponse struct {
+	Clusters []analytics.Cluster `json:"clusters"`
+}
+
+type CostResponse struct {
+	Interval string                 `json:"interval"`
+	Buckets  []analytics.CostBucket `json:"buckets"`
+}
+
+// TopQuestionsHandler returns the questions asked most often
+func TopQuestionsHandler(appCtx *app.AppContext) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		q, limit, err := reportQuery(c)
+		if err != nil {
+			return err
+		}
+		questions := appCtx.Analytics.TopQuestions(q, limit)
+		return c.JSON(http.StatusOK, TopQuestionsResponse{Questions: nonNil(questions)})
+	}
+}
+
+// UnansweredQuestionsHandler returns the questions that got a fallback
+// answer, clustered by the words they share
+func UnansweredQuestionsHandler(appCtx *app.AppContext) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		q, limit, err := reportQuery(c)
+		if err != nil {
+			return err
+		}
+		clusters := appCtx.Analytics.UnansweredClusters(q, limit)
+		return c.JSON(http.StatusOK, UnansweredResponse{Clusters: nonNil(clusters)})
+	}
+}
+
+// LatencyHandler returns latency percentiles, overall and by provider
+func LatencyHandler(appCtx *app.AppContext) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		q, _, err := reportQuery(c)
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, appCtx.Analytics.Latency(q))
+	}
+}
+
+// CostHandler returns token usage and cost over time, by the hour or by
+// the day as the interval query parameter picks
+func CostHandler(appCtx *app.AppContext) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		q, _, err := reportQuery(c)
+		if err != nil {
+			return err
+		}
+
+		interval := c.QueryParam("interval")
+		var length time.Duration
+		switch interval {
+		case "hour":
+			length = time.Hour
+		case "", "day":
+			interval, length = "day", 24*time.Hour
+		default:
+			return &requestError{Status: http.StatusBadRequest, Message: "interval must be hour or day"}
+		}
+
+		buckets := appCtx.Analytics.Cost(q, length)
+		return c.JSON(http.StatusOK, CostResponse{Interval: interval, Buckets: nonNil(buckets)})
+	}
+}
+
+// reportQuery reads the tenant, since, until and limit query parameters
+// the reports share
+func reportQuery(c echo.Context) (analytics.Query, int, error) {
+	v := &ValidationError{}
+	q := analytics.Query{TenantID: c.QueryParam("tenant")}
+
+	for _, param := range []struct {
+		name string
+		t    *time.Time
+	}{{"since", &q.Since}, {"until", &q.Until}} {
+		value := c.QueryParam(param.name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			v.add(param.name, "must be an RFC 3339 time")
+			continue
+		}
+		*param.t = t
+	}
+
+	limit := defaultReportLimit
+	if value := c.QueryParam("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxReportLimit {
+			v.add("limit", "must be a number from 1 to %d", maxReportLimit)
+		}
+		limit = n
+	}
+
+	if len(v.Errors) > 0 {
+		return analytics.Query{}, 0, v
+	}
+	return q, limit, nil
+}
+
+// nonNil keeps empty reports as [] rather than null in JSON
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"chat-backend/internal/app"
+	"chat-backend/internal/chat"
+)
+
+func getReport(t *testing.T, handler echo.HandlerFunc, query string, v any) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/analytics"+query, nil)
+	recorder := httptest.NewRecorder()
+	runHandler(handler, echo.New().NewContext(req, recorder))
+	if recorder.Code == http.StatusOK {
+		if err := json.Unmarshal(recorder.Body.Bytes(), v); err != nil {
+			t.Fatalf("Failed to decode report: %v", err)
+		}
+	}
+	return recorder
+}
+
+func TestAnalyticsHandlers(t *testing.T) {
+	provider := &mockChatProvider{response: &chat.ChatResponse{
+		Content:      "I don't have an answer for that question.",
+		FinishReason: chat.FinishReasonNoAnswer,
+		Usage:        &chat.Usage{PromptTokens: 10, CompletionTokens: 8, TotalTokens: 18},
+	}}
+	appCtx := app.NewAppContext(provider)
+	appCtx.ProviderName, appCtx.Model = "azure", "kb"
+
+	sendChat(t, appCtx, ChatRequest{Messages: []Message{{Role: "user", Content: "Do you ship to Canada?"}}})
+	sendChat(t, appCtx, ChatRequest{Messages: []Message{{Role: "user", Content: "Do you ship to Canada?"}}, Streaming: true})
+
+	var questions TopQuestionsResponse
+	getReport(t, TopQuestionsHandler(appCtx), "/questions", &questions)
+	if len(questions.Questions) != 1 || questions.Questions[0].Count != 2 {
+		t.Fatalf("Expected the question asked twice, got %+v", questions)
+	}
+
+	var unanswered UnansweredResponse
+	getReport(t, UnansweredQuestionsHandler(appCtx), "/unanswered", &unanswered)
+	if len(unanswered.Clusters) != 1 || unanswered.Clusters[0].Count != 2 {
+		t.Errorf("Expected streamed and plain fallbacks to be unanswered, got %+v", unanswered)
+	}
+
+	var cost CostResponse
+	getReport(t, CostHandler(appCtx), "/cost", &cost)
+	if cost.Interval != "day" || len(cost.Buckets) != 1 || cost.Buckets[0].PromptTokens != 20 || cost.Buckets[0].Unpriced != 2 {
+		t.Errorf("Expected a day of unpriced usage, got %+v", cost)
+	}
+
+	var empty TopQuestionsResponse
+	getReport(t, TopQuestionsHandler(appCtx), "/questions?tenant=globex", &empty)
+	if empty.Questions == nil || len(empty.Questions) != 0 {
+		t.Errorf("Expected no questions for another tenant, got %+v", empty)
+	}
+
+	for _, query := range []string{"?limit=0", "?since=yesterday", "?interval=week"} {
+		if recorder := getReport(t, CostHandler(appCtx), query, nil); recorder.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status code %d, got %d", query, http.StatusBadRequest, recorder.Code)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/labstack/echo/v4"
+
+	"chat-backend/internal/chat"
+)
+
+const (
+	maxAttachments          = 8
+	maxAttachmentBytes      = 5 << 20
+	maxTotalAttachmentBytes = 20 << 20
+
+	// Room for the messages and other form fields on top of the files
+	maxMultipartOverhead = 1 << 20
+
+	// JSON requests carry attachments base64 encoded, a third larger
+	maxJSONBodyBytes = maxTotalAttachmentBytes/3*4 + maxMultipartOverhead
+)
+
+var allowedAttachmentTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// multipartFields are the form fields of a multipart chat request. The
+// JSON ones hold the same values as in a JSON body.
+var multipartFields = map[string]bool{
+	"messages":        true,
+	"prompt":          true,
+	"streaming":       true,
+	"tools":           true,
+	"conversation_id": true,
+}
+
+// bindChatRequest decodes a chat request from either a JSON body or a
+// multipart form. Multipart forms carry the messages as a JSON array in the
+// "messages" field and attach every file in the "files" field to the last
+// user message. Other fields are rejected rather than ignored.
+func bindChatRequest(c echo.Context, chatReq *ChatRequest) error {
+	contentType := c.Request().Header.Get(echo.HeaderContentType)
+	req := c.Request()
+
+	if !strings.HasPrefix(contentType, echo.MIMEMultipartForm) {
+		return bindJSON(c, chatReq)
+	}
+
+	req.Body = http.MaxBytesReader(c.Response(), req.Body, maxTotalAttachmentBytes+maxMultipartOverhead)
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return &requestError{Status: http.StatusRequestEntityTooLarge, Message: "Request body is too large"}
+		}
+		return &requestError{Status: http.StatusBadRequest, Message: "Invalid multipart form"}
+	}
+
+	for field := range form.Value {
+		if !multipartFields[field] {
+			return &requestError{Status: http.StatusBadRequest, Message: fmt.Sprintf("Unknown form field %q", field)}
+		}
+	}
+
+	if values := form.Value["messages"]; len(values) > 0 {
+		if !utf8.ValidString(values[0]) {
+			return invalidUTF8("messages")
+		}
+		if err := json.Unmarshal([]byte(values[0]), &chatReq.Messages); err != nil {
+			return &requestError{Status: http.StatusBadRequest, Message: "Invalid messages field"}
+		}
+	}
+
+	if values := form.Value["prompt"]; len(values) > 0 {
+		if err := json.Unmarshal([]byte(values[0]), &chatReq.Prompt); err != nil {
+			return &requestError{Status: http.StatusBadRequest, Message: "Invalid prompt field"}
+		}
+	}
+
+	if values := form.Value["streaming"]; len(values) > 0 {
+		chatReq.Streaming, _ = strconv.ParseBool(values[0])
+	}
+
+	if values := form.Value["tools"]; len(values) > 0 {
+		if !utf8.ValidString(values[0]) {
+			return invalidUTF8("tools")
+		}
+		if err := json.Unmarshal([]byte(values[0]), &chatReq.Tools); err != nil {
+			return &requestError{Status: http.StatusBadRequest, Message: "Invalid tools field"}
+		}
+	}
+
+	if values := form.Value["conversation_id"]; len(values) > 0 {
+		chatReq.ConversationID = values[0]
+	}
+
+	files := form.File["files"]
+	if len(files) == 0 {
+		return nil
+	}
+
+	lastUser := -1
+	for i := len(chatReq.Messages) - 1; i >= 0; i-- {
+		if chatReq.Messages[i].Role == "user" {
+			lastUser = i
+			break
+		}
+	}
+	if lastUser < 0 {
+		return &requestError{Status: http.StatusBadRequest, Message: "Files require a user message to attach to"}
+	}
+
+	for _, file := range files {
+		if file.Size > maxAttachmentBytes {
+			return attachmentTooLarge(file.Filename)
+		}
+
+		attachment, err := readAttachment(file)
+		if err != nil {
+			return &requestError{Status: http.StatusBadRequest, Message: "Failed to read uploaded file"}
+		}
+		chatReq.Messages[lastUser].Attachments = append(chatReq.Messages[lastUser].Attachments, attachment)
+	}
+
+	return nil
+}
+
+// bindJSON decodes a JSON request body into target, rejecting bodies that
+// are too large or not valid UTF-8
+func bindJSON(c echo.Context, target any) error {
+	req := c.Request()
+
+	// The JSON decoder silently replaces invalid UTF-8, so check the raw
+	// body before binding
+	body, err := io.ReadAll(http.MaxBytesReader(c.Response(), req.Body, maxJSONBodyBytes))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return &requestError{Status: http.StatusRequestEntityTooLarge, Message: "Request body is too large"}
+		}
+		return &requestError{Status: http.StatusBadRequest, Message: "Failed to read request body"}
+	}
+	if !utf8.Valid(body) {
+		return invalidUTF8("body")
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	if err := c.Bind(target); err != nil {
+		return &requestError{Status: http.StatusBadRequest, Message: "Invalid request format"}
+	}
+	return nil
+}
+
+func readAttachment(file *multipart.FileHeader) (chat.Attachment, error) {
+	f, err := file.Open()
+	if err != nil {
+		return chat.Attachment{}, err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, maxAttachmentBytes+1))
+	if err != nil {
+		return chat.Attachment{}, err
+	}
+
+	return chat.Attachment{
+		Filename: file.Filename,
+		MimeType: file.Header.Get(echo.HeaderContentType),
+		Data:     data,
+	}, nil
+}
+
+// validateAttachments enforces count, size and type limits on attachments.
+// The MIME type is sniffed from the content rather than trusted from the
+// client, and replaced with the detected type.
+func validateAttachments(messages []Message) error {
+	count := 0
+	total := 0
+
+	for i := range messages {
+		for j := range messages[i].Attachments {
+			attachment := &messages[i].Attachments[j]
+
+			count++
+			if count > maxAttachments {
+				return &requestError{
+					Status:  http.StatusBadRequest,
+					Message: fmt.Sprintf("At most %d attachments are allowed", maxAttachments),
+				}
+			}
+
+			size := len(attachment.Data)
+			if size == 0 {
+				return &requestError{Status: http.StatusBadRequest, Message: "Attachments cannot be empty"}
+			}
+			if size > maxAttachmentBytes {
+				return attachmentTooLarge(attachment.Filename)
+			}
+
+			total += size
+			if total > maxTotalAttachmentBytes {
+				return &requestError{Status: http.StatusRequestEntityTooLarge, Message: "Attachments exceed the total size limit"}
+			}
+
+			detected := http.DetectContentType(attachment.Data)
+			if !allowedAttachmentTypes[detected] {
+				return &requestError{
+					Status:  http.StatusUnsupportedMediaType,
+					Message: fmt.Sprintf("Unsupported attachment type %s", detected),
+				}
+			}
+			attachment.MimeType = detected
+		}
+	}
+
+	return nil
+}
+
+func attachmentTooLarge(filename string) error {
+	return &requestError{
+		Status:  http.StatusRequestEntityTooLarge,
+		Message: fmt.Sprintf("Attachment %s exceeds the %d MB limit", filename, maxAttachmentBytes>>20),
+	}
+}
+
+func invalidUTF8(field string) error {
+	return &ValidationError{Errors: []FieldError{{Field: field, Message: "must be valid UTF-8"}}}
+}
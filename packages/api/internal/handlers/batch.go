@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"chat-backend/internal/app"
+	"chat-backend/internal/batch"
+	"chat-backend/internal/chat"
+)
+
+const (
+	// MIMEApplicationNDJSON is the content type of batch results, one JSON
+	// document per line
+	MIMEApplicationNDJSON = "application/x-ndjson"
+
+	maxBatchParallelism = 16
+	maxBatchBodyBytes   = 64 << 20
+	// maxBatchItems bounds the provider calls one request can make
+	maxBatchItems = 1000
+)
+
+// BatchHandler runs a JSONL file of chat requests and streams a JSONL line
+// back for each as it completes, in input order. The ?parallelism= query
+// parameter bounds how many run at once, and a batch stops with an error
+// after maxBatchItems.
+func BatchHandler(appCtx *app.AppContext) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		parallelism := 0
+		if raw := c.QueryParam("parallelism"); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 1 || n > maxBatchParallelism {
+				return &ValidationError{Errors: []FieldError{{Field: "parallelism", Message: fmt.Sprintf("must be between 1 and %d", maxBatchParallelism)}}}
+			}
+			parallelism = n
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, MIMEApplicationNDJSON)
+		c.Response().Header().Set("Cache-Control", "no-cache")
+		c.Response().WriteHeader(http.StatusOK)
+
+		body := http.MaxBytesReader(c.Response(), c.Request().Body, maxBatchBodyBytes)
+		opts := BatchOptions(appCtx, parallelism)
+		opts.MaxItems = maxBatchItems
+		summary, err := batch.Run(c.Request().Context(), appCtx.ChatProvider, body, flushWriter{c.Response()}, opts)
+		if err != nil {
+			slog.Error("Batch failed part way", "error", err, "items", summary.Items)
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				err = &requestError{Status: http.StatusRequestEntityTooLarge, Message: "Request body is too large"}
+			}
+
+			// Results are already streaming, so the error goes on the last line
+			problem := NewProblemDetails(err)
+			errorLine, _ := json.Marshal(StreamError{
+				Error:  problem.Detail,
+				Type:   problem.Type,
+				Status: problem.Status,
+			})
+			c.Response().Write(append(errorLine, '\n'))
+			c.Response().Flush()
+			return nil
+		}
+
+		slog.Info("Batch completed", "items", summary.Items, "failed", summary.Failed, "totalTokens", summary.Usage.TotalTokens, "duration", summary.Duration)
+		return nil
+	}
+}
+
+// BatchOptions validates, prompts and reports batch items the way the chat
+// endpoint does. The batch command uses it too, so both give the same
+// results.
+func BatchOptions(appCtx *app.AppContext, parallelism int) batch.Options {
+	return batch.Options{
+		Parallelism: parallelism,
+		Prepare: func(ctx context.Context, req *chat.ChatRequest) (*chat.ChatRequest, map[string]string, error) {
+			return prepareBatchRequest(ctx, appCtx, req)
+		},
+		Describe: BatchFailure,
+	}
+}
+
+// BatchFailure reports why a batch item failed the way a single chat
+// request would have been answered, with any invalid fields in the detail
+func BatchFailure(err error) batch.Failure {
+	problem := NewProblemDetails(err)
+	if problem.Status >= http.StatusInternalServerError {
+		// The detail is generic, so keep the upstream text in the logs
+		slog.Error("Batch item failed", "error", err, "status", problem.Status)
+	}
+	failure := batch.Failure{
+		Type:   problem.Type,
+		Title:  problem.Title,
+		Status: problem.Status,
+		Detail: problem.Detail,
+	}
+	if len(problem.Errors) > 0 {
+		fields := make([]string, len(problem.Errors))
+		for i, fieldErr := range problem.Errors {
+			fields[i] = fieldErr.Field + " " + fieldErr.Message
+		}
+		failure.Detail = strings.Join(fields, "; ")
+	}
+	return failure
+}
+
+// prepareBatchRequest turns a batch item into the request the chat endpoint
+// would send for it, validated and with the tenant's prompt in ctx
+func prepareBatchRequest(ctx context.Context, appCtx *app.AppContext, req *chat.ChatRequest) (*chat.ChatRequest, map[string]string, error) {
+	chatReq := ChatRequest{Tools: req.Tools}
+	for _, msg := range req.Messages {
+		chatReq.Messages = append(chatReq.Messages, Message{
+			Role:        msg.Role,
+			Content:     msg.Content,
+			ToolCalls:   msg.ToolCalls,
+			ToolCallID:  msg.ToolCallID,
+			Attachments: msg.Attachments,
+		})
+	}
+
+	return NewChatRequest(ctx, appCtx, &chatReq)
+}
+
+// flushWriter sends each batch result to the client as soon as it is written
+type flushWriter struct {
+	response *echo.Response
+}
+
+func (w flushWriter) Write(p []byte) (int, error) {
+	n, err := w.response.Write(p)
+	w.response.Flush()
+	return n, err
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"chat-backend/internal/batch"
+	"chat-backend/internal/prompts"
+	"chat-backend/internal/tenant"
+)
+
+func TestBatchHandler_StreamsResults(t *testing.T) {
+	appCtx, _ := newScriptedAppContext(t, `
+rules:
+  - match: "refund"
+    response: "Refunds take 5 days"
+  - match: "broken"
+    error: unavailable
+default:
+  response: "I don't know"
+`)
+
+	body := `{"id": "q1", "question": "How do refunds work?"}
+{"id": "q2", "messages": [{"role": "bogus", "content": "Hi"}]}
+{"id": "q3", "question": "Is this broken?"}
+`
+	req := httptest.NewRequest(http.MethodPost, "/api/chat/batch?parallelism=2", strings.NewReader(body))
+	recorder := httptest.NewRecorder()
+	c := echo.New().NewContext(req, recorder)
+
+	runHandler(BatchHandler(appCtx), c)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, recorder.Code)
+	}
+	if contentType := recorder.Header().Get(echo.HeaderContentType); contentType != MIMEApplicationNDJSON {
+		t.Errorf("Expected NDJSON content type, got '%s'", contentType)
+	}
+
+	lines := strings.Split(strings.TrimSpace(recorder.Body.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 result lines, got %d: %s", len(lines), recorder.Body.String())
+	}
+	results := make([]batch.Result, len(lines))
+	for i, line := range lines {
+		if err := json.Unmarshal([]byte(line), &results[i]); err != nil {
+			t.Fatalf("Failed to decode result line: %v", err)
+		}
+	}
+
+	if results[0].ID != "q1" || results[0].Response != "Refunds take 5 days" {
+		t.Errorf("Unexpected first result: %+v", results[0])
+	}
+	if failure := results[1].Error; failure == nil || failure.Type != "urn:chat-backend:problem:validation-failed" || !strings.Contains(failure.Detail, "messages[0].role") {
+		t.Errorf("Expected the chat endpoint's validation, got %+v", failure)
+	}
+	if failure := results[2].Error; failure == nil || failure.Status != http.StatusServiceUnavailable {
+		t.Errorf("Expected the provider failure, got %+v", failure)
+	}
+}
+
+func TestBatchHandler_TenantPrompt(t *testing.T) {
+	appCtx, provider := newScriptedAppContext(t, `default: {response: "ok"}`)
+	appCtx.Prompts.Create(prompts.Template{Name: "faq", Content: "Answer questions about {{.product}}."})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/chat/batch", strings.NewReader(`{"question": "Hi"}`+"\n"))
+	req = req.WithContext(tenant.WithTenant(req.Context(), tenant.Tenant{
+		ID:              "acme",
+		PromptTemplate:  "faq",
+		PromptVariables: map[string]string{"product": "rockets"},
+	}))
+	recorder := httptest.NewRecorder()
+	runHandler(BatchHandler(appCtx), echo.New().NewContext(req, recorder))
+
+	messages := provider.Requests()[0].Messages
+	if len(messages) != 2 || messages[0].Content != "Answer questions about rockets." {
+		t.Errorf("Expected the tenant prompt ahead of the item, got %+v", messages)
+	}
+
+	var result batch.Result
+	if err := json.Unmarshal(recorder.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Failed to decode result line: %v", err)
+	}
+	if result.Metadata[MetadataPromptTemplate] != "faq" {
+		t.Errorf("Expected the prompt template in the metadata, got %v", result.Metadata)
+	}
+}
+
+func TestBatchHandler_InvalidParallelism(t *testing.T) {
+	appCtx, _ := newScriptedAppContext(t, `default: {response: "ok"}`)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/chat/batch?parallelism=100", strings.NewReader(`{"question": "Hi"}`))
+	recorder := httptest.NewRecorder()
+	c := echo.New().NewContext(req, recorder)
+
+	runHandler(BatchHandler(appCtx), c)
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, recorder.Code)
+	}
+}
+
+func TestBatchHandler_TooManyItems(t *testing.T) {
+	appCtx, provider := newScriptedAppContext(t, `default: {response: "ok"}`)
+
+	body := strings.Repeat(`{"question": "Hi"}`+"\n", maxBatchItems+1)
+	req := httptest.NewRequest(http.MethodPost, "/api/chat/batch?parallelism=16", strings.NewReader(body))
+	recorder := httptest.NewRecorder()
+	runHandler(BatchHandler(appCtx), echo.New().NewContext(req, recorder))
+
+	lines := strings.Split(strings.TrimSpace(recorder.Body.String()), "\n")
+	var last StreamError
+	json.Unmarshal([]byte(lines[len(lines)-1]), &last)
+	if len(lines) != maxBatchItems+1 || last.Status != http.StatusBadRequest {
+		t.Errorf("Expected %d results and a 400 error line, got %d lines ending with %+v", maxBatchItems, len(lines), last)
+	}
+	if requests := len(provider.Requests()); requests != maxBatchItems {
+		t.Errorf("Expected %d provider calls, got %d", maxBatchItems, requests)
+	}
+}
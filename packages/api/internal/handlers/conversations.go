@@ -0,0 +1,292 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"slices"
+	"time"
+	"unicode/utf8"
+
+	"github.com/labstack/echo/v4"
+
+	"chat-backend/internal/analytics"
+	"chat-backend/internal/app"
+	"chat-backend/internal/chat"
+	"chat-backend/internal/chat/cache"
+	"chat-backend/internal/conversations"
+	"chat-backend/internal/feedback"
+	"chat-backend/internal/ids"
+	"chat-backend/internal/tenant"
+)
+
+// exchange is a chat request and the answer it is getting, under a message
+// ID. Once answered, it is recorded for feedback and analytics, and in the
+// conversation the request continues, if any.
+type exchange struct {
+	messageID      string
+	tenantID       string
+	conversationID string
+	received       time.Time
+	// messages are the request's own, and prompt adds the history ahead
+	messages []Message
+	prompt   []Message
+}
+
+// maxHistoryMessages is how much of a conversation's history goes with a
+// request. Providers with a context window trim it further.
+const maxHistoryMessages = maxMessages
+
+// conversationIDPattern limits conversation IDs to characters that are
+// safe in the export URL
+var conversationIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// startExchange loads the history of the conversation a request continues,
+// to go ahead of its messages. Conversations must have been started, by
+// CreateConversationHandler or ImportConversationHandler.
+func startExchange(c echo.Context, appCtx *app.AppContext, chatReq *ChatRequest) (*exchange, error) {
+	if chatReq.ConversationID != "" && !conversationIDPattern.MatchString(chatReq.ConversationID) {
+		v := &ValidationError{}
+		v.add("conversation_id", "must be 1 to 64 letters, digits, '-' or '_'")
+		return nil, v
+	}
+
+	ex := &exchange{
+		messageID:      ids.New(),
+		tenantID:       tenant.FromContext(c.Request().Context()).ID,
+		conversationID: chatReq.ConversationID,
+		received:       time.Now().UTC(),
+		messages:       chatReq.Messages,
+		prompt:         chatReq.Messages,
+	}
+	if ex.conversationID == "" {
+		return ex, nil
+	}
+
+	conversation, err := appCtx.Conversations.Get(ex.tenantID, ex.conversationID)
+	if errors.Is(err, conversations.ErrNotFound) {
+		return nil, &requestError{Status: http.StatusNotFound, Message: err.Error()}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, msg := range recentHistory(conversation.ChatMessages()) {
+		chatReq.history = append(chatReq.history, Message{
+			Role:       msg.Role,
+			Content:    msg.Content,
+			ToolCalls:  msg.ToolCalls,
+			ToolCallID: msg.ToolCallID,
+		})
+	}
+	ex.prompt = slices.Concat(chatReq.history, chatReq.Messages)
+	return ex, nil
+}
+
+// recentHistory keeps the leading system messages of a conversation and
+// its latest messages, up to maxHistoryMessages in all. It does not start
+// with tool results whose call was cut off.
+func recentHistory(messages []chat.Message) []chat.Message {
+	if len(messages) <= maxHistoryMessages {
+		return messages
+	}
+
+	system := 0
+	for system < len(messages) && messages[system].Role == "system" {
+		system++
+	}
+	start := max(len(messages)-(maxHistoryMessages-min(system, maxHistoryMessages)), system)
+	for start < len(messages) && messages[start].Role == "tool" {
+		start++
+	}
+	return slices.Concat(messages[:min(system, maxHistoryMessages)], messages[start:])
+}
+
+// record keeps the answer for feedback and analytics, and adds the
+// request's messages and the answer to the conversation
+func (ex *exchange) record(appCtx *app.AppContext, answer *chat.ChatResponse) {
+	event := analytics.Event{
+		MessageID:  ex.messageID,
+		TenantID:   ex.tenantID,
+		Answer:     answer.Content,
+		Provider:   appCtx.ProviderName,
+		Model:      appCtx.Model,
+		Latency:    time.Since(ex.received),
+		Unanswered: answer.FinishReason == chat.FinishReasonNoAnswer,
+		Cached:     answer.Metadata[cache.MetadataCache] == cache.StatusHit,
+	}
+	for i := len(ex.messages) - 1; i >= 0; i-- {
+		if ex.messages[i].Role == "user" {
+			event.Question = ex.messages[i].Content
+			break
+		}
+	}
+	if answer.Usage != nil {
+		event.PromptTokens, event.CompletionTokens = answer.Usage.PromptTokens, answer.Usage.CompletionTokens
+	}
+	appCtx.Analytics.Record(event)
+
+	var prompt []chat.Message
+	for _, msg := range ex.prompt {
+		prompt = append(prompt, chat.Message{
+			Role:        msg.Role,
+			Content:     msg.Content,
+			ToolCalls:   msg.ToolCalls,
+			ToolCallID:  msg.ToolCallID,
+			Attachments: msg.Attachments,
+		})
+	}
+	appCtx.Feedback.Add(feedback.Record{
+		MessageID:      ex.messageID,
+		TenantID:       ex.tenantID,
+		ConversationID: ex.conversationID,
+		Prompt:         prompt,
+		Answer:         answer.Content,
+		Provider:       appCtx.ProviderName,
+		Model:          appCtx.Model,
+	})
+
+	if ex.conversationID == "" {
+		return
+	}
+
+	var messages []conversations.Message
+	for _, msg := range ex.messages {
+		messages = append(messages, conversations.Message{
+			Role:       msg.Role,
+			Content:    msg.Content,
+			ToolCalls:  msg.ToolCalls,
+			ToolCallID: msg.ToolCallID,
+			CreatedAt:  ex.received,
+		})
+	}
+	messages = append(messages, conversations.Message{
+		ID:        ex.messageID,
+		Role:      "assistant",
+		Content:   answer.Content,
+		ToolCalls: answer.ToolCalls,
+		Provider:  appCtx.ProviderName,
+		Model:     appCtx.Model,
+		Usage:     answer.Usage,
+	})
+
+	if _, err := appCtx.Conversations.Append(ex.tenantID, ex.conversationID, messages...); err != nil {
+		slog.Error("Failed to record conversation", "conversation", ex.conversationID, "error", err)
+	}
+}
+
+// ExportConversationHandler returns a conversation as a transcript to
+// download, in the format named by the format query parameter: json, the
+// default, markdown or text
+func ExportConversationHandler(appCtx *app.AppContext) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tenantID := tenant.FromContext(c.Request().Context()).ID
+		conversation, err := appCtx.Conversations.Get(tenantID, c.Param("id"))
+		if errors.Is(err, conversations.ErrNotFound) {
+			return &requestError{Status: http.StatusNotFound, Message: err.Error()}
+		}
+		if err != nil {
+			return err
+		}
+
+		var write func(io.Writer, conversations.Conversation) error
+		var contentType, extension string
+		switch format := c.QueryParam("format"); format {
+		case "", "json":
+			c.Response().Header().Set("Content-Disposition", attachmentDisposition(conversation.ID, "json"))
+			return c.JSON(http.StatusOK, conversation)
+		case "markdown", "md":
+			write, contentType, extension = conversations.WriteMarkdown, "text/markdown; charset=utf-8", "md"
+		case "text", "txt":
+			write, contentType, extension = conversations.WriteText, echo.MIMETextPlainCharsetUTF8, "txt"
+		default:
+			return &requestError{Status: http.StatusBadRequest, Message: "format must be json, markdown or text"}
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, contentType)
+		c.Response().Header().Set("Content-Disposition", attachmentDisposition(conversation.ID, extension))
+		c.Response().WriteHeader(http.StatusOK)
+		return write(c.Response(), conversation)
+	}
+}
+
+func attachmentDisposition(id, extension string) string {
+	return fmt.Sprintf(`attachment; filename="conversation-%s.%s"`, id, extension)
+}
+
+// CreateConversationHandler starts an empty conversation, whose generated
+// ID chat requests then continue
+func CreateConversationHandler(appCtx *app.AppContext) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tenantID := tenant.FromContext(c.Request().Context()).ID
+		conversation := appCtx.Conversations.Create(tenantID)
+
+		slog.Info("Started conversation", "conversation", conversation.ID)
+		c.Response().Header().Set(echo.HeaderLocation, "/api/conversations/"+conversation.ID+"/export")
+		return c.JSON(http.StatusCreated, conversation)
+	}
+}
+
+// ImportConversationHandler stores a conversation sent in the export JSON
+// format, or as OpenAI-style messages, so that a chat can go on from it.
+// It gets a new ID, whatever ID the body has.
+func ImportConversationHandler(appCtx *app.AppContext) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		body, err := io.ReadAll(http.MaxBytesReader(c.Response(), c.Request().Body, maxJSONBodyBytes))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				return &requestError{Status: http.StatusRequestEntityTooLarge, Message: "Request body is too large"}
+			}
+			return &requestError{Status: http.StatusBadRequest, Message: "Failed to read request body"}
+		}
+		if !utf8.Valid(body) {
+			return invalidUTF8("body")
+		}
+
+		conversation, err := conversations.Parse(body)
+		if err != nil {
+			return &requestError{Status: http.StatusBadRequest, Message: err.Error()}
+		}
+		if err := validateConversation(conversation); err != nil {
+			return err
+		}
+
+		tenantID := tenant.FromContext(c.Request().Context()).ID
+		imported := appCtx.Conversations.Import(tenantID, conversation)
+
+		slog.Info("Imported conversation", "conversation", imported.ID, "messages", len(imported.Messages))
+		c.Response().Header().Set(echo.HeaderLocation, "/api/conversations/"+imported.ID+"/export")
+		return c.JSON(http.StatusCreated, imported)
+	}
+}
+
+// validateConversation checks an imported conversation can be continued
+// by chat requests. Unlike a request, it may end with an answer.
+func validateConversation(conversation conversations.Conversation) error {
+	v := &ValidationError{}
+
+	if len(conversation.Messages) > maxMessages {
+		v.add("messages", "cannot contain more than %d messages", maxMessages)
+	}
+	for i, msg := range conversation.Messages {
+		field := fmt.Sprintf("messages[%d]", i)
+		if !allowedRoles[msg.Role] {
+			v.add(field+".role", "must be one of system, user, assistant or tool")
+		}
+		if len(msg.ToolCalls) > 0 && msg.Role != "assistant" {
+			v.add(field+".tool_calls", "is only allowed on assistant messages")
+		}
+		if msg.ToolCallID != "" && msg.Role != "tool" {
+			v.add(field+".tool_call_id", "is only allowed on tool messages")
+		}
+	}
+
+	if len(v.Errors) > 0 {
+		return v
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"chat-backend/internal/app"
+	"chat-backend/internal/conversations"
+)
+
+func sendChat(t *testing.T, appCtx *app.AppContext, reqBody ChatRequest) *httptest.ResponseRecorder {
+	t.Helper()
+
+	jsonBody, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest(http.MethodPost, "/api/chat", bytes.NewBuffer(jsonBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	recorder := httptest.NewRecorder()
+	runHandler(ChatHandler(appCtx), echo.New().NewContext(req, recorder))
+	return recorder
+}
+
+func exportConversation(t *testing.T, appCtx *app.AppContext, id, format string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/conversations/"+id+"/export?format="+format, nil)
+	recorder := httptest.NewRecorder()
+	c := echo.New().NewContext(req, recorder)
+	c.SetParamNames("id")
+	c.SetParamValues(id)
+	runHandler(ExportConversationHandler(appCtx), c)
+	return recorder
+}
+
+func TestChatHandler_ContinuesConversation(t *testing.T) {
+	appCtx, provider := newScriptedAppContext(t, `
+rules:
+  - match: "capital of France"
+    response: "Paris."
+  - match: "population"
+    chunks: ["About ", "2 million."]
+`)
+	appCtx.ProviderName, appCtx.Model = "mock", "scripted"
+
+	req := httptest.NewRequest(http.MethodPost, "/api/conversations", nil)
+	recorder := httptest.NewRecorder()
+	runHandler(CreateConversationHandler(appCtx), echo.New().NewContext(req, recorder))
+	var created conversations.Conversation
+	json.Unmarshal(recorder.Body.Bytes(), &created)
+	if recorder.Code != http.StatusCreated || created.ID == "" {
+		t.Fatalf("Expected a conversation to be started, got %d: %s", recorder.Code, recorder.Body)
+	}
+
+	if recorder := sendChat(t, appCtx, ChatRequest{
+		ConversationID: created.ID,
+		Messages:       []Message{{Role: "user", Content: "What is the capital of France?"}},
+	}); recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body)
+	}
+
+	// The follow-up only sends the new message, streamed this time
+	recorder = sendChat(t, appCtx, ChatRequest{
+		ConversationID: created.ID,
+		Messages:       []Message{{Role: "user", Content: "And its population?"}},
+		Streaming:      true,
+	})
+	if content, done := readStreamedResponse(t, recorder.Body.String()); !done || content != "About 2 million." {
+		t.Fatalf("Expected the streamed answer, got '%s' (done %v)", content, done)
+	}
+
+	requests := provider.Requests()
+	if len(requests) != 2 || len(requests[1].Messages) != 3 {
+		t.Fatalf("Expected the follow-up to carry the history, got %+v", requests)
+	}
+	if requests[1].Messages[1].Content != "Paris." {
+		t.Errorf("Expected the earlier answer in the history, got %+v", requests[1].Messages)
+	}
+
+	conversation, err := appCtx.Conversations.Get("", created.ID)
+	if err != nil {
+		t.Fatalf("Expected the conversation to be recorded: %v", err)
+	}
+	if len(conversation.Messages) != 4 {
+		t.Fatalf("Expected both exchanges, got %+v", conversation.Messages)
+	}
+	answer := conversation.Messages[3]
+	if answer.Role != "assistant" || answer.Content != "About 2 million." || answer.Provider != "mock" || answer.Model != "scripted" || answer.CreatedAt.IsZero() {
+		t.Errorf("Expected the streamed answer with its provider and model, got %+v", answer)
+	}
+}
+
+func TestChatHandler_InvalidConversationID(t *testing.T) {
+	appCtx := app.NewAppContext(&mockChatProvider{})
+
+	recorder := sendChat(t, appCtx, ChatRequest{
+		ConversationID: "../other",
+		Messages:       []Message{{Role: "user", Content: "Hi"}},
+	})
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, recorder.Code)
+	}
+
+	// Conversations are only started by the server
+	recorder = sendChat(t, appCtx, ChatRequest{
+		ConversationID: "support-123",
+		Messages:       []Message{{Role: "user", Content: "Hi"}},
+	})
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d for a conversation that was not started, got %d", http.StatusNotFound, recorder.Code)
+	}
+}
+
+func TestChatHandler_LongConversation(t *testing.T) {
+	appCtx, provider := newScriptedAppContext(t, `
+rules:
+  - match: ".*"
+    response: "Noted."
+`)
+
+	// A conversation at the request limit still takes follow-ups, with
+	// only its latest history sent along
+	var messages []conversations.Message
+	messages = append(messages, conversations.Message{Role: "system", Content: "Be brief."})
+	for i := 0; i < maxMessages; i++ {
+		messages = append(messages,
+			conversations.Message{Role: "user", Content: "Next"},
+			conversations.Message{Role: "assistant", Content: "Noted."},
+		)
+	}
+	conversation := appCtx.Conversations.Import("", conversations.Conversation{Messages: messages})
+
+	recorder := sendChat(t, appCtx, ChatRequest{
+		ConversationID: conversation.ID,
+		Messages:       []Message{{Role: "user", Content: "One more"}},
+	})
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body)
+	}
+
+	sent := provider.Requests()[0].Messages
+	if len(sent) != maxHistoryMessages+1 || sent[0].Role != "system" || sent[len(sent)-1].Content != "One more" {
+		t.Errorf("Expected the system message, the latest history and the new message, got %d messages", len(sent))
+	}
+}
+
+func TestExportConversationHandler(t *testing.T) {
+	appCtx := app.NewAppContext(&mockChatProvider{})
+	id := appCtx.Conversations.Import("", conversations.Conversation{Messages: []conversations.Message{
+		{Role: "user", Content: "Hi"},
+		{Role: "assistant", Content: "Hello!", Provider: "ollama", Model: "gemma3:1b"},
+	}}).ID
+
+	tests := []struct {
+		format      string
+		contentType string
+		filename    string
+		contains    string
+	}{
+		{"", echo.MIMEApplicationJSON, "conversation-" + id + ".json", `"content":"Hello!"`},
+		{"markdown", "text/markdown; charset=utf-8", "conversation-" + id + ".md", "### Assistant ·"},
+		{"text", echo.MIMETextPlainCharsetUTF8, "conversation-" + id + ".txt", "Assistant (ollama · gemma3:1b):\nHello!"},
+	}
+	for _, tt := range tests {
+		recorder := exportConversation(t, appCtx, id, tt.format)
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("%q: expected status code %d, got %d", tt.format, http.StatusOK, recorder.Code)
+		}
+		if got := recorder.Header().Get(echo.HeaderContentType); !strings.HasPrefix(got, tt.contentType) {
+			t.Errorf("%q: expected content type %s, got %s", tt.format, tt.contentType, got)
+		}
+		if got := recorder.Header().Get("Content-Disposition"); !strings.Contains(got, tt.filename) {
+			t.Errorf("%q: expected the file to be named %s, got %s", tt.format, tt.filename, got)
+		}
+		if !strings.Contains(recorder.Body.String(), tt.contains) {
+			t.Errorf("%q: expected the transcript to contain %q, got:\n%s", tt.format, tt.contains, recorder.Body)
+		}
+	}
+
+	if recorder := exportConversation(t, appCtx, id, "pdf"); recorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d for an unknown format, got %d", http.StatusBadRequest, recorder.Code)
+	}
+	if recorder := exportConversation(t, appCtx, "unknown", ""); recorder.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d for an unknown conversation, got %d", http.StatusNotFound, recorder.Code)
+	}
+}
+
+func TestImportConversationHandler(t *testing.T) {
+	appCtx := app.NewAppContext(&mockChatProvider{})
+
+	importConversation := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/conversations/import", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		recorder := httptest.NewRecorder()
+		runHandler(ImportConversationHandler(appCtx), echo.New().NewContext(req, recorder))
+		return recorder
+	}
+
+	openAI := `[
+		{"role": "system", "content": "You are terse."},
+		{"role": "user", "content": [{"type": "text", "text": "Hi"}]},
+		{"role": "assistant", "content": "Hello."}
+	]`
+	recorder := importConversation(openAI)
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusCreated, recorder.Code, recorder.Body)
+	}
+	var imported conversations.Conversation
+	json.Unmarshal(recorder.Body.Bytes(), &imported)
+	if location := recorder.Header().Get(echo.HeaderLocation); location != "/api/conversations/"+imported.ID+"/export" {
+		t.Errorf("Expected the export location, got '%s'", location)
+	}
+
+	conversation, err := appCtx.Conversations.Get("", imported.ID)
+	if err != nil || len(conversation.Messages) != 3 || conversation.Messages[1].Content != "Hi" {
+		t.Fatalf("Expected the imported messages, got %+v, %v", conversation, err)
+	}
+
+	// The ID of an export is not reused, so that IDs cannot be picked
+	recorder = importConversation(`{"id": "support-123", "messages": [{"role": "user", "content": "Hi"}]}`)
+	json.Unmarshal(recorder.Body.Bytes(), &imported)
+	if recorder.Code != http.StatusCreated || imported.ID == "support-123" {
+		t.Errorf("Expected a generated ID, got %d: %s", recorder.Code, recorder.Body)
+	}
+
+	if recorder := importConversation(`[{"role": "robot", "content": "Hi"}]`); recorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d for an invalid role, got %d", http.StatusBadRequest, recorder.Code)
+	}
+	if recorder := importConversation(`[]`); recorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d for no messages, got %d", http.StatusBadRequest, recorder.Code)
+	}
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"chat-backend/internal/chat"
+)
+
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// StatusClientClosedRequest reports a request that was cancelled by the
+// client, following the nginx convention
+const StatusClientClosedRequest = 499
+
+// ProblemDetails is an RFC 7807 error body. Errors is an extension member
+// listing the invalid fields of a request that failed validation.
+type ProblemDetails struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// requestError is a client error with the status code it should be reported with
+type requestError struct {
+	Status  int
+	Message string
+}
+
+func (e *requestError) Error() string {
+	return e.Message
+}
+
+// problemKind describes how a chat error kind is reported to clients
+type problemKind struct {
+	err    error
+	status int
+	name   string
+	title  string
+	// Upstream messages for server side failures can leak internals, so
+	// only client errors echo the error text as the detail
+	detail string
+}
+
+// problemKinds is checked in order. Timeouts come before unavailability
+// because a transport error may match both. The provider rejecting the
+// server's own credentials is a gateway failure, not the client's, so it is
+// not reported as 401.
+var problemKinds = []problemKind{
+	{err: chat.ErrUnauthorized, status: http.StatusBadGateway, name: "provider-unauthorized", title: "Chat provider rejected credentials", detail: "The chat provider rejected the server's credentials"},
+	{err: chat.ErrRateLimited, status: http.StatusTooManyRequests, name: "rate-limited", title: "Rate limited"},
+	{err: chat.ErrContextLength, status: http.StatusRequestEntityTooLarge, name: "context-length-exceeded", title: "Context length exceeded"},
+	{err: chat.ErrContentFiltered, status: http.StatusUnprocessableEntity, name: "content-filtered", title: "Content filtered"},
+	{err: chat.ErrTimeout, status: http.StatusGatewayTimeout, name: "timeout", title: "Chat provider timed out", detail: "The chat provider did not respond in time"},
+	{err: chat.ErrOverloaded, status: http.StatusServiceUnavailable, name: "overloaded", title: "Chat provider overloaded", detail: "Too many requests are waiting for the chat provider, retry later"},
+	{err: chat.ErrProviderUnavailable, status: http.StatusServiceUnavailable, name: "provider-unavailable", title: "Chat provider unavailable", detail: "The chat provider is currently unavailable"},
+	{err: chat.ErrMalformedResponse, status: http.StatusBadGateway, name: "malformed-response", title: "Malformed provider response", detail: "The chat provider returned a response that could not be read"},
+	{err: chat.ErrCancelled, status: StatusClientClosedRequest, name: "cancelled", title: "Generation cancelled", detail: "The generation was cancelled"},
+	{err: chat.ErrInvalidRequest, status: http.StatusBadRequest, name: "invalid-request", title: "Invalid request"},
+}
+
+func problemType(name string) string {
+	return "urn:chat-backend:problem:" + name
+}
+
+// NewProblemDetails classifies err and builds the body it is reported with
+func NewProblemDetails(err error) ProblemDetails {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return ProblemDetails{
+			Type:   problemType("validation-failed"),
+			Title:  "Request validation failed",
+			Status: http.StatusBadRequest,
+			Detail: "One or more fields are invalid",
+			Errors: validationErr.Errors,
+		}
+	}
+
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		return ProblemDetails{
+			Type:   "about:blank",
+			Title:  http.StatusText(reqErr.Status),
+			Status: reqErr.Status,
+			Detail: reqErr.Message,
+		}
+	}
+
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		problem := ProblemDetails{
+			Type:   "about:blank",
+			Title:  http.StatusText(httpErr.Code),
+			Status: httpErr.Code,
+		}
+		if httpErr.Message != nil {
+			problem.Detail = fmt.Sprint(httpErr.Message)
+		}
+		return problem
+	}
+
+	for _, kind := range problemKinds {
+		if !errors.Is(err, kind.err) && !(kind.err == chat.ErrTimeout && chat.IsTimeout(err)) {
+			continue
+		}
+		problem := ProblemDetails{
+			Type:   problemType(kind.name),
+			Title:  kind.title,
+			Status: kind.status,
+			Detail: kind.detail,
+		}
+		if problem.Detail == "" {
+			problem.Detail = err.Error()
+		}
+		return problem
+	}
+
+	return ProblemDetails{
+		Type:   "about:blank",
+		Title:  http.StatusText(http.StatusInternalServerError),
+		Status: http.StatusInternalServerError,
+		Detail: "Failed to process request",
+	}
+}
+
+// HTTPErrorHandler reports errors returned by handlers as problem+json
+// responses. It is installed as the Echo HTTPErrorHandler.
+func HTTPErrorHandler(err error, c echo.Context) {
+	problem := NewProblemDetails(err)
+	problem.Instance = c.Request().URL.Path
+
+	if problem.Status >= http.StatusInternalServerError {
+		slog.Error("Request failed", "error", err, "status", problem.Status, "path", problem.Instance)
+	} else {
+		slog.Warn("Request rejected", "error", err, "status", problem.Status, "path", problem.Instance)
+	}
+
+	// Streaming handlers report their own errors once headers are sent
+	if c.Response().Committed {
+		return
+	}
+
+	if c.Request().Method == http.MethodHead {
+		c.NoContent(problem.Status)
+		return
+	}
+
+	// Errors that know when capacity frees up, such as a full request
+	// queue, tell the client when to retry
+	var retry interface{ RetryAfter() time.Duration }
+	if errors.As(err, &retry) {
+		seconds := int(math.Ceil(retry.RetryAfter().Seconds()))
+		c.Response().Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, MIMEApplicationProblemJSON)
+	if err := c.JSON(problem.Status, problem); err != nil {
+		slog.Error("Failed to write error response", "error", err)
+	}
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"chat-backend/internal/chat"
+	"chat-backend/internal/chat/limiter"
+)
+
+func TestNewProblemDetails_StatusMapping(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"invalid request", chat.Errorf(chat.ErrInvalidRequest, "no messages provided"), http.StatusBadRequest},
+		{"attachments not supported", fmt.Errorf("ollama: %w", chat.ErrAttachmentsNotSupported), http.StatusBadRequest},
+		{"unauthorized", chat.StatusKind(http.StatusForbidden), http.StatusBadGateway},
+		{"rate limited", chat.StatusKind(http.StatusTooManyRequests), http.StatusTooManyRequests},
+		{"context length", chat.Errorf(chat.ErrContextLength, "prompt is too long"), http.StatusRequestEntityTooLarge},
+		{"content filtered", chat.ErrContentFiltered, http.StatusUnprocessableEntity},
+		{"timeout", chat.TransportError(fmt.Errorf("failed to send request: %w", context.DeadlineExceeded)), http.StatusGatewayTimeout},
+		{"bare deadline", context.DeadlineExceeded, http.StatusGatewayTimeout},
+		{"unavailable", chat.TransportError(errors.New("connection refused")), http.StatusServiceUnavailable},
+		{"overloaded", &limiter.QueueError{Reason: limiter.ErrQueueFull, Retry: time.Second}, http.StatusServiceUnavailable},
+		{"malformed", chat.Errorf(chat.ErrMalformedResponse, "failed to decode response"), http.StatusBadGateway},
+		{"cancelled", chat.ErrCancelled, StatusClientClosedRequest},
+		{"request error", &requestError{Status: http.StatusUnsupportedMediaType, Message: "nope"}, http.StatusUnsupportedMediaType},
+		{"echo error", echo.ErrNotFound, http.StatusNotFound},
+		{"unknown", errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problem := NewProblemDetails(tt.err)
+			if problem.Status != tt.status {
+				t.Errorf("Expected status %d, got %d (%+v)", tt.status, problem.Status, problem)
+			}
+			if problem.Type == "" || problem.Title == "" {
+				t.Errorf("Expected type and title to be set, got %+v", problem)
+			}
+		})
+	}
+}
+
+func TestNewProblemDetails_HidesUpstreamDetailForServerErrors(t *testing.T) {
+	problem := NewProblemDetails(chat.TransportError(errors.New("dial tcp 10.0.0.7:11434: connection refused")))
+
+	if strings.Contains(problem.Detail, "10.0.0.7") {
+		t.Errorf("Expected upstream address not to be exposed, got '%s'", problem.Detail)
+	}
+}
+
+func TestNewProblemDetails_UpstreamUnauthorized(t *testing.T) {
+	err := fmt.Errorf("openai API returned status 401: Incorrect API key provided: sk-abc***: %w", chat.ErrUnauthorized)
+	problem := NewProblemDetails(err)
+
+	if problem.Status != http.StatusBadGateway || problem.Type != problemType("provider-unauthorized") {
+		t.Errorf("Expected the provider rejecting our credentials to be a gateway failure, got %+v", problem)
+	}
+	if strings.Contains(problem.Detail, "sk-abc") {
+		t.Errorf("Expected the upstream error not to be exposed, got '%s'", problem.Detail)
+	}
+}
+
+func TestHTTPErrorHandler_WritesProblemJSON(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/chat", nil)
+	recorder := httptest.NewRecorder()
+	c := echo.New().NewContext(req, recorder)
+
+	HTTPErrorHandler(chat.StatusKind(http.StatusTooManyRequests), c)
+
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected status code %d, got %d", http.StatusTooManyRequests, recorder.Code)
+	}
+
+	if contentType := recorder.Header().Get("Content-Type"); contentType != MIMEApplicationProblemJSON {
+		t.Errorf("Expected problem+json content type, got '%s'", contentType)
+	}
+
+	var problem ProblemDetails
+	if err := json.NewDecoder(recorder.Body).Decode(&problem); err != nil {
+		t.Fatalf("Failed to decode problem details: %v", err)
+	}
+
+	if problem.Type != "urn:chat-backend:problem:rate-limited" || problem.Status != http.StatusTooManyRequests || problem.Instance != "/api/chat" {
+		t.Errorf("Unexpected problem details: %+v", problem)
+	}
+}
+
+func TestHTTPErrorHandler_RetryAfter(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/chat", nil)
+	recorder := httptest.NewRecorder()
+	c := echo.New().NewContext(req, recorder)
+
+	HTTPErrorHandler(&limiter.QueueError{Reason: limiter.ErrQueueTimeout, Retry: 2500 * time.Millisecond}, c)
+
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status code %d, got %d", http.StatusServiceUnavailable, recorder.Code)
+	}
+	if retryAfter := recorder.Header().Get("Retry-After"); retryAfter != "3" {
+		t.Errorf("Expected Retry-After to round up to 3 seconds, got '%s'", retryAfter)
+	}
+
+	var problem ProblemDetails
+	if err := json.NewDecoder(recorder.Body).Decode(&problem); err != nil {
+		t.Fatalf("Failed to decode problem details: %v", err)
+	}
+	if problem.Type != "urn:chat-backend:problem:overloaded" {
+		t.Errorf("Unexpected problem details: %+v", problem)
+	}
+}
+
+func TestChatHandler_StreamErrorCarriesStatus(t *testing.T) {
+	appCtx, _ := newScriptedAppContext(t, `
+default:
+  chunks: ["Partial"]
+  error: malformed
+`)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/chat", strings.NewReader(`{"messages": [{"role": "user", "content": "Hi"}], "streaming": true}`))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	c := echo.New().NewContext(req, recorder)
+
+	runHandler(ChatHandler(appCtx), c)
+
+	var streamErr StreamError
+	for _, line := range strings.Split(recorder.Body.String(), "\n") {
+		data, ok := strings.CutPrefix(line, "data: ")
+		if ok && strings.Contains(data, `"error"`) {
+			if err := json.Unmarshal([]byte(data), &streamErr); err != nil {
+				t.Fatalf("Failed to decode error event: %v", err)
+			}
+		}
+	}
+
+	if streamErr.Status != http.StatusBadGateway || streamErr.Type != "urn:chat-backend:problem:malformed-response" {
+		t.Errorf("Unexpected stream error: %+v", streamErr)
+	}
+}
+
+func TestChatHandler_StreamErrorBeforeFirstChunk(t *testing.T) {
+	appCtx, _ := newScriptedAppContext(t, `
+default:
+  error: unavailable
+`)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/chat", strings.NewReader(`{"messages": [{"role": "user", "content": "Hi"}], "streaming": true}`))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	c := echo.New().NewContext(req, recorder)
+
+	runHandler(ChatHandler(appCtx), c)
+
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status code %d, got %d", http.StatusServiceUnavailable, recorder.Code)
+	}
+	if contentType := recorder.Header().Get("Content-Type"); contentType != MIMEApplicationProblemJSON {
+		t.Errorf("Expected problem+json before any chunk was sent, got '%s'", contentType)
+	}
+}
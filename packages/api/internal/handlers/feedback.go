@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"github.com/labstack/echo/v4"
+
+	"chat-backend/internal/app"
+	"chat-backend/internal/eval"
+	"chat-backend/internal/feedback"
+	"chat-backend/internal/tenant"
+)
+
+const (
+	maxFeedbackCategoryLength = 64
+	maxFeedbackCommentBytes   = 4 << 10
+)
+
+// FeedbackRequest is the body of the feedback endpoint. Rating is "up" or
+// "down", and the category and comment say why.
+type FeedbackRequest struct {
+	Rating   string `json:"rating"`
+	Category string `json:"category,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+type FeedbackResponse struct {
+	MessageID string            `json:"message_id"`
+	Feedback  feedback.Feedback `json:"feedback"`
+}
+
+// SubmitFeedbackHandler stores feedback on an answer, by the message ID it
+// came with. Sending feedback again replaces it.
+func SubmitFeedbackHandler(appCtx *app.AppContext) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req FeedbackRequest
+		if err := c.Bind(&req); err != nil {
+			return &requestError{Status: http.StatusBadRequest, Message: "Invalid request format"}
+		}
+		if err := validateFeedback(&req); err != nil {
+			return err
+		}
+
+		messageID := c.Param("id")
+		tenantID := tenant.FromContext(c.Request().Context()).ID
+		record, err := appCtx.Feedback.Submit(tenantID, messageID, feedback.Feedback{
+			Rating:   req.Rating,
+			Category: req.Category,
+			Comment:  req.Comment,
+		})
+		if errors.Is(err, feedback.ErrNotFound) {
+			return &requestError{Status: http.StatusNotFound, Message: err.Error()}
+		}
+		if err != nil {
+			return err
+		}
+		appCtx.Analytics.Rate(tenantID, messageID, req.Rating)
+
+		slog.Info("Received feedback", "message", messageID, "rating", req.Rating, "category", req.Category)
+		return c.JSON(http.StatusOK, FeedbackResponse{MessageID: record.MessageID, Feedback: *record.Feedback})
+	}
+}
+
+func validateFeedback(req *FeedbackRequest) error {
+	v := &ValidationError{}
+
+	if req.Rating != feedback.RatingUp && req.Rating != feedback.RatingDown {
+		v.add("rating", "must be up or down")
+	}
+	if utf8.RuneCountInString(req.Category) > maxFeedbackCategoryLength {
+		v.add("category", "cannot be longer than %d characters", maxFeedbackCategoryLength)
+	}
+	if len(req.Comment) > maxFeedbackCommentBytes {
+		v.add("comment", "cannot exceed %d bytes", maxFeedbackCommentBytes)
+	}
+	if !utf8.ValidString(req.Category) {
+		v.add("category", "must be valid UTF-8")
+	}
+	if !utf8.ValidString(req.Comment) {
+		v.add("comment", "must be valid UTF-8")
+	}
+
+	if len(v.Errors) > 0 {
+		return v
+	}
+	return nil
+}
+
+// ExportFeedbackHandler returns answers with feedback as JSON Lines, picked
+// by the tenant, rating, category and since query parameters. With
+// format=eval, thumbs-up answers are written as an evaluation dataset, each
+// answer being the expected one for its question.
+func ExportFeedbackHandler(appCtx *app.AppContext) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		filter := feedback.Filter{
+			TenantID: c.QueryParam("tenant"),
+			Rating:   c.QueryParam("rating"),
+			Category: c.QueryParam("category"),
+		}
+		if since := c.QueryParam("since"); since != "" {
+			t, err := time.Parse(time.RFC3339, since)
+			if err != nil {
+				return &requestError{Status: http.StatusBadRequest, Message: "since must be an RFC 3339 time"}
+			}
+			filter.Since = t
+		}
+
+		var rows []any
+		switch c.QueryParam("format") {
+		case "", "jsonl":
+			for _, record := range appCtx.Feedback.List(filter) {
+				rows = append(rows, record)
+			}
+		case "eval":
+			if filter.Rating == feedback.RatingDown {
+				return &requestError{Status: http.StatusBadRequest, Message: "the eval format only has thumbs-up answers"}
+			}
+			filter.Rating = feedback.RatingUp
+			for _, record := range appCtx.Feedback.List(filter) {
+				if question := record.Question(); question != "" {
+					rows = append(rows, eval.Case{ID: record.MessageID, Question: question, Expected: record.Answer})
+				}
+			}
+		default:
+			return &requestError{Status: http.StatusBadRequest, Message: "format must be jsonl or eval"}
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, MIMEApplicationNDJSON)
+		c.Response().Header().Set("Content-Disposition", `attachment; filename="feedback.jsonl"`)
+		c.Response().WriteHeader(http.StatusOK)
+		encoder := json.NewEncoder(c.Response())
+		for _, row := range rows {
+			if err := encoder.Encode(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
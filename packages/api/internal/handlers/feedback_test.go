@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"chat-backend/internal/app"
+	"chat-backend/internal/chat"
+	"chat-backend/internal/eval"
+)
+
+func submitFeedback(t *testing.T, appCtx *app.AppContext, messageID, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/messages/"+messageID+"/feedback", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	recorder := httptest.NewRecorder()
+	c := echo.New().NewContext(req, recorder)
+	c.SetParamNames("id")
+	c.SetParamValues(messageID)
+	runHandler(SubmitFeedbackHandler(appCtx), c)
+	return recorder
+}
+
+func TestSubmitFeedbackHandler(t *testing.T) {
+	appCtx := app.NewAppContext(&mockChatProvider{response: &chat.ChatResponse{Content: "Paris."}})
+
+	recorder := sendChat(t, appCtx, ChatRequest{Messages: []Message{{Role: "user", Content: "What is the capital of France?"}}})
+	var chatResp ChatResponse
+	json.Unmarshal(recorder.Body.Bytes(), &chatResp)
+	if chatResp.MessageID == "" || recorder.Header().Get(HeaderMessageID) != chatResp.MessageID {
+		t.Fatalf("Expected the answer's message ID in the body and header, got %+v, header '%s'", chatResp, recorder.Header().Get(HeaderMessageID))
+	}
+
+	recorder = submitFeedback(t, appCtx, chatResp.MessageID, `{"rating": "up", "category": "accurate", "comment": "Thanks"}`)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body)
+	}
+
+	// Feedback lands next to the prompt and answer it is about
+	req := httptest.NewRequest(http.MethodGet, "/api/feedback/export?format=eval", nil)
+	recorder = httptest.NewRecorder()
+	runHandler(ExportFeedbackHandler(appCtx), echo.New().NewContext(req, recorder))
+	var exported eval.Case
+	if err := json.Unmarshal(recorder.Body.Bytes(), &exported); err != nil {
+		t.Fatalf("Expected one eval case, got %s", recorder.Body)
+	}
+	want := eval.Case{ID: chatResp.MessageID, Question: "What is the capital of France?", Expected: "Paris."}
+	if exported != want {
+		t.Errorf("Expected %+v, got %+v", want, exported)
+	}
+}
+
+func TestSubmitFeedbackHandler_Invalid(t *testing.T) {
+	appCtx := app.NewAppContext(&mockChatProvider{})
+
+	if recorder := submitFeedback(t, appCtx, "unknown", `{"rating": "up"}`); recorder.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d for an unknown message, got %d", http.StatusNotFound, recorder.Code)
+	}
+
+	recorder := submitFeedback(t, appCtx, "unknown", `{"rating": "meh", "category": "`+strings.Repeat("x", 65)+`"}`)
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status code %d, got %d", http.StatusBadRequest, recorder.Code)
+	}
+	var problem ProblemDetails
+	json.Unmarshal(recorder.Body.Bytes(), &problem)
+	if len(problem.Errors) != 2 {
+		t.Errorf("Expected the rating and category to be reported, got %+v", problem.Errors)
+	}
+}
+
+func TestChatHandler_StreamsMessageID(t *testing.T) {
+	appCtx := app.NewAppContext(&mockChatProvider{response: &chat.ChatResponse{Content: "Paris."}})
+
+	recorder := sendChat(t, appCtx, ChatRequest{
+		Messages:  []Message{{Role: "user", Content: "What is the capital of France?"}},
+		Streaming: true,
+	})
+	messageID := recorder.Header().Get(HeaderMessageID)
+	if messageID == "" || !strings.Contains(recorder.Body.String(), `"message_id":"`+messageID+`"`) {
+		t.Fatalf("Expected the message ID in the header and first event, got:\n%s", recorder.Body)
+	}
+
+	if recorder := submitFeedback(t, appCtx, messageID, `{"rating": "down"}`); recorder.Code != http.StatusOK {
+		t.Errorf("Expected the streamed answer to take feedback, got status code %d", recorder.Code)
+	}
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"chat-backend/internal/app"
+	"chat-backend/internal/generations"
+	"chat-backend/internal/tenant"
+)
+
+// CancelGenerationHandler stops a chat generation in progress, by the ID
+// its response carries. The cancelled request ends on its own: a stream
+// with a cancelled event, and a plain request with a 499 problem.
+func CancelGenerationHandler(appCtx *app.AppContext) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id := c.Param("id")
+		tenantID := tenant.FromContext(c.Request().Context()).ID
+
+		if err := appCtx.Generations.Cancel(tenantID, id); err != nil {
+			if errors.Is(err, generations.ErrNotFound) {
+				return &requestError{Status: http.StatusNotFound, Message: err.Error()}
+			}
+			return err
+		}
+
+		slog.Info("Cancelling chat generation", "generation", id)
+		return c.NoContent(http.StatusAccepted)
+	}
+}
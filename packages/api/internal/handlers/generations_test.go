@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"chat-backend/internal/app"
+	"chat-backend/internal/chat"
+)
+
+// runawayProvider streams one chunk, then keeps generating until it is
+// cancelled
+type runawayProvider struct {
+	started   chan struct{}
+	cancelled chan error
+}
+
+func newRunawayProvider() *runawayProvider {
+	return &runawayProvider{started: make(chan struct{}, 1), cancelled: make(chan error, 1)}
+}
+
+func (p *runawayProvider) Chat(ctx context.Context, req *chat.ChatRequest) (*chat.ChatResponse, error) {
+	p.started <- struct{}{}
+	<-ctx.Done()
+	p.cancelled <- ctx.Err()
+	return nil, ctx.Err()
+}
+
+func (p *runawayProvider) ChatStream(ctx context.Context, req *chat.ChatRequest, callback chat.StreamCallback) error {
+	if err := callback(&chat.ChatResponse{Content: "On and on and on"}); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	p.cancelled <- ctx.Err()
+	return ctx.Err()
+}
+
+func newGenerationServer(t *testing.T, provider chat.ChatProvider) (*httptest.Server, *app.AppContext) {
+	t.Helper()
+
+	appCtx := app.NewAppContext(provider)
+	e := echo.New()
+	e.HTTPErrorHandler = HTTPErrorHandler
+	e.POST("/api/chat", ChatHandler(appCtx))
+	e.POST("/api/chat/generations/:id/cancel", CancelGenerationHandler(appCtx))
+	e.GET("/api/chat/streams/:id", ResumeStreamHandler(appCtx))
+
+	server := httptest.NewServer(e)
+	t.Cleanup(server.Close)
+	return server, appCtx
+}
+
+func cancelGeneration(t *testing.T, server *httptest.Server, id string) int {
+	t.Helper()
+
+	resp, err := http.Post(server.URL+"/api/chat/generations/"+id+"/cancel", "", nil)
+	if err != nil {
+		t.Fatalf("Failed to cancel: %v", err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestCancelGeneration_Stream(t *testing.T) {
+	provider := newRunawayProvider()
+	server, appCtx := newGenerationServer(t, provider)
+
+	resp, err := http.Post(server.URL+"/api/chat", echo.MIMEApplicationJSON,
+		strings.NewReader(`{"messages": [{"role": "user", "content": "Tell me everything"}], "streaming": true}`))
+	if err != nil {
+		t.Fatalf("Failed to start the stream: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var events []StreamEvent
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var event StreamEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			t.Fatalf("Failed to decode event %q: %v", data, err)
+		}
+		events = append(events, event)
+
+		if len(events) == 1 {
+			if event.ID == "" || event.ID != resp.Header.Get(HeaderGenerationID) {
+				t.Fatalf("Expected the first event and header to carry the generation ID, got '%s' and '%s'", event.ID, resp.Header.Get(HeaderGenerationID))
+			}
+			if status := cancelGeneration(t, server, event.ID); status != http.StatusAccepted {
+				t.Fatalf("Expected status code %d, got %d", http.StatusAccepted, status)
+			}
+		}
+	}
+
+	if err := <-provider.cancelled; err == nil {
+		t.Error("Expected the provider's context to be cancelled")
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("Expected the chunk and a cancelled event, got %+v", events)
+	}
+	last := events[1]
+	if !last.Done || !last.Cancelled || last.ID != events[0].ID {
+		t.Errorf("Expected the stream to end with a cancelled event, got %+v", last)
+	}
+	if last.Usage == nil || !last.Usage.Estimated || last.Usage.PromptTokens == 0 || last.Usage.CompletionTokens == 0 {
+		t.Errorf("Expected the partial usage to be estimated, got %+v", last.Usage)
+	}
+	if appCtx.Generations.Active() != 0 {
+		t.Errorf("Expected the generation to be finished, got %d active", appCtx.Generations.Active())
+	}
+}
+
+func TestCancelGeneration_NotFound(t *testing.T) {
+	server, _ := newGenerationServer(t, &mockChatProvider{})
+
+	if status := cancelGeneration(t, server, "unknown"); status != http.StatusNotFound {
+		t.Errorf("Expected status code %d, got %d", http.StatusNotFound, status)
+	}
+}
+
+func TestChatHandler_IgnoresClientGenerationID(t *testing.T) {
+	server, _ := newGenerationServer(t, &mockChatProvider{response: &chat.ChatResponse{Content: "Hi"}})
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/api/chat",
+		strings.NewReader(`{"messages": [{"role": "user", "content": "Hi"}]}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(HeaderGenerationID, "chat")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+	resp.Body.Close()
+
+	if id := resp.Header.Get(HeaderGenerationID); id == "chat" || len(id) != 32 {
+		t.Errorf("Expected a generated ID in place of the client's, got '%s'", id)
+	}
+}
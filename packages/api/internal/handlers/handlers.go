@@ -1,34 +1,95 @@
 package handlers
 
 import (
-	"fmt"
+	"context"
 	"log/slog"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
 
 	"chat-backend/internal/app"
 	"chat-backend/internal/chat"
+	"chat-backend/internal/chat/cache"
+	"chat-backend/internal/chat/contextwindow"
+	"chat-backend/internal/chat/limiter"
+	"chat-backend/internal/generations"
+	"chat-backend/internal/tenant"
 )
 
+// HeaderCache reports whether a chat response was a cache HIT or MISS, or
+// BYPASSed the cache
+const HeaderCache = "X-Cache"
+
+// HeaderGenerationID carries the ID a chat generation can be cancelled by
+const HeaderGenerationID = "X-Generation-ID"
+
+// HeaderMessageID carries the ID feedback on the answer is given by
+const HeaderMessageID = "X-Message-ID"
+
 type Status struct {
-	Date   string `json:"date"`
-	Status string `json:"status"`
+	Date          string         `json:"date"`
+	Status        string         `json:"status"`
+	SemanticCache *cache.Stats   `json:"semantic_cache,omitempty"`
+	Limiter       *limiter.Stats `json:"limiter,omitempty"`
 }
 
 type Message struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role        string            `json:"role"`
+	Content     string            `json:"content"`
+	ToolCalls   []chat.ToolCall   `json:"tool_calls,omitempty"`
+	ToolCallID  string            `json:"tool_call_id,omitempty"`
+	Attachments []chat.Attachment `json:"attachments,omitempty"`
 }
 
 type ChatRequest struct {
-	Messages  []Message `json:"messages"`
-	Streaming bool      `json:"streaming,omitempty"`
+	Messages  []Message      `json:"messages"`
+	Streaming bool           `json:"streaming,omitempty"`
+	Tools     []chat.Tool    `json:"tools,omitempty"`
+	Prompt    *PromptRequest `json:"prompt,omitempty"`
+	// ConversationID continues a conversation kept on the server, whose
+	// history goes ahead of Messages. Only /api/chat and /api/ws record
+	// conversations.
+	ConversationID string `json:"conversation_id,omitempty"`
+
+	// history is the stored part of the conversation, which was checked
+	// when it was recorded and is left out of the request limits
+	history []Message
 }
 
 type ChatResponse struct {
-	Response string `json:"response"`
+	ID        string            `json:"id,omitempty"`
+	MessageID string            `json:"message_id,omitempty"`
+	Response  string            `json:"response"`
+	Answers   []chat.Answer     `json:"answers,omitempty"`
+	ToolCalls []chat.ToolCall   `json:"tool_calls,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+// StreamEvent is the payload of each server-sent event in a streamed
+// response. The generation and message IDs ride on the first event, and a
+// cancelled stream ends with an event that has Cancelled set and the usage
+// so far.
+type StreamEvent struct {
+	ID        string            `json:"id,omitempty"`
+	MessageID string            `json:"message_id,omitempty"`
+	Response  string            `json:"response"`
+	Done      bool              `json:"done"`
+	Cancelled bool              `json:"cancelled,omitempty"`
+	Answers   []chat.Answer     `json:"answers,omitempty"`
+	ToolCalls []chat.ToolCall   `json:"tool_calls,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	Usage     *chat.Usage       `json:"usage,omitempty"`
+}
+
+// StreamError is the final event of a stream that failed part way. Status
+// and Type match the problem details a non-streaming request would get.
+type StreamError struct {
+	Error  string `json:"error"`
+	Type   string `json:"type"`
+	Status int    `json:"status"`
 }
 
 func StatusHandler(appCtx *app.AppContext) echo.HandlerFunc {
@@ -37,6 +98,14 @@ func StatusHandler(appCtx *app.AppContext) echo.HandlerFunc {
 			Date:   time.Now().UTC().String(),
 			Status: "Running",
 		}
+		if semantic, ok := chat.Layer[*cache.SemanticCacheProvider](appCtx.ChatProvider); ok {
+			stats := semantic.Stats()
+			status.SemanticCache = &stats
+		}
+		if limited, ok := chat.Layer[*limiter.LimiterProvider](appCtx.ChatProvider); ok {
+			stats := limited.Stats()
+			status.Limiter = &stats
+		}
 		return c.JSON(http.StatusOK, status)
 	}
 }
@@ -44,80 +113,154 @@ func StatusHandler(appCtx *app.AppContext) echo.HandlerFunc {
 func ChatHandler(appCtx *app.AppContext) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		var chatReq ChatRequest
-		if err := c.Bind(&chatReq); err != nil {
-			slog.Error("Failed to decode chat request", "error", err)
-			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
-		}
-
-		// Validate messages array
-		if len(chatReq.Messages) == 0 {
-			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Messages array is required and cannot be empty"})
+		if err := bindChatRequest(c, &chatReq); err != nil {
+			return err
 		}
 
-		// Convert to internal message format
-		var messages []chat.Message
-		for _, msg := range chatReq.Messages {
-			messages = append(messages, chat.Message{
-				Role:    msg.Role,
-				Content: msg.Content,
-			})
+		ex, err := startExchange(c, appCtx, &chatReq)
+		if err != nil {
+			return err
 		}
 
-		chatRequest := &chat.ChatRequest{
-			Messages:  messages,
-			Streaming: chatReq.Streaming,
+		chatRequest, metadata, err := NewChatRequest(c.Request().Context(), appCtx, &chatReq)
+		if err != nil {
+			return err
 		}
 
+		// Resumable streams keep going when the client disconnects, so they
+		// are detached from the request
 		ctx := c.Request().Context()
+		if chatReq.Streaming && appCtx.Streams.Resumable() {
+			ctx = context.WithoutCancel(ctx)
+		}
+		id, ctx, finish := appCtx.Generations.Start(ctx, tenant.FromContext(ctx).ID)
+		c.Response().Header().Set(HeaderGenerationID, id)
+		c.Response().Header().Set(HeaderMessageID, ex.messageID)
 
 		if chatReq.Streaming {
-			// Set up Server-Sent Events headers
-			c.Response().Header().Set("Content-Type", "text/event-stream")
-			c.Response().Header().Set("Cache-Control", "no-cache")
-			c.Response().Header().Set("Connection", "keep-alive")
-			c.Response().Header().Set("Access-Control-Allow-Origin", "*")
-
-			// Flush headers immediately
-			c.Response().WriteHeader(http.StatusOK)
-			c.Response().Flush()
-
-			// Stream callback function
-			streamCallback := func(chunk *chat.ChatResponse) error {
-				data := fmt.Sprintf("data: {\"response\": \"%s\", \"done\": false}\n\n", chunk.Content)
-				if _, err := c.Response().Write([]byte(data)); err != nil {
-					return err
+			// The generation publishes to a stream that this request, and
+			// any that resume it, follow
+			tenantID := tenant.FromContext(ctx).ID
+			stream := appCtx.Streams.Open(tenantID, id)
+			go func() {
+				answer, err := streamGeneration(ctx, appCtx.ChatProvider, chatRequest, metadata, id, ex.messageID, stream)
+				finish()
+				if answer != nil {
+					ex.record(appCtx, answer)
 				}
-				c.Response().Flush()
-				return nil
-			}
-
-			err := appCtx.ChatProvider.ChatStream(ctx, chatRequest, streamCallback)
-			if err != nil {
-				slog.Error("Failed to stream chat response", "error", err, "messages_count", len(chatReq.Messages))
-				errorData := fmt.Sprintf("data: {\"error\": \"Failed to process request\"}\n\n")
-				c.Response().Write([]byte(errorData))
-				c.Response().Flush()
-				return nil
-			}
-
-			// Send final done message
-			doneData := fmt.Sprintf("data: {\"done\": true}\n\n")
-			c.Response().Write([]byte(doneData))
-			c.Response().Flush()
-			return nil
+				appCtx.Streams.Finish(tenantID, id, stream, err)
+			}()
+			return followStream(c, stream, 0)
 		}
+		defer finish()
 
 		// Non-streaming response (existing behavior)
 		chatResp, err := appCtx.ChatProvider.Chat(ctx, chatRequest)
+		if err != nil && generations.Cancelled(ctx) {
+			logCancelled(id, partialUsage(chatRequest, "", nil))
+			return chat.ErrCancelled
+		}
 		if err != nil {
 			slog.Error("Failed to get answer from chat provider", "error", err, "messages_count", len(chatReq.Messages))
-			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to process request"})
+			return err
 		}
 
+		ex.record(appCtx, chatResp)
+
+		setCacheHeader(c, chatResp.Metadata)
 		chatResponse := ChatResponse{
-			Response: chatResp.Content,
+			ID:        id,
+			MessageID: ex.messageID,
+			Response:  chatResp.Content,
+			Answers:   chatResp.Answers,
+			ToolCalls: chatResp.ToolCalls,
+			Metadata:  mergeMetadata(chatResp.Metadata, metadata),
 		}
 
 		return c.JSON(http.StatusOK, chatResponse)
 	}
 }
+
+// NewChatRequest validates a bound chat request and converts it for the
+// provider, adding any server-side prompt for the tenant in ctx. metadata
+// holds the notes the handler adds to the response.
+func NewChatRequest(ctx context.Context, appCtx *app.AppContext, chatReq *ChatRequest) (*chat.ChatRequest, map[string]string, error) {
+	if err := validateChatRequest(chatReq); err != nil {
+		return nil, nil, err
+	}
+
+	if err := validateAttachments(chatReq.Messages); err != nil {
+		return nil, nil, err
+	}
+
+	// Convert to internal message format
+	var messages []chat.Message
+	for _, msg := range slices.Concat(chatReq.history, chatReq.Messages) {
+		messages = append(messages, chat.Message{
+			Role:        msg.Role,
+			Content:     msg.Content,
+			ToolCalls:   msg.ToolCalls,
+			ToolCallID:  msg.ToolCallID,
+			Attachments: msg.Attachments,
+		})
+	}
+
+	// Server-side prompt goes ahead of any system messages the client sent,
+	// and of the conversation's history
+	prompt, metadata, err := systemPrompt(ctx, appCtx.Prompts, chatReq.Prompt)
+	if err != nil {
+		return nil, nil, err
+	}
+	if prompt != nil {
+		messages = append([]chat.Message{*prompt}, messages...)
+	}
+
+	chatRequest := &chat.ChatRequest{
+		Messages:  messages,
+		Streaming: chatReq.Streaming,
+		Tools:     chatReq.Tools,
+	}
+	return chatRequest, metadata, nil
+}
+
+// setCacheHeader reports in the X-Cache header whether the response came
+// from the response cache
+func setCacheHeader(c echo.Context, metadata map[string]string) {
+	if status := metadata[cache.MetadataCache]; status != "" {
+		c.Response().Header().Set(HeaderCache, strings.ToUpper(status))
+	}
+}
+
+func mergeMetadata(dst, src map[string]string) map[string]string {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string]string, len(src))
+	}
+	for key, value := range src {
+		dst[key] = value
+	}
+	return dst
+}
+
+// partialUsage is the usage of a generation cut short. Providers report
+// usage at the end, so unless one already has, it is estimated from the
+// prompt and whatever content was generated.
+func partialUsage(req *chat.ChatRequest, content string, reported *chat.Usage) *chat.Usage {
+	if reported != nil {
+		return reported
+	}
+
+	usage := &chat.Usage{PromptTokens: contextwindow.EstimateTotal(req.Messages), Estimated: true}
+	if content != "" {
+		usage.CompletionTokens = contextwindow.EstimateTokens(chat.Message{Role: "assistant", Content: content})
+	}
+	usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	return usage
+}
+
+func logCancelled(id string, usage *chat.Usage) {
+	slog.Info("Chat generation cancelled", "generation", id,
+		"promptTokens", usage.PromptTokens, "completionTokens", usage.CompletionTokens, "estimated", usage.Estimated)
+}
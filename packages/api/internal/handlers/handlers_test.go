@@ -5,15 +5,19 @@ import (
 	"context"
 	"encoding/json"
 	"log/slog"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/labstack/echo/v4"
 
 	"chat-backend/internal/app"
 	"chat-backend/internal/chat"
+	"chat-backend/internal/chat/cache"
+	"chat-backend/internal/chat/mock"
 )
 
 type mockChatProvider struct {
@@ -28,6 +32,49 @@ func (m *mockChatProvider) Chat(ctx context.Context, req *chat.ChatRequest) (*ch
 	return m.response, nil
 }
 
+func (m *mockChatProvider) ChatStream(ctx context.Context, req *chat.ChatRequest, callback chat.StreamCallback) error {
+	if m.err != nil {
+		return m.err
+	}
+	return callback(m.response)
+}
+
+// readStreamedResponse concatenates the response chunks of an SSE body
+func readStreamedResponse(t *testing.T, body string) (string, bool) {
+	t.Helper()
+
+	var content strings.Builder
+	done := false
+	for _, line := range strings.Split(body, "\n") {
+		data, ok := strings.CutPrefix(line, "data: ")
+		if !ok {
+			continue
+		}
+		var event struct {
+			Response string `json:"response"`
+			Done     bool   `json:"done"`
+			Error    string `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			t.Fatalf("Failed to decode event %q: %v", data, err)
+		}
+		if event.Error != "" {
+			return content.String(), false
+		}
+		content.WriteString(event.Response)
+		done = done || event.Done
+	}
+	return content.String(), done
+}
+
+// runHandler calls handler the way Echo does, sending any returned error
+// through HTTPErrorHandler
+func runHandler(handler echo.HandlerFunc, c echo.Context) {
+	if err := handler(c); err != nil {
+		HTTPErrorHandler(err, c)
+	}
+}
+
 func init() {
 	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
 		Level: slog.LevelWarn,
@@ -91,11 +138,7 @@ func TestChatHandler_EmptyMessages(t *testing.T) {
 	recorder := httptest.NewRecorder()
 	c := e.NewContext(req, recorder)
 
-	handler := ChatHandler(appCtx)
-	err := handler(c)
-	if err != nil {
-		t.Fatalf("Handler returned error: %v", err)
-	}
+	runHandler(ChatHandler(appCtx), c)
 
 	if recorder.Code != http.StatusBadRequest {
 		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, recorder.Code)
@@ -112,11 +155,7 @@ func TestChatHandler_InvalidJSON(t *testing.T) {
 	recorder := httptest.NewRecorder()
 	c := e.NewContext(req, recorder)
 
-	handler := ChatHandler(appCtx)
-	err := handler(c)
-	if err != nil {
-		t.Fatalf("Handler returned error: %v", err)
-	}
+	runHandler(ChatHandler(appCtx), c)
 
 	if recorder.Code != http.StatusBadRequest {
 		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, recorder.Code)
@@ -142,14 +181,14 @@ func TestChatHandler_ChatProviderError(t *testing.T) {
 	recorder := httptest.NewRecorder()
 	c := e.NewContext(req, recorder)
 
-	handler := ChatHandler(appCtx)
-	err := handler(c)
-	if err != nil {
-		t.Fatalf("Handler returned error: %v", err)
+	runHandler(ChatHandler(appCtx), c)
+
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status code %d, got %d", http.StatusServiceUnavailable, recorder.Code)
 	}
 
-	if recorder.Code != http.StatusInternalServerError {
-		t.Errorf("Expected status code %d, got %d", http.StatusInternalServerError, recorder.Code)
+	if contentType := recorder.Header().Get("Content-Type"); contentType != MIMEApplicationProblemJSON {
+		t.Errorf("Expected problem+json content type, got '%s'", contentType)
 	}
 }
 
@@ -186,6 +225,24 @@ func TestStatusHandler(t *testing.T) {
 	}
 }
 
+func TestStatusHandler_SemanticCacheStats(t *testing.T) {
+	semantic := cache.NewSemanticCacheProvider(&mockChatProvider{}, cache.SemanticConfig{})
+	appCtx := app.NewAppContext(cache.NewCacheProvider(semantic, cache.Config{}))
+
+	recorder := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest("GET", "/status", nil), recorder)
+
+	if err := StatusHandler(appCtx)(c); err != nil {
+		t.Fatalf("Handler returned error: %v", err)
+	}
+
+	var response Status
+	json.NewDecoder(recorder.Body).Decode(&response)
+	if response.SemanticCache == nil {
+		t.Error("Expected semantic cache stats in the status")
+	}
+}
+
 func TestChatHandler_MultipleMessages(t *testing.T) {
 	mockProvider := &mockChatProvider{
 		response: &chat.ChatResponse{
@@ -220,14 +277,14 @@ func TestChatHandler_MultipleMessages(t *testing.T) {
 		t.Errorf("Expected status code %d, got %d", http.StatusOK, recorder.Code)
 	}
 
-	var response ChatResponse
-	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
-		t.Fatalf("Failed to decode response: %v", err)
+	response, done := readStreamedResponse(t, recorder.Body.String())
+	if !done {
+		t.Error("Expected stream to finish with a done event")
 	}
 
 	expectedResponse := "Based on our conversation, here's my response."
-	if response.Response != expectedResponse {
-		t.Errorf("Expected response '%s', got '%s'", expectedResponse, response.Response)
+	if response != expectedResponse {
+		t.Errorf("Expected response '%s', got '%s'", expectedResponse, response)
 	}
 }
 
@@ -263,13 +320,272 @@ func TestChatHandler_WithStreaming(t *testing.T) {
 		t.Errorf("Expected status code %d, got %d", http.StatusOK, recorder.Code)
 	}
 
-	var response ChatResponse
-	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
-		t.Fatalf("Failed to decode response: %v", err)
+	response, done := readStreamedResponse(t, recorder.Body.String())
+	if !done {
+		t.Error("Expected stream to finish with a done event")
 	}
 
 	expectedResponse := "Streaming response content."
-	if response.Response != expectedResponse {
-		t.Errorf("Expected response '%s', got '%s'", expectedResponse, response.Response)
+	if response != expectedResponse {
+		t.Errorf("Expected response '%s', got '%s'", expectedResponse, response)
+	}
+}
+
+func newScriptedAppContext(t *testing.T, scenario string) (*app.AppContext, *mock.ScriptedChatProvider) {
+	t.Helper()
+
+	parsed, err := mock.ParseScenario([]byte(scenario))
+	if err != nil {
+		t.Fatalf("Failed to parse scenario: %v", err)
+	}
+	provider, err := mock.NewScriptedChatProvider(parsed)
+	if err != nil {
+		t.Fatalf("Failed to build scripted provider: %v", err)
+	}
+	return app.NewAppContext(provider), provider
+}
+
+func TestChatHandler_ScriptedStreamFailure(t *testing.T) {
+	appCtx, provider := newScriptedAppContext(t, `
+rules:
+  - match: "story"
+    chunks: ["Once upon ", "a time"]
+    error: unavailable
+`)
+
+	reqBody := ChatRequest{
+		Messages:  []Message{{Role: "user", Content: "Tell me a story"}},
+		Streaming: true,
+	}
+	jsonBody, _ := json.Marshal(reqBody)
+
+	e := echo.New()
+	req := httptest.NewRequest("POST", "/api/chat", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	c := e.NewContext(req, recorder)
+
+	if err := ChatHandler(appCtx)(c); err != nil {
+		t.Fatalf("Handler returned error: %v", err)
+	}
+
+	response, done := readStreamedResponse(t, recorder.Body.String())
+	if done {
+		t.Error("Expected stream to end with an error rather than done")
+	}
+	if response != "Once upon a time" {
+		t.Errorf("Expected partial response before the error, got '%s'", response)
+	}
+
+	requests := provider.Requests()
+	if len(requests) != 1 || !requests[0].Streaming {
+		t.Fatalf("Expected one streaming request, got %+v", requests)
+	}
+	if requests[0].Messages[0].Content != "Tell me a story" {
+		t.Errorf("Unexpected message forwarded to provider: %+v", requests[0].Messages)
+	}
+}
+
+// pngBytes is enough of a PNG header for content sniffing
+var pngBytes = append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 64)...)
+
+func newMultipartRequest(t *testing.T, messages string, files map[string][]byte) *http.Request {
+	t.Helper()
+	return newMultipartForm(t, map[string]string{"messages": messages}, files)
+}
+
+func newMultipartForm(t *testing.T, fields map[string]string, files map[string][]byte) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, value := range fields {
+		if err := writer.WriteField(name, value); err != nil {
+			t.Fatalf("Failed to write field: %v", err)
+		}
+	}
+	for name, data := range files {
+		part, err := writer.CreateFormFile("files", name)
+		if err != nil {
+			t.Fatalf("Failed to create file part: %v", err)
+		}
+		part.Write(data)
+	}
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "/api/chat", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestChatHandler_MultipartAttachments(t *testing.T) {
+	appCtx, provider := newScriptedAppContext(t, `
+default:
+  response: "That looks like a cat."
+`)
+
+	req := newMultipartRequest(t, `[{"role": "user", "content": "What is in this picture?"}]`, map[string][]byte{
+		"cat.png": pngBytes,
+	})
+	recorder := httptest.NewRecorder()
+	c := echo.New().NewContext(req, recorder)
+
+	if err := ChatHandler(appCtx)(c); err != nil {
+		t.Fatalf("Handler returned error: %v", err)
+	}
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body.String())
 	}
-}
\ No newline at end of file
+
+	requests := provider.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("Expected one request, got %d", len(requests))
+	}
+
+	attachments := requests[0].Messages[0].Attachments
+	if len(attachments) != 1 {
+		t.Fatalf("Expected one attachment, got %d", len(attachments))
+	}
+
+	if attachments[0].Filename != "cat.png" || attachments[0].MimeType != "image/png" {
+		t.Errorf("Unexpected attachment: %s (%s)", attachments[0].Filename, attachments[0].MimeType)
+	}
+}
+
+func TestChatHandler_MultipartFields(t *testing.T) {
+	appCtx, provider := newScriptedAppContext(t, `default: {response: "It is sunny."}`)
+	conversation := appCtx.Conversations.Create("")
+
+	req := newMultipartForm(t, map[string]string{
+		"messages":        `[{"role": "user", "content": "What is the weather?"}]`,
+		"tools":           `[{"name": "weather", "parameters": {"type": "object"}}]`,
+		"conversation_id": conversation.ID,
+	}, map[string][]byte{"sky.png": pngBytes})
+	recorder := httptest.NewRecorder()
+	runHandler(ChatHandler(appCtx), echo.New().NewContext(req, recorder))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body)
+	}
+	if tools := provider.Requests()[0].Tools; len(tools) != 1 || tools[0].Name != "weather" {
+		t.Errorf("Expected the tools to be forwarded, got %+v", tools)
+	}
+	if conversation, _ := appCtx.Conversations.Get("", conversation.ID); len(conversation.Messages) != 2 {
+		t.Errorf("Expected the exchange to be recorded in the conversation, got %+v", conversation.Messages)
+	}
+
+	req = newMultipartForm(t, map[string]string{
+		"messages": `[{"role": "user", "content": "Hi"}]`,
+		"stream":   "true",
+	}, nil)
+	recorder = httptest.NewRecorder()
+	runHandler(ChatHandler(appCtx), echo.New().NewContext(req, recorder))
+
+	if recorder.Code != http.StatusBadRequest || !strings.Contains(recorder.Body.String(), "stream") {
+		t.Errorf("Expected the unknown field to be rejected, got %d: %s", recorder.Code, recorder.Body)
+	}
+}
+
+func TestChatHandler_RejectsUnsupportedAttachmentType(t *testing.T) {
+	appCtx, provider := newScriptedAppContext(t, `
+default:
+  response: "unused"
+`)
+
+	req := newMultipartRequest(t, `[{"role": "user", "content": "Read this"}]`, map[string][]byte{
+		"notes.png": []byte("just some text pretending to be an image"),
+	})
+	recorder := httptest.NewRecorder()
+	c := echo.New().NewContext(req, recorder)
+
+	runHandler(ChatHandler(appCtx), c)
+
+	if recorder.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("Expected status code %d, got %d", http.StatusUnsupportedMediaType, recorder.Code)
+	}
+
+	if len(provider.Requests()) != 0 {
+		t.Error("Expected the provider not to be called")
+	}
+}
+
+func TestChatHandler_RejectsOversizedAttachment(t *testing.T) {
+	appCtx, _ := newScriptedAppContext(t, `
+default:
+  response: "unused"
+`)
+
+	large := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, maxAttachmentBytes)...)
+	req := newMultipartRequest(t, `[{"role": "user", "content": "Look"}]`, map[string][]byte{
+		"huge.png": large,
+	})
+	recorder := httptest.NewRecorder()
+	c := echo.New().NewContext(req, recorder)
+
+	runHandler(ChatHandler(appCtx), c)
+
+	if recorder.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status code %d, got %d", http.StatusRequestEntityTooLarge, recorder.Code)
+	}
+}
+
+func TestChatHandler_TextOnlyProviderRejectsAttachments(t *testing.T) {
+	appCtx := app.NewAppContext(mock.NewMockChatProvider())
+
+	reqBody := ChatRequest{
+		Messages: []Message{{
+			Role:        "user",
+			Content:     "What is in this picture?",
+			Attachments: []chat.Attachment{{Filename: "cat.png", Data: pngBytes}},
+		}},
+	}
+	jsonBody, _ := json.Marshal(reqBody)
+
+	req := httptest.NewRequest("POST", "/api/chat", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	c := echo.New().NewContext(req, recorder)
+
+	runHandler(ChatHandler(appCtx), c)
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, recorder.Code)
+	}
+}
+
+func TestChatHandler_CacheHeader(t *testing.T) {
+	appCtx, provider := newScriptedAppContext(t, `default: {response: "Cached answer."}`)
+	appCtx.ChatProvider = cache.NewCacheProvider(appCtx.ChatProvider, cache.Config{})
+
+	send := func(streaming bool) *httptest.ResponseRecorder {
+		jsonBody, _ := json.Marshal(ChatRequest{
+			Messages:  []Message{{Role: "user", Content: "Hello"}},
+			Streaming: streaming,
+		})
+		req := httptest.NewRequest("POST", "/api/chat", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		recorder := httptest.NewRecorder()
+		runHandler(ChatHandler(appCtx), echo.New().NewContext(req, recorder))
+		return recorder
+	}
+
+	if got := send(false).Header().Get(HeaderCache); got != "MISS" {
+		t.Errorf("Expected %s MISS, got '%s'", HeaderCache, got)
+	}
+	if got := send(false).Header().Get(HeaderCache); got != "HIT" {
+		t.Errorf("Expected %s HIT, got '%s'", HeaderCache, got)
+	}
+
+	recorder := send(true)
+	if got := recorder.Header().Get(HeaderCache); got != "HIT" {
+		t.Errorf("Expected %s HIT on a stream, got '%s'", HeaderCache, got)
+	}
+	if response, _ := readStreamedResponse(t, recorder.Body.String()); response != "Cached answer." {
+		t.Errorf("Expected the cached answer to be streamed, got '%s'", response)
+	}
+
+	if got := len(provider.Requests()); got != 1 {
+		t.Errorf("Expected 1 upstream request, got %d", got)
+	}
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"chat-backend/internal/app"
+	"chat-backend/internal/jobs"
+	"chat-backend/internal/tenant"
+)
+
+// JobRequest is the body of the submit endpoint: a chat request, plus a URL
+// to call back once the job has finished
+type JobRequest struct {
+	ChatRequest
+	WebhookURL string `json:"webhook_url,omitempty"`
+}
+
+// SubmitJobHandler queues a chat request and answers straight away with the
+// job, which clients poll or get called back about
+func SubmitJobHandler(appCtx *app.AppContext) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var jobReq JobRequest
+		if err := bindJobRequest(c, &jobReq); err != nil {
+			return err
+		}
+
+		chatRequest, metadata, err := NewChatRequest(c.Request().Context(), appCtx, &jobReq.ChatRequest)
+		if err != nil {
+			return err
+		}
+
+		if err := validateWebhookURL(jobReq.WebhookURL, appCtx.Jobs); err != nil {
+			return err
+		}
+
+		job, err := appCtx.Jobs.Submit(c.Request().Context(), chatRequest, jobs.Options{
+			WebhookURL: jobReq.WebhookURL,
+			Metadata:   metadata,
+		})
+		if err != nil {
+			return err
+		}
+
+		slog.Info("Queued chat job", "job", job.ID, "tenant", job.TenantID)
+		c.Response().Header().Set(echo.HeaderLocation, "/api/chat/jobs/"+job.ID)
+		return c.JSON(http.StatusAccepted, job)
+	}
+}
+
+func GetJobHandler(appCtx *app.AppContext) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		job, err := tenantJob(c, appCtx.Jobs)
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, job)
+	}
+}
+
+// CancelJobHandler stops a queued or running job
+func CancelJobHandler(appCtx *app.AppContext) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		job, err := tenantJob(c, appCtx.Jobs)
+		if err != nil {
+			return err
+		}
+
+		job, err = appCtx.Jobs.Cancel(job.ID)
+		if err != nil {
+			return jobError(err)
+		}
+
+		slog.Info("Cancelled chat job", "job", job.ID)
+		return c.JSON(http.StatusOK, job)
+	}
+}
+
+// JobFailure reports why a job failed the way a synchronous request would
+// have been answered. It is installed as the jobs manager's describer.
+func JobFailure(err error) jobs.Failure {
+	problem := NewProblemDetails(err)
+	return jobs.Failure{
+		Type:   problem.Type,
+		Title:  problem.Title,
+		Status: problem.Status,
+		Detail: problem.Detail,
+	}
+}
+
+// bindJobRequest decodes a job from a JSON body, or from a multipart form
+// laid out as for the chat endpoint with an extra "webhook_url" field
+func bindJobRequest(c echo.Context, jobReq *JobRequest) error {
+	if !strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		return bindJSON(c, jobReq)
+	}
+
+	if err := bindChatRequest(c, &jobReq.ChatRequest); err != nil {
+		return err
+	}
+	jobReq.WebhookURL = c.FormValue("webhook_url")
+	return nil
+}
+
+func validateWebhookURL(raw string, manager *jobs.Manager) error {
+	if raw == "" {
+		return nil
+	}
+	if !manager.WebhooksEnabled() {
+		return &ValidationError{Errors: []FieldError{{Field: "webhook_url", Message: "webhooks are not enabled on this server"}}}
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return &ValidationError{Errors: []FieldError{{Field: "webhook_url", Message: "must be an absolute http or https URL"}}}
+	}
+	if err := manager.CheckWebhookHost(parsed.Hostname()); err != nil {
+		return &ValidationError{Errors: []FieldError{{Field: "webhook_url", Message: "must not point at a loopback, link-local or private address"}}}
+	}
+	return nil
+}
+
+// tenantJob looks up the job named in the path. Jobs of other tenants are
+// reported as not found.
+func tenantJob(c echo.Context, manager *jobs.Manager) (jobs.Job, error) {
+	job, err := manager.Get(c.Param("id"))
+	if err != nil {
+		return jobs.Job{}, jobError(err)
+	}
+	if job.TenantID != tenant.FromContext(c.Request().Context()).ID {
+		return jobs.Job{}, &requestError{Status: http.StatusNotFound, Message: "job not found: " + c.Param("id")}
+	}
+	return job, nil
+}
+
+func jobError(err error) error {
+	switch {
+	case errors.Is(err, jobs.ErrNotFound):
+		return &requestError{Status: http.StatusNotFound, Message: err.Error()}
+	case errors.Is(err, jobs.ErrFinished):
+		return &requestError{Status: http.StatusConflict, Message: err.Error()}
+	}
+	return err
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"chat-backend/internal/app"
+	"chat-backend/internal/jobs"
+	"chat-backend/internal/tenant"
+)
+
+// callJob runs a jobs handler as tenantID, with id as the path parameter
+func callJob(handler echo.HandlerFunc, method, tenantID, id, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/api/chat/jobs", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(tenant.WithTenant(req.Context(), tenant.Tenant{ID: tenantID}))
+	recorder := httptest.NewRecorder()
+	c := echo.New().NewContext(req, recorder)
+	if id != "" {
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+	}
+	runHandler(handler, c)
+	return recorder
+}
+
+// pollJob fetches the job until it has finished
+func pollJob(t *testing.T, appCtx *app.AppContext, tenantID, id string) jobs.Job {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		recorder := callJob(GetJobHandler(appCtx), http.MethodGet, tenantID, id, "")
+		var job jobs.Job
+		json.NewDecoder(recorder.Body).Decode(&job)
+		if job.Status.Done() {
+			return job
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("Timed out waiting for job %s", id)
+	return jobs.Job{}
+}
+
+func TestJobHandlers_SubmitAndPoll(t *testing.T) {
+	appCtx, _ := newScriptedAppContext(t, `default: {response: "A long answer"}`)
+
+	recorder := callJob(SubmitJobHandler(appCtx), http.MethodPost, "acme", "", `{"messages": [{"role": "user", "content": "Write an essay"}]}`)
+	if recorder.Code != http.StatusAccepted {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusAccepted, recorder.Code, recorder.Body.String())
+	}
+
+	var submitted jobs.Job
+	if err := json.NewDecoder(recorder.Body).Decode(&submitted); err != nil {
+		t.Fatalf("Failed to decode job: %v", err)
+	}
+	if location := recorder.Header().Get(echo.HeaderLocation); location != "/api/chat/jobs/"+submitted.ID {
+		t.Errorf("Expected the job location, got '%s'", location)
+	}
+
+	job := pollJob(t, appCtx, "acme", submitted.ID)
+	if job.Status != jobs.StatusSucceeded || job.Result == nil || job.Result.Content != "A long answer" {
+		t.Errorf("Expected the job to succeed with the answer, got %+v", job)
+	}
+
+	// Other tenants cannot see the job
+	recorder = callJob(GetJobHandler(appCtx), http.MethodGet, "globex", submitted.ID, "")
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d for another tenant's job, got %d", http.StatusNotFound, recorder.Code)
+	}
+
+	recorder = callJob(CancelJobHandler(appCtx), http.MethodDelete, "acme", submitted.ID, "")
+	if recorder.Code != http.StatusConflict {
+		t.Errorf("Expected status code %d cancelling a finished job, got %d", http.StatusConflict, recorder.Code)
+	}
+}
+
+func TestJobHandlers_FailureUsesProblemDetails(t *testing.T) {
+	appCtx, _ := newScriptedAppContext(t, `default: {error: unavailable}`)
+	appCtx.Jobs.WithDescriber(JobFailure)
+
+	recorder := callJob(SubmitJobHandler(appCtx), http.MethodPost, "", "", `{"messages": [{"role": "user", "content": "Hi"}]}`)
+	var submitted jobs.Job
+	json.NewDecoder(recorder.Body).Decode(&submitted)
+
+	job := pollJob(t, appCtx, "", submitted.ID)
+	if job.Status != jobs.StatusFailed || job.Error == nil || job.Error.Type != "urn:chat-backend:problem:provider-unavailable" {
+		t.Errorf("Expected a provider-unavailable failure, got %+v", job.Error)
+	}
+}
+
+func TestJobHandlers_Validation(t *testing.T) {
+	appCtx, _ := newScriptedAppContext(t, `default: {response: "ok"}`)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"invalid chat request", `{"messages": []}`, http.StatusBadRequest},
+		{"webhooks disabled", `{"messages": [{"role": "user", "content": "Hi"}], "webhook_url": "https://example.com/hook"}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := callJob(SubmitJobHandler(appCtx), http.MethodPost, "", "", tt.body)
+			if recorder.Code != tt.status {
+				t.Errorf("Expected status code %d, got %d: %s", tt.status, recorder.Code, recorder.Body.String())
+			}
+		})
+	}
+
+	recorder := callJob(GetJobHandler(appCtx), http.MethodGet, "", "missing", "")
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d for an unknown job, got %d", http.StatusNotFound, recorder.Code)
+	}
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	manager := jobs.NewManager(&mockChatProvider{}, jobs.Config{WebhookSecret: "s3cret"})
+	for raw, valid := range map[string]bool{
+		"":                                   true,
+		"https://example.com/hook":           true,
+		"http://93.184.215.14:8080/cb":       true,
+		"http://10.0.0.1:8080/cb":            false,
+		"http://169.254.169.254/latest/meta": false,
+		"http://localhost:3000/hook":         false,
+		"http://[::1]/hook":                  false,
+		"ftp://example.com/hook":             false,
+		"/relative/hook":                     false,
+		"https://":                           false,
+	} {
+		if err := validateWebhookURL(raw, manager); (err == nil) != valid {
+			t.Errorf("Expected %q valid=%v, got: %v", raw, valid, err)
+		}
+	}
+
+	manager = jobs.NewManager(&mockChatProvider{}, jobs.Config{WebhookSecret: "s3cret", AllowPrivateWebhooks: true})
+	if err := validateWebhookURL("http://10.0.0.1:8080/cb", manager); err != nil {
+		t.Errorf("Expected private addresses to be allowed when configured, got: %v", err)
+	}
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"chat-backend/internal/app"
+	"chat-backend/internal/chat"
+	"chat-backend/internal/prompts"
+	"chat-backend/internal/tenant"
+)
+
+// Keys of the response metadata naming the server-side prompt that was used
+const (
+	MetadataPromptTemplate = "prompt_template"
+	MetadataPromptVersion  = "prompt_version"
+)
+
+// PromptRequest picks a server-side prompt template for one chat request.
+// Version 0 means the latest version.
+type PromptRequest struct {
+	Name      string            `json:"name"`
+	Version   int               `json:"version,omitempty"`
+	Variables map[string]string `json:"variables,omitempty"`
+}
+
+// PromptTemplateRequest is the body of the create and update endpoints
+type PromptTemplateRequest struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Content     string            `json:"content"`
+	Variables   map[string]string `json:"variables,omitempty"`
+}
+
+// systemPrompt renders the template named by the request, or else the
+// tenant's default template, as a system message. It returns nil when
+// neither picks a template.
+func systemPrompt(ctx context.Context, store *prompts.Store, prompt *PromptRequest) (*chat.Message, map[string]string, error) {
+	t := tenant.FromContext(ctx)
+
+	// A request without a name, even one that sets variables, gets the
+	// tenant's template
+	requested := prompt != nil && prompt.Name != ""
+	name, version := t.PromptTemplate, 0
+	if requested {
+		name, version = prompt.Name, prompt.Version
+	}
+	if name == "" {
+		return nil, nil, nil
+	}
+
+	// Request variables override the tenant's, which override the
+	// template's own defaults
+	vars := map[string]string{"tenant": t.ID}
+	for key, value := range t.PromptVariables {
+		vars[key] = value
+	}
+	if prompt != nil {
+		for key, value := range prompt.Variables {
+			vars[key] = value
+		}
+	}
+
+	tmpl, err := store.Get(name, version)
+	if err == nil {
+		var content string
+		content, err = tmpl.Render(vars)
+		if err == nil {
+			metadata := map[string]string{
+				MetadataPromptTemplate: tmpl.Name,
+				MetadataPromptVersion:  strconv.Itoa(tmpl.Version),
+			}
+			return &chat.Message{Role: "system", Content: content}, metadata, nil
+		}
+	}
+
+	if !requested {
+		// A broken tenant default should not take the tenant's chat down
+		slog.Error("Failed to apply tenant prompt template", "tenant", t.ID, "template", name, "error", err)
+		return nil, nil, nil
+	}
+
+	field := "prompt.variables"
+	if errors.Is(err, prompts.ErrNotFound) {
+		field = "prompt.name"
+	}
+	return nil, nil, &ValidationError{Errors: []FieldError{{Field: field, Message: err.Error()}}}
+}
+
+func ListPromptsHandler(appCtx *app.AppContext) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, appCtx.Prompts.List())
+	}
+}
+
+func GetPromptHandler(appCtx *app.AppContext) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		version := 0
+		if raw := c.QueryParam("version"); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 1 {
+				return &ValidationError{Errors: []FieldError{{Field: "version", Message: "must be a positive integer"}}}
+			}
+			version = n
+		}
+
+		tmpl, err := appCtx.Prompts.Get(c.Param("name"), version)
+		if err != nil {
+			return promptError(err)
+		}
+		return c.JSON(http.StatusOK, tmpl)
+	}
+}
+
+func ListPromptVersionsHandler(appCtx *app.AppContext) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		versions, err := appCtx.Prompts.Versions(c.Param("name"))
+		if err != nil {
+			return promptError(err)
+		}
+		return c.JSON(http.StatusOK, versions)
+	}
+}
+
+func CreatePromptHandler(appCtx *app.AppContext) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req PromptTemplateRequest
+		if err := c.Bind(&req); err != nil {
+			return &requestError{Status: http.StatusBadRequest, Message: "Invalid request format"}
+		}
+
+		tmpl, err := appCtx.Prompts.Create(prompts.Template{
+			Name:        req.Name,
+			Description: req.Description,
+			Content:     req.Content,
+			Variables:   req.Variables,
+		})
+		if err != nil {
+			return promptError(err)
+		}
+
+		slog.Info("Created prompt template", "name", tmpl.Name)
+		return c.JSON(http.StatusCreated, tmpl)
+	}
+}
+
+// UpdatePromptHandler stores a new version of a template. The name in the
+// path wins over any name in the body.
+func UpdatePromptHandler(appCtx *app.AppContext) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req PromptTemplateRequest
+		if err := c.Bind(&req); err != nil {
+			return &requestError{Status: http.StatusBadRequest, Message: "Invalid request format"}
+		}
+
+		tmpl, err := appCtx.Prompts.Update(c.Param("name"), prompts.Template{
+			Description: req.Description,
+			Content:     req.Content,
+			Variables:   req.Variables,
+		})
+		if err != nil {
+			return promptError(err)
+		}
+
+		slog.Info("Updated prompt template", "name", tmpl.Name, "version", tmpl.Version)
+		return c.JSON(http.StatusOK, tmpl)
+	}
+}
+
+func DeletePromptHandler(appCtx *app.AppContext) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if err := appCtx.Prompts.Delete(c.Param("name")); err != nil {
+			return promptError(err)
+		}
+
+		slog.Info("Deleted prompt template", "name", c.Param("name"))
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func promptError(err error) error {
+	switch {
+	case errors.Is(err, prompts.ErrNotFound):
+		return &requestError{Status: http.StatusNotFound, Message: err.Error()}
+	case errors.Is(err, prompts.ErrExists):
+		return &requestError{Status: http.StatusConflict, Message: err.Error()}
+	case errors.Is(err, prompts.ErrInvalidTemplate):
+		return &requestError{Status: http.StatusBadRequest, Message: err.Error()}
+	}
+	return err
+}
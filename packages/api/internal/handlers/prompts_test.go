@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"chat-backend/internal/prompts"
+	"chat-backend/internal/tenant"
+)
+
+func TestPromptHandlers_CRUD(t *testing.T) {
+	appCtx, _ := newScriptedAppContext(t, `default: {response: "ok"}`)
+	e := echo.New()
+
+	call := func(handler echo.HandlerFunc, method, path, body string, params ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		recorder := httptest.NewRecorder()
+		c := e.NewContext(req, recorder)
+		if len(params) > 0 {
+			c.SetParamNames("name")
+			c.SetParamValues(params...)
+		}
+		runHandler(handler, c)
+		return recorder
+	}
+
+	recorder := call(CreatePromptHandler(appCtx), http.MethodPost, "/api/prompts", `{"name": "support", "content": "You are {{.company}} support."}`)
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusCreated, recorder.Code, recorder.Body.String())
+	}
+
+	recorder = call(CreatePromptHandler(appCtx), http.MethodPost, "/api/prompts", `{"name": "support", "content": "again"}`)
+	if recorder.Code != http.StatusConflict {
+		t.Errorf("Expected status code %d for a duplicate, got %d", http.StatusConflict, recorder.Code)
+	}
+
+	recorder = call(UpdatePromptHandler(appCtx), http.MethodPut, "/api/prompts/support", `{"content": "You are {{.company}} support. Be kind."}`, "support")
+	var updated prompts.Template
+	json.NewDecoder(recorder.Body).Decode(&updated)
+	if recorder.Code != http.StatusOK || updated.Version != 2 {
+		t.Errorf("Expected version 2, got %d: %+v", recorder.Code, updated)
+	}
+
+	recorder = call(ListPromptVersionsHandler(appCtx), http.MethodGet, "/api/prompts/support/versions", "", "support")
+	var versions []prompts.Template
+	json.NewDecoder(recorder.Body).Decode(&versions)
+	if len(versions) != 2 {
+		t.Errorf("Expected 2 versions, got %d", len(versions))
+	}
+
+	recorder = call(UpdatePromptHandler(appCtx), http.MethodPut, "/api/prompts/support", `{"content": "{{.broken"}`, "support")
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d for an invalid template, got %d", http.StatusBadRequest, recorder.Code)
+	}
+
+	recorder = call(DeletePromptHandler(appCtx), http.MethodDelete, "/api/prompts/support", "", "support")
+	if recorder.Code != http.StatusNoContent {
+		t.Errorf("Expected status code %d, got %d", http.StatusNoContent, recorder.Code)
+	}
+
+	recorder = call(GetPromptHandler(appCtx), http.MethodGet, "/api/prompts/support", "", "support")
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d after delete, got %d", http.StatusNotFound, recorder.Code)
+	}
+}
+
+func TestChatHandler_AppliesPromptTemplate(t *testing.T) {
+	appCtx, provider := newScriptedAppContext(t, `default: {response: "ok"}`)
+	appCtx.Prompts.Create(prompts.Template{
+		Name:      "support",
+		Content:   "You are {{.company}} support for tenant {{.tenant}}.",
+		Variables: map[string]string{"company": "Acme"},
+	})
+
+	body := `{"messages": [{"role": "user", "content": "Hi"}], "prompt": {"name": "support", "variables": {"company": "Globex"}}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/chat", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(tenant.WithTenant(req.Context(), tenant.Tenant{ID: "globex"}))
+	recorder := httptest.NewRecorder()
+	c := echo.New().NewContext(req, recorder)
+
+	runHandler(ChatHandler(appCtx), c)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body.String())
+	}
+
+	messages := provider.Requests()[0].Messages
+	if len(messages) != 2 || messages[0].Role != "system" || messages[0].Content != "You are Globex support for tenant globex." {
+		t.Errorf("Expected rendered system prompt first, got %+v", messages)
+	}
+
+	var response ChatResponse
+	json.NewDecoder(recorder.Body).Decode(&response)
+	if response.Metadata[MetadataPromptTemplate] != "support" || response.Metadata[MetadataPromptVersion] != "1" {
+		t.Errorf("Unexpected metadata: %v", response.Metadata)
+	}
+}
+
+func TestChatHandler_TenantDefaultPrompt(t *testing.T) {
+	appCtx, provider := newScriptedAppContext(t, `default: {response: "ok"}`)
+	appCtx.Prompts.Create(prompts.Template{Name: "faq", Content: "Answer questions about {{.product}}."})
+
+	tests := []struct {
+		name   string
+		prompt string
+		want   string
+	}{
+		{"no prompt", ``, "Answer questions about rockets."},
+		{"empty name", `, "prompt": {"name": ""}`, "Answer questions about rockets."},
+		{"variables only", `, "prompt": {"variables": {"product": "boosters"}}`, "Answer questions about boosters."},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `{"messages": [{"role": "user", "content": "Hi"}]` + tt.prompt + `}`
+			req := httptest.NewRequest(http.MethodPost, "/api/chat", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req = req.WithContext(tenant.WithTenant(req.Context(), tenant.Tenant{
+				ID:              "acme",
+				PromptTemplate:  "faq",
+				PromptVariables: map[string]string{"product": "rockets"},
+			}))
+			recorder := httptest.NewRecorder()
+			c := echo.New().NewContext(req, recorder)
+
+			runHandler(ChatHandler(appCtx), c)
+
+			messages := provider.Requests()[i].Messages
+			if messages[0].Content != tt.want {
+				t.Errorf("Expected tenant prompt to be applied, got %+v", messages)
+			}
+		})
+	}
+}
+
+func TestChatHandler_UnknownPromptTemplate(t *testing.T) {
+	appCtx, provider := newScriptedAppContext(t, `default: {response: "ok"}`)
+
+	body := `{"messages": [{"role": "user", "content": "Hi"}], "prompt": {"name": "missing"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/chat", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	c := echo.New().NewContext(req, recorder)
+
+	runHandler(ChatHandler(appCtx), c)
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, recorder.Code)
+	}
+	if len(provider.Requests()) != 0 {
+		t.Error("Expected the provider not to be called")
+	}
+}
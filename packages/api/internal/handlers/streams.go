@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"chat-backend/internal/app"
+	"chat-backend/internal/chat"
+	"chat-backend/internal/generations"
+	"chat-backend/internal/streams"
+	"chat-backend/internal/tenant"
+)
+
+// ResumeStreamHandler lets a client that lost its connection pick a
+// streamed generation up again. It replays the events after the one named
+// by the Last-Event-ID header, or all of them without it, and then follows
+// the generation live.
+func ResumeStreamHandler(appCtx *app.AppContext) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tenantID := tenant.FromContext(c.Request().Context()).ID
+		stream, err := appCtx.Streams.Get(tenantID, c.Param("id"))
+		if err != nil {
+			if errors.Is(err, streams.ErrNotFound) {
+				return &requestError{Status: http.StatusNotFound, Message: err.Error()}
+			}
+			return err
+		}
+
+		after := 0
+		if lastEventID := c.Request().Header.Get("Last-Event-ID"); lastEventID != "" {
+			after, err = strconv.Atoi(lastEventID)
+			if err != nil || after < 0 {
+				return &requestError{Status: http.StatusBadRequest, Message: "Last-Event-ID must be an event id of the stream"}
+			}
+		}
+
+		slog.Info("Resuming chat stream", "generation", c.Param("id"), "after", after)
+		return followStream(c, stream, after)
+	}
+}
+
+// streamGeneration runs a streamed generation and publishes its events.
+// It returns the whole answer when the generation completes. It only
+// returns an error when the generation failed before it published
+// anything, so that the client can still get a plain error response.
+func streamGeneration(ctx context.Context, provider chat.ChatProvider, req *chat.ChatRequest, metadata map[string]string, id, messageID string, stream *streams.Stream) (*chat.ChatResponse, error) {
+	// What was streamed so far, to account for a cancelled stream
+	var content strings.Builder
+	var usage *chat.Usage
+	var toolCalls []chat.ToolCall
+	var finishReason string
+	var answerMetadata map[string]string
+	published := false
+
+	publish := func(event any, metadata map[string]string) error {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		stream.Publish(data, metadata)
+		published = true
+		return nil
+	}
+
+	err := provider.ChatStream(ctx, req, func(chunk *chat.ChatResponse) error {
+		content.WriteString(chunk.Content)
+		if chunk.Usage != nil {
+			usage = chunk.Usage
+		}
+		toolCalls = append(toolCalls, chunk.ToolCalls...)
+		if chunk.FinishReason != "" {
+			finishReason = chunk.FinishReason
+		}
+		answerMetadata = mergeMetadata(answerMetadata, chunk.Metadata)
+
+		// The IDs and handler metadata ride on the first event
+		event := StreamEvent{
+			Response:  chunk.Content,
+			Answers:   chunk.Answers,
+			ToolCalls: chunk.ToolCalls,
+			Metadata:  mergeMetadata(chunk.Metadata, metadata),
+		}
+		if !published {
+			event.ID, event.MessageID = id, messageID
+		}
+		metadata = nil
+		return publish(event, event.Metadata)
+	})
+
+	switch {
+	case err != nil && generations.Cancelled(ctx):
+		// A cancelled stream still ends normally, with what it used
+		usage = partialUsage(req, content.String(), usage)
+		logCancelled(id, usage)
+		return nil, publish(StreamEvent{ID: id, Done: true, Cancelled: true, Usage: usage}, nil)
+
+	case err != nil && !published:
+		// Nothing was streamed yet, so report a plain error response,
+		// such as a 503 when the provider's queue is full
+		slog.Error("Failed to start chat stream", "error", err, "messages_count", len(req.Messages))
+		return nil, err
+
+	case err != nil:
+		slog.Error("Failed to stream chat response", "error", err, "messages_count", len(req.Messages))
+		// Headers are already sent, so the error goes in the stream
+		problem := NewProblemDetails(err)
+		return nil, publish(StreamError{
+			Error:  problem.Detail,
+			Type:   problem.Type,
+			Status: problem.Status,
+		}, nil)
+	}
+
+	stream.Publish([]byte(`{"done": true}`), nil)
+	return &chat.ChatResponse{
+		Content:      content.String(),
+		ToolCalls:    toolCalls,
+		Usage:        usage,
+		FinishReason: finishReason,
+		Metadata:     answerMetadata,
+	}, nil
+}
+
+// followStream writes a stream's events after the given ID as server-sent
+// events, with their IDs for Last-Event-ID. Headers go out with the first
+// event, so they can report how it was answered, such as whether it came
+// from the cache, and a stream that failed before its first event gets a
+// plain error response.
+func followStream(c echo.Context, stream *streams.Stream, after int) error {
+	started := false
+	start := func(metadata map[string]string) {
+		if started {
+			return
+		}
+		started = true
+		setCacheHeader(c, metadata)
+
+		// Set up Server-Sent Events headers
+		c.Response().Header().Set("Content-Type", "text/event-stream")
+		c.Response().Header().Set("Cache-Control", "no-cache")
+		c.Response().Header().Set("Connection", "keep-alive")
+		c.Response().Header().Set("Access-Control-Allow-Origin", "*")
+
+		c.Response().WriteHeader(http.StatusOK)
+		c.Response().Flush()
+	}
+
+	ctx := c.Request().Context()
+	err := stream.Follow(ctx, after, func(event streams.Event) error {
+		start(event.Metadata)
+		if _, err := fmt.Fprintf(c.Response(), "id: %d\ndata: %s\n\n", event.ID, event.Data); err != nil {
+			return err
+		}
+		c.Response().Flush()
+		return nil
+	})
+
+	// Once the client is gone, or the response has started, there is no
+	// one left to report an error to
+	if err != nil && !started && ctx.Err() == nil {
+		return err
+	}
+	start(nil)
+	return nil
+}
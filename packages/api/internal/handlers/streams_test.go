@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"chat-backend/internal/chat"
+	"chat-backend/internal/streams"
+)
+
+// pausedProvider streams one chunk, then waits to be released before it
+// streams the rest
+type pausedProvider struct {
+	release chan struct{}
+}
+
+func (p *pausedProvider) Chat(ctx context.Context, req *chat.ChatRequest) (*chat.ChatResponse, error) {
+	return &chat.ChatResponse{Content: "Hello world"}, nil
+}
+
+func (p *pausedProvider) ChatStream(ctx context.Context, req *chat.ChatRequest, callback chat.StreamCallback) error {
+	if err := callback(&chat.ChatResponse{Content: "Hello"}); err != nil {
+		return err
+	}
+	select {
+	case <-p.release:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return callback(&chat.ChatResponse{Content: " world"})
+}
+
+type sseEvent struct {
+	id    string
+	event StreamEvent
+}
+
+// readEvent reads the next server-sent event, or returns false at the end
+// of the stream
+func readEvent(t *testing.T, reader *bufio.Reader) (sseEvent, bool) {
+	t.Helper()
+
+	var got sseEvent
+	for {
+		line, err := reader.ReadString('\n')
+		if err == io.EOF {
+			return got, false
+		}
+		if err != nil {
+			t.Fatalf("Failed to read the stream: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return got, true
+		}
+		if id, ok := strings.CutPrefix(line, "id: "); ok {
+			got.id = id
+		}
+		if data, ok := strings.CutPrefix(line, "data: "); ok {
+			if err := json.Unmarshal([]byte(data), &got.event); err != nil {
+				t.Fatalf("Failed to decode event %q: %v", data, err)
+			}
+		}
+	}
+}
+
+func startStream(t *testing.T, url string) *http.Response {
+	t.Helper()
+
+	resp, err := http.Post(url+"/api/chat", echo.MIMEApplicationJSON,
+		strings.NewReader(`{"messages": [{"role": "user", "content": "Hi"}], "streaming": true}`))
+	if err != nil {
+		t.Fatalf("Failed to start the stream: %v", err)
+	}
+	return resp
+}
+
+func TestResumeStream(t *testing.T) {
+	provider := &pausedProvider{release: make(chan struct{})}
+	server, _ := newGenerationServer(t, provider)
+
+	// The client drops the connection after the first event
+	resp := startStream(t, server.URL)
+	first, _ := readEvent(t, bufio.NewReader(resp.Body))
+	resp.Body.Close()
+	if first.id != "1" || first.event.Response != "Hello" {
+		t.Fatalf("Expected the first chunk as event 1, got %+v", first)
+	}
+	id := resp.Header.Get(HeaderGenerationID)
+
+	// and reconnects, while the generation goes on
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/chat/streams/"+id, nil)
+	req.Header.Set("Last-Event-ID", first.id)
+	close(provider.release)
+	resumed, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to resume the stream: %v", err)
+	}
+	defer resumed.Body.Close()
+
+	if resumed.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, resumed.StatusCode)
+	}
+
+	var events []sseEvent
+	reader := bufio.NewReader(resumed.Body)
+	for {
+		event, ok := readEvent(t, reader)
+		if !ok {
+			break
+		}
+		events = append(events, event)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("Expected the rest of the stream, got %+v", events)
+	}
+	if events[0].id != "2" || events[0].event.Response != " world" {
+		t.Errorf("Expected the missed chunk as event 2, got %+v", events[0])
+	}
+	if events[1].id != "3" || !events[1].event.Done || events[1].event.Cancelled {
+		t.Errorf("Expected the stream to end as event 3, got %+v", events[1])
+	}
+}
+
+func TestResumeStream_NotResumable(t *testing.T) {
+	provider := newRunawayProvider()
+	server, appCtx := newGenerationServer(t, provider)
+	appCtx.Streams = streams.NewBuffer(0)
+
+	// Without a TTL, the generation stops with its client
+	resp := startStream(t, server.URL)
+	readEvent(t, bufio.NewReader(resp.Body))
+	resp.Body.Close()
+
+	if err := <-provider.cancelled; err == nil {
+		t.Error("Expected the provider's context to be cancelled")
+	}
+}
+
+func TestResumeStream_NotFound(t *testing.T) {
+	server, _ := newGenerationServer(t, &mockChatProvider{})
+
+	resp, err := http.Get(server.URL + "/api/chat/streams/unknown")
+	if err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected status code %d, got %d", http.StatusNotFound, resp.StatusCode)
+	}
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxMessages          = 100
+	maxMessageBytes      = 32 << 10
+	maxTotalContentBytes = 256 << 10
+)
+
+var allowedRoles = map[string]bool{
+	"system":    true,
+	"user":      true,
+	"assistant": true,
+	"tool":      true,
+}
+
+// FieldError describes one invalid field of a request. Field is a path such
+// as "messages[2].role".
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError collects every problem found in a request so the client
+// can fix them in one go
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, fieldErr := range e.Errors {
+		messages[i] = fmt.Sprintf("%s: %s", fieldErr.Field, fieldErr.Message)
+	}
+	return "invalid request: " + strings.Join(messages, "; ")
+}
+
+func (e *ValidationError) add(field, format string, args ...any) {
+	e.Errors = append(e.Errors, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// validateChatRequest checks roles, sizes, encoding and message ordering.
+// Attachments are checked separately by validateAttachments.
+func validateChatRequest(req *ChatRequest) error {
+	v := &ValidationError{}
+
+	if len(req.Messages) == 0 {
+		v.add("messages", "is required and cannot be empty")
+		return v
+	}
+	if len(req.Messages) > maxMessages {
+		v.add("messages", "cannot contain more than %d messages", maxMessages)
+		return v
+	}
+
+	total := 0
+	seenConversation := false
+	// Tool call IDs requested by the latest assistant message that have not
+	// been answered yet
+	pendingToolCalls := map[string]bool{}
+
+	// The history of a continued conversation only counts for the order of
+	// the messages that follow it
+	for _, msg := range req.history {
+		if msg.Role == "tool" {
+			delete(pendingToolCalls, msg.ToolCallID)
+		} else if msg.Role != "system" {
+			seenConversation = true
+			pendingToolCalls = map[string]bool{}
+			for _, call := range msg.ToolCalls {
+				pendingToolCalls[call.ID] = true
+			}
+		}
+	}
+
+	for i, msg := range req.Messages {
+		field := fmt.Sprintf("messages[%d]", i)
+
+		if !allowedRoles[msg.Role] {
+			v.add(field+".role", "must be one of system, user, assistant or tool")
+		}
+
+		if !utf8.ValidString(msg.Content) {
+			v.add(field+".content", "must be valid UTF-8")
+		}
+		if len(msg.Content) > maxMessageBytes {
+			v.add(field+".content", "cannot be longer than %d bytes", maxMessageBytes)
+		}
+		total += len(msg.Content)
+
+		if strings.TrimSpace(msg.Content) == "" && !allowsEmptyContent(msg) {
+			v.add(field+".content", "cannot be empty")
+		}
+
+		if len(msg.ToolCalls) > 0 && msg.Role != "assistant" {
+			v.add(field+".tool_calls", "is only allowed on assistant messages")
+		}
+		if msg.ToolCallID != "" && msg.Role != "tool" {
+			v.add(field+".tool_call_id", "is only allowed on tool messages")
+		}
+		for j, call := range msg.ToolCalls {
+			if call.ID == "" {
+				v.add(fmt.Sprintf("%s.tool_calls[%d].id", field, j), "is required")
+			}
+			if call.Name == "" {
+				v.add(fmt.Sprintf("%s.tool_calls[%d].name", field, j), "is required")
+			}
+		}
+
+		switch msg.Role {
+		case "system":
+			if seenConversation {
+				v.add(field+".role", "system messages must come before the rest of the conversation")
+			}
+		case "tool":
+			seenConversation = true
+			if msg.ToolCallID == "" {
+				v.add(field+".tool_call_id", "is required on tool messages")
+			} else if !pendingToolCalls[msg.ToolCallID] {
+				v.add(field+".tool_call_id", "does not answer a tool call of the preceding assistant message")
+			}
+			delete(pendingToolCalls, msg.ToolCallID)
+		default:
+			seenConversation = true
+			pendingToolCalls = map[string]bool{}
+			for _, call := range msg.ToolCalls {
+				pendingToolCalls[call.ID] = true
+			}
+		}
+	}
+
+	if total > maxTotalContentBytes {
+		v.add("messages", "total content cannot be longer than %d bytes", maxTotalContentBytes)
+	}
+
+	if last := req.Messages[len(req.Messages)-1]; last.Role != "user" && last.Role != "tool" {
+		v.add("messages", "must end with a user or tool message")
+	}
+
+	if len(v.Errors) > 0 {
+		return v
+	}
+	return nil
+}
+
+// allowsEmptyContent reports whether a message says something without text,
+// by calling tools or carrying attachments
+func allowsEmptyContent(msg Message) bool {
+	return (msg.Role == "assistant" && len(msg.ToolCalls) > 0) || len(msg.Attachments) > 0
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"chat-backend/internal/app"
+	"chat-backend/internal/chat"
+)
+
+func TestValidateChatRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		messages []Message
+		fields   []string
+	}{
+		{
+			name: "valid conversation",
+			messages: []Message{
+				{Role: "system", Content: "Be brief"},
+				{Role: "user", Content: "Hello"},
+				{Role: "assistant", Content: "Hi!"},
+				{Role: "user", Content: "How are you?"},
+			},
+		},
+		{
+			name: "valid tool round trip",
+			messages: []Message{
+				{Role: "user", Content: "Weather in Paris?"},
+				{Role: "assistant", ToolCalls: []chat.ToolCall{{ID: "call_1", Name: "get_weather", Arguments: "{}"}}},
+				{Role: "tool", ToolCallID: "call_1", Content: "18C"},
+			},
+		},
+		{
+			name:     "empty",
+			messages: nil,
+			fields:   []string{"messages"},
+		},
+		{
+			name:     "unknown role",
+			messages: []Message{{Role: "wizard", Content: "Hello"}, {Role: "user", Content: "Hi"}},
+			fields:   []string{"messages[0].role"},
+		},
+		{
+			name:     "empty content",
+			messages: []Message{{Role: "user", Content: "  "}},
+			fields:   []string{"messages[0].content"},
+		},
+		{
+			name:     "invalid utf8",
+			messages: []Message{{Role: "user", Content: "caf\xe9"}},
+			fields:   []string{"messages[0].content"},
+		},
+		{
+			name:     "message too long",
+			messages: []Message{{Role: "user", Content: strings.Repeat("a", maxMessageBytes+1)}},
+			fields:   []string{"messages[0].content"},
+		},
+		{
+			name: "late system message",
+			messages: []Message{
+				{Role: "user", Content: "Hello"},
+				{Role: "system", Content: "Be brief"},
+				{Role: "user", Content: "Hi"},
+			},
+			fields: []string{"messages[1].role"},
+		},
+		{
+			name: "tool message without matching call",
+			messages: []Message{
+				{Role: "user", Content: "Weather?"},
+				{Role: "tool", ToolCallID: "call_9", Content: "18C"},
+			},
+			fields: []string{"messages[1].tool_call_id"},
+		},
+		{
+			name: "tool calls on user message",
+			messages: []Message{
+				{Role: "user", Content: "Hi", ToolCalls: []chat.ToolCall{{ID: "call_1", Name: "x"}}},
+			},
+			fields: []string{"messages[0].tool_calls"},
+		},
+		{
+			name: "ends with assistant",
+			messages: []Message{
+				{Role: "user", Content: "Hello"},
+				{Role: "assistant", Content: "Hi!"},
+			},
+			fields: []string{"messages"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateChatRequest(&ChatRequest{Messages: tt.messages})
+
+			if len(tt.fields) == 0 {
+				if err != nil {
+					t.Fatalf("Expected request to be valid, got: %v", err)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Expected ValidationError, got: %v", err)
+			}
+
+			var got []string
+			for _, fieldErr := range validationErr.Errors {
+				got = append(got, fieldErr.Field)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.fields, ",") {
+				t.Errorf("Expected errors on %v, got %v", tt.fields, validationErr.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateChatRequest_TooManyMessages(t *testing.T) {
+	messages := make([]Message, maxMessages+1)
+	for i := range messages {
+		messages[i] = Message{Role: "user", Content: "Hello"}
+	}
+
+	if err := validateChatRequest(&ChatRequest{Messages: messages}); err == nil {
+		t.Error("Expected too many messages to be rejected")
+	}
+}
+
+func TestChatHandler_ValidationErrorDetails(t *testing.T) {
+	appCtx := app.NewAppContext(&mockChatProvider{})
+
+	jsonBody, _ := json.Marshal(ChatRequest{
+		Messages: []Message{{Role: "robot", Content: ""}},
+	})
+	req := httptest.NewRequest("POST", "/api/chat", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	c := echo.New().NewContext(req, recorder)
+
+	runHandler(ChatHandler(appCtx), c)
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status code %d, got %d", http.StatusBadRequest, recorder.Code)
+	}
+
+	var problem ProblemDetails
+	if err := json.NewDecoder(recorder.Body).Decode(&problem); err != nil {
+		t.Fatalf("Failed to decode problem details: %v", err)
+	}
+
+	if problem.Type != "urn:chat-backend:problem:validation-failed" {
+		t.Errorf("Unexpected problem type '%s'", problem.Type)
+	}
+
+	if len(problem.Errors) != 3 {
+		t.Errorf("Expected role, content and ordering errors, got %+v", problem.Errors)
+	}
+}
+
+func TestChatHandler_RejectsInvalidUTF8Body(t *testing.T) {
+	appCtx := app.NewAppContext(&mockChatProvider{})
+
+	body := []byte(`{"messages": [{"role": "user", "content": "caf` + "\xe9" + `"}]}`)
+	req := httptest.NewRequest("POST", "/api/chat", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	c := echo.New().NewContext(req, recorder)
+
+	runHandler(ChatHandler(appCtx), c)
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, recorder.Code)
+	}
+}
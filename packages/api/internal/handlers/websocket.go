@@ -0,0 +1,353 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+
+	"chat-backend/internal/app"
+	"chat-backend/internal/chat"
+)
+
+// Frame types sent by WebSocket clients
+const (
+	FrameChat   = "chat"
+	FrameCancel = "cancel"
+	FramePing   = "ping"
+)
+
+// Frame types sent by the server
+const (
+	FrameDelta = "delta"
+	FrameUsage = "usage"
+	FrameDone  = "done"
+	FrameError = "error"
+	FramePong  = "pong"
+)
+
+// wsSendQueue bounds the frames waiting for a slow client. Once it is
+// full, the stream callback blocks, which holds back the provider's
+// stream until the client catches up.
+const wsSendQueue = 16
+
+// Keepalive timings. The server pings every wsPingPeriod and drops
+// connections that have sent nothing, not even a pong, for wsPongWait, or
+// that do not take a frame within wsWriteWait. They are variables so tests
+// can shorten them.
+var (
+	wsPingPeriod = 30 * time.Second
+	wsPongWait   = 60 * time.Second
+	wsWriteWait  = 10 * time.Second
+)
+
+// ClientFrame is a message from a WebSocket client. A chat frame carries a
+// turn in the same shape as a POST /api/chat body, always streamed back. A
+// cancel frame stops the turn with its ID, or the current one without it.
+type ClientFrame struct {
+	Type string `json:"type"`
+	ID   string `json:"id,omitempty"`
+	ChatRequest
+}
+
+// ServerFrame is a message to a WebSocket client. Every frame of a turn
+// carries its ID, the first delta also carries the answer's message ID,
+// and a turn ends with exactly one done or error frame.
+type ServerFrame struct {
+	Type      string            `json:"type"`
+	ID        string            `json:"id,omitempty"`
+	MessageID string            `json:"message_id,omitempty"`
+	Response  string            `json:"response,omitempty"`
+	Answers   []chat.Answer     `json:"answers,omitempty"`
+	ToolCalls []chat.ToolCall   `json:"tool_calls,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	Usage     *chat.Usage       `json:"usage,omitempty"`
+	Cancelled bool              `json:"cancelled,omitempty"`
+	Error     *ProblemDetails   `json:"error,omitempty"`
+}
+
+var upgrader = websocket.Upgrader{
+	// Like the SSE responses, which allow any origin, the socket carries no
+	// cookies or credentials, so cross-origin clients are accepted
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// WebSocketHandler serves multi-turn chats over one WebSocket connection.
+// Turns run one at a time through the same provider, request handling and
+// conversation history as ChatHandler.
+func WebSocketHandler(appCtx *app.AppContext) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		conn, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
+		if err != nil {
+			// The upgrader has already replied with an HTTP error
+			slog.Warn("Failed to upgrade WebSocket connection", "error", err)
+			return nil
+		}
+
+		session := &wsSession{
+			c:      c,
+			appCtx: appCtx,
+			conn:   conn,
+			out:    make(chan ServerFrame, wsSendQueue),
+		}
+		session.serve()
+		return nil
+	}
+}
+
+// wsSession is one WebSocket connection. Its reader handles client frames,
+// turns run in their own goroutine so that cancel frames are read while
+// they stream, and a single writer sends every frame and ping.
+type wsSession struct {
+	c      echo.Context
+	appCtx *app.AppContext
+	conn   *websocket.Conn
+	out    chan ServerFrame
+
+	mu     sync.Mutex
+	turns  int
+	turnID string
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func (s *wsSession) serve() {
+	ctx, cancel := context.WithCancel(s.c.Request().Context())
+	defer cancel()
+
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(writerDone)
+		s.writeLoop(ctx)
+	}()
+
+	s.readLoop(ctx)
+
+	// The client is gone, so stop the turn and the writer
+	cancel()
+	s.wg.Wait()
+	<-writerDone
+	s.conn.Close()
+}
+
+func (s *wsSession) readLoop(ctx context.Context) {
+	s.conn.SetReadLimit(maxJSONBodyBytes)
+	s.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	s.conn.SetPongHandler(func(string) error {
+		return s.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				slog.Warn("WebSocket connection lost", "error", err)
+			}
+			return
+		}
+		s.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+
+		var frame ClientFrame
+		if err := json.Unmarshal(data, &frame); err != nil {
+			s.sendError(ctx, "", chat.Errorf(chat.ErrInvalidRequest, "invalid frame: %v", err))
+			continue
+		}
+
+		switch frame.Type {
+		case FrameChat:
+			s.startTurn(ctx, &frame)
+		case FrameCancel:
+			s.cancelTurn(frame.ID)
+		case FramePing:
+			s.send(ctx, ServerFrame{Type: FramePong, ID: frame.ID})
+		default:
+			s.sendError(ctx, frame.ID, chat.Errorf(chat.ErrInvalidRequest, "unknown frame type %q", frame.Type))
+		}
+	}
+}
+
+// writeLoop sends queued frames and keepalive pings until ctx is done or a
+// write fails. Closing the connection on failure also ends the reader.
+func (s *wsSession) writeLoop(ctx context.Context) {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case frame := <-s.out:
+			s.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := s.conn.WriteJSON(frame); err != nil {
+				slog.Warn("Failed to write WebSocket frame", "error", err)
+				s.conn.Close()
+				return
+			}
+
+		case <-ticker.C:
+			if err := s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+				s.conn.Close()
+				return
+			}
+
+		case <-ctx.Done():
+			s.conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(wsWriteWait))
+			return
+		}
+	}
+}
+
+// send queues a frame for the writer, waiting while the queue is full
+func (s *wsSession) send(ctx context.Context, frame ServerFrame) error {
+	select {
+	case s.out <- frame:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *wsSession) sendError(ctx context.Context, id string, err error) {
+	s.send(ctx, errorFrame(id, err))
+}
+
+// startTurn runs a chat frame in the background, or rejects it while
+// another turn is still streaming. Turns without an ID are numbered.
+func (s *wsSession) startTurn(ctx context.Context, frame *ClientFrame) {
+	s.mu.Lock()
+	s.turns++
+	if frame.ID == "" {
+		frame.ID = strconv.Itoa(s.turns)
+	}
+	if s.cancel != nil {
+		current := s.turnID
+		s.mu.Unlock()
+		s.sendError(ctx, frame.ID, chat.Errorf(chat.ErrInvalidRequest, "turn %s is still in progress", current))
+		return
+	}
+
+	turnCtx, cancel := context.WithCancel(ctx)
+	s.turnID, s.cancel = frame.ID, cancel
+	s.wg.Add(1)
+	s.mu.Unlock()
+
+	go func() {
+		defer s.wg.Done()
+		closing := s.runTurn(ctx, turnCtx, frame)
+
+		// The turn is over before the client hears so, so that it can
+		// start the next one as soon as it does
+		s.mu.Lock()
+		s.turnID, s.cancel = "", nil
+		s.mu.Unlock()
+		cancel()
+
+		for _, frame := range closing {
+			s.send(ctx, frame)
+		}
+	}()
+}
+
+// cancelTurn stops the current turn if it has the given ID, or whatever
+// turn is running when no ID is given
+func (s *wsSession) cancelTurn(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil && (id == "" || id == s.turnID) {
+		s.cancel()
+	}
+}
+
+// runTurn streams one turn's response, records the answer like
+// ChatHandler does, and returns the frames that end the turn
+func (s *wsSession) runTurn(ctx, turnCtx context.Context, frame *ClientFrame) []ServerFrame {
+	chatReq := frame.ChatRequest
+	chatReq.Streaming = true
+
+	ex, err := startExchange(s.c, s.appCtx, &chatReq)
+	if err != nil {
+		return []ServerFrame{errorFrame(frame.ID, err)}
+	}
+
+	chatRequest, metadata, err := NewChatRequest(s.c.Request().Context(), s.appCtx, &chatReq)
+	if err != nil {
+		return []ServerFrame{errorFrame(frame.ID, err)}
+	}
+
+	var (
+		content        strings.Builder
+		toolCalls      []chat.ToolCall
+		usage          *chat.Usage
+		finishReason   string
+		answerMetadata map[string]string
+		sent           bool
+	)
+	err = s.appCtx.ChatProvider.ChatStream(turnCtx, chatRequest, func(chunk *chat.ChatResponse) error {
+		content.WriteString(chunk.Content)
+		toolCalls = append(toolCalls, chunk.ToolCalls...)
+		if chunk.FinishReason != "" {
+			finishReason = chunk.FinishReason
+		}
+		answerMetadata = mergeMetadata(answerMetadata, chunk.Metadata)
+
+		// Usage goes out in its own frame at the end, so chunks that only
+		// carry usage send no delta
+		if chunk.Usage != nil {
+			usage = chunk.Usage
+			if chunk.Content == "" && len(chunk.Answers) == 0 && len(chunk.ToolCalls) == 0 && len(chunk.Metadata) == 0 {
+				return nil
+			}
+		}
+
+		// The message ID and handler metadata ride on the first frame
+		delta := ServerFrame{
+			Type:      FrameDelta,
+			ID:        frame.ID,
+			Response:  chunk.Content,
+			Answers:   chunk.Answers,
+			ToolCalls: chunk.ToolCalls,
+			Metadata:  mergeMetadata(chunk.Metadata, metadata),
+		}
+		if !sent {
+			delta.MessageID = ex.messageID
+		}
+		metadata, sent = nil, true
+		return s.send(turnCtx, delta)
+	})
+
+	switch {
+	case ctx.Err() != nil:
+		// The connection is gone, so there is no one left to tell
+		return nil
+	case turnCtx.Err() != nil:
+		return []ServerFrame{{Type: FrameDone, ID: frame.ID, Cancelled: true}}
+	case err != nil:
+		slog.Error("Failed to stream chat response", "error", err, "messages_count", len(chatReq.Messages))
+		return []ServerFrame{errorFrame(frame.ID, err)}
+	}
+
+	ex.record(s.appCtx, &chat.ChatResponse{
+		Content:      content.String(),
+		ToolCalls:    toolCalls,
+		Usage:        usage,
+		FinishReason: finishReason,
+		Metadata:     answerMetadata,
+	})
+
+	var closing []ServerFrame
+	if usage != nil {
+		closing = append(closing, ServerFrame{Type: FrameUsage, ID: frame.ID, Usage: usage})
+	}
+	return append(closing, ServerFrame{Type: FrameDone, ID: frame.ID})
+}
+
+func errorFrame(id string, err error) ServerFrame {
+	problem := NewProblemDetails(err)
+	return ServerFrame{Type: FrameError, ID: id, Error: &problem}
+}
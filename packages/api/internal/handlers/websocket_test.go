@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+
+	"chat-backend/internal/app"
+	"chat-backend/internal/chat"
+	"chat-backend/internal/feedback"
+)
+
+// dialWebSocket serves WebSocketHandler and connects a client to it
+func dialWebSocket(t *testing.T, appCtx *app.AppContext) *websocket.Conn {
+	t.Helper()
+
+	e := echo.New()
+	e.GET("/api/ws", WebSocketHandler(appCtx))
+	server := httptest.NewServer(e)
+	t.Cleanup(server.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/api/ws", nil)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func sendFrame(t *testing.T, conn *websocket.Conn, frame any) {
+	t.Helper()
+	if err := conn.WriteJSON(frame); err != nil {
+		t.Fatalf("Failed to send frame: %v", err)
+	}
+}
+
+// readTurn reads frames until the turn ends with a done or error frame
+func readTurn(t *testing.T, conn *websocket.Conn) []ServerFrame {
+	t.Helper()
+
+	var frames []ServerFrame
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		var frame ServerFrame
+		if err := conn.ReadJSON(&frame); err != nil {
+			t.Fatalf("Failed to read frame: %v", err)
+		}
+		frames = append(frames, frame)
+		if frame.Type == FrameDone || frame.Type == FrameError {
+			return frames
+		}
+	}
+}
+
+func chatFrame(id, content string) ClientFrame {
+	return ClientFrame{
+		Type:        FrameChat,
+		ID:          id,
+		ChatRequest: ChatRequest{Messages: []Message{{Role: "user", Content: content}}},
+	}
+}
+
+func TestWebSocketHandler_MultipleTurns(t *testing.T) {
+	appCtx, provider := newScriptedAppContext(t, `
+rules:
+  - match: "story"
+    chunks: ["Once upon ", "a time"]
+default:
+  response: "Hello!"
+`)
+	conn := dialWebSocket(t, appCtx)
+
+	sendFrame(t, conn, chatFrame("first", "Tell me a story"))
+	frames := readTurn(t, conn)
+	if len(frames) != 3 || frames[0].Response != "Once upon " || frames[1].Response != "a time" {
+		t.Fatalf("Expected two deltas and done, got %+v", frames)
+	}
+	for _, frame := range frames {
+		if frame.ID != "first" {
+			t.Errorf("Expected every frame to carry the turn ID, got '%s'", frame.ID)
+		}
+	}
+	if frames[2].Type != FrameDone || frames[2].Cancelled {
+		t.Errorf("Expected the turn to finish, got %+v", frames[2])
+	}
+
+	sendFrame(t, conn, chatFrame("", "Hi"))
+	frames = readTurn(t, conn)
+	if frames[0].Type != FrameDelta || frames[0].Response != "Hello!" || frames[0].ID != "2" {
+		t.Errorf("Expected the second turn to be answered and numbered, got %+v", frames)
+	}
+
+	requests := provider.Requests()
+	if len(requests) != 2 || !requests[0].Streaming {
+		t.Errorf("Expected two streamed requests, got %+v", requests)
+	}
+}
+
+func TestWebSocketHandler_ContinuesConversation(t *testing.T) {
+	appCtx, provider := newScriptedAppContext(t, `
+rules:
+  - match: "capital of France"
+    response: "Paris."
+  - match: "population"
+    chunks: ["About ", "2 million."]
+`)
+	conversation := appCtx.Conversations.Create("")
+	conn := dialWebSocket(t, appCtx)
+
+	frame := chatFrame("first", "What is the capital of France?")
+	frame.ConversationID = conversation.ID
+	sendFrame(t, conn, frame)
+	frames := readTurn(t, conn)
+	if frames[0].Type != FrameDelta || frames[0].MessageID == "" {
+		t.Fatalf("Expected the first delta to carry the message ID, got %+v", frames)
+	}
+	if _, err := appCtx.Feedback.Submit("", frames[0].MessageID, feedback.Feedback{Rating: feedback.RatingUp}); err != nil {
+		t.Errorf("Expected feedback on the answer to be accepted, got: %v", err)
+	}
+
+	frame = chatFrame("second", "And its population?")
+	frame.ConversationID = conversation.ID
+	sendFrame(t, conn, frame)
+	frames = readTurn(t, conn)
+	if len(frames) != 3 || frames[0].MessageID == "" || frames[1].MessageID != "" {
+		t.Fatalf("Expected only the first delta to carry the message ID, got %+v", frames)
+	}
+
+	requests := provider.Requests()
+	if len(requests) != 2 || len(requests[1].Messages) != 3 || requests[1].Messages[1].Content != "Paris." {
+		t.Fatalf("Expected the follow-up to carry the history, got %+v", requests)
+	}
+
+	conversation, err := appCtx.Conversations.Get("", conversation.ID)
+	if err != nil || len(conversation.Messages) != 4 {
+		t.Fatalf("Expected both turns to be recorded, got %+v, %v", conversation.Messages, err)
+	}
+	if answer := conversation.Messages[3]; answer.Content != "About 2 million." || answer.ID != frames[0].MessageID {
+		t.Errorf("Expected the streamed answer under its message ID, got %+v", answer)
+	}
+
+	frame = chatFrame("third", "Hi")
+	frame.ConversationID = "missing"
+	sendFrame(t, conn, frame)
+	frames = readTurn(t, conn)
+	if frames[0].Type != FrameError || frames[0].Error.Status != http.StatusNotFound {
+		t.Errorf("Expected an unknown conversation to be rejected, got %+v", frames)
+	}
+}
+
+func TestWebSocketHandler_Cancel(t *testing.T) {
+	appCtx, _ := newScriptedAppContext(t, `
+rules:
+  - match: "slow"
+    latency: 10s
+    response: "Too late"
+default:
+  response: "Hello!"
+`)
+	conn := dialWebSocket(t, appCtx)
+
+	sendFrame(t, conn, chatFrame("slow", "Be slow"))
+	sendFrame(t, conn, chatFrame("eager", "Hi"))
+	frames := readTurn(t, conn)
+	if frames[0].Type != FrameError || frames[0].ID != "eager" || frames[0].Error.Status != http.StatusBadRequest {
+		t.Errorf("Expected a second turn to be rejected while one runs, got %+v", frames)
+	}
+
+	sendFrame(t, conn, ClientFrame{Type: FrameCancel, ID: "slow"})
+	frames = readTurn(t, conn)
+	if last := frames[len(frames)-1]; last.Type != FrameDone || !last.Cancelled || last.ID != "slow" {
+		t.Errorf("Expected the turn to end cancelled, got %+v", frames)
+	}
+
+	sendFrame(t, conn, chatFrame("next", "Hi"))
+	if frames := readTurn(t, conn); frames[0].Response != "Hello!" {
+		t.Errorf("Expected the connection to carry on after a cancel, got %+v", frames)
+	}
+}
+
+func TestWebSocketHandler_Errors(t *testing.T) {
+	appCtx, _ := newScriptedAppContext(t, `
+rules:
+  - match: "broken"
+    chunks: ["Partial"]
+    error: unavailable
+default:
+  response: "Hello!"
+`)
+	conn := dialWebSocket(t, appCtx)
+
+	sendFrame(t, conn, ClientFrame{Type: FrameChat, ID: "invalid", ChatRequest: ChatRequest{
+		Messages: []Message{{Role: "bogus", Content: "Hi"}},
+	}})
+	frames := readTurn(t, conn)
+	if frames[0].Error == nil || frames[0].Error.Type != "urn:chat-backend:problem:validation-failed" {
+		t.Errorf("Expected the chat endpoint's validation, got %+v", frames)
+	}
+
+	sendFrame(t, conn, chatFrame("broken", "This is broken"))
+	frames = readTurn(t, conn)
+	last := frames[len(frames)-1]
+	if frames[0].Response != "Partial" || last.Error == nil || last.Error.Status != http.StatusServiceUnavailable {
+		t.Errorf("Expected the partial answer then the provider failure, got %+v", frames)
+	}
+
+	sendFrame(t, conn, map[string]string{"type": "shout"})
+	if frames := readTurn(t, conn); frames[0].Error == nil || frames[0].Error.Status != http.StatusBadRequest {
+		t.Errorf("Expected an unknown frame to be rejected, got %+v", frames)
+	}
+}
+
+func TestWebSocketHandler_PingAndUsage(t *testing.T) {
+	appCtx := app.NewAppContext(&mockChatProvider{response: &chat.ChatResponse{
+		Content: "Hello!",
+		Usage:   &chat.Usage{PromptTokens: 3, CompletionTokens: 2, TotalTokens: 5},
+	}})
+	conn := dialWebSocket(t, appCtx)
+
+	sendFrame(t, conn, ClientFrame{Type: FramePing, ID: "keepalive"})
+	var pong ServerFrame
+	if err := conn.ReadJSON(&pong); err != nil || pong.Type != FramePong || pong.ID != "keepalive" {
+		t.Errorf("Expected a pong, got %+v, %v", pong, err)
+	}
+
+	sendFrame(t, conn, chatFrame("", "Hi"))
+	frames := readTurn(t, conn)
+	if len(frames) != 3 || frames[1].Type != FrameUsage || frames[1].Usage.TotalTokens != 5 {
+		t.Errorf("Expected a usage frame before done, got %+v", frames)
+	}
+}
+
+func TestWebSocketHandler_ServerPings(t *testing.T) {
+	previous := wsPingPeriod
+	wsPingPeriod = 10 * time.Millisecond
+	t.Cleanup(func() { wsPingPeriod = previous })
+
+	conn := dialWebSocket(t, app.NewAppContext(&mockChatProvider{}))
+
+	pinged := make(chan struct{}, 1)
+	conn.SetPingHandler(func(string) error {
+		select {
+		case pinged <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+	go conn.ReadMessage()
+
+	select {
+	case <-pinged:
+	case <-time.After(2 * time.Second):
+		t.Error("Expected the server to ping")
+	}
+}
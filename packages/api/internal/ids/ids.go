@@ -0,0 +1,18 @@
+// Package ids generates the random IDs of jobs, generations, conversations
+// and answers.
+package ids
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// New returns a random 128-bit ID as 32 hex characters. IDs are
+// unguessable, so they can double as a capability for whatever they name.
+func New() string {
+	b := make([]byte, 16)
+	// Since Go 1.24, rand.Read never returns an error; it crashes the
+	// program instead when the system has no randomness to give
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,16 @@
+package ids
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	id := New()
+	if b, err := hex.DecodeString(id); err != nil || len(b) != 16 {
+		t.Errorf("Expected 16 random bytes in hex, got %q", id)
+	}
+	if New() == id {
+		t.Error("Expected a new ID on every call")
+	}
+}
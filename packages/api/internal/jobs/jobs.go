@@ -0,0 +1,375 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"chat-backend/internal/chat"
+	"chat-backend/internal/ids"
+	"chat-backend/internal/tenant"
+)
+
+type Status string
+
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+)
+
+// Done reports whether a job in this status has finished for good
+func (s Status) Done() bool {
+	return s == StatusSucceeded || s == StatusFailed || s == StatusCancelled
+}
+
+var (
+	ErrNotFound = errors.New("job not found")
+	ErrFinished = errors.New("job already finished")
+	// ErrQueueFull matches chat.ErrOverloaded, so it is reported as a 503
+	ErrQueueFull = fmt.Errorf("job queue is full: %w", chat.ErrOverloaded)
+)
+
+const (
+	defaultWorkers     = 4
+	defaultQueueLength = 100
+	defaultRetention   = 24 * time.Hour
+)
+
+// Failure describes why a job failed, in the same terms as the problem
+// details a synchronous request would have been answered with
+type Failure struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Job is an asynchronous chat request and, once it has finished, its
+// result or failure
+type Job struct {
+	ID         string             `json:"id"`
+	Status     Status             `json:"status"`
+	TenantID   string             `json:"tenant_id,omitempty"`
+	WebhookURL string             `json:"webhook_url,omitempty"`
+	Result     *chat.ChatResponse `json:"result,omitempty"`
+	Error      *Failure           `json:"error,omitempty"`
+	// WebhookStatus is "pending" until the callback for a finished job has
+	// been "delivered", or has "failed" after every attempt
+	WebhookStatus string     `json:"webhook_status,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	StartedAt     *time.Time `json:"started_at,omitempty"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+}
+
+// Config controls the worker pool. Finished jobs are deleted after
+// Retention. Webhooks are only sent when WebhookSecret is set, since every
+// callback is signed with it.
+type Config struct {
+	Workers     int
+	QueueLength int
+	Retention   time.Duration
+	// Store defaults to an in-memory store
+	Store          Store
+	WebhookSecret  string
+	WebhookTimeout time.Duration
+	// WebhookAttempts is how often a callback is tried before giving up
+	WebhookAttempts int
+	// AllowPrivateWebhooks lets callbacks go to loopback, link-local and
+	// private addresses, for receivers on the same network
+	AllowPrivateWebhooks bool
+}
+
+// Options are the per-job settings given on submission. Metadata is added
+// to the job's result, as the handler does for synchronous requests.
+type Options struct {
+	WebhookURL string
+	Metadata   map[string]string
+}
+
+// pending is a queued job with everything needed to run it. It lives in
+// memory only, so jobs that are still queued do not survive a restart.
+type pending struct {
+	id       string
+	ctx      context.Context
+	req      *chat.ChatRequest
+	metadata map[string]string
+}
+
+// Manager runs chat jobs on a fixed pool of workers, keeping their state in
+// the configured store
+type Manager struct {
+	provider chat.ChatProvider
+	config   Config
+	queue    chan *pending
+	client   *http.Client
+	describe func(error) Failure
+	// start launches the workers with the first job
+	start sync.Once
+
+	// mu serializes status changes, so a job cancelled while it runs is
+	// never reported as succeeded as well
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+func NewManager(provider chat.ChatProvider, config Config) *Manager {
+	if config.Workers <= 0 {
+		config.Workers = defaultWorkers
+	}
+	if config.QueueLength <= 0 {
+		config.QueueLength = defaultQueueLength
+	}
+	if config.Retention <= 0 {
+		config.Retention = defaultRetention
+	}
+	if config.Store == nil {
+		config.Store = NewMemoryStore()
+	}
+	if config.WebhookTimeout <= 0 {
+		config.WebhookTimeout = defaultWebhookTimeout
+	}
+	if config.WebhookAttempts <= 0 {
+		config.WebhookAttempts = defaultWebhookAttempts
+	}
+
+	m := &Manager{
+		provider: provider,
+		config:   config,
+		queue:    make(chan *pending, config.QueueLength),
+		client:   newWebhookClient(config.WebhookTimeout, config.AllowPrivateWebhooks),
+		describe: describe,
+		cancels:  map[string]context.CancelFunc{},
+	}
+	m.recover()
+	return m
+}
+
+// WithDescriber sets how errors are turned into the failures reported to
+// clients. It must be called before any job is submitted.
+func (m *Manager) WithDescriber(describe func(error) Failure) *Manager {
+	m.describe = describe
+	return m
+}
+
+// WebhooksEnabled reports whether jobs may ask for a callback
+func (m *Manager) WebhooksEnabled() bool {
+	return m.config.WebhookSecret != ""
+}
+
+// Submit queues a chat request. The job keeps the values of ctx, such as
+// the tenant, but not its cancellation, so it outlives the HTTP request.
+func (m *Manager) Submit(ctx context.Context, req *chat.ChatRequest, opts Options) (Job, error) {
+	job := Job{
+		ID:         ids.New(),
+		Status:     StatusQueued,
+		TenantID:   tenant.FromContext(ctx).ID,
+		WebhookURL: opts.WebhookURL,
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	m.start.Do(func() {
+		for i := 0; i < m.config.Workers; i++ {
+			go m.work()
+		}
+	})
+
+	// Jobs always get the whole answer at once
+	copied := *req
+	copied.Streaming = false
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.config.Store.Put(job); err != nil {
+		cancel()
+		return Job{}, err
+	}
+
+	select {
+	case m.queue <- &pending{id: job.ID, ctx: runCtx, req: &copied, metadata: opts.Metadata}:
+	default:
+		cancel()
+		m.config.Store.Delete(job.ID)
+		return Job{}, ErrQueueFull
+	}
+
+	m.cancels[job.ID] = cancel
+	return job, nil
+}
+
+func (m *Manager) Get(id string) (Job, error) {
+	return m.config.Store.Get(id)
+}
+
+// Cancel stops a queued or running job. Cancelling a finished job is an
+// error wrapping ErrFinished.
+func (m *Manager) Cancel(id string) (Job, error) {
+	m.mu.Lock()
+	job, err := m.config.Store.Get(id)
+	if err != nil {
+		m.mu.Unlock()
+		return Job{}, err
+	}
+	if job.Status.Done() {
+		m.mu.Unlock()
+		return job, fmt.Errorf("%w: %s", ErrFinished, job.Status)
+	}
+
+	if cancel, ok := m.cancels[id]; ok {
+		cancel()
+		delete(m.cancels, id)
+	}
+	job, err = m.finishLocked(job, func(job *Job) {
+		job.Status = StatusCancelled
+	})
+	m.mu.Unlock()
+
+	if err != nil {
+		return Job{}, err
+	}
+	m.notify(job)
+	return job, nil
+}
+
+func (m *Manager) work() {
+	for p := range m.queue {
+		m.run(p)
+	}
+}
+
+func (m *Manager) run(p *pending) {
+	m.mu.Lock()
+	job, err := m.config.Store.Get(p.id)
+	if err != nil || job.Status != StatusQueued {
+		// Cancelled while it waited in the queue
+		m.mu.Unlock()
+		return
+	}
+	started := time.Now().UTC()
+	job.Status = StatusRunning
+	job.StartedAt = &started
+	if err := m.config.Store.Put(job); err != nil {
+		slog.Error("Failed to start chat job", "job", p.id, "error", err)
+	}
+	m.mu.Unlock()
+
+	resp, err := m.provider.Chat(p.ctx, p.req)
+
+	m.mu.Lock()
+	if cancel, ok := m.cancels[p.id]; ok {
+		cancel()
+		delete(m.cancels, p.id)
+	}
+	job, getErr := m.config.Store.Get(p.id)
+	if getErr != nil || job.Status.Done() {
+		// Cancelled while it ran, which has already been reported
+		m.mu.Unlock()
+		return
+	}
+	job, finishErr := m.finishLocked(job, func(job *Job) {
+		if err != nil {
+			slog.Error("Chat job failed", "job", job.ID, "error", err)
+			failure := m.describe(err)
+			job.Status = StatusFailed
+			job.Error = &failure
+			return
+		}
+		for key, value := range p.metadata {
+			if resp.Metadata == nil {
+				resp.Metadata = make(map[string]string, len(p.metadata))
+			}
+			resp.Metadata[key] = value
+		}
+		job.Status = StatusSucceeded
+		job.Result = resp
+	})
+	m.mu.Unlock()
+
+	if finishErr != nil {
+		slog.Error("Failed to record chat job result", "job", p.id, "error", finishErr)
+		return
+	}
+	m.notify(job)
+}
+
+// finishLocked moves a job to its final status and schedules its removal.
+// Callers hold the lock.
+func (m *Manager) finishLocked(job Job, update func(*Job)) (Job, error) {
+	finished := time.Now().UTC()
+	update(&job)
+	job.FinishedAt = &finished
+	if job.WebhookURL != "" && m.WebhooksEnabled() {
+		job.WebhookStatus = webhookPending
+	}
+
+	if err := m.config.Store.Put(job); err != nil {
+		return Job{}, err
+	}
+
+	m.expire(job)
+	return job, nil
+}
+
+// expire removes a finished job once its retention period is over, right
+// away when it already is
+func (m *Manager) expire(job Job) {
+	wait := m.config.Retention
+	if job.FinishedAt != nil {
+		wait -= time.Since(*job.FinishedAt)
+	}
+	if wait <= 0 {
+		m.config.Store.Delete(job.ID)
+		return
+	}
+	time.AfterFunc(wait, func() {
+		m.config.Store.Delete(job.ID)
+	})
+}
+
+// recover fails jobs that a previous run of the server left unfinished in
+// a persistent store, since their requests were lost with it, and schedules
+// the removal of the finished ones
+func (m *Manager) recover() {
+	jobs, err := m.config.Store.List()
+	if err != nil {
+		slog.Error("Failed to list stored chat jobs", "error", err)
+		return
+	}
+
+	for _, job := range jobs {
+		if job.Status.Done() {
+			m.expire(job)
+			continue
+		}
+		m.finishLocked(job, func(job *Job) {
+			job.Status = StatusFailed
+			job.Error = &Failure{
+				Type:   "about:blank",
+				Title:  http.StatusText(http.StatusInternalServerError),
+				Status: http.StatusInternalServerError,
+				Detail: "The job was interrupted by a server restart",
+			}
+			// Nobody is left to deliver the callback
+			job.WebhookURL = ""
+		})
+	}
+}
+
+// describe is the fallback for managers without a describer, which never
+// exposes the error text
+func describe(err error) Failure {
+	return Failure{
+		Type:   "about:blank",
+		Title:  http.StatusText(http.StatusInternalServerError),
+		Status: http.StatusInternalServerError,
+		Detail: "The chat job failed",
+	}
+}
@@ -0,0 +1,323 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"chat-backend/internal/chat"
+	"chat-backend/internal/tenant"
+)
+
+// stubProvider answers every request with the same response or error,
+// optionally holding it until release is closed
+type stubProvider struct {
+	resp    *chat.ChatResponse
+	err     error
+	release chan struct{}
+	// cancelled is signalled when a held request is cancelled
+	cancelled chan struct{}
+}
+
+func (p *stubProvider) Chat(ctx context.Context, req *chat.ChatRequest) (*chat.ChatResponse, error) {
+	if p.release != nil {
+		select {
+		case <-p.release:
+		case <-ctx.Done():
+			p.cancelled <- struct{}{}
+			return nil, ctx.Err()
+		}
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	copied := *p.resp
+	return &copied, nil
+}
+
+func (p *stubProvider) ChatStream(ctx context.Context, req *chat.ChatRequest, callback chat.StreamCallback) error {
+	return errors.New("jobs never stream")
+}
+
+func heldProvider() *stubProvider {
+	return &stubProvider{
+		resp:      &chat.ChatResponse{Content: "done"},
+		release:   make(chan struct{}),
+		cancelled: make(chan struct{}, 1),
+	}
+}
+
+func question() *chat.ChatRequest {
+	return &chat.ChatRequest{Messages: []chat.Message{{Role: "user", Content: "Summarize this"}}, Streaming: true}
+}
+
+// waitForJob polls the manager until the job satisfies cond
+func waitForJob(t *testing.T, m *Manager, id string, cond func(Job) bool) Job {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := m.Get(id)
+		if err == nil && cond(job) {
+			return job
+		}
+		time.Sleep(time.Millisecond)
+	}
+	job, _ := m.Get(id)
+	t.Fatalf("Timed out waiting for job %s, last: %+v", id, job)
+	return Job{}
+}
+
+func hasStatus(status Status) func(Job) bool {
+	return func(job Job) bool { return job.Status == status }
+}
+
+func TestManager_RunsJob(t *testing.T) {
+	m := NewManager(&stubProvider{resp: &chat.ChatResponse{Content: "A summary"}}, Config{})
+
+	ctx := tenant.WithTenant(context.Background(), tenant.Tenant{ID: "acme"})
+	job, err := m.Submit(ctx, question(), Options{Metadata: map[string]string{"prompt_template": "support"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if job.Status != StatusQueued || job.ID == "" || job.TenantID != "acme" {
+		t.Errorf("Unexpected submitted job: %+v", job)
+	}
+
+	job = waitForJob(t, m, job.ID, hasStatus(StatusSucceeded))
+	if job.Result == nil || job.Result.Content != "A summary" {
+		t.Fatalf("Expected the answer as the result, got %+v", job.Result)
+	}
+	if job.Result.Metadata["prompt_template"] != "support" {
+		t.Errorf("Expected the job metadata on the result, got %v", job.Result.Metadata)
+	}
+	if job.StartedAt == nil || job.FinishedAt == nil {
+		t.Errorf("Expected start and finish times, got %+v", job)
+	}
+}
+
+func TestManager_Failure(t *testing.T) {
+	m := NewManager(&stubProvider{err: chat.ErrRateLimited}, Config{})
+	m.WithDescriber(func(err error) Failure {
+		return Failure{Type: "rate-limited", Status: http.StatusTooManyRequests}
+	})
+
+	job, _ := m.Submit(context.Background(), question(), Options{})
+
+	job = waitForJob(t, m, job.ID, hasStatus(StatusFailed))
+	if job.Error == nil || job.Error.Status != http.StatusTooManyRequests || job.Result != nil {
+		t.Errorf("Expected the described failure, got %+v", job)
+	}
+}
+
+func TestManager_Cancel(t *testing.T) {
+	provider := heldProvider()
+	m := NewManager(provider, Config{Workers: 1})
+
+	running, _ := m.Submit(context.Background(), question(), Options{})
+	waitForJob(t, m, running.ID, hasStatus(StatusRunning))
+	queued, _ := m.Submit(context.Background(), question(), Options{})
+
+	// A queued job is never run
+	if job, err := m.Cancel(queued.ID); err != nil || job.Status != StatusCancelled {
+		t.Fatalf("Expected the queued job to be cancelled, got %+v, %v", job, err)
+	}
+
+	// A running job has its provider call cancelled
+	if job, err := m.Cancel(running.ID); err != nil || job.Status != StatusCancelled {
+		t.Fatalf("Expected the running job to be cancelled, got %+v, %v", job, err)
+	}
+	select {
+	case <-provider.cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the provider call to be cancelled")
+	}
+
+	if _, err := m.Cancel(running.ID); !errors.Is(err, ErrFinished) {
+		t.Errorf("Expected cancelling a finished job to fail, got: %v", err)
+	}
+
+	// Neither job is reported as anything but cancelled afterwards
+	time.Sleep(10 * time.Millisecond)
+	for _, id := range []string{running.ID, queued.ID} {
+		if job, _ := m.Get(id); job.Status != StatusCancelled {
+			t.Errorf("Expected job %s to stay cancelled, got %s", id, job.Status)
+		}
+	}
+}
+
+func TestManager_QueueFull(t *testing.T) {
+	provider := heldProvider()
+	defer close(provider.release)
+	m := NewManager(provider, Config{Workers: 1, QueueLength: 1})
+
+	running, _ := m.Submit(context.Background(), question(), Options{})
+	waitForJob(t, m, running.ID, hasStatus(StatusRunning))
+	if _, err := m.Submit(context.Background(), question(), Options{}); err != nil {
+		t.Fatalf("Expected the second job to queue, got: %v", err)
+	}
+
+	_, err := m.Submit(context.Background(), question(), Options{})
+	if !errors.Is(err, ErrQueueFull) || !errors.Is(err, chat.ErrOverloaded) {
+		t.Errorf("Expected a full queue, got: %v", err)
+	}
+}
+
+func TestManager_Retention(t *testing.T) {
+	m := NewManager(&stubProvider{resp: &chat.ChatResponse{Content: "done"}}, Config{Retention: 10 * time.Millisecond})
+
+	job, _ := m.Submit(context.Background(), question(), Options{})
+	waitForJob(t, m, job.ID, hasStatus(StatusSucceeded))
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if _, err := m.Get(job.ID); errors.Is(err, ErrNotFound) {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Error("Expected the finished job to be removed after its retention period")
+}
+
+func TestManager_Webhook(t *testing.T) {
+	var attempts atomic.Int32
+	verified := make(chan bool, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail the first attempt to exercise retries
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+		verified <- r.Header.Get(HeaderSignature) == Sign("s3cret", timestamp, body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	backoff := webhookBackoff
+	webhookBackoff = time.Millisecond
+	defer func() { webhookBackoff = backoff }()
+
+	m := NewManager(&stubProvider{resp: &chat.ChatResponse{Content: "done"}}, Config{WebhookSecret: "s3cret", AllowPrivateWebhooks: true})
+	job, _ := m.Submit(context.Background(), question(), Options{WebhookURL: server.URL})
+
+	waitForJob(t, m, job.ID, func(job Job) bool { return job.WebhookStatus == webhookDelivered })
+	if !<-verified {
+		t.Error("Expected the callback to carry a valid signature")
+	}
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("Expected 2 delivery attempts, got %d", got)
+	}
+}
+
+func TestManager_WebhookGivesUpOnClientError(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer server.Close()
+
+	m := NewManager(&stubProvider{resp: &chat.ChatResponse{Content: "done"}}, Config{WebhookSecret: "s3cret", AllowPrivateWebhooks: true})
+	job, _ := m.Submit(context.Background(), question(), Options{WebhookURL: server.URL})
+
+	waitForJob(t, m, job.ID, func(job Job) bool { return job.WebhookStatus == webhookFailed })
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("Expected no retries after a client error, got %d attempts", got)
+	}
+}
+
+func TestManager_WebhookRefusesPrivateAddresses(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+	}))
+	defer server.Close()
+
+	m := NewManager(&stubProvider{resp: &chat.ChatResponse{Content: "done"}}, Config{WebhookSecret: "s3cret"})
+	job, _ := m.Submit(context.Background(), question(), Options{WebhookURL: server.URL})
+
+	waitForJob(t, m, job.ID, func(job Job) bool { return job.WebhookStatus == webhookFailed })
+	if got := attempts.Load(); got != 0 {
+		t.Errorf("Expected no callback to reach a loopback address, got %d", got)
+	}
+}
+
+func TestDirStore(t *testing.T) {
+	store, err := NewDirStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+
+	if err := store.Put(Job{ID: "abc", Status: StatusSucceeded, Result: &chat.ChatResponse{Content: "kept"}}); err != nil {
+		t.Fatalf("Failed to put job: %v", err)
+	}
+
+	job, err := store.Get("abc")
+	if err != nil || job.Result == nil || job.Result.Content != "kept" {
+		t.Errorf("Expected the stored job back, got %+v, %v", job, err)
+	}
+
+	for _, id := range []string{"missing", "../abc", ""} {
+		if _, err := store.Get(id); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected %q not to be found, got: %v", id, err)
+		}
+	}
+
+	store.Delete("abc")
+	if jobs, _ := store.List(); len(jobs) != 0 {
+		t.Errorf("Expected the job to be deleted, got %+v", jobs)
+	}
+}
+
+func TestNewManager_FailsInterruptedJobs(t *testing.T) {
+	store, _ := NewDirStore(t.TempDir())
+	store.Put(Job{ID: "interrupted", Status: StatusRunning, WebhookURL: "http://example.com/hook"})
+	store.Put(Job{ID: "finished", Status: StatusSucceeded})
+
+	m := NewManager(&stubProvider{}, Config{Store: store, WebhookSecret: "s3cret"})
+
+	job, _ := m.Get("interrupted")
+	if job.Status != StatusFailed || job.Error == nil || job.WebhookStatus != "" {
+		t.Errorf("Expected the interrupted job to be failed without a callback, got %+v", job)
+	}
+	if job, _ := m.Get("finished"); job.Status != StatusSucceeded {
+		t.Errorf("Expected finished jobs to be left alone, got %+v", job)
+	}
+}
+
+func TestNewManager_ExpiresFinishedJobs(t *testing.T) {
+	store, _ := NewDirStore(t.TempDir())
+	old := time.Now().Add(-time.Hour)
+	recent := time.Now()
+	store.Put(Job{ID: "old", Status: StatusSucceeded, FinishedAt: &old})
+	store.Put(Job{ID: "recent", Status: StatusFailed, FinishedAt: &recent})
+
+	m := NewManager(&stubProvider{}, Config{Store: store, Retention: 50 * time.Millisecond})
+
+	if _, err := m.Get("old"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected the expired job to be deleted on start, got: %v", err)
+	}
+	if _, err := m.Get("recent"); err != nil {
+		t.Fatalf("Expected the recent job to be kept until it expires, got: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, err := store.Get("recent"); errors.Is(err, ErrNotFound) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the recent job to be deleted after the retention period")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,161 @@
+package jobs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Store keeps jobs by ID. Get returns an error wrapping ErrNotFound for
+// unknown jobs.
+type Store interface {
+	Put(job Job) error
+	Get(id string) (Job, error)
+	Delete(id string) error
+	List() ([]Job, error)
+}
+
+// MemoryStore keeps jobs in memory, so they are lost on restart
+type MemoryStore struct {
+	mu   sync.RWMutex
+	jobs map[string]Job
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{jobs: map[string]Job{}}
+}
+
+func (s *MemoryStore) Put(job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobs[job.ID] = job
+	return nil
+}
+
+func (s *MemoryStore) Get(id string) (Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return Job{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return job, nil
+}
+
+func (s *MemoryStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.jobs, id)
+	return nil
+}
+
+func (s *MemoryStore) List() ([]Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	jobs := make([]Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// DirStore keeps each job as a JSON file in a directory, so job results
+// survive a restart
+type DirStore struct {
+	dir string
+}
+
+func NewDirStore(dir string) (*DirStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create jobs directory: %w", err)
+	}
+	return &DirStore{dir: dir}, nil
+}
+
+func (s *DirStore) path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+func (s *DirStore) Put(job Job) error {
+	data, err := json.Marshal(&job)
+	if err != nil {
+		return fmt.Errorf("failed to encode job: %w", err)
+	}
+
+	// Write to a temporary file first so readers never see a torn job
+	tmp, err := os.CreateTemp(s.dir, ".job-*")
+	if err != nil {
+		return fmt.Errorf("failed to save job: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save job: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save job: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path(job.ID)); err != nil {
+		return fmt.Errorf("failed to save job: %w", err)
+	}
+	return nil
+}
+
+func (s *DirStore) Get(id string) (Job, error) {
+	// IDs come from URLs, so refuse anything that could leave the directory
+	if id == "" || strings.ContainsAny(id, `/\.`) {
+		return Job{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+
+	data, err := os.ReadFile(s.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return Job{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	if err != nil {
+		return Job{}, fmt.Errorf("failed to read job: %w", err)
+	}
+
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return Job{}, fmt.Errorf("failed to parse job %s: %w", id, err)
+	}
+	return job, nil
+}
+
+func (s *DirStore) Delete(id string) error {
+	err := os.Remove(s.path(id))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete job: %w", err)
+	}
+	return nil
+}
+
+func (s *DirStore) List() ([]Job, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+
+	var jobs []Job
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		job, err := s.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
@@ -0,0 +1,189 @@
+package jobs
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Headers of a webhook callback. The signature covers the timestamp and the
+// body, so receivers can reject replays of old callbacks.
+const (
+	HeaderSignature = "X-Webhook-Signature"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+)
+
+// Values of Job.WebhookStatus
+const (
+	webhookPending   = "pending"
+	webhookDelivered = "delivered"
+	webhookFailed    = "failed"
+)
+
+const (
+	defaultWebhookTimeout  = 10 * time.Second
+	defaultWebhookAttempts = 3
+)
+
+// ErrPrivateAddress is returned for webhooks to loopback, link-local or
+// private addresses, which would let clients probe the server's network
+var ErrPrivateAddress = errors.New("webhook address is not public")
+
+// sharedAddressSpace is the carrier-grade NAT range, which some clouds use
+// for their metadata services
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// webhookBackoff is the wait before the second attempt, doubling after that
+var webhookBackoff = 2 * time.Second
+
+// Sign computes the signature header value for a callback body sent at the
+// given unix timestamp: "sha256=" followed by the hex HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with the webhook secret
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// publicAddress reports whether ip may receive webhooks
+func publicAddress(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	return ip.IsGlobalUnicast() && !ip.IsPrivate() && !sharedAddressSpace.Contains(ip)
+}
+
+// refusePrivate is a dialer control that refuses connections to addresses
+// that are not public. It runs on the resolved address, so host names that
+// resolve to internal addresses are caught as well.
+func refusePrivate(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !publicAddress(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, addrPort.Addr())
+	}
+	return nil
+}
+
+// newWebhookClient returns the client callbacks are sent with. Unless
+// private addresses are allowed, it only connects to public ones and
+// ignores proxy settings, since a proxy would connect on its behalf.
+func newWebhookClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !allowPrivate {
+		dialer := &net.Dialer{Timeout: timeout, Control: refusePrivate}
+		transport.DialContext = dialer.DialContext
+		transport.Proxy = nil
+	}
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// CheckWebhookHost refuses webhook hosts that are known to be private
+// without resolving them: localhost and literal addresses. Other names are
+// checked when the callback is sent.
+func (m *Manager) CheckWebhookHost(host string) error {
+	if m.config.AllowPrivateWebhooks {
+		return nil
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrPrivateAddress
+	}
+	if ip, err := netip.ParseAddr(host); err == nil && !publicAddress(ip) {
+		return ErrPrivateAddress
+	}
+	return nil
+}
+
+// notify delivers the callback for a finished job in the background
+func (m *Manager) notify(job Job) {
+	if job.WebhookStatus != webhookPending {
+		return
+	}
+	go m.deliver(job)
+}
+
+// deliver posts the finished job to its webhook, retrying failures that
+// may be temporary, and records the outcome on the job
+func (m *Manager) deliver(job Job) {
+	body, err := json.Marshal(&job)
+	if err != nil {
+		slog.Error("Failed to encode job for webhook", "job", job.ID, "error", err)
+		m.recordDelivery(job.ID, webhookFailed)
+		return
+	}
+
+	status := webhookFailed
+	backoff := webhookBackoff
+	for attempt := 1; attempt <= m.config.WebhookAttempts; attempt++ {
+		retry, err := m.post(job.WebhookURL, body)
+		if err == nil {
+			status = webhookDelivered
+			break
+		}
+
+		slog.Warn("Failed to deliver job webhook", "job", job.ID, "attempt", attempt, "error", err)
+		if !retry || attempt == m.config.WebhookAttempts {
+			break
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+
+	m.recordDelivery(job.ID, status)
+}
+
+// post sends one callback. retry reports whether a failure may succeed on
+// another attempt.
+func (m *Manager) post(url string, body []byte) (retry bool, err error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(m.config.WebhookSecret, timestamp, body))
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return !errors.Is(err, ErrPrivateAddress), err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	// Other client errors will not go away by sending the same callback
+	retry = resp.StatusCode >= 500 || resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+}
+
+func (m *Manager) recordDelivery(id, status string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, err := m.config.Store.Get(id)
+	if err != nil {
+		// Already removed after its retention period
+		return
+	}
+	job.WebhookStatus = status
+	if err := m.config.Store.Put(job); err != nil {
+		slog.Error("Failed to record job webhook delivery", "job", id, "error", err)
+	}
+}
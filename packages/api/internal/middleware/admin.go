@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AdminAuth protects management endpoints with a shared bearer token. When
+// no key is configured the endpoints refuse every request, unless open is
+// set, which is only meant for local development.
+func AdminAuth(key string, open bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if key == "" {
+				if open {
+					return next(c)
+				}
+				return echo.NewHTTPError(http.StatusForbidden, "Admin endpoints are disabled until ADMIN_API_KEY is set")
+			}
+
+			token, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(key)) != 1 {
+				return echo.NewHTTPError(http.StatusUnauthorized, "A valid admin API key is required")
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"chat-backend/internal/tenant"
+)
+
+// Tenant resolves the X-Tenant-ID header against the registry and stores
+// the tenant in the request context
+func Tenant(registry *tenant.Registry) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			t := registry.Lookup(req.Header.Get(tenant.Header))
+			c.SetRequest(req.WithContext(tenant.WithTenant(req.Context(), t)))
+
+			return next(c)
+		}
+	}
+}
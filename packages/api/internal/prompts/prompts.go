@@ -0,0 +1,245 @@
+package prompts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+var (
+	ErrNotFound        = errors.New("prompt template not found")
+	ErrExists          = errors.New("prompt template already exists")
+	ErrInvalidTemplate = errors.New("invalid prompt template")
+)
+
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// Template is one version of a named system prompt. Content is a Go
+// text/template; Variables holds default values for its variables.
+type Template struct {
+	Name        string            `json:"name"`
+	Version     int               `json:"version"`
+	Description string            `json:"description,omitempty"`
+	Content     string            `json:"content"`
+	Variables   map[string]string `json:"variables,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// Render executes the template with its default variables overridden by
+// vars. Referencing a variable that has no value is an error.
+func (t Template) Render(vars map[string]string) (string, error) {
+	tmpl, err := parse(t.Name, t.Content)
+	if err != nil {
+		return "", err
+	}
+
+	data := make(map[string]string, len(t.Variables)+len(vars))
+	for key, value := range t.Variables {
+		data[key] = value
+	}
+	for key, value := range vars {
+		data[key] = value
+	}
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
+func parse(name, content string) (*template.Template, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	return tmpl, nil
+}
+
+// Store keeps every version of every template. With a path it persists to
+// a JSON file after each change, otherwise it lives in memory only.
+type Store struct {
+	mu        sync.RWMutex
+	path      string
+	templates map[string][]Template
+}
+
+func NewStore() *Store {
+	return &Store{templates: map[string][]Template{}}
+}
+
+// LoadStore opens a store persisted at path, starting empty if the file
+// does not exist yet
+func LoadStore(path string) (*Store, error) {
+	store := NewStore()
+	store.path = path
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prompts file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &store.templates); err != nil {
+		return nil, fmt.Errorf("failed to parse prompts file: %w", err)
+	}
+	return store, nil
+}
+
+// List returns the latest version of each template, sorted by name
+func (s *Store) List() []Template {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	latest := make([]Template, 0, len(s.templates))
+	for _, versions := range s.templates {
+		latest = append(latest, versions[len(versions)-1])
+	}
+	sort.Slice(latest, func(i, j int) bool {
+		return latest[i].Name < latest[j].Name
+	})
+	return latest
+}
+
+// Get returns a version of a template, or the latest one when version is 0
+func (s *Store) Get(name string, version int) (Template, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	versions, ok := s.templates[name]
+	if !ok {
+		return Template{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if version == 0 {
+		return versions[len(versions)-1], nil
+	}
+	if version < 0 || version > len(versions) {
+		return Template{}, fmt.Errorf("%w: %s version %d", ErrNotFound, name, version)
+	}
+	return versions[version-1], nil
+}
+
+// Versions returns every version of a template, oldest first
+func (s *Store) Versions(name string) ([]Template, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	versions, ok := s.templates[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return append([]Template(nil), versions...), nil
+}
+
+// Create adds a new template as version 1
+func (s *Store) Create(t Template) (Template, error) {
+	if !namePattern.MatchString(t.Name) {
+		return Template{}, fmt.Errorf("%w: name must be lowercase letters, digits, '-' or '_'", ErrInvalidTemplate)
+	}
+	if _, err := parse(t.Name, t.Content); err != nil {
+		return Template{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.templates[t.Name]; ok {
+		return Template{}, fmt.Errorf("%w: %s", ErrExists, t.Name)
+	}
+
+	t.Version = 1
+	t.CreatedAt = time.Now().UTC()
+	s.templates[t.Name] = []Template{t}
+
+	if err := s.save(); err != nil {
+		delete(s.templates, t.Name)
+		return Template{}, err
+	}
+	return t, nil
+}
+
+// Update stores t as the next version of the named template. Earlier
+// versions stay available so requests can pin them.
+func (s *Store) Update(name string, t Template) (Template, error) {
+	if _, err := parse(name, t.Content); err != nil {
+		return Template{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	versions, ok := s.templates[name]
+	if !ok {
+		return Template{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	t.Name = name
+	t.Version = len(versions) + 1
+	t.CreatedAt = time.Now().UTC()
+	s.templates[name] = append(versions, t)
+
+	if err := s.save(); err != nil {
+		s.templates[name] = versions
+		return Template{}, err
+	}
+	return t, nil
+}
+
+// Delete removes a template with all of its versions
+func (s *Store) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	versions, ok := s.templates[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	delete(s.templates, name)
+
+	if err := s.save(); err != nil {
+		s.templates[name] = versions
+		return err
+	}
+	return nil
+}
+
+// save writes the store to disk. Callers hold the write lock.
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s.templates, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode prompts: %w", err)
+	}
+
+	// Write to a temporary file first so a crash never leaves a torn file
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".prompts-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to save prompts: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save prompts: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save prompts: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to save prompts: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package prompts
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestStore_Versions(t *testing.T) {
+	store := NewStore()
+
+	created, err := store.Create(Template{Name: "support", Content: "You are {{.company}} support."})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if created.Version != 1 || created.CreatedAt.IsZero() {
+		t.Errorf("Unexpected created template: %+v", created)
+	}
+
+	updated, err := store.Update("support", Template{Content: "You are {{.company}} support. Be brief."})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if updated.Version != 2 || updated.Name != "support" {
+		t.Errorf("Unexpected updated template: %+v", updated)
+	}
+
+	latest, _ := store.Get("support", 0)
+	if latest.Version != 2 {
+		t.Errorf("Expected latest version 2, got %d", latest.Version)
+	}
+
+	first, _ := store.Get("support", 1)
+	if first.Content != "You are {{.company}} support." {
+		t.Errorf("Expected version 1 to be kept, got '%s'", first.Content)
+	}
+
+	if _, err := store.Get("support", 3); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing version, got: %v", err)
+	}
+
+	if list := store.List(); len(list) != 1 || list[0].Version != 2 {
+		t.Errorf("Expected List to return the latest version only, got %+v", list)
+	}
+}
+
+func TestStore_RejectsInvalidTemplates(t *testing.T) {
+	store := NewStore()
+
+	if _, err := store.Create(Template{Name: "Bad Name", Content: "hi"}); !errors.Is(err, ErrInvalidTemplate) {
+		t.Errorf("Expected invalid name to be rejected, got: %v", err)
+	}
+
+	if _, err := store.Create(Template{Name: "broken", Content: "{{.unclosed"}); !errors.Is(err, ErrInvalidTemplate) {
+		t.Errorf("Expected unparseable template to be rejected, got: %v", err)
+	}
+
+	store.Create(Template{Name: "dup", Content: "hi"})
+	if _, err := store.Create(Template{Name: "dup", Content: "hi"}); !errors.Is(err, ErrExists) {
+		t.Errorf("Expected duplicate name to be rejected, got: %v", err)
+	}
+}
+
+func TestTemplate_Render(t *testing.T) {
+	tmpl := Template{
+		Name:      "support",
+		Content:   "You are {{.company}} support for {{.product}}.",
+		Variables: map[string]string{"company": "Acme", "product": "widgets"},
+	}
+
+	got, err := tmpl.Render(map[string]string{"product": "gadgets"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if got != "You are Acme support for gadgets." {
+		t.Errorf("Unexpected render: '%s'", got)
+	}
+
+	tmpl.Variables = nil
+	if _, err := tmpl.Render(nil); !errors.Is(err, ErrInvalidTemplate) {
+		t.Errorf("Expected missing variables to fail, got: %v", err)
+	}
+}
+
+func TestLoadStore_Persists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prompts.json")
+
+	store, err := LoadStore(path)
+	if err != nil {
+		t.Fatalf("Expected a missing file to start an empty store, got: %v", err)
+	}
+	store.Create(Template{Name: "faq", Content: "Answer FAQs."})
+	store.Update("faq", Template{Content: "Answer FAQs briefly."})
+
+	reloaded, err := LoadStore(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	versions, err := reloaded.Versions("faq")
+	if err != nil || len(versions) != 2 {
+		t.Fatalf("Expected both versions to be persisted, got %+v (%v)", versions, err)
+	}
+
+	if err := reloaded.Delete("faq"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if again, _ := LoadStore(path); len(again.List()) != 0 {
+		t.Error("Expected delete to be persisted")
+	}
+}
@@ -0,0 +1,75 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+
+	"chat-backend/internal/handlers"
+)
+
+// statusCodes maps the status an HTTP request would get onto a gRPC code.
+// Anything else is reported as Internal.
+var statusCodes = map[int]codes.Code{
+	http.StatusBadRequest:              codes.InvalidArgument,
+	http.StatusUnauthorized:            codes.Unauthenticated,
+	http.StatusForbidden:               codes.PermissionDenied,
+	http.StatusNotFound:                codes.NotFound,
+	http.StatusConflict:                codes.AlreadyExists,
+	http.StatusRequestEntityTooLarge:   codes.InvalidArgument,
+	http.StatusUnsupportedMediaType:    codes.InvalidArgument,
+	http.StatusUnprocessableEntity:     codes.FailedPrecondition,
+	http.StatusTooManyRequests:         codes.ResourceExhausted,
+	handlers.StatusClientClosedRequest: codes.Canceled,
+	http.StatusServiceUnavailable:      codes.Unavailable,
+	http.StatusGatewayTimeout:          codes.DeadlineExceeded,
+}
+
+// statusError reports err with the code matching the problem details an
+// HTTP request would get. The problem type goes in an ErrorInfo detail, so
+// that clients can tell apart errors that share a code, and the invalid
+// fields of a request that failed validation in a BadRequest detail.
+func statusError(err error) error {
+	if errors.Is(err, context.Canceled) {
+		return status.Error(codes.Canceled, err.Error())
+	}
+
+	problem := handlers.NewProblemDetails(err)
+	code, ok := statusCodes[problem.Status]
+	if !ok {
+		code = codes.Internal
+	}
+	st := status.New(code, problem.Detail)
+
+	var details []protoadapt.MessageV1
+	if name, ok := strings.CutPrefix(problem.Type, "urn:chat-backend:problem:"); ok {
+		details = append(details, &errdetails.ErrorInfo{
+			Reason:   strings.ToUpper(strings.ReplaceAll(name, "-", "_")),
+			Domain:   "chat-backend",
+			Metadata: map[string]string{"type": problem.Type},
+		})
+	}
+	if len(problem.Errors) > 0 {
+		badRequest := &errdetails.BadRequest{}
+		for _, fieldErr := range problem.Errors {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       fieldErr.Field,
+				Description: fieldErr.Message,
+			})
+		}
+		details = append(details, badRequest)
+	}
+
+	if len(details) > 0 {
+		if detailed, err := st.WithDetails(details...); err == nil {
+			st = detailed
+		}
+	}
+	return st.Err()
+}
@@ -0,0 +1,134 @@
+package rpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"log/slog"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"chat-backend/internal/tenant"
+)
+
+// UnaryLogger logs each unary call once it is answered, with its status code
+func UnaryLogger() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logCall(info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// StreamLogger logs each streaming call once it ends, with its status code
+func StreamLogger() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, stream)
+		logCall(info.FullMethod, start, err)
+		return err
+	}
+}
+
+func logCall(method string, start time.Time, err error) {
+	code := status.Code(err)
+	attrs := []any{"method", method, "code", code.String(), "duration", time.Since(start)}
+	switch code {
+	case codes.OK, codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists,
+		codes.PermissionDenied, codes.Unauthenticated, codes.ResourceExhausted:
+		slog.Info("gRPC call", attrs...)
+	default:
+		slog.Error("gRPC call failed", append(attrs, "error", status.Convert(err).Message())...)
+	}
+}
+
+// UnaryRecovery turns a panic in a unary call into an Internal error. grpc
+// does not recover handler panics, so one would end the whole process.
+func UnaryRecovery() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer recoverCall(info.FullMethod, &err)
+		return handler(ctx, req)
+	}
+}
+
+// StreamRecovery turns a panic in a streaming call into an Internal error,
+// see UnaryRecovery
+func StreamRecovery() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer recoverCall(info.FullMethod, &err)
+		return handler(srv, stream)
+	}
+}
+
+func recoverCall(method string, err *error) {
+	if r := recover(); r != nil {
+		slog.Error("gRPC call panicked", "method", method, "panic", r, "stack", string(debug.Stack()))
+		*err = status.Error(codes.Internal, "Internal server error")
+	}
+}
+
+// UnaryAuth checks the API key and resolves the tenant of unary calls, see
+// authorize
+func UnaryAuth(key string, registry *tenant.Registry) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authorize(ctx, info.FullMethod, key, registry)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuth checks the API key and resolves the tenant of streaming calls,
+// see authorize
+func StreamAuth(key string, registry *tenant.Registry) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authorize(stream.Context(), info.FullMethod, key, registry)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// authorize requires the bearer token in the authorization metadata to
+// match key, and stores the tenant named by the x-tenant-id metadata in the
+// context, like the HTTP tenant middleware. Without a key every call is
+// refused. Health checks are left open so that probes need no key.
+func authorize(ctx context.Context, method, key string, registry *tenant.Registry) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if !strings.HasPrefix(method, "/"+healthpb.Health_ServiceDesc.ServiceName+"/") {
+		token, ok := strings.CutPrefix(firstValue(md, "authorization"), "Bearer ")
+		if key == "" || !ok || subtle.ConstantTimeCompare([]byte(token), []byte(key)) != 1 {
+			return nil, status.Error(codes.Unauthenticated, "A valid API key is required")
+		}
+	}
+
+	t := registry.Lookup(firstValue(md, strings.ToLower(tenant.Header)))
+	return tenant.WithTenant(ctx, t), nil
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// contextStream replaces the context of a server stream
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
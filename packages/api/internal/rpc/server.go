@@ -0,0 +1,163 @@
+// Package rpc serves the chat API over gRPC, for services that want a typed
+// interface instead of JSON over HTTP. It answers with the same provider
+// chain and request checks as the HTTP handlers.
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"chat-backend/internal/app"
+	"chat-backend/internal/chat"
+	"chat-backend/internal/handlers"
+	chatv1 "chat-backend/proto/chat/v1"
+)
+
+// NewServer builds the gRPC server with the chat service and grpc.health.v1
+// health checking. Every call is logged, recovered from panics, and needs
+// the API key.
+func NewServer(appCtx *app.AppContext) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(UnaryLogger(), UnaryRecovery(), UnaryAuth(appCtx.GRPCAPIKey, appCtx.Tenants)),
+		grpc.ChainStreamInterceptor(StreamLogger(), StreamRecovery(), StreamAuth(appCtx.GRPCAPIKey, appCtx.Tenants)),
+	)
+	chatv1.RegisterChatServiceServer(server, &chatService{appCtx: appCtx})
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(chatv1.ChatService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	return server
+}
+
+type chatService struct {
+	chatv1.UnimplementedChatServiceServer
+	appCtx *app.AppContext
+}
+
+func (s *chatService) Chat(ctx context.Context, req *chatv1.ChatRequest) (*chatv1.ChatResponse, error) {
+	chatRequest, metadata, err := s.newChatRequest(ctx, req, false)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	chatResp, err := s.appCtx.ChatProvider.Chat(ctx, chatRequest)
+	if err != nil {
+		slog.Error("Failed to get answer from chat provider", "error", err, "messages_count", len(req.Messages))
+		return nil, statusError(err)
+	}
+
+	return toResponse(chatResp, metadata), nil
+}
+
+func (s *chatService) ChatStream(req *chatv1.ChatRequest, stream grpc.ServerStreamingServer[chatv1.ChatResponse]) error {
+	ctx := stream.Context()
+	chatRequest, metadata, err := s.newChatRequest(ctx, req, true)
+	if err != nil {
+		return statusError(err)
+	}
+
+	err = s.appCtx.ChatProvider.ChatStream(ctx, chatRequest, func(chunk *chat.ChatResponse) error {
+		// The handler metadata rides on the first chunk
+		resp := toResponse(chunk, metadata)
+		metadata = nil
+		return stream.Send(resp)
+	})
+	if err != nil {
+		slog.Error("Failed to stream chat response", "error", err, "messages_count", len(req.Messages))
+		return statusError(err)
+	}
+	return nil
+}
+
+// newChatRequest checks a request like the HTTP handlers do and converts it
+// for the provider, with the tenant's server-side prompt
+func (s *chatService) newChatRequest(ctx context.Context, req *chatv1.ChatRequest, streaming bool) (*chat.ChatRequest, map[string]string, error) {
+	chatReq := handlers.ChatRequest{Streaming: streaming}
+	for _, msg := range req.Messages {
+		message := handlers.Message{
+			Role:       msg.Role,
+			Content:    msg.Content,
+			ToolCalls:  fromToolCalls(msg.ToolCalls),
+			ToolCallID: msg.ToolCallId,
+		}
+		for _, attachment := range msg.Attachments {
+			message.Attachments = append(message.Attachments, chat.Attachment{
+				Filename: attachment.Filename,
+				MimeType: attachment.MimeType,
+				Data:     attachment.Data,
+			})
+		}
+		chatReq.Messages = append(chatReq.Messages, message)
+	}
+	for _, tool := range req.Tools {
+		chatTool := chat.Tool{Name: tool.Name, Description: tool.Description}
+		if tool.Parameters != "" {
+			chatTool.Parameters = json.RawMessage(tool.Parameters)
+		}
+		chatReq.Tools = append(chatReq.Tools, chatTool)
+	}
+
+	return handlers.NewChatRequest(ctx, s.appCtx, &chatReq)
+}
+
+func fromToolCalls(calls []*chatv1.ToolCall) []chat.ToolCall {
+	var toolCalls []chat.ToolCall
+	for _, call := range calls {
+		toolCalls = append(toolCalls, chat.ToolCall{ID: call.Id, Name: call.Name, Arguments: call.Arguments})
+	}
+	return toolCalls
+}
+
+func toToolCalls(calls []chat.ToolCall) []*chatv1.ToolCall {
+	var toolCalls []*chatv1.ToolCall
+	for _, call := range calls {
+		toolCalls = append(toolCalls, &chatv1.ToolCall{Id: call.ID, Name: call.Name, Arguments: call.Arguments})
+	}
+	return toolCalls
+}
+
+// toResponse converts a provider response, adding the handler's metadata
+func toResponse(chatResp *chat.ChatResponse, metadata map[string]string) *chatv1.ChatResponse {
+	resp := &chatv1.ChatResponse{
+		Content:      chatResp.Content,
+		ToolCalls:    toToolCalls(chatResp.ToolCalls),
+		FinishReason: chatResp.FinishReason,
+	}
+
+	if len(chatResp.Metadata) > 0 || len(metadata) > 0 {
+		resp.Metadata = make(map[string]string, len(chatResp.Metadata)+len(metadata))
+		for key, value := range chatResp.Metadata {
+			resp.Metadata[key] = value
+		}
+		for key, value := range metadata {
+			resp.Metadata[key] = value
+		}
+	}
+
+	for _, answer := range chatResp.Answers {
+		resp.Answers = append(resp.Answers, &chatv1.Answer{
+			Content:  answer.Content,
+			Score:    answer.Score,
+			Source:   answer.Source,
+			Metadata: answer.Metadata,
+		})
+	}
+
+	if usage := chatResp.Usage; usage != nil {
+		resp.Usage = &chatv1.Usage{
+			PromptTokens:     int32(usage.PromptTokens),
+			CompletionTokens: int32(usage.CompletionTokens),
+			TotalTokens:      int32(usage.TotalTokens),
+			Estimated:        usage.Estimated,
+		}
+	}
+
+	return resp
+}
@@ -0,0 +1,275 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"chat-backend/internal/app"
+	"chat-backend/internal/chat"
+	"chat-backend/internal/tenant"
+	chatv1 "chat-backend/proto/chat/v1"
+)
+
+// echoProvider answers with the last message, streamed word by word, and
+// records the tenant of each request
+type echoProvider struct {
+	tenants []string
+	err     error
+}
+
+func (p *echoProvider) Chat(ctx context.Context, req *chat.ChatRequest) (*chat.ChatResponse, error) {
+	p.tenants = append(p.tenants, tenant.FromContext(ctx).ID)
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &chat.ChatResponse{
+		Content: req.Messages[len(req.Messages)-1].Content,
+		Usage:   &chat.Usage{PromptTokens: 3, CompletionTokens: 2, TotalTokens: 5},
+	}, nil
+}
+
+func (p *echoProvider) ChatStream(ctx context.Context, req *chat.ChatRequest, callback chat.StreamCallback) error {
+	p.tenants = append(p.tenants, tenant.FromContext(ctx).ID)
+	if p.err != nil {
+		return p.err
+	}
+	for _, word := range []string{"Hello", " world"} {
+		if err := callback(&chat.ChatResponse{Content: word}); err != nil {
+			return err
+		}
+	}
+	return callback(&chat.ChatResponse{Usage: &chat.Usage{TotalTokens: 5}})
+}
+
+// testKey is the API key of test servers
+const testKey = "secret"
+
+// bearer sends an API key with every call
+type bearer string
+
+func (b bearer) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(b)}, nil
+}
+
+func (b bearer) RequireTransportSecurity() bool {
+	return false
+}
+
+// newTestClient connects to a test server, sending key with every call
+// unless it is empty
+func newTestClient(t *testing.T, provider chat.ChatProvider, key string) *grpc.ClientConn {
+	t.Helper()
+
+	appCtx := app.NewAppContext(provider)
+	appCtx.GRPCAPIKey = testKey
+
+	listener := bufconn.Listen(1 << 20)
+	server := NewServer(appCtx)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	opts := []grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}
+	if key != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(bearer(key)))
+	}
+	conn, err := grpc.NewClient("passthrough:///bufnet", opts...)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func userMessage(content string) *chatv1.ChatRequest {
+	return &chatv1.ChatRequest{Messages: []*chatv1.Message{{Role: "user", Content: content}}}
+}
+
+func TestChat(t *testing.T) {
+	provider := &echoProvider{}
+	client := chatv1.NewChatServiceClient(newTestClient(t, provider, testKey))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-tenant-id", "acme")
+	resp, err := client.Chat(ctx, userMessage("Hi there"))
+	if err != nil {
+		t.Fatalf("Expected an answer, got: %v", err)
+	}
+
+	if resp.Content != "Hi there" {
+		t.Errorf("Expected 'Hi there', got '%s'", resp.Content)
+	}
+	if resp.Usage.GetTotalTokens() != 5 {
+		t.Errorf("Expected the usage, got %+v", resp.Usage)
+	}
+	if len(provider.tenants) != 1 || provider.tenants[0] != "acme" {
+		t.Errorf("Expected the tenant from the metadata, got %v", provider.tenants)
+	}
+}
+
+func TestChatStream(t *testing.T) {
+	client := chatv1.NewChatServiceClient(newTestClient(t, &echoProvider{}, testKey))
+
+	stream, err := client.ChatStream(context.Background(), userMessage("Hi"))
+	if err != nil {
+		t.Fatalf("Failed to start the stream: %v", err)
+	}
+
+	content := ""
+	var usage *chatv1.Usage
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to read the stream: %v", err)
+		}
+		content += chunk.Content
+		if chunk.Usage != nil {
+			usage = chunk.Usage
+		}
+	}
+
+	if content != "Hello world" {
+		t.Errorf("Expected 'Hello world', got '%s'", content)
+	}
+	if usage.GetTotalTokens() != 5 {
+		t.Errorf("Expected the usage on the last chunk, got %+v", usage)
+	}
+}
+
+func TestChat_InvalidRequest(t *testing.T) {
+	client := chatv1.NewChatServiceClient(newTestClient(t, &echoProvider{}, testKey))
+
+	_, err := client.Chat(context.Background(), &chatv1.ChatRequest{
+		Messages: []*chatv1.Message{{Role: "robot", Content: "Hi"}},
+	})
+
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("Expected %s, got %s", codes.InvalidArgument, st.Code())
+	}
+
+	var info *errdetails.ErrorInfo
+	var badRequest *errdetails.BadRequest
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.ErrorInfo:
+			info = detail
+		case *errdetails.BadRequest:
+			badRequest = detail
+		}
+	}
+	if info.GetReason() != "VALIDATION_FAILED" {
+		t.Errorf("Expected a VALIDATION_FAILED reason, got %+v", info)
+	}
+	if len(badRequest.GetFieldViolations()) == 0 || badRequest.FieldViolations[0].Field != "messages[0].role" {
+		t.Errorf("Expected the invalid role as a field violation, got %+v", badRequest)
+	}
+}
+
+func TestChat_ProviderError(t *testing.T) {
+	provider := &echoProvider{err: chat.Errorf(chat.ErrOverloaded, "queue is full")}
+	client := chatv1.NewChatServiceClient(newTestClient(t, provider, testKey))
+
+	_, err := client.Chat(context.Background(), userMessage("Hi"))
+	if code := status.Code(err); code != codes.Unavailable {
+		t.Errorf("Expected %s, got %s", codes.Unavailable, code)
+	}
+}
+
+func TestAuth(t *testing.T) {
+	conn := newTestClient(t, &echoProvider{}, "")
+	client := chatv1.NewChatServiceClient(conn)
+
+	_, err := client.Chat(context.Background(), userMessage("Hi"))
+	if code := status.Code(err); code != codes.Unauthenticated {
+		t.Errorf("Expected %s without a key, got %s", codes.Unauthenticated, code)
+	}
+
+	stream, err := client.ChatStream(context.Background(), userMessage("Hi"))
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if code := status.Code(err); code != codes.Unauthenticated {
+		t.Errorf("Expected %s for a stream without a key, got %s", codes.Unauthenticated, code)
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer wrong")
+	if _, err := client.Chat(ctx, userMessage("Hi")); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected a wrong key to be refused, got: %v", err)
+	}
+
+	ctx = metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+testKey)
+	if _, err := client.Chat(ctx, userMessage("Hi")); err != nil {
+		t.Errorf("Expected the key to be accepted, got: %v", err)
+	}
+
+	// Health checks need no key
+	resp, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{
+		Service: chatv1.ChatService_ServiceDesc.ServiceName,
+	})
+	if err != nil || resp.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Expected the chat service to be serving, got %v, %v", resp, err)
+	}
+}
+
+func TestAuthorize_NoKey(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "))
+	_, err := authorize(ctx, "/chat.v1.ChatService/Chat", "", tenant.NewRegistry())
+	if code := status.Code(err); code != codes.Unauthenticated {
+		t.Errorf("Expected calls to be refused without a configured key, got %s", code)
+	}
+}
+
+// panicProvider panics on every request
+type panicProvider struct{}
+
+func (panicProvider) Chat(context.Context, *chat.ChatRequest) (*chat.ChatResponse, error) {
+	panic("boom")
+}
+
+func (panicProvider) ChatStream(context.Context, *chat.ChatRequest, chat.StreamCallback) error {
+	panic("boom")
+}
+
+func TestRecovery(t *testing.T) {
+	client := chatv1.NewChatServiceClient(newTestClient(t, panicProvider{}, testKey))
+
+	if _, err := client.Chat(context.Background(), userMessage("Hi")); status.Code(err) != codes.Internal {
+		t.Errorf("Expected a panic to end the call with %s, got %v", codes.Internal, err)
+	}
+
+	stream, err := client.ChatStream(context.Background(), userMessage("Hi"))
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.Internal {
+		t.Errorf("Expected a panic to end the stream with %s, got %v", codes.Internal, err)
+	}
+}
+
+func TestStatusError_Cancelled(t *testing.T) {
+	if code := status.Code(statusError(context.Canceled)); code != codes.Canceled {
+		t.Errorf("Expected %s, got %s", codes.Canceled, code)
+	}
+	if code := status.Code(statusError(errors.New("boom"))); code != codes.Internal {
+		t.Errorf("Expected %s, got %s", codes.Internal, code)
+	}
+}
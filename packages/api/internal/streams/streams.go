@@ -0,0 +1,171 @@
+// Package streams buffers the events of streamed generations so that a
+// client that loses its connection can reconnect and pick up where it
+// left off.
+package streams
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultTTL is how long a finished stream can still be resumed
+const DefaultTTL = 2 * time.Minute
+
+var ErrNotFound = errors.New("stream not found")
+
+// Event is one server-sent event of a stream. IDs count from 1 in the order
+// events were published.
+type Event struct {
+	ID   int
+	Data []byte
+	// Metadata of the chunk the event carries, which the response headers
+	// of the first event written report
+	Metadata map[string]string
+}
+
+// Stream is the event log of one generation. It is written by the
+// generation and read by any number of connections, each following it
+// from its own position.
+type Stream struct {
+	mu      sync.Mutex
+	events  []Event
+	err     error
+	done    bool
+	changed chan struct{}
+}
+
+func newStream() *Stream {
+	return &Stream{changed: make(chan struct{})}
+}
+
+// Publish appends an event and wakes every follower
+func (s *Stream) Publish(data []byte, metadata map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, Event{ID: len(s.events) + 1, Data: data, Metadata: metadata})
+	s.notify()
+}
+
+// Close ends the stream. A non-nil err is what followers get once they
+// have read every event, for a generation that failed before it could
+// publish any.
+func (s *Stream) Close(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.err, s.done = err, true
+	s.notify()
+}
+
+// notify wakes everyone waiting on the stream. Callers hold the lock.
+func (s *Stream) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// Follow calls fn with every event after the one with ID after, first the
+// buffered ones and then live ones as they are published, until the
+// stream ends or ctx is done. It returns the stream's error, ctx's, or
+// fn's.
+func (s *Stream) Follow(ctx context.Context, after int, fn func(Event) error) error {
+	next := max(after, 0)
+	for {
+		s.mu.Lock()
+		var events []Event
+		if next < len(s.events) {
+			events = s.events[next:]
+		}
+		done, err, changed := s.done, s.err, s.changed
+		s.mu.Unlock()
+
+		for _, event := range events {
+			if err := fn(event); err != nil {
+				return err
+			}
+			next++
+		}
+
+		if done {
+			return err
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Buffer holds the streams of a server. Streams are kept while their
+// generation runs and for the TTL after it ends.
+type Buffer struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	streams map[key]*Stream
+}
+
+type key struct {
+	tenantID string
+	id       string
+}
+
+func NewBuffer(ttl time.Duration) *Buffer {
+	return &Buffer{ttl: ttl, streams: map[key]*Stream{}}
+}
+
+// Resumable reports whether streams outlive the connection that started
+// them. Without a TTL they are dropped as soon as their generation ends.
+func (b *Buffer) Resumable() bool {
+	return b.ttl > 0
+}
+
+// Open starts the stream of a tenant's generation, replacing any finished
+// stream kept under the same ID
+func (b *Buffer) Open(tenantID, id string) *Stream {
+	k := key{tenantID: tenantID, id: id}
+	s := newStream()
+
+	b.mu.Lock()
+	b.streams[k] = s
+	b.mu.Unlock()
+	return s
+}
+
+// Finish closes a stream and schedules it to be dropped after the TTL
+func (b *Buffer) Finish(tenantID, id string, s *Stream, err error) {
+	s.Close(err)
+
+	k := key{tenantID: tenantID, id: id}
+	drop := func() {
+		b.mu.Lock()
+		if b.streams[k] == s {
+			delete(b.streams, k)
+		}
+		b.mu.Unlock()
+	}
+
+	if b.ttl <= 0 {
+		drop()
+		return
+	}
+	time.AfterFunc(b.ttl, drop)
+}
+
+// Get returns a tenant's stream. Streams of other tenants are reported as
+// not found, like expired ones.
+func (b *Buffer) Get(tenantID, id string) (*Stream, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s, ok := b.streams[key{tenantID: tenantID, id: id}]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return s, nil
+}
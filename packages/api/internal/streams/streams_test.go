@@ -0,0 +1,122 @@
+package streams
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// follow collects a stream's data after the given ID until it ends
+func follow(t *testing.T, s *Stream, after int) ([]string, error) {
+	t.Helper()
+
+	var data []string
+	err := s.Follow(context.Background(), after, func(event Event) error {
+		if event.ID != after+len(data)+1 {
+			t.Errorf("Expected event %d, got %d", after+len(data)+1, event.ID)
+		}
+		data = append(data, string(event.Data))
+		return nil
+	})
+	return data, err
+}
+
+func TestStream_ReplayThenLive(t *testing.T) {
+	s := newStream()
+	s.Publish([]byte("a"), nil)
+	s.Publish([]byte("b"), nil)
+
+	done := make(chan []string)
+	go func() {
+		data, _ := follow(t, s, 1)
+		done <- data
+	}()
+
+	s.Publish([]byte("c"), nil)
+	s.Close(nil)
+
+	data := <-done
+	if len(data) != 2 || data[0] != "b" || data[1] != "c" {
+		t.Errorf("Expected the events after the first, got %q", data)
+	}
+}
+
+func TestStream_Error(t *testing.T) {
+	failed := errors.New("provider unavailable")
+	s := newStream()
+	s.Close(failed)
+
+	if _, err := follow(t, s, 0); !errors.Is(err, failed) {
+		t.Errorf("Expected the stream's error, got: %v", err)
+	}
+}
+
+func TestStream_FollowCancelled(t *testing.T) {
+	s := newStream()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := s.Follow(ctx, 0, func(Event) error { return nil }); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the follower to stop with its context, got: %v", err)
+	}
+}
+
+func TestBuffer_Get(t *testing.T) {
+	buffer := NewBuffer(time.Minute)
+	s := buffer.Open("acme", "1")
+
+	if got, err := buffer.Get("acme", "1"); err != nil || got != s {
+		t.Errorf("Expected the tenant's stream, got %v, %v", got, err)
+	}
+	if _, err := buffer.Get("globex", "1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected another tenant's stream to be not found, got: %v", err)
+	}
+
+	buffer.Finish("acme", "1", s, nil)
+	if _, err := buffer.Get("acme", "1"); err != nil {
+		t.Errorf("Expected a finished stream to be kept for the TTL, got: %v", err)
+	}
+}
+
+func TestBuffer_Expire(t *testing.T) {
+	buffer := NewBuffer(10 * time.Millisecond)
+	s := buffer.Open("", "1")
+	buffer.Finish("", "1", s, nil)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, err := buffer.Get("", "1"); errors.Is(err, ErrNotFound) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the stream to expire after the TTL")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestBuffer_NotResumable(t *testing.T) {
+	buffer := NewBuffer(0)
+	if buffer.Resumable() {
+		t.Error("Expected a buffer without a TTL not to be resumable")
+	}
+
+	s := buffer.Open("", "1")
+	buffer.Finish("", "1", s, nil)
+	if _, err := buffer.Get("", "1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected the stream to be dropped when it ends, got: %v", err)
+	}
+}
+
+func TestBuffer_ReopenKeepsNewStream(t *testing.T) {
+	buffer := NewBuffer(10 * time.Millisecond)
+	old := buffer.Open("", "1")
+	buffer.Finish("", "1", old, nil)
+	current := buffer.Open("", "1")
+
+	time.Sleep(30 * time.Millisecond)
+	if got, err := buffer.Get("", "1"); err != nil || got != current {
+		t.Errorf("Expected the old stream's expiry to leave the new one, got %v, %v", got, err)
+	}
+}
@@ -0,0 +1,98 @@
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Header names the request header that identifies the calling tenant
+const Header = "X-Tenant-ID"
+
+// Tenant holds per-tenant settings. Requests without a known tenant get the
+// registry's default settings.
+type Tenant struct {
+	ID              string            `yaml:"id" json:"id"`
+	PromptTemplate  string            `yaml:"prompt_template,omitempty" json:"prompt_template,omitempty"`
+	PromptVariables map[string]string `yaml:"prompt_variables,omitempty" json:"prompt_variables,omitempty"`
+	// Tier names the tenant's service tier, which can give its requests
+	// priority when they have to queue for the provider. Tenants are named
+	// by an unauthenticated header, so a proxy must set it for tiers to be
+	// trusted.
+	Tier string `yaml:"tier,omitempty" json:"tier,omitempty"`
+	// DisableCache opts the tenant out of response caching
+	DisableCache bool `yaml:"disable_cache,omitempty" json:"disable_cache,omitempty"`
+}
+
+// Registry looks tenants up by ID
+type Registry struct {
+	tenants  map[string]Tenant
+	fallback Tenant
+}
+
+type registryFile struct {
+	Tenants []Tenant `yaml:"tenants"`
+	Default Tenant   `yaml:"default"`
+}
+
+// NewRegistry returns a registry with no tenants configured
+func NewRegistry() *Registry {
+	return &Registry{tenants: map[string]Tenant{}}
+}
+
+// LoadFile reads a YAML or JSON tenants file from disk
+func LoadFile(path string) (*Registry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tenants file: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse decodes a YAML or JSON tenants file
+func Parse(data []byte) (*Registry, error) {
+	var file registryFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse tenants file: %w", err)
+	}
+
+	registry := NewRegistry()
+	registry.fallback = file.Default
+	for i, t := range file.Tenants {
+		if t.ID == "" {
+			return nil, fmt.Errorf("tenant %d: id is required", i)
+		}
+		if _, ok := registry.tenants[t.ID]; ok {
+			return nil, fmt.Errorf("tenant %q is configured twice", t.ID)
+		}
+		registry.tenants[t.ID] = t
+	}
+	return registry, nil
+}
+
+// Lookup returns the settings for id. Unknown or empty IDs get the default
+// settings, keeping the ID they were asked for.
+func (r *Registry) Lookup(id string) Tenant {
+	if t, ok := r.tenants[id]; ok {
+		return t
+	}
+	t := r.fallback
+	t.ID = id
+	return t
+}
+
+type contextKey struct{}
+
+// WithTenant stores t in ctx
+func WithTenant(ctx context.Context, t Tenant) context.Context {
+	return context.WithValue(ctx, contextKey{}, t)
+}
+
+// FromContext returns the tenant stored in ctx, or the zero Tenant when the
+// request did not go through the tenant middleware
+func FromContext(ctx context.Context) Tenant {
+	t, _ := ctx.Value(contextKey{}).(Tenant)
+	return t
+}
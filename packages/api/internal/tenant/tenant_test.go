@@ -0,0 +1,53 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	registry, err := Parse([]byte(`
+tenants:
+  - id: acme
+    prompt_template: acme-support
+    prompt_variables:
+      company: Acme
+default:
+  prompt_template: faq
+`))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	acme := registry.Lookup("acme")
+	if acme.PromptTemplate != "acme-support" || acme.PromptVariables["company"] != "Acme" {
+		t.Errorf("Unexpected tenant: %+v", acme)
+	}
+
+	other := registry.Lookup("globex")
+	if other.ID != "globex" || other.PromptTemplate != "faq" {
+		t.Errorf("Expected unknown tenants to get the defaults, got %+v", other)
+	}
+}
+
+func TestParse_RejectsDuplicates(t *testing.T) {
+	_, err := Parse([]byte(`
+tenants:
+  - id: acme
+  - id: acme
+`))
+	if err == nil {
+		t.Error("Expected duplicate tenants to be rejected")
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if got := FromContext(context.Background()); got.ID != "" {
+		t.Errorf("Expected zero tenant without middleware, got %+v", got)
+	}
+
+	ctx := WithTenant(context.Background(), Tenant{ID: "acme"})
+	if got := FromContext(ctx); got.ID != "acme" {
+		t.Errorf("Expected tenant from context, got %+v", got)
+	}
+}
@@ -2,9 +2,12 @@ package main
 
 import (
 	"embed"
+	"fmt"
 	"log"
 	"log/slog"
+	"net"
 	"net/http"
+	"os"
 
 	"github.com/labstack/echo/v4"
 	emiddleware "github.com/labstack/echo/v4/middleware"
@@ -12,19 +15,30 @@ import (
 	"chat-backend/internal/app"
 	"chat-backend/internal/handlers"
 	"chat-backend/internal/middleware"
+	"chat-backend/internal/rpc"
 )
 
 //go:embed static/dist
 var webAssets embed.FS
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "batch" {
+		os.Exit(batchCommand(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "eval" {
+		os.Exit(evalCommand(os.Args[2:]))
+	}
+
 	ctx := app.BuildAppContext()
+	ctx.Jobs.WithDescriber(handlers.JobFailure)
 
 	e := echo.New()
+	e.HTTPErrorHandler = handlers.HTTPErrorHandler
 
 	// Add middleware
 	e.Use(middleware.Logger())
 	e.Use(middleware.RateLimit())
+	e.Use(middleware.Tenant(ctx.Tenants))
 	e.Use(emiddleware.StaticWithConfig(emiddleware.StaticConfig{
 		HTML5:      true,
 		Root:       "static/dist",
@@ -38,6 +52,45 @@ func main() {
 	// Serve the api endpoints
 	e.GET("/status", handlers.StatusHandler(ctx))
 	e.POST("/api/chat", handlers.ChatHandler(ctx))
+	e.POST("/api/chat/generations/:id/cancel", handlers.CancelGenerationHandler(ctx))
+	e.GET("/api/chat/streams/:id", handlers.ResumeStreamHandler(ctx))
+	e.POST("/api/chat/jobs", handlers.SubmitJobHandler(ctx))
+	e.GET("/api/chat/jobs/:id", handlers.GetJobHandler(ctx))
+	e.DELETE("/api/chat/jobs/:id", handlers.CancelJobHandler(ctx))
+	e.POST("/api/chat/batch", handlers.BatchHandler(ctx))
+	e.GET("/api/ws", handlers.WebSocketHandler(ctx))
+	e.POST("/api/conversations", handlers.CreateConversationHandler(ctx))
+	e.POST("/api/conversations/import", handlers.ImportConversationHandler(ctx))
+	e.GET("/api/conversations/:id/export", handlers.ExportConversationHandler(ctx))
+	e.POST("/api/messages/:id/feedback", handlers.SubmitFeedbackHandler(ctx))
+
+	// Management endpoints
+	prompts := e.Group("/api/prompts", middleware.AdminAuth(ctx.AdminAPIKey, ctx.AdminOpen))
+	prompts.GET("", handlers.ListPromptsHandler(ctx))
+	prompts.POST("", handlers.CreatePromptHandler(ctx))
+	prompts.GET("/:name", handlers.GetPromptHandler(ctx))
+	prompts.PUT("/:name", handlers.UpdatePromptHandler(ctx))
+	prompts.DELETE("/:name", handlers.DeletePromptHandler(ctx))
+	prompts.GET("/:name/versions", handlers.ListPromptVersionsHandler(ctx))
+	e.GET("/api/feedback/export", handlers.ExportFeedbackHandler(ctx), middleware.AdminAuth(ctx.AdminAPIKey, ctx.AdminOpen))
+
+	reports := e.Group("/api/admin/analytics", middleware.AdminAuth(ctx.AdminAPIKey, ctx.AdminOpen))
+	reports.GET("/questions", handlers.TopQuestionsHandler(ctx))
+	reports.GET("/unanswered", handlers.UnansweredQuestionsHandler(ctx))
+	reports.GET("/latency", handlers.LatencyHandler(ctx))
+	reports.GET("/cost", handlers.CostHandler(ctx))
+
+	// The gRPC API is served from the same process on its own port
+	if ctx.GRPCPort > 0 {
+		listener, err := net.Listen("tcp", fmt.Sprintf(":%d", ctx.GRPCPort))
+		if err != nil {
+			log.Fatalf("Failed to listen for gRPC: %v", err)
+		}
+		go func() {
+			slog.Info("Starting gRPC server", "port", ctx.GRPCPort)
+			log.Fatal(rpc.NewServer(ctx).Serve(listener))
+		}()
+	}
 
 	slog.Info("Starting server on localhost:8090")
 	log.Fatal(e.Start(":8090"))
@@ -47,4 +47,4 @@
 Error generating stack: `+u.message+`
 `+u.stack}}function nt(l){switch(typeof l){case"bigint":case"boolean":case"number":case"string":case"undefined":return l;case"object":return l;default:return""}}function Ti(l){var t=l.type;return(l=l.nodeName)&&l.toLowerCase()==="input"&&(t==="checkbox"||t==="radio")}function Dv(l){var t=Ti(l)?"checked":"value",u=Object.getOwnPropertyDescriptor(l.constructor.prototype,t),a=""+l[t];if(!l.hasOwnProperty(t)&&typeof u<"u"&&typeof u.get=="function"&&typeof u.set=="function"){var e=u.get,n=u.set;return Object.defineProperty(l,t,{configurable:!0,get:function(){return e.call(this)},set:function(f){a=""+f,n.call(this,f)}}),Object.defineProperty(l,t,{enumerable:u.enumerable}),{getValue:function(){return a},setValue:function(f){a=""+f},stopTracking:function(){l._valueTracker=null,delete l[t]}}}}function Me(l){l._valueTracker||(l._valueTracker=Dv(l))}function Ei(l){if(!l)return!1;var t=l._valueTracker;if(!t)return!0;var u=t.getValue(),a="";return l&&(a=Ti(l)?l.checked?"true":"false":l.value),l=a,l!==u?(t.setValue(l),!0):!1}function _e(l){if(l=l||(typeof document<"u"?document:void 0),typeof l>"u")return null;try{return l.activeElement||l.body}catch{return l.body}}var Uv=/[\n"\\]/g;function ft(l){return l.replace(Uv,function(t){return"\\"+t.charCodeAt(0).toString(16)+" "})}function Jn(l,t,u,a,e,n,f,c){l.name="",f!=null&&typeof f!="function"&&typeof f!="symbol"&&typeof f!="boolean"?l.type=f:l.removeAttribute("type"),t!=null?f==="number"?(t===0&&l.value===""||l.value!=t)&&(l.value=""+nt(t)):l.value!==""+nt(t)&&(l.value=""+nt(t)):f!=="submit"&&f!=="reset"||l.removeAttribute("value"),t!=null?wn(l,f,nt(t)):u!=null?wn(l,f,nt(u)):a!=null&&l.removeAttribute("value"),e==null&&n!=null&&(l.defaultChecked=!!n),e!=null&&(l.checked=e&&typeof e!="function"&&typeof e!="symbol"),c!=null&&typeof c!="function"&&typeof c!="symbol"&&typeof c!="boolean"?l.name=""+nt(c):l.removeAttribute("name")}function Ai(l,t,u,a,e,n,f,c){if(n!=null&&typeof n!="function"&&typeof n!="symbol"&&typeof n!="boolean"&&(l.type=n),t!=null||u!=null){if(!(n!=="submit"&&n!=="reset"||t!=null))return;u=u!=null?""+nt(u):"",t=t!=null?""+nt(t):u,c||t===l.value||(l.value=t),l.defaultValue=t}a=a??e,a=typeof a!="function"&&typeof a!="symbol"&&!!a,l.checked=c?l.checked:!!a,l.defaultChecked=!!a,f!=null&&typeof f!="function"&&typeof f!="symbol"&&typeof f!="boolean"&&(l.name=f)}function wn(l,t,u){t==="number"&&_e(l.ownerDocument)===l||l.defaultValue===""+u||(l.defaultValue=""+u)}function Qu(l,t,u,a){if(l=l.options,t){t={};for(var e=0;e<u.length;e++)t["$"+u[e]]=!0;for(u=0;u<l.length;u++)e=t.hasOwnProperty("$"+l[u].value),l[u].selected!==e&&(l[u].selected=e),e&&a&&(l[u].defaultSelected=!0)}else{for(u=""+nt(u),t=null,e=0;e<l.length;e++){if(l[e].value===u){l[e].selected=!0,a&&(l[e].defaultSelected=!0);return}t!==null||l[e].disabled||(t=l[e])}t!==null&&(t.selected=!0)}}function zi(l,t,u){if(t!=null&&(t=""+nt(t),t!==l.value&&(l.value=t),u==null)){l.defaultValue!==t&&(l.defaultValue=t);return}l.defaultValue=u!=null?""+nt(u):""}function Oi(l,t,u,a){if(t==null){if(a!=null){if(u!=null)throw Error(v(92));if(Bl(a)){if(1<a.length)throw Error(v(93));a=a[0]}u=a}u==null&&(u=""),t=u}u=nt(t),l.defaultValue=u,a=l.textContent,a===u&&a!==""&&a!==null&&(l.value=a)}function ju(l,t){if(t){var u=l.firstChild;if(u&&u===l.lastChild&&u.nodeType===3){u.nodeValue=t;return}}l.textContent=t}var Rv=new Set("animationIterationCount aspectRatio borderImageOutset borderImageSlice borderImageWidth boxFlex boxFlexGroup boxOrdinalGroup columnCount columns flex flexGrow flexPositive flexShrink flexNegative flexOrder gridArea gridRow gridRowEnd gridRowSpan gridRowStart gridColumn gridColumnEnd gridColumnSpan gridColumnStart fontWeight lineClamp lineHeight opacity order orphans scale tabSize widows zIndex zoom fillOpacity floodOpacity stopOpacity strokeDasharray strokeDashoffset strokeMiterlimit strokeOpacity strokeWidth MozAnimationIterationCount MozBoxFlex MozBoxFlexGroup MozLineClamp msAnimationIterationCount msFlex msZoom msFlexGrow msFlexNegative msFlexOrder msFlexPositive msFlexShrink msGridColumn msGridColumnSpan msGridRow msGridRowSpan WebkitAnimationIterationCount WebkitBoxFlex WebKitBoxFlexGroup WebkitBoxOrdinalGroup WebkitColumnCount WebkitColumns WebkitFlex WebkitFlexGrow WebkitFlexPositive WebkitFlexShrink WebkitLineClamp".split(" "));function Mi(l,t,u){var a=t.indexOf("--")===0;u==null||typeof u=="boolean"||u===""?a?l.setProperty(t,""):t==="float"?l.cssFloat="":l[t]="":a?l.setProperty(t,u):typeof u!="number"||u===0||Rv.has(t)?t==="float"?l.cssFloat=u:l[t]=(""+u).trim():l[t]=u+"px"}function _i(l,t,u){if(t!=null&&typeof t!="object")throw Error(v(62));if(l=l.style,u!=null){for(var a in u)!u.hasOwnProperty(a)||t!=null&&t.hasOwnProperty(a)||(a.indexOf("--")===0?l.setProperty(a,""):a==="float"?l.cssFloat="":l[a]="");for(var e in t)a=t[e],t.hasOwnProperty(e)&&u[e]!==a&&Mi(l,e,a)}else for(var n in t)t.hasOwnProperty(n)&&Mi(l,n,t[n])}function Wn(l){if(l.indexOf("-")===-1)return!1;switch(l){case"annotation-xml":case"color-profile":case"font-face":case"font-face-src":case"font-face-uri":case"font-face-format":case"font-face-name":case"missing-glyph":return!1;default:return!0}}var Nv=new Map([["acceptCharset","accept-charset"],["htmlFor","for"],["httpEquiv","http-equiv"],["crossOrigin","crossorigin"],["accentHeight","accent-height"],["alignmentBaseline","alignment-baseline"],["arabicForm","arabic-form"],["baselineShift","baseline-shift"],["capHeight","cap-height"],["clipPath","clip-path"],["clipRule","clip-rule"],["colorInterpolation","color-interpolation"],["colorInterpolationFilters","color-interpolation-filters"],["colorProfile","color-profile"],["colorRendering","color-rendering"],["dominantBaseline","dominant-baseline"],["enableBackground","enable-background"],["fillOpacity","fill-opacity"],["fillRule","fill-rule"],["floodColor","flood-color"],["floodOpacity","flood-opacity"],["fontFamily","font-family"],["fontSize","font-size"],["fontSizeAdjust","font-size-adjust"],["fontStretch","font-stretch"],["fontStyle","font-style"],["fontVariant","font-variant"],["fontWeight","font-weight"],["glyphName","glyph-name"],["glyphOrientationHorizontal","glyph-orientation-horizontal"],["glyphOrientationVertical","glyph-orientation-vertical"],["horizAdvX","horiz-adv-x"],["horizOriginX","horiz-origin-x"],["imageRendering","image-rendering"],["letterSpacing","letter-spacing"],["lightingColor","lighting-color"],["markerEnd","marker-end"],["markerMid","marker-mid"],["markerStart","marker-start"],["overlinePosition","overline-position"],["overlineThickness","overline-thickness"],["paintOrder","paint-order"],["panose-1","panose-1"],["pointerEvents","pointer-events"],["renderingIntent","rendering-intent"],["shapeRendering","shape-rendering"],["stopColor","stop-color"],["stopOpacity","stop-opacity"],["strikethroughPosition","strikethrough-position"],["strikethroughThickness","strikethrough-thickness"],["strokeDasharray","stroke-dasharray"],["strokeDashoffset","stroke-dashoffset"],["strokeLinecap","stroke-linecap"],["strokeLinejoin","stroke-linejoin"],["strokeMiterlimit","stroke-miterlimit"],["strokeOpacity","stroke-opacity"],["strokeWidth","stroke-width"],["textAnchor","text-anchor"],["textDecoration","text-decoration"],["textRendering","text-rendering"],["transformOrigin","transform-origin"],["underlinePosition","underline-position"],["underlineThickness","underline-thickness"],["unicodeBidi","unicode-bidi"],["unicodeRange","unicode-range"],["unitsPerEm","units-per-em"],["vAlphabetic","v-alphabetic"],["vHanging","v-hanging"],["vIdeographic","v-ideographic"],["vMathematical","v-mathematical"],["vectorEffect","vector-effect"],["vertAdvY","vert-adv-y"],["vertOriginX","vert-origin-x"],["vertOriginY","vert-origin-y"],["wordSpacing","word-spacing"],["writingMode","writing-mode"],["xmlnsXlink","xmlns:xlink"],["xHeight","x-height"]]),Hv=/^[\u0000-\u001F ]*j[\r\n\t]*a[\r\n\t]*v[\r\n\t]*a[\r\n\t]*s[\r\n\t]*c[\r\n\t]*r[\r\n\t]*i[\r\n\t]*p[\r\n\t]*t[\r\n\t]*:/i;function De(l){return Hv.test(""+l)?"javascript:throw new Error('React has blocked a javascript: URL as a security precaution.')":l}var $n=null;function kn(l){return l=l.target||l.srcElement||window,l.correspondingUseElement&&(l=l.correspondingUseElement),l.nodeType===3?l.parentNode:l}var Cu=null,Zu=null;function Di(l){var t=Bu(l);if(t&&(l=t.stateNode)){var u=l[Zl]||null;l:switch(l=t.stateNode,t.type){case"input":if(Jn(l,u.value,u.defaultValue,u.defaultValue,u.checked,u.defaultChecked,u.type,u.name),t=u.name,u.type==="radio"&&t!=null){for(u=l;u.parentNode;)u=u.parentNode;for(u=u.querySelectorAll('input[name="'+ft(""+t)+'"][type="radio"]'),t=0;t<u.length;t++){var a=u[t];if(a!==l&&a.form===l.form){var e=a[Zl]||null;if(!e)throw Error(v(90));Jn(a,e.value,e.defaultValue,e.defaultValue,e.checked,e.defaultChecked,e.type,e.name)}}for(t=0;t<u.length;t++)a=u[t],a.form===l.form&&Ei(a)}break l;case"textarea":zi(l,u.value,u.defaultValue);break l;case"select":t=u.value,t!=null&&Qu(l,!!u.multiple,t,!1)}}}var Fn=!1;function Ui(l,t,u){if(Fn)return l(t,u);Fn=!0;try{var a=l(t);return a}finally{if(Fn=!1,(Cu!==null||Zu!==null)&&(yn(),Cu&&(t=Cu,l=Zu,Zu=Cu=null,Di(t),l)))for(t=0;t<l.length;t++)Di(l[t])}}function Oa(l,t){var u=l.stateNode;if(u===null)return null;var a=u[Zl]||null;if(a===null)return null;u=a[t];l:switch(t){case"onClick":case"onClickCapture":case"onDoubleClick":case"onDoubleClickCapture":case"onMouseDown":case"onMouseDownCapture":case"onMouseMove":case"onMouseMoveCapture":case"onMouseUp":case"onMouseUpCapture":case"onMouseEnter":(a=!a.disabled)||(l=l.type,a=!(l==="button"||l==="input"||l==="select"||l==="textarea")),l=!a;break l;default:l=!1}if(l)return null;if(u&&typeof u!="function")throw Error(v(231,t,typeof u));return u}var Dt=!(typeof window>"u"||typeof window.document>"u"||typeof window.document.createElement>"u"),In=!1;if(Dt)try{var Ma={};Object.defineProperty(Ma,"passive",{get:function(){In=!0}}),window.addEventListener("test",Ma,Ma),window.removeEventListener("test",Ma,Ma)}catch{In=!1}var Lt=null,Pn=null,Ue=null;function Ri(){if(Ue)return Ue;var l,t=Pn,u=t.length,a,e="value"in Lt?Lt.value:Lt.textContent,n=e.length;for(l=0;l<u&&t[l]===e[l];l++);var f=u-l;for(a=1;a<=f&&t[u-a]===e[n-a];a++);return Ue=e.slice(l,1<a?1-a:void 0)}function Re(l){var t=l.keyCode;return"charCode"in l?(l=l.charCode,l===0&&t===13&&(l=13)):l=t,l===10&&(l=13),32<=l||l===13?l:0}function Ne(){return!0}function Ni(){return!1}function Vl(l){function t(u,a,e,n,f){this._reactName=u,this._targetInst=e,this.type=a,this.nativeEvent=n,this.target=f,this.currentTarget=null;for(var c in l)l.hasOwnProperty(c)&&(u=l[c],this[c]=u?u(n):n[c]);return this.isDefaultPrevented=(n.defaultPrevented!=null?n.defaultPrevented:n.returnValue===!1)?Ne:Ni,this.isPropagationStopped=Ni,this}return p(t.prototype,{preventDefault:function(){this.defaultPrevented=!0;var u=this.nativeEvent;u&&(u.preventDefault?u.preventDefault():typeof u.returnValue!="unknown"&&(u.returnValue=!1),this.isDefaultPrevented=Ne)},stopPropagation:function(){var u=this.nativeEvent;u&&(u.stopPropagation?u.stopPropagation():typeof u.cancelBubble!="unknown"&&(u.cancelBubble=!0),this.isPropagationStopped=Ne)},persist:function(){},isPersistent:Ne}),t}var mu={eventPhase:0,bubbles:0,cancelable:0,timeStamp:function(l){return l.timeStamp||Date.now()},defaultPrevented:0,isTrusted:0},He=Vl(mu),_a=p({},mu,{view:0,detail:0}),pv=Vl(_a),lf,tf,Da,pe=p({},_a,{screenX:0,screenY:0,clientX:0,clientY:0,pageX:0,pageY:0,ctrlKey:0,shiftKey:0,altKey:0,metaKey:0,getModifierState:af,button:0,buttons:0,relatedTarget:function(l){return l.relatedTarget===void 0?l.fromElement===l.srcElement?l.toElement:l.fromElement:l.relatedTarget},movementX:function(l){return"movementX"in l?l.movementX:(l!==Da&&(Da&&l.type==="mousemove"?(lf=l.screenX-Da.screenX,tf=l.screenY-Da.screenY):tf=lf=0,Da=l),lf)},movementY:function(l){return"movementY"in l?l.movementY:tf}}),Hi=Vl(pe),qv=p({},pe,{dataTransfer:0}),Yv=Vl(qv),Bv=p({},_a,{relatedTarget:0}),uf=Vl(Bv),Gv=p({},mu,{animationName:0,elapsedTime:0,pseudoElement:0}),xv=Vl(Gv),Xv=p({},mu,{clipboardData:function(l){return"clipboardData"in l?l.clipboardData:window.clipboardData}}),Qv=Vl(Xv),jv=p({},mu,{data:0}),pi=Vl(jv),Cv={Esc:"Escape",Spacebar:" ",Left:"ArrowLeft",Up:"ArrowUp",Right:"ArrowRight",Down:"ArrowDown",Del:"Delete",Win:"OS",Menu:"ContextMenu",Apps:"ContextMenu",Scroll:"ScrollLock",MozPrintableKey:"Unidentified"},Zv={8:"Backspace",9:"Tab",12:"Clear",13:"Enter",16:"Shift",17:"Control",18:"Alt",19:"Pause",20:"CapsLock",27:"Escape",32:" ",33:"PageUp",34:"PageDown",35:"End",36:"Home",37:"ArrowLeft",38:"ArrowUp",39:"ArrowRight",40:"ArrowDown",45:"Insert",46:"Delete",112:"F1",113:"F2",114:"F3",115:"F4",116:"F5",117:"F6",118:"F7",119:"F8",120:"F9",121:"F10",122:"F11",123:"F12",144:"NumLock",145:"ScrollLock",224:"Meta"},Vv={Alt:"altKey",Control:"ctrlKey",Meta:"metaKey",Shift:"shiftKey"};function Lv(l){var t=this.nativeEvent;return t.getModifierState?t.getModifierState(l):(l=Vv[l])?!!t[l]:!1}function af(){return Lv}var Kv=p({},_a,{key:function(l){if(l.key){var t=Cv[l.key]||l.key;if(t!=="Unidentified")return t}return l.type==="keypress"?(l=Re(l),l===13?"Enter":String.fromCharCode(l)):l.type==="keydown"||l.type==="keyup"?Zv[l.keyCode]||"Unidentified":""},code:0,location:0,ctrlKey:0,shiftKey:0,altKey:0,metaKey:0,repeat:0,locale:0,getModifierState:af,charCode:function(l){return l.type==="keypress"?Re(l):0},keyCode:function(l){return l.type==="keydown"||l.type==="keyup"?l.keyCode:0},which:function(l){return l.type==="keypress"?Re(l):l.type==="keydown"||l.type==="keyup"?l.keyCode:0}}),Jv=Vl(Kv),wv=p({},pe,{pointerId:0,width:0,height:0,pressure:0,tangentialPressure:0,tiltX:0,tiltY:0,twist:0,pointerType:0,isPrimary:0}),qi=Vl(wv),Wv=p({},_a,{touches:0,targetTouches:0,changedTouches:0,altKey:0,metaKey:0,ctrlKey:0,shiftKey:0,getModifierState:af}),$v=Vl(Wv),kv=p({},mu,{propertyName:0,elapsedTime:0,pseudoElement:0}),Fv=Vl(kv),Iv=p({},pe,{deltaX:function(l){return"deltaX"in l?l.deltaX:"wheelDeltaX"in l?-l.wheelDeltaX:0},deltaY:function(l){return"deltaY"in l?l.deltaY:"wheelDeltaY"in l?-l.wheelDeltaY:"wheelDelta"in l?-l.wheelDelta:0},deltaZ:0,deltaMode:0}),Pv=Vl(Iv),lo=p({},mu,{newState:0,oldState:0}),to=Vl(lo),uo=[9,13,27,32],ef=Dt&&"CompositionEvent"in window,Ua=null;Dt&&"documentMode"in document&&(Ua=document.documentMode);var ao=Dt&&"TextEvent"in window&&!Ua,Yi=Dt&&(!ef||Ua&&8<Ua&&11>=Ua),Bi=" ",Gi=!1;function xi(l,t){switch(l){case"keyup":return uo.indexOf(t.keyCode)!==-1;case"keydown":return t.keyCode!==229;case"keypress":case"mousedown":case"focusout":return!0;default:return!1}}function Xi(l){return l=l.detail,typeof l=="object"&&"data"in l?l.data:null}var Vu=!1;function eo(l,t){switch(l){case"compositionend":return Xi(t);case"keypress":return t.which!==32?null:(Gi=!0,Bi);case"textInput":return l=t.data,l===Bi&&Gi?null:l;default:return null}}function no(l,t){if(Vu)return l==="compositionend"||!ef&&xi(l,t)?(l=Ri(),Ue=Pn=Lt=null,Vu=!1,l):null;switch(l){case"paste":return null;case"keypress":if(!(t.ctrlKey||t.altKey||t.metaKey)||t.ctrlKey&&t.altKey){if(t.char&&1<t.char.length)return t.char;if(t.which)return String.fromCharCode(t.which)}return null;case"compositionend":return Yi&&t.locale!=="ko"?null:t.data;default:return null}}var fo={color:!0,date:!0,datetime:!0,"datetime-local":!0,email:!0,month:!0,number:!0,password:!0,range:!0,search:!0,tel:!0,text:!0,time:!0,url:!0,week:!0};function Qi(l){var t=l&&l.nodeName&&l.nodeName.toLowerCase();return t==="input"?!!fo[l.type]:t==="textarea"}function ji(l,t,u,a){Cu?Zu?Zu.push(a):Zu=[a]:Cu=a,t=bn(t,"onChange"),0<t.length&&(u=new He("onChange","change",null,u,a),l.push({event:u,listeners:t}))}var Ra=null,Na=null;function co(l){Td(l,0)}function qe(l){var t=za(l);if(Ei(t))return l}function Ci(l,t){if(l==="change")return t}var Zi=!1;if(Dt){var nf;if(Dt){var ff="oninput"in document;if(!ff){var Vi=document.createElement("div");Vi.setAttribute("oninput","return;"),ff=typeof Vi.oninput=="function"}nf=ff}else nf=!1;Zi=nf&&(!document.documentMode||9<document.documentMode)}function Li(){Ra&&(Ra.detachEvent("onpropertychange",Ki),Na=Ra=null)}function Ki(l){if(l.propertyName==="value"&&qe(Na)){var t=[];ji(t,Na,l,kn(l)),Ui(co,t)}}function io(l,t,u){l==="focusin"?(Li(),Ra=t,Na=u,Ra.attachEvent("onpropertychange",Ki)):l==="focusout"&&Li()}function so(l){if(l==="selectionchange"||l==="keyup"||l==="keydown")return qe(Na)}function vo(l,t){if(l==="click")return qe(t)}function oo(l,t){if(l==="input"||l==="change")return qe(t)}function yo(l,t){return l===t&&(l!==0||1/l===1/t)||l!==l&&t!==t}var Il=typeof Object.is=="function"?Object.is:yo;function Ha(l,t){if(Il(l,t))return!0;if(typeof l!="object"||l===null||typeof t!="object"||t===null)return!1;var u=Object.keys(l),a=Object.keys(t);if(u.length!==a.length)return!1;for(a=0;a<u.length;a++){var e=u[a];if(!Bn.call(t,e)||!Il(l[e],t[e]))return!1}return!0}function Ji(l){for(;l&&l.firstChild;)l=l.firstChild;return l}function wi(l,t){var u=Ji(l);l=0;for(var a;u;){if(u.nodeType===3){if(a=l+u.textContent.length,l<=t&&a>=t)return{node:u,offset:t-l};l=a}l:{for(;u;){if(u.nextSibling){u=u.nextSibling;break l}u=u.parentNode}u=void 0}u=Ji(u)}}function Wi(l,t){return l&&t?l===t?!0:l&&l.nodeType===3?!1:t&&t.nodeType===3?Wi(l,t.parentNode):"contains"in l?l.contains(t):l.compareDocumentPosition?!!(l.compareDocumentPosition(t)&16):!1:!1}function $i(l){l=l!=null&&l.ownerDocument!=null&&l.ownerDocument.defaultView!=null?l.ownerDocument.defaultView:window;for(var t=_e(l.document);t instanceof l.HTMLIFrameElement;){try{var u=typeof t.contentWindow.location.href=="string"}catch{u=!1}if(u)l=t.contentWindow;else break;t=_e(l.document)}return t}function cf(l){var t=l&&l.nodeName&&l.nodeName.toLowerCase();return t&&(t==="input"&&(l.type==="text"||l.type==="search"||l.type==="tel"||l.type==="url"||l.type==="password")||t==="textarea"||l.contentEditable==="true")}var ho=Dt&&"documentMode"in document&&11>=document.documentMode,Lu=null,sf=null,pa=null,df=!1;function ki(l,t,u){var a=u.window===u?u.document:u.nodeType===9?u:u.ownerDocument;df||Lu==null||Lu!==_e(a)||(a=Lu,"selectionStart"in a&&cf(a)?a={start:a.selectionStart,end:a.selectionEnd}:(a=(a.ownerDocument&&a.ownerDocument.defaultView||window).getSelection(),a={anchorNode:a.anchorNode,anchorOffset:a.anchorOffset,focusNode:a.focusNode,focusOffset:a.focusOffset}),pa&&Ha(pa,a)||(pa=a,a=bn(sf,"onSelect"),0<a.length&&(t=new He("onSelect","select",null,t,u),l.push({event:t,listeners:a}),t.target=Lu)))}function gu(l,t){var u={};return u[l.toLowerCase()]=t.toLowerCase(),u["Webkit"+l]="webkit"+t,u["Moz"+l]="moz"+t,u}var Ku={animationend:gu("Animation","AnimationEnd"),animationiteration:gu("Animation","AnimationIteration"),animationstart:gu("Animation","AnimationStart"),transitionrun:gu("Transition","TransitionRun"),transitionstart:gu("Transition","TransitionStart"),transitioncancel:gu("Transition","TransitionCancel"),transitionend:gu("Transition","TransitionEnd")},vf={},Fi={};Dt&&(Fi=document.createElement("div").style,"AnimationEvent"in window||(delete Ku.animationend.animation,delete Ku.animationiteration.animation,delete Ku.animationstart.animation),"TransitionEvent"in window||delete Ku.transitionend.transition);function Su(l){if(vf[l])return vf[l];if(!Ku[l])return l;var t=Ku[l],u;for(u in t)if(t.hasOwnProperty(u)&&u in Fi)return vf[l]=t[u];return l}var Ii=Su("animationend"),Pi=Su("animationiteration"),ls=Su("animationstart"),ro=Su("transitionrun"),mo=Su("transitionstart"),go=Su("transitioncancel"),ts=Su("transitionend"),us=new Map,of="abort auxClick beforeToggle cancel canPlay canPlayThrough click close contextMenu copy cut drag dragEnd dragEnter dragExit dragLeave dragOver dragStart drop durationChange emptied encrypted ended error gotPointerCapture input invalid keyDown keyPress keyUp load loadedData loadedMetadata loadStart lostPointerCapture mouseDown mouseMove mouseOut mouseOver mouseUp paste pause play playing pointerCancel pointerDown pointerMove pointerOut pointerOver pointerUp progress rateChange reset resize seeked seeking stalled submit suspend timeUpdate touchCancel touchEnd touchStart volumeChange scroll toggle touchMove waiting wheel".split(" ");of.push("scrollEnd");function rt(l,t){us.set(l,t),ru(t,[l])}var as=new WeakMap;function ct(l,t){if(typeof l=="object"&&l!==null){var u=as.get(l);return u!==void 0?u:(t={value:l,source:t,stack:bi(t)},as.set(l,t),t)}return{value:l,source:t,stack:bi(t)}}var it=[],Ju=0,yf=0;function Ye(){for(var l=Ju,t=yf=Ju=0;t<l;){var u=it[t];it[t++]=null;var a=it[t];it[t++]=null;var e=it[t];it[t++]=null;var n=it[t];if(it[t++]=null,a!==null&&e!==null){var f=a.pending;f===null?e.next=e:(e.next=f.next,f.next=e),a.pending=e}n!==0&&es(u,e,n)}}function Be(l,t,u,a){it[Ju++]=l,it[Ju++]=t,it[Ju++]=u,it[Ju++]=a,yf|=a,l.lanes|=a,l=l.alternate,l!==null&&(l.lanes|=a)}function hf(l,t,u,a){return Be(l,t,u,a),Ge(l)}function wu(l,t){return Be(l,null,null,t),Ge(l)}function es(l,t,u){l.lanes|=u;var a=l.alternate;a!==null&&(a.lanes|=u);for(var e=!1,n=l.return;n!==null;)n.childLanes|=u,a=n.alternate,a!==null&&(a.childLanes|=u),n.tag===22&&(l=n.stateNode,l===null||l._visibility&1||(e=!0)),l=n,n=n.return;return l.tag===3?(n=l.stateNode,e&&t!==null&&(e=31-Fl(u),l=n.hiddenUpdates,a=l[e],a===null?l[e]=[t]:a.push(t),t.lane=u|536870912),n):null}function Ge(l){if(50<ae)throw ae=0,Tc=null,Error(v(185));for(var t=l.return;t!==null;)l=t,t=l.return;return l.tag===3?l.stateNode:null}var Wu={};function So(l,t,u,a){this.tag=l,this.key=u,this.sibling=this.child=this.return=this.stateNode=this.type=this.elementType=null,this.index=0,this.refCleanup=this.ref=null,this.pendingProps=t,this.dependencies=this.memoizedState=this.updateQueue=this.memoizedProps=null,this.mode=a,this.subtreeFlags=this.flags=0,this.deletions=null,this.childLanes=this.lanes=0,this.alternate=null}function Pl(l,t,u,a){return new So(l,t,u,a)}function rf(l){return l=l.prototype,!(!l||!l.isReactComponent)}function Ut(l,t){var u=l.alternate;return u===null?(u=Pl(l.tag,t,l.key,l.mode),u.elementType=l.elementType,u.type=l.type,u.stateNode=l.stateNode,u.alternate=l,l.alternate=u):(u.pendingProps=t,u.type=l.type,u.flags=0,u.subtreeFlags=0,u.deletions=null),u.flags=l.flags&65011712,u.childLanes=l.childLanes,u.lanes=l.lanes,u.child=l.child,u.memoizedProps=l.memoizedProps,u.memoizedState=l.memoizedState,u.updateQueue=l.updateQueue,t=l.dependencies,u.dependencies=t===null?null:{lanes:t.lanes,firstContext:t.firstContext},u.sibling=l.sibling,u.index=l.index,u.ref=l.ref,u.refCleanup=l.refCleanup,u}function ns(l,t){l.flags&=65011714;var u=l.alternate;return u===null?(l.childLanes=0,l.lanes=t,l.child=null,l.subtreeFlags=0,l.memoizedProps=null,l.memoizedState=null,l.updateQueue=null,l.dependencies=null,l.stateNode=null):(l.childLanes=u.childLanes,l.lanes=u.lanes,l.child=u.child,l.subtreeFlags=0,l.deletions=null,l.memoizedProps=u.memoizedProps,l.memoizedState=u.memoizedState,l.updateQueue=u.updateQueue,l.type=u.type,t=u.dependencies,l.dependencies=t===null?null:{lanes:t.lanes,firstContext:t.firstContext}),l}function xe(l,t,u,a,e,n){var f=0;if(a=l,typeof l=="function")rf(l)&&(f=1);else if(typeof l=="string")f=Ty(l,u,Y.current)?26:l==="html"||l==="head"||l==="body"?27:5;else l:switch(l){case St:return l=Pl(31,u,t,e),l.elementType=St,l.lanes=n,l;case ml:return bu(u.children,e,n,t);case Cl:f=8,e|=24;break;case Rl:return l=Pl(12,u,t,e|2),l.elementType=Rl,l.lanes=n,l;case w:return l=Pl(13,u,t,e),l.elementType=w,l.lanes=n,l;case Nl:return l=Pl(19,u,t,e),l.elementType=Nl,l.lanes=n,l;default:if(typeof l=="object"&&l!==null)switch(l.$$typeof){case ht:case zl:f=10;break l;case wl:f=9;break l;case Wl:f=11;break l;case Ol:f=14;break l;case ol:f=16,a=null;break l}f=29,u=Error(v(130,l===null?"null":typeof l,"")),a=null}return t=Pl(f,u,t,e),t.elementType=l,t.type=a,t.lanes=n,t}function bu(l,t,u,a){return l=Pl(7,l,a,t),l.lanes=u,l}function mf(l,t,u){return l=Pl(6,l,null,t),l.lanes=u,l}function gf(l,t,u){return t=Pl(4,l.children!==null?l.children:[],l.key,t),t.lanes=u,t.stateNode={containerInfo:l.containerInfo,pendingChildren:null,implementation:l.implementation},t}var $u=[],ku=0,Xe=null,Qe=0,st=[],dt=0,Tu=null,Rt=1,Nt="";function Eu(l,t){$u[ku++]=Qe,$u[ku++]=Xe,Xe=l,Qe=t}function fs(l,t,u){st[dt++]=Rt,st[dt++]=Nt,st[dt++]=Tu,Tu=l;var a=Rt;l=Nt;var e=32-Fl(a)-1;a&=~(1<<e),u+=1;var n=32-Fl(t)+e;if(30<n){var f=e-e%5;n=(a&(1<<f)-1).toString(32),a>>=f,e-=f,Rt=1<<32-Fl(t)+e|u<<e|a,Nt=n+l}else Rt=1<<n|u<<e|a,Nt=l}function Sf(l){l.return!==null&&(Eu(l,1),fs(l,1,0))}function bf(l){for(;l===Xe;)Xe=$u[--ku],$u[ku]=null,Qe=$u[--ku],$u[ku]=null;for(;l===Tu;)Tu=st[--dt],st[dt]=null,Nt=st[--dt],st[dt]=null,Rt=st[--dt],st[dt]=null}var jl=null,yl=null,P=!1,Au=null,Tt=!1,Tf=Error(v(519));function zu(l){var t=Error(v(418,""));throw Ba(ct(t,l)),Tf}function cs(l){var t=l.stateNode,u=l.type,a=l.memoizedProps;switch(t[Gl]=l,t[Zl]=a,u){case"dialog":J("cancel",t),J("close",t);break;case"iframe":case"object":case"embed":J("load",t);break;case"video":case"audio":for(u=0;u<ne.length;u++)J(ne[u],t);break;case"source":J("error",t);break;case"img":case"image":case"link":J("error",t),J("load",t);break;case"details":J("toggle",t);break;case"input":J("invalid",t),Ai(t,a.value,a.defaultValue,a.checked,a.defaultChecked,a.type,a.name,!0),Me(t);break;case"select":J("invalid",t);break;case"textarea":J("invalid",t),Oi(t,a.value,a.defaultValue,a.children),Me(t)}u=a.children,typeof u!="string"&&typeof u!="number"&&typeof u!="bigint"||t.textContent===""+u||a.suppressHydrationWarning===!0||Od(t.textContent,u)?(a.popover!=null&&(J("beforetoggle",t),J("toggle",t)),a.onScroll!=null&&J("scroll",t),a.onScrollEnd!=null&&J("scrollend",t),a.onClick!=null&&(t.onclick=Tn),t=!0):t=!1,t||zu(l)}function is(l){for(jl=l.return;jl;)switch(jl.tag){case 5:case 13:Tt=!1;return;case 27:case 3:Tt=!0;return;default:jl=jl.return}}function qa(l){if(l!==jl)return!1;if(!P)return is(l),P=!0,!1;var t=l.tag,u;if((u=t!==3&&t!==27)&&((u=t===5)&&(u=l.type,u=!(u!=="form"&&u!=="button")||Gc(l.type,l.memoizedProps)),u=!u),u&&yl&&zu(l),is(l),t===13){if(l=l.memoizedState,l=l!==null?l.dehydrated:null,!l)throw Error(v(317));l:{for(l=l.nextSibling,t=0;l;){if(l.nodeType===8)if(u=l.data,u==="/$"){if(t===0){yl=gt(l.nextSibling);break l}t--}else u!=="$"&&u!=="$!"&&u!=="$?"||t++;l=l.nextSibling}yl=null}}else t===27?(t=yl,fu(l.type)?(l=jc,jc=null,yl=l):yl=t):yl=jl?gt(l.stateNode.nextSibling):null;return!0}function Ya(){yl=jl=null,P=!1}function ss(){var l=Au;return l!==null&&(Jl===null?Jl=l:Jl.push.apply(Jl,l),Au=null),l}function Ba(l){Au===null?Au=[l]:Au.push(l)}var Ef=A(null),Ou=null,Ht=null;function Kt(l,t,u){M(Ef,t._currentValue),t._currentValue=u}function pt(l){l._currentValue=Ef.current,R(Ef)}function Af(l,t,u){for(;l!==null;){var a=l.alternate;if((l.childLanes&t)!==t?(l.childLanes|=t,a!==null&&(a.childLanes|=t)):a!==null&&(a.childLanes&t)!==t&&(a.childLanes|=t),l===u)break;l=l.return}}function zf(l,t,u,a){var e=l.child;for(e!==null&&(e.return=l);e!==null;){var n=e.dependencies;if(n!==null){var f=e.child;n=n.firstContext;l:for(;n!==null;){var c=n;n=e;for(var i=0;i<t.length;i++)if(c.context===t[i]){n.lanes|=u,c=n.alternate,c!==null&&(c.lanes|=u),Af(n.return,u,l),a||(f=null);break l}n=c.next}}else if(e.tag===18){if(f=e.return,f===null)throw Error(v(341));f.lanes|=u,n=f.alternate,n!==null&&(n.lanes|=u),Af(f,u,l),f=null}else f=e.child;if(f!==null)f.return=e;else for(f=e;f!==null;){if(f===l){f=null;break}if(e=f.sibling,e!==null){e.return=f.return,f=e;break}f=f.return}e=f}}function Ga(l,t,u,a){l=null;for(var e=t,n=!1;e!==null;){if(!n){if((e.flags&524288)!==0)n=!0;else if((e.flags&262144)!==0)break}if(e.tag===10){var f=e.alternate;if(f===null)throw Error(v(387));if(f=f.memoizedProps,f!==null){var c=e.type;Il(e.pendingProps.value,f.value)||(l!==null?l.push(c):l=[c])}}else if(e===$l.current){if(f=e.alternate,f===null)throw Error(v(387));f.memoizedState.memoizedState!==e.memoizedState.memoizedState&&(l!==null?l.push(ve):l=[ve])}e=e.return}l!==null&&zf(t,l,u,a),t.flags|=262144}function je(l){for(l=l.firstContext;l!==null;){if(!Il(l.context._currentValue,l.memoizedValue))return!0;l=l.next}return!1}function Mu(l){Ou=l,Ht=null,l=l.dependencies,l!==null&&(l.firstContext=null)}function xl(l){return ds(Ou,l)}function Ce(l,t){return Ou===null&&Mu(l),ds(l,t)}function ds(l,t){var u=t._currentValue;if(t={context:t,memoizedValue:u,next:null},Ht===null){if(l===null)throw Error(v(308));Ht=t,l.dependencies={lanes:0,firstContext:t},l.flags|=524288}else Ht=Ht.next=t;return u}var bo=typeof AbortController<"u"?AbortController:function(){var l=[],t=this.signal={aborted:!1,addEventListener:function(u,a){l.push(a)}};this.abort=function(){t.aborted=!0,l.forEach(function(u){return u()})}},To=z.unstable_scheduleCallback,Eo=z.unstable_NormalPriority,El={$$typeof:zl,Consumer:null,Provider:null,_currentValue:null,_currentValue2:null,_threadCount:0};function Of(){return{controller:new bo,data:new Map,refCount:0}}function xa(l){l.refCount--,l.refCount===0&&To(Eo,function(){l.controller.abort()})}var Xa=null,Mf=0,Fu=0,Iu=null;function Ao(l,t){if(Xa===null){var u=Xa=[];Mf=0,Fu=Dc(),Iu={status:"pending",value:void 0,then:function(a){u.push(a)}}}return Mf++,t.then(vs,vs),t}function vs(){if(--Mf===0&&Xa!==null){Iu!==null&&(Iu.status="fulfilled");var l=Xa;Xa=null,Fu=0,Iu=null;for(var t=0;t<l.length;t++)(0,l[t])()}}function zo(l,t){var u=[],a={status:"pending",value:null,reason:null,then:function(e){u.push(e)}};return l.then(function(){a.status="fulfilled",a.value=t;for(var e=0;e<u.length;e++)(0,u[e])(t)},function(e){for(a.status="rejected",a.reason=e,e=0;e<u.length;e++)(0,u[e])(void 0)}),a}var os=S.S;S.S=function(l,t){typeof t=="object"&&t!==null&&typeof t.then=="function"&&Ao(l,t),os!==null&&os(l,t)};var _u=A(null);function _f(){var l=_u.current;return l!==null?l:il.pooledCache}function Ze(l,t){t===null?M(_u,_u.current):M(_u,t.pool)}function ys(){var l=_f();return l===null?null:{parent:El._currentValue,pool:l}}var Qa=Error(v(460)),hs=Error(v(474)),Ve=Error(v(542)),Df={then:function(){}};function rs(l){return l=l.status,l==="fulfilled"||l==="rejected"}function Le(){}function ms(l,t,u){switch(u=l[u],u===void 0?l.push(t):u!==t&&(t.then(Le,Le),t=u),t.status){case"fulfilled":return t.value;case"rejected":throw l=t.reason,Ss(l),l;default:if(typeof t.status=="string")t.then(Le,Le);else{if(l=il,l!==null&&100<l.shellSuspendCounter)throw Error(v(482));l=t,l.status="pending",l.then(function(a){if(t.status==="pending"){var e=t;e.status="fulfilled",e.value=a}},function(a){if(t.status==="pending"){var e=t;e.status="rejected",e.reason=a}})}switch(t.status){case"fulfilled":return t.value;case"rejected":throw l=t.reason,Ss(l),l}throw ja=t,Qa}}var ja=null;function gs(){if(ja===null)throw Error(v(459));var l=ja;return ja=null,l}function Ss(l){if(l===Qa||l===Ve)throw Error(v(483))}var Jt=!1;function Uf(l){l.updateQueue={baseState:l.memoizedState,firstBaseUpdate:null,lastBaseUpdate:null,shared:{pending:null,lanes:0,hiddenCallbacks:null},callbacks:null}}function Rf(l,t){l=l.updateQueue,t.updateQueue===l&&(t.updateQueue={baseState:l.baseState,firstBaseUpdate:l.firstBaseUpdate,lastBaseUpdate:l.lastBaseUpdate,shared:l.shared,callbacks:null})}function wt(l){return{lane:l,tag:0,payload:null,callback:null,next:null}}function Wt(l,t,u){var a=l.updateQueue;if(a===null)return null;if(a=a.shared,(ll&2)!==0){var e=a.pending;return e===null?t.next=t:(t.next=e.next,e.next=t),a.pending=t,t=Ge(l),es(l,null,u),t}return Be(l,a,t,u),Ge(l)}function Ca(l,t,u){if(t=t.updateQueue,t!==null&&(t=t.shared,(u&4194048)!==0)){var a=t.lanes;a&=l.pendingLanes,u|=a,t.lanes=u,vi(l,u)}}function Nf(l,t){var u=l.updateQueue,a=l.alternate;if(a!==null&&(a=a.updateQueue,u===a)){var e=null,n=null;if(u=u.firstBaseUpdate,u!==null){do{var f={lane:u.lane,tag:u.tag,payload:u.payload,callback:null,next:null};n===null?e=n=f:n=n.next=f,u=u.next}while(u!==null);n===null?e=n=t:n=n.next=t}else e=n=t;u={baseState:a.baseState,firstBaseUpdate:e,lastBaseUpdate:n,shared:a.shared,callbacks:a.callbacks},l.updateQueue=u;return}l=u.lastBaseUpdate,l===null?u.firstBaseUpdate=t:l.next=t,u.lastBaseUpdate=t}var Hf=!1;function Za(){if(Hf){var l=Iu;if(l!==null)throw l}}function Va(l,t,u,a){Hf=!1;var e=l.updateQueue;Jt=!1;var n=e.firstBaseUpdate,f=e.lastBaseUpdate,c=e.shared.pending;if(c!==null){e.shared.pending=null;var i=c,h=i.next;i.next=null,f===null?n=h:f.next=h,f=i;var g=l.alternate;g!==null&&(g=g.updateQueue,c=g.lastBaseUpdate,c!==f&&(c===null?g.firstBaseUpdate=h:c.next=h,g.lastBaseUpdate=i))}if(n!==null){var E=e.baseState;f=0,g=h=i=null,c=n;do{var r=c.lane&-536870913,m=r!==c.lane;if(m?(W&r)===r:(a&r)===r){r!==0&&r===Fu&&(Hf=!0),g!==null&&(g=g.next={lane:0,tag:c.tag,payload:c.payload,callback:null,next:null});l:{var Q=l,B=c;r=t;var nl=u;switch(B.tag){case 1:if(Q=B.payload,typeof Q=="function"){E=Q.call(nl,E,r);break l}E=Q;break l;case 3:Q.flags=Q.flags&-65537|128;case 0:if(Q=B.payload,r=typeof Q=="function"?Q.call(nl,E,r):Q,r==null)break l;E=p({},E,r);break l;case 2:Jt=!0}}r=c.callback,r!==null&&(l.flags|=64,m&&(l.flags|=8192),m=e.callbacks,m===null?e.callbacks=[r]:m.push(r))}else m={lane:r,tag:c.tag,payload:c.payload,callback:c.callback,next:null},g===null?(h=g=m,i=E):g=g.next=m,f|=r;if(c=c.next,c===null){if(c=e.shared.pending,c===null)break;m=c,c=m.next,m.next=null,e.lastBaseUpdate=m,e.shared.pending=null}}while(!0);g===null&&(i=E),e.baseState=i,e.firstBaseUpdate=h,e.lastBaseUpdate=g,n===null&&(e.shared.lanes=0),uu|=f,l.lanes=f,l.memoizedState=E}}function bs(l,t){if(typeof l!="function")throw Error(v(191,l));l.call(t)}function Ts(l,t){var u=l.callbacks;if(u!==null)for(l.callbacks=null,l=0;l<u.length;l++)bs(u[l],t)}var Pu=A(null),Ke=A(0);function Es(l,t){l=Qt,M(Ke,l),M(Pu,t),Qt=l|t.baseLanes}function pf(){M(Ke,Qt),M(Pu,Pu.current)}function qf(){Qt=Ke.current,R(Pu),R(Ke)}var $t=0,V=null,al=null,bl=null,Je=!1,la=!1,Du=!1,we=0,La=0,ta=null,Oo=0;function gl(){throw Error(v(321))}function Yf(l,t){if(t===null)return!1;for(var u=0;u<t.length&&u<l.length;u++)if(!Il(l[u],t[u]))return!1;return!0}function Bf(l,t,u,a,e,n){return $t=n,V=t,t.memoizedState=null,t.updateQueue=null,t.lanes=0,S.H=l===null||l.memoizedState===null?e0:n0,Du=!1,n=u(a,e),Du=!1,la&&(n=zs(t,u,a,e)),As(l),n}function As(l){S.H=Pe;var t=al!==null&&al.next!==null;if($t=0,bl=al=V=null,Je=!1,La=0,ta=null,t)throw Error(v(300));l===null||_l||(l=l.dependencies,l!==null&&je(l)&&(_l=!0))}function zs(l,t,u,a){V=l;var e=0;do{if(la&&(ta=null),La=0,la=!1,25<=e)throw Error(v(301));if(e+=1,bl=al=null,l.updateQueue!=null){var n=l.updateQueue;n.lastEffect=null,n.events=null,n.stores=null,n.memoCache!=null&&(n.memoCache.index=0)}S.H=Ho,n=t(u,a)}while(la);return n}function Mo(){var l=S.H,t=l.useState()[0];return t=typeof t.then=="function"?Ka(t):t,l=l.useState()[0],(al!==null?al.memoizedState:null)!==l&&(V.flags|=1024),t}function Gf(){var l=we!==0;return we=0,l}function xf(l,t,u){t.updateQueue=l.updateQueue,t.flags&=-2053,l.lanes&=~u}function Xf(l){if(Je){for(l=l.memoizedState;l!==null;){var t=l.queue;t!==null&&(t.pending=null),l=l.next}Je=!1}$t=0,bl=al=V=null,la=!1,La=we=0,ta=null}function Ll(){var l={memoizedState:null,baseState:null,baseQueue:null,queue:null,next:null};return bl===null?V.memoizedState=bl=l:bl=bl.next=l,bl}function Tl(){if(al===null){var l=V.alternate;l=l!==null?l.memoizedState:null}else l=al.next;var t=bl===null?V.memoizedState:bl.next;if(t!==null)bl=t,al=l;else{if(l===null)throw V.alternate===null?Error(v(467)):Error(v(310));al=l,l={memoizedState:al.memoizedState,baseState:al.baseState,baseQueue:al.baseQueue,queue:al.queue,next:null},bl===null?V.memoizedState=bl=l:bl=bl.next=l}return bl}function Qf(){return{lastEffect:null,events:null,stores:null,memoCache:null}}function Ka(l){var t=La;return La+=1,ta===null&&(ta=[]),l=ms(ta,l,t),t=V,(bl===null?t.memoizedState:bl.next)===null&&(t=t.alternate,S.H=t===null||t.memoizedState===null?e0:n0),l}function We(l){if(l!==null&&typeof l=="object"){if(typeof l.then=="function")return Ka(l);if(l.$$typeof===zl)return xl(l)}throw Error(v(438,String(l)))}function jf(l){var t=null,u=V.updateQueue;if(u!==null&&(t=u.memoCache),t==null){var a=V.alternate;a!==null&&(a=a.updateQueue,a!==null&&(a=a.memoCache,a!=null&&(t={data:a.data.map(function(e){return e.slice()}),index:0})))}if(t==null&&(t={data:[],index:0}),u===null&&(u=Qf(),V.updateQueue=u),u.memoCache=t,u=t.data[t.index],u===void 0)for(u=t.data[t.index]=Array(l),a=0;a<l;a++)u[a]=pu;return t.index++,u}function qt(l,t){return typeof t=="function"?t(l):t}function $e(l){var t=Tl();return Cf(t,al,l)}function Cf(l,t,u){var a=l.queue;if(a===null)throw Error(v(311));a.lastRenderedReducer=u;var e=l.baseQueue,n=a.pending;if(n!==null){if(e!==null){var f=e.next;e.next=n.next,n.next=f}t.baseQueue=e=n,a.pending=null}if(n=l.baseState,e===null)l.memoizedState=n;else{t=e.next;var c=f=null,i=null,h=t,g=!1;do{var E=h.lane&-536870913;if(E!==h.lane?(W&E)===E:($t&E)===E){var r=h.revertLane;if(r===0)i!==null&&(i=i.next={lane:0,revertLane:0,action:h.action,hasEagerState:h.hasEagerState,eagerState:h.eagerState,next:null}),E===Fu&&(g=!0);else if(($t&r)===r){h=h.next,r===Fu&&(g=!0);continue}else E={lane:0,revertLane:h.revertLane,action:h.action,hasEagerState:h.hasEagerState,eagerState:h.eagerState,next:null},i===null?(c=i=E,f=n):i=i.next=E,V.lanes|=r,uu|=r;E=h.action,Du&&u(n,E),n=h.hasEagerState?h.eagerState:u(n,E)}else r={lane:E,revertLane:h.revertLane,action:h.action,hasEagerState:h.hasEagerState,eagerState:h.eagerState,next:null},i===null?(c=i=r,f=n):i=i.next=r,V.lanes|=E,uu|=E;h=h.next}while(h!==null&&h!==t);if(i===null?f=n:i.next=c,!Il(n,l.memoizedState)&&(_l=!0,g&&(u=Iu,u!==null)))throw u;l.memoizedState=n,l.baseState=f,l.baseQueue=i,a.lastRenderedState=n}return e===null&&(a.lanes=0),[l.memoizedState,a.dispatch]}function Zf(l){var t=Tl(),u=t.queue;if(u===null)throw Error(v(311));u.lastRenderedReducer=l;var a=u.dispatch,e=u.pending,n=t.memoizedState;if(e!==null){u.pending=null;var f=e=e.next;do n=l(n,f.action),f=f.next;while(f!==e);Il(n,t.memoizedState)||(_l=!0),t.memoizedState=n,t.baseQueue===null&&(t.baseState=n),u.lastRenderedState=n}return[n,a]}function Os(l,t,u){var a=V,e=Tl(),n=P;if(n){if(u===void 0)throw Error(v(407));u=u()}else u=t();var f=!Il((al||e).memoizedState,u);f&&(e.memoizedState=u,_l=!0),e=e.queue;var c=Ds.bind(null,a,e,l);if(Ja(2048,8,c,[l]),e.getSnapshot!==t||f||bl!==null&&bl.memoizedState.tag&1){if(a.flags|=2048,ua(9,ke(),_s.bind(null,a,e,u,t),null),il===null)throw Error(v(349));n||($t&124)!==0||Ms(a,t,u)}return u}function Ms(l,t,u){l.flags|=16384,l={getSnapshot:t,value:u},t=V.updateQueue,t===null?(t=Qf(),V.updateQueue=t,t.stores=[l]):(u=t.stores,u===null?t.stores=[l]:u.push(l))}function _s(l,t,u,a){t.value=u,t.getSnapshot=a,Us(t)&&Rs(l)}function Ds(l,t,u){return u(function(){Us(t)&&Rs(l)})}function Us(l){var t=l.getSnapshot;l=l.value;try{var u=t();return!Il(l,u)}catch{return!0}}function Rs(l){var t=wu(l,2);t!==null&&et(t,l,2)}function Vf(l){var t=Ll();if(typeof l=="function"){var u=l;if(l=u(),Du){Zt(!0);try{u()}finally{Zt(!1)}}}return t.memoizedState=t.baseState=l,t.queue={pending:null,lanes:0,dispatch:null,lastRenderedReducer:qt,lastRenderedState:l},t}function Ns(l,t,u,a){return l.baseState=u,Cf(l,al,typeof a=="function"?a:qt)}function _o(l,t,u,a,e){if(Ie(l))throw Error(v(485));if(l=t.action,l!==null){var n={payload:e,action:l,next:null,isTransition:!0,status:"pending",value:null,reason:null,listeners:[],then:function(f){n.listeners.push(f)}};S.T!==null?u(!0):n.isTransition=!1,a(n),u=t.pending,u===null?(n.next=t.pending=n,Hs(t,n)):(n.next=u.next,t.pending=u.next=n)}}function Hs(l,t){var u=t.action,a=t.payload,e=l.state;if(t.isTransition){var n=S.T,f={};S.T=f;try{var c=u(e,a),i=S.S;i!==null&&i(f,c),ps(l,t,c)}catch(h){Lf(l,t,h)}finally{S.T=n}}else try{n=u(e,a),ps(l,t,n)}catch(h){Lf(l,t,h)}}function ps(l,t,u){u!==null&&typeof u=="object"&&typeof u.then=="function"?u.then(function(a){qs(l,t,a)},function(a){return Lf(l,t,a)}):qs(l,t,u)}function qs(l,t,u){t.status="fulfilled",t.value=u,Ys(t),l.state=u,t=l.pending,t!==null&&(u=t.next,u===t?l.pending=null:(u=u.next,t.next=u,Hs(l,u)))}function Lf(l,t,u){var a=l.pending;if(l.pending=null,a!==null){a=a.next;do t.status="rejected",t.reason=u,Ys(t),t=t.next;while(t!==a)}l.action=null}function Ys(l){l=l.listeners;for(var t=0;t<l.length;t++)(0,l[t])()}function Bs(l,t){return t}function Gs(l,t){if(P){var u=il.formState;if(u!==null){l:{var a=V;if(P){if(yl){t:{for(var e=yl,n=Tt;e.nodeType!==8;){if(!n){e=null;break t}if(e=gt(e.nextSibling),e===null){e=null;break t}}n=e.data,e=n==="F!"||n==="F"?e:null}if(e){yl=gt(e.nextSibling),a=e.data==="F!";break l}}zu(a)}a=!1}a&&(t=u[0])}}return u=Ll(),u.memoizedState=u.baseState=t,a={pending:null,lanes:0,dispatch:null,lastRenderedReducer:Bs,lastRenderedState:t},u.queue=a,u=t0.bind(null,V,a),a.dispatch=u,a=Vf(!1),n=$f.bind(null,V,!1,a.queue),a=Ll(),e={state:t,dispatch:null,action:l,pending:null},a.queue=e,u=_o.bind(null,V,e,n,u),e.dispatch=u,a.memoizedState=l,[t,u,!1]}function xs(l){var t=Tl();return Xs(t,al,l)}function Xs(l,t,u){if(t=Cf(l,t,Bs)[0],l=$e(qt)[0],typeof t=="object"&&t!==null&&typeof t.then=="function")try{var a=Ka(t)}catch(f){throw f===Qa?Ve:f}else a=t;t=Tl();var e=t.queue,n=e.dispatch;return u!==t.memoizedState&&(V.flags|=2048,ua(9,ke(),Do.bind(null,e,u),null)),[a,n,l]}function Do(l,t){l.action=t}function Qs(l){var t=Tl(),u=al;if(u!==null)return Xs(t,u,l);Tl(),t=t.memoizedState,u=Tl();var a=u.queue.dispatch;return u.memoizedState=l,[t,a,!1]}function ua(l,t,u,a){return l={tag:l,create:u,deps:a,inst:t,next:null},t=V.updateQueue,t===null&&(t=Qf(),V.updateQueue=t),u=t.lastEffect,u===null?t.lastEffect=l.next=l:(a=u.next,u.next=l,l.next=a,t.lastEffect=l),l}function ke(){return{destroy:void 0,resource:void 0}}function js(){return Tl().memoizedState}function Fe(l,t,u,a){var e=Ll();a=a===void 0?null:a,V.flags|=l,e.memoizedState=ua(1|t,ke(),u,a)}function Ja(l,t,u,a){var e=Tl();a=a===void 0?null:a;var n=e.memoizedState.inst;al!==null&&a!==null&&Yf(a,al.memoizedState.deps)?e.memoizedState=ua(t,n,u,a):(V.flags|=l,e.memoizedState=ua(1|t,n,u,a))}function Cs(l,t){Fe(8390656,8,l,t)}function Zs(l,t){Ja(2048,8,l,t)}function Vs(l,t){return Ja(4,2,l,t)}function Ls(l,t){return Ja(4,4,l,t)}function Ks(l,t){if(typeof t=="function"){l=l();var u=t(l);return function(){typeof u=="function"?u():t(null)}}if(t!=null)return l=l(),t.current=l,function(){t.current=null}}function Js(l,t,u){u=u!=null?u.concat([l]):null,Ja(4,4,Ks.bind(null,t,l),u)}function Kf(){}function ws(l,t){var u=Tl();t=t===void 0?null:t;var a=u.memoizedState;return t!==null&&Yf(t,a[1])?a[0]:(u.memoizedState=[l,t],l)}function Ws(l,t){var u=Tl();t=t===void 0?null:t;var a=u.memoizedState;if(t!==null&&Yf(t,a[1]))return a[0];if(a=l(),Du){Zt(!0);try{l()}finally{Zt(!1)}}return u.memoizedState=[a,t],a}function Jf(l,t,u){return u===void 0||($t&1073741824)!==0?l.memoizedState=t:(l.memoizedState=u,l=F0(),V.lanes|=l,uu|=l,u)}function $s(l,t,u,a){return Il(u,t)?u:Pu.current!==null?(l=Jf(l,u,a),Il(l,t)||(_l=!0),l):($t&42)===0?(_l=!0,l.memoizedState=u):(l=F0(),V.lanes|=l,uu|=l,t)}function ks(l,t,u,a,e){var n=_.p;_.p=n!==0&&8>n?n:8;var f=S.T,c={};S.T=c,$f(l,!1,t,u);try{var i=e(),h=S.S;if(h!==null&&h(c,i),i!==null&&typeof i=="object"&&typeof i.then=="function"){var g=zo(i,a);wa(l,t,g,at(l))}else wa(l,t,a,at(l))}catch(E){wa(l,t,{then:function(){},status:"rejected",reason:E},at())}finally{_.p=n,S.T=f}}function Uo(){}function wf(l,t,u,a){if(l.tag!==5)throw Error(v(476));var e=Fs(l).queue;ks(l,e,t,x,u===null?Uo:function(){return Is(l),u(a)})}function Fs(l){var t=l.memoizedState;if(t!==null)return t;t={memoizedState:x,baseState:x,baseQueue:null,queue:{pending:null,lanes:0,dispatch:null,lastRenderedReducer:qt,lastRenderedState:x},next:null};var u={};return t.next={memoizedState:u,baseState:u,baseQueue:null,queue:{pending:null,lanes:0,dispatch:null,lastRenderedReducer:qt,lastRenderedState:u},next:null},l.memoizedState=t,l=l.alternate,l!==null&&(l.memoizedState=t),t}function Is(l){var t=Fs(l).next.queue;wa(l,t,{},at())}function Wf(){return xl(ve)}function Ps(){return Tl().memoizedState}function l0(){return Tl().memoizedState}function Ro(l){for(var t=l.return;t!==null;){switch(t.tag){case 24:case 3:var u=at();l=wt(u);var a=Wt(t,l,u);a!==null&&(et(a,t,u),Ca(a,t,u)),t={cache:Of()},l.payload=t;return}t=t.return}}function No(l,t,u){var a=at();u={lane:a,revertLane:0,action:u,hasEagerState:!1,eagerState:null,next:null},Ie(l)?u0(t,u):(u=hf(l,t,u,a),u!==null&&(et(u,l,a),a0(u,t,a)))}function t0(l,t,u){var a=at();wa(l,t,u,a)}function wa(l,t,u,a){var e={lane:a,revertLane:0,action:u,hasEagerState:!1,eagerState:null,next:null};if(Ie(l))u0(t,e);else{var n=l.alternate;if(l.lanes===0&&(n===null||n.lanes===0)&&(n=t.lastRenderedReducer,n!==null))try{var f=t.lastRenderedState,c=n(f,u);if(e.hasEagerState=!0,e.eagerState=c,Il(c,f))return Be(l,t,e,0),il===null&&Ye(),!1}catch{}finally{}if(u=hf(l,t,e,a),u!==null)return et(u,l,a),a0(u,t,a),!0}return!1}function $f(l,t,u,a){if(a={lane:2,revertLane:Dc(),action:a,hasEagerState:!1,eagerState:null,next:null},Ie(l)){if(t)throw Error(v(479))}else t=hf(l,u,a,2),t!==null&&et(t,l,2)}function Ie(l){var t=l.alternate;return l===V||t!==null&&t===V}function u0(l,t){la=Je=!0;var u=l.pending;u===null?t.next=t:(t.next=u.next,u.next=t),l.pending=t}function a0(l,t,u){if((u&4194048)!==0){var a=t.lanes;a&=l.pendingLanes,u|=a,t.lanes=u,vi(l,u)}}var Pe={readContext:xl,use:We,useCallback:gl,useContext:gl,useEffect:gl,useImperativeHandle:gl,useLayoutEffect:gl,useInsertionEffect:gl,useMemo:gl,useReducer:gl,useRef:gl,useState:gl,useDebugValue:gl,useDeferredValue:gl,useTransition:gl,useSyncExternalStore:gl,useId:gl,useHostTransitionStatus:gl,useFormState:gl,useActionState:gl,useOptimistic:gl,useMemoCache:gl,useCacheRefresh:gl},e0={readContext:xl,use:We,useCallback:function(l,t){return Ll().memoizedState=[l,t===void 0?null:t],l},useContext:xl,useEffect:Cs,useImperativeHandle:function(l,t,u){u=u!=null?u.concat([l]):null,Fe(4194308,4,Ks.bind(null,t,l),u)},useLayoutEffect:function(l,t){return Fe(4194308,4,l,t)},useInsertionEffect:function(l,t){Fe(4,2,l,t)},useMemo:function(l,t){var u=Ll();t=t===void 0?null:t;var a=l();if(Du){Zt(!0);try{l()}finally{Zt(!1)}}return u.memoizedState=[a,t],a},useReducer:function(l,t,u){var a=Ll();if(u!==void 0){var e=u(t);if(Du){Zt(!0);try{u(t)}finally{Zt(!1)}}}else e=t;return a.memoizedState=a.baseState=e,l={pending:null,lanes:0,dispatch:null,lastRenderedReducer:l,lastRenderedState:e},a.queue=l,l=l.dispatch=No.bind(null,V,l),[a.memoizedState,l]},useRef:function(l){var t=Ll();return l={current:l},t.memoizedState=l},useState:function(l){l=Vf(l);var t=l.queue,u=t0.bind(null,V,t);return t.dispatch=u,[l.memoizedState,u]},useDebugValue:Kf,useDeferredValue:function(l,t){var u=Ll();return Jf(u,l,t)},useTransition:function(){var l=Vf(!1);return l=ks.bind(null,V,l.queue,!0,!1),Ll().memoizedState=l,[!1,l]},useSyncExternalStore:function(l,t,u){var a=V,e=Ll();if(P){if(u===void 0)throw Error(v(407));u=u()}else{if(u=t(),il===null)throw Error(v(349));(W&124)!==0||Ms(a,t,u)}e.memoizedState=u;var n={value:u,getSnapshot:t};return e.queue=n,Cs(Ds.bind(null,a,n,l),[l]),a.flags|=2048,ua(9,ke(),_s.bind(null,a,n,u,t),null),u},useId:function(){var l=Ll(),t=il.identifierPrefix;if(P){var u=Nt,a=Rt;u=(a&~(1<<32-Fl(a)-1)).toString(32)+u,t="«"+t+"R"+u,u=we++,0<u&&(t+="H"+u.toString(32)),t+="»"}else u=Oo++,t="«"+t+"r"+u.toString(32)+"»";return l.memoizedState=t},useHostTransitionStatus:Wf,useFormState:Gs,useActionState:Gs,useOptimistic:function(l){var t=Ll();t.memoizedState=t.baseState=l;var u={pending:null,lanes:0,dispatch:null,lastRenderedReducer:null,lastRenderedState:null};return t.queue=u,t=$f.bind(null,V,!0,u),u.dispatch=t,[l,t]},useMemoCache:jf,useCacheRefresh:function(){return Ll().memoizedState=Ro.bind(null,V)}},n0={readContext:xl,use:We,useCallback:ws,useContext:xl,useEffect:Zs,useImperativeHandle:Js,useInsertionEffect:Vs,useLayoutEffect:Ls,useMemo:Ws,useReducer:$e,useRef:js,useState:function(){return $e(qt)},useDebugValue:Kf,useDeferredValue:function(l,t){var u=Tl();return $s(u,al.memoizedState,l,t)},useTransition:function(){var l=$e(qt)[0],t=Tl().memoizedState;return[typeof l=="boolean"?l:Ka(l),t]},useSyncExternalStore:Os,useId:Ps,useHostTransitionStatus:Wf,useFormState:xs,useActionState:xs,useOptimistic:function(l,t){var u=Tl();return Ns(u,al,l,t)},useMemoCache:jf,useCacheRefresh:l0},Ho={readContext:xl,use:We,useCallback:ws,useContext:xl,useEffect:Zs,useImperativeHandle:Js,useInsertionEffect:Vs,useLayoutEffect:Ls,useMemo:Ws,useReducer:Zf,useRef:js,useState:function(){return Zf(qt)},useDebugValue:Kf,useDeferredValue:function(l,t){var u=Tl();return al===null?Jf(u,l,t):$s(u,al.memoizedState,l,t)},useTransition:function(){var l=Zf(qt)[0],t=Tl().memoizedState;return[typeof l=="boolean"?l:Ka(l),t]},useSyncExternalStore:Os,useId:Ps,useHostTransitionStatus:Wf,useFormState:Qs,useActionState:Qs,useOptimistic:function(l,t){var u=Tl();return al!==null?Ns(u,al,l,t):(u.baseState=l,[l,u.queue.dispatch])},useMemoCache:jf,useCacheRefresh:l0},aa=null,Wa=0;function ln(l){var t=Wa;return Wa+=1,aa===null&&(aa=[]),ms(aa,l,t)}function $a(l,t){t=t.props.ref,l.ref=t!==void 0?t:null}function tn(l,t){throw t.$$typeof===I?Error(v(525)):(l=Object.prototype.toString.call(t),Error(v(31,l==="[object Object]"?"object with keys {"+Object.keys(t).join(", ")+"}":l)))}function f0(l){var t=l._init;return t(l._payload)}function c0(l){function t(o,d){if(l){var y=o.deletions;y===null?(o.deletions=[d],o.flags|=16):y.push(d)}}function u(o,d){if(!l)return null;for(;d!==null;)t(o,d),d=d.sibling;return null}function a(o){for(var d=new Map;o!==null;)o.key!==null?d.set(o.key,o):d.set(o.index,o),o=o.sibling;return d}function e(o,d){return o=Ut(o,d),o.index=0,o.sibling=null,o}function n(o,d,y){return o.index=y,l?(y=o.alternate,y!==null?(y=y.index,y<d?(o.flags|=67108866,d):y):(o.flags|=67108866,d)):(o.flags|=1048576,d)}function f(o){return l&&o.alternate===null&&(o.flags|=67108866),o}function c(o,d,y,b){return d===null||d.tag!==6?(d=mf(y,o.mode,b),d.return=o,d):(d=e(d,y),d.return=o,d)}function i(o,d,y,b){var H=y.type;return H===ml?g(o,d,y.props.children,b,y.key):d!==null&&(d.elementType===H||typeof H=="object"&&H!==null&&H.$$typeof===ol&&f0(H)===d.type)?(d=e(d,y.props),$a(d,y),d.return=o,d):(d=xe(y.type,y.key,y.props,null,o.mode,b),$a(d,y),d.return=o,d)}function h(o,d,y,b){return d===null||d.tag!==4||d.stateNode.containerInfo!==y.containerInfo||d.stateNode.implementation!==y.implementation?(d=gf(y,o.mode,b),d.return=o,d):(d=e(d,y.children||[]),d.return=o,d)}function g(o,d,y,b,H){return d===null||d.tag!==7?(d=bu(y,o.mode,b,H),d.return=o,d):(d=e(d,y),d.return=o,d)}function E(o,d,y){if(typeof d=="string"&&d!==""||typeof d=="number"||typeof d=="bigint")return d=mf(""+d,o.mode,y),d.return=o,d;if(typeof d=="object"&&d!==null){switch(d.$$typeof){case ul:return y=xe(d.type,d.key,d.props,null,o.mode,y),$a(y,d),y.return=o,y;case rl:return d=gf(d,o.mode,y),d.return=o,d;case ol:var b=d._init;return d=b(d._payload),E(o,d,y)}if(Bl(d)||Yl(d))return d=bu(d,o.mode,y,null),d.return=o,d;if(typeof d.then=="function")return E(o,ln(d),y);if(d.$$typeof===zl)return E(o,Ce(o,d),y);tn(o,d)}return null}function r(o,d,y,b){var H=d!==null?d.key:null;if(typeof y=="string"&&y!==""||typeof y=="number"||typeof y=="bigint")return H!==null?null:c(o,d,""+y,b);if(typeof y=="object"&&y!==null){switch(y.$$typeof){case ul:return y.key===H?i(o,d,y,b):null;case rl:return y.key===H?h(o,d,y,b):null;case ol:return H=y._init,y=H(y._payload),r(o,d,y,b)}if(Bl(y)||Yl(y))return H!==null?null:g(o,d,y,b,null);if(typeof y.then=="function")return r(o,d,ln(y),b);if(y.$$typeof===zl)return r(o,d,Ce(o,y),b);tn(o,y)}return null}function m(o,d,y,b,H){if(typeof b=="string"&&b!==""||typeof b=="number"||typeof b=="bigint")return o=o.get(y)||null,c(d,o,""+b,H);if(typeof b=="object"&&b!==null){switch(b.$$typeof){case ul:return o=o.get(b.key===null?y:b.key)||null,i(d,o,b,H);case rl:return o=o.get(b.key===null?y:b.key)||null,h(d,o,b,H);case ol:var L=b._init;return b=L(b._payload),m(o,d,y,b,H)}if(Bl(b)||Yl(b))return o=o.get(y)||null,g(d,o,b,H,null);if(typeof b.then=="function")return m(o,d,y,ln(b),H);if(b.$$typeof===zl)return m(o,d,y,Ce(d,b),H);tn(d,b)}return null}function Q(o,d,y,b){for(var H=null,L=null,q=d,G=d=0,Ul=null;q!==null&&G<y.length;G++){q.index>G?(Ul=q,q=null):Ul=q.sibling;var F=r(o,q,y[G],b);if(F===null){q===null&&(q=Ul);break}l&&q&&F.alternate===null&&t(o,q),d=n(F,d,G),L===null?H=F:L.sibling=F,L=F,q=Ul}if(G===y.length)return u(o,q),P&&Eu(o,G),H;if(q===null){for(;G<y.length;G++)q=E(o,y[G],b),q!==null&&(d=n(q,d,G),L===null?H=q:L.sibling=q,L=q);return P&&Eu(o,G),H}for(q=a(q);G<y.length;G++)Ul=m(q,o,G,y[G],b),Ul!==null&&(l&&Ul.alternate!==null&&q.delete(Ul.key===null?G:Ul.key),d=n(Ul,d,G),L===null?H=Ul:L.sibling=Ul,L=Ul);return l&&q.forEach(function(vu){return t(o,vu)}),P&&Eu(o,G),H}function B(o,d,y,b){if(y==null)throw Error(v(151));for(var H=null,L=null,q=d,G=d=0,Ul=null,F=y.next();q!==null&&!F.done;G++,F=y.next()){q.index>G?(Ul=q,q=null):Ul=q.sibling;var vu=r(o,q,F.value,b);if(vu===null){q===null&&(q=Ul);break}l&&q&&vu.alternate===null&&t(o,q),d=n(vu,d,G),L===null?H=vu:L.sibling=vu,L=vu,q=Ul}if(F.done)return u(o,q),P&&Eu(o,G),H;if(q===null){for(;!F.done;G++,F=y.next())F=E(o,F.value,b),F!==null&&(d=n(F,d,G),L===null?H=F:L.sibling=F,L=F);return P&&Eu(o,G),H}for(q=a(q);!F.done;G++,F=y.next())F=m(q,o,G,F.value,b),F!==null&&(l&&F.alternate!==null&&q.delete(F.key===null?G:F.key),d=n(F,d,G),L===null?H=F:L.sibling=F,L=F);return l&&q.forEach(function(py){return t(o,py)}),P&&Eu(o,G),H}function nl(o,d,y,b){if(typeof y=="object"&&y!==null&&y.type===ml&&y.key===null&&(y=y.props.children),typeof y=="object"&&y!==null){switch(y.$$typeof){case ul:l:{for(var H=y.key;d!==null;){if(d.key===H){if(H=y.type,H===ml){if(d.tag===7){u(o,d.sibling),b=e(d,y.props.children),b.return=o,o=b;break l}}else if(d.elementType===H||typeof H=="object"&&H!==null&&H.$$typeof===ol&&f0(H)===d.type){u(o,d.sibling),b=e(d,y.props),$a(b,y),b.return=o,o=b;break l}u(o,d);break}else t(o,d);d=d.sibling}y.type===ml?(b=bu(y.props.children,o.mode,b,y.key),b.return=o,o=b):(b=xe(y.type,y.key,y.props,null,o.mode,b),$a(b,y),b.return=o,o=b)}return f(o);case rl:l:{for(H=y.key;d!==null;){if(d.key===H)if(d.tag===4&&d.stateNode.containerInfo===y.containerInfo&&d.stateNode.implementation===y.implementation){u(o,d.sibling),b=e(d,y.children||[]),b.return=o,o=b;break l}else{u(o,d);break}else t(o,d);d=d.sibling}b=gf(y,o.mode,b),b.return=o,o=b}return f(o);case ol:return H=y._init,y=H(y._payload),nl(o,d,y,b)}if(Bl(y))return Q(o,d,y,b);if(Yl(y)){if(H=Yl(y),typeof H!="function")throw Error(v(150));return y=H.call(y),B(o,d,y,b)}if(typeof y.then=="function")return nl(o,d,ln(y),b);if(y.$$typeof===zl)return nl(o,d,Ce(o,y),b);tn(o,y)}return typeof y=="string"&&y!==""||typeof y=="number"||typeof y=="bigint"?(y=""+y,d!==null&&d.tag===6?(u(o,d.sibling),b=e(d,y),b.return=o,o=b):(u(o,d),b=mf(y,o.mode,b),b.return=o,o=b),f(o)):u(o,d)}return function(o,d,y,b){try{Wa=0;var H=nl(o,d,y,b);return aa=null,H}catch(q){if(q===Qa||q===Ve)throw q;var L=Pl(29,q,null,o.mode);return L.lanes=b,L.return=o,L}finally{}}}var ea=c0(!0),i0=c0(!1),vt=A(null),Et=null;function kt(l){var t=l.alternate;M(Al,Al.current&1),M(vt,l),Et===null&&(t===null||Pu.current!==null||t.memoizedState!==null)&&(Et=l)}function s0(l){if(l.tag===22){if(M(Al,Al.current),M(vt,l),Et===null){var t=l.alternate;t!==null&&t.memoizedState!==null&&(Et=l)}}else Ft()}function Ft(){M(Al,Al.current),M(vt,vt.current)}function Yt(l){R(vt),Et===l&&(Et=null),R(Al)}var Al=A(0);function un(l){for(var t=l;t!==null;){if(t.tag===13){var u=t.memoizedState;if(u!==null&&(u=u.dehydrated,u===null||u.data==="$?"||Qc(u)))return t}else if(t.tag===19&&t.memoizedProps.revealOrder!==void 0){if((t.flags&128)!==0)return t}else if(t.child!==null){t.child.return=t,t=t.child;continue}if(t===l)break;for(;t.sibling===null;){if(t.return===null||t.return===l)return null;t=t.return}t.sibling.return=t.return,t=t.sibling}return null}function kf(l,t,u,a){t=l.memoizedState,u=u(a,t),u=u==null?t:p({},t,u),l.memoizedState=u,l.lanes===0&&(l.updateQueue.baseState=u)}var Ff={enqueueSetState:function(l,t,u){l=l._reactInternals;var a=at(),e=wt(a);e.payload=t,u!=null&&(e.callback=u),t=Wt(l,e,a),t!==null&&(et(t,l,a),Ca(t,l,a))},enqueueReplaceState:function(l,t,u){l=l._reactInternals;var a=at(),e=wt(a);e.tag=1,e.payload=t,u!=null&&(e.callback=u),t=Wt(l,e,a),t!==null&&(et(t,l,a),Ca(t,l,a))},enqueueForceUpdate:function(l,t){l=l._reactInternals;var u=at(),a=wt(u);a.tag=2,t!=null&&(a.callback=t),t=Wt(l,a,u),t!==null&&(et(t,l,u),Ca(t,l,u))}};function d0(l,t,u,a,e,n,f){return l=l.stateNode,typeof l.shouldComponentUpdate=="function"?l.shouldComponentUpdate(a,n,f):t.prototype&&t.prototype.isPureReactComponent?!Ha(u,a)||!Ha(e,n):!0}function v0(l,t,u,a){l=t.state,typeof t.componentWillReceiveProps=="function"&&t.componentWillReceiveProps(u,a),typeof t.UNSAFE_componentWillReceiveProps=="function"&&t.UNSAFE_componentWillReceiveProps(u,a),t.state!==l&&Ff.enqueueReplaceState(t,t.state,null)}function Uu(l,t){var u=t;if("ref"in t){u={};for(var a in t)a!=="ref"&&(u[a]=t[a])}if(l=l.defaultProps){u===t&&(u=p({},u));for(var e in l)u[e]===void 0&&(u[e]=l[e])}return u}var an=typeof reportError=="function"?reportError:function(l){if(typeof window=="object"&&typeof window.ErrorEvent=="function"){var t=new window.ErrorEvent("error",{bubbles:!0,cancelable:!0,message:typeof l=="object"&&l!==null&&typeof l.message=="string"?String(l.message):String(l),error:l});if(!window.dispatchEvent(t))return}else if(typeof process=="object"&&typeof process.emit=="function"){process.emit("uncaughtException",l);return}console.error(l)};function o0(l){an(l)}function y0(l){console.error(l)}function h0(l){an(l)}function en(l,t){try{var u=l.onUncaughtError;u(t.value,{componentStack:t.stack})}catch(a){setTimeout(function(){throw a})}}function r0(l,t,u){try{var a=l.onCaughtError;a(u.value,{componentStack:u.stack,errorBoundary:t.tag===1?t.stateNode:null})}catch(e){setTimeout(function(){throw e})}}function If(l,t,u){return u=wt(u),u.tag=3,u.payload={element:null},u.callback=function(){en(l,t)},u}function m0(l){return l=wt(l),l.tag=3,l}function g0(l,t,u,a){var e=u.type.getDerivedStateFromError;if(typeof e=="function"){var n=a.value;l.payload=function(){return e(n)},l.callback=function(){r0(t,u,a)}}var f=u.stateNode;f!==null&&typeof f.componentDidCatch=="function"&&(l.callback=function(){r0(t,u,a),typeof e!="function"&&(au===null?au=new Set([this]):au.add(this));var c=a.stack;this.componentDidCatch(a.value,{componentStack:c!==null?c:""})})}function po(l,t,u,a,e){if(u.flags|=32768,a!==null&&typeof a=="object"&&typeof a.then=="function"){if(t=u.alternate,t!==null&&Ga(t,u,e,!0),u=vt.current,u!==null){switch(u.tag){case 13:return Et===null?Ac():u.alternate===null&&hl===0&&(hl=3),u.flags&=-257,u.flags|=65536,u.lanes=e,a===Df?u.flags|=16384:(t=u.updateQueue,t===null?u.updateQueue=new Set([a]):t.add(a),Oc(l,a,e)),!1;case 22:return u.flags|=65536,a===Df?u.flags|=16384:(t=u.updateQueue,t===null?(t={transitions:null,markerInstances:null,retryQueue:new Set([a])},u.updateQueue=t):(u=t.retryQueue,u===null?t.retryQueue=new Set([a]):u.add(a)),Oc(l,a,e)),!1}throw Error(v(435,u.tag))}return Oc(l,a,e),Ac(),!1}if(P)return t=vt.current,t!==null?((t.flags&65536)===0&&(t.flags|=256),t.flags|=65536,t.lanes=e,a!==Tf&&(l=Error(v(422),{cause:a}),Ba(ct(l,u)))):(a!==Tf&&(t=Error(v(423),{cause:a}),Ba(ct(t,u))),l=l.current.alternate,l.flags|=65536,e&=-e,l.lanes|=e,a=ct(a,u),e=If(l.stateNode,a,e),Nf(l,e),hl!==4&&(hl=2)),!1;var n=Error(v(520),{cause:a});if(n=ct(n,u),ue===null?ue=[n]:ue.push(n),hl!==4&&(hl=2),t===null)return!0;a=ct(a,u),u=t;do{switch(u.tag){case 3:return u.flags|=65536,l=e&-e,u.lanes|=l,l=If(u.stateNode,a,l),Nf(u,l),!1;case 1:if(t=u.type,n=u.stateNode,(u.flags&128)===0&&(typeof t.getDerivedStateFromError=="function"||n!==null&&typeof n.componentDidCatch=="function"&&(au===null||!au.has(n))))return u.flags|=65536,e&=-e,u.lanes|=e,e=m0(e),g0(e,l,u,a),Nf(u,e),!1}u=u.return}while(u!==null);return!1}var S0=Error(v(461)),_l=!1;function Hl(l,t,u,a){t.child=l===null?i0(t,null,u,a):ea(t,l.child,u,a)}function b0(l,t,u,a,e){u=u.render;var n=t.ref;if("ref"in a){var f={};for(var c in a)c!=="ref"&&(f[c]=a[c])}else f=a;return Mu(t),a=Bf(l,t,u,f,n,e),c=Gf(),l!==null&&!_l?(xf(l,t,e),Bt(l,t,e)):(P&&c&&Sf(t),t.flags|=1,Hl(l,t,a,e),t.child)}function T0(l,t,u,a,e){if(l===null){var n=u.type;return typeof n=="function"&&!rf(n)&&n.defaultProps===void 0&&u.compare===null?(t.tag=15,t.type=n,E0(l,t,n,a,e)):(l=xe(u.type,null,a,t,t.mode,e),l.ref=t.ref,l.return=t,t.child=l)}if(n=l.child,!fc(l,e)){var f=n.memoizedProps;if(u=u.compare,u=u!==null?u:Ha,u(f,a)&&l.ref===t.ref)return Bt(l,t,e)}return t.flags|=1,l=Ut(n,a),l.ref=t.ref,l.return=t,t.child=l}function E0(l,t,u,a,e){if(l!==null){var n=l.memoizedProps;if(Ha(n,a)&&l.ref===t.ref)if(_l=!1,t.pendingProps=a=n,fc(l,e))(l.flags&131072)!==0&&(_l=!0);else return t.lanes=l.lanes,Bt(l,t,e)}return Pf(l,t,u,a,e)}function A0(l,t,u){var a=t.pendingProps,e=a.children,n=l!==null?l.memoizedState:null;if(a.mode==="hidden"){if((t.flags&128)!==0){if(a=n!==null?n.baseLanes|u:u,l!==null){for(e=t.child=l.child,n=0;e!==null;)n=n|e.lanes|e.childLanes,e=e.sibling;t.childLanes=n&~a}else t.childLanes=0,t.child=null;return z0(l,t,a,u)}if((u&536870912)!==0)t.memoizedState={baseLanes:0,cachePool:null},l!==null&&Ze(t,n!==null?n.cachePool:null),n!==null?Es(t,n):pf(),s0(t);else return t.lanes=t.childLanes=536870912,z0(l,t,n!==null?n.baseLanes|u:u,u)}else n!==null?(Ze(t,n.cachePool),Es(t,n),Ft(),t.memoizedState=null):(l!==null&&Ze(t,null),pf(),Ft());return Hl(l,t,e,u),t.child}function z0(l,t,u,a){var e=_f();return e=e===null?null:{parent:El._currentValue,pool:e},t.memoizedState={baseLanes:u,cachePool:e},l!==null&&Ze(t,null),pf(),s0(t),l!==null&&Ga(l,t,a,!0),null}function nn(l,t){var u=t.ref;if(u===null)l!==null&&l.ref!==null&&(t.flags|=4194816);else{if(typeof u!="function"&&typeof u!="object")throw Error(v(284));(l===null||l.ref!==u)&&(t.flags|=4194816)}}function Pf(l,t,u,a,e){return Mu(t),u=Bf(l,t,u,a,void 0,e),a=Gf(),l!==null&&!_l?(xf(l,t,e),Bt(l,t,e)):(P&&a&&Sf(t),t.flags|=1,Hl(l,t,u,e),t.child)}function O0(l,t,u,a,e,n){return Mu(t),t.updateQueue=null,u=zs(t,a,u,e),As(l),a=Gf(),l!==null&&!_l?(xf(l,t,n),Bt(l,t,n)):(P&&a&&Sf(t),t.flags|=1,Hl(l,t,u,n),t.child)}function M0(l,t,u,a,e){if(Mu(t),t.stateNode===null){var n=Wu,f=u.contextType;typeof f=="object"&&f!==null&&(n=xl(f)),n=new u(a,n),t.memoizedState=n.state!==null&&n.state!==void 0?n.state:null,n.updater=Ff,t.stateNode=n,n._reactInternals=t,n=t.stateNode,n.props=a,n.state=t.memoizedState,n.refs={},Uf(t),f=u.contextType,n.context=typeof f=="object"&&f!==null?xl(f):Wu,n.state=t.memoizedState,f=u.getDerivedStateFromProps,typeof f=="function"&&(kf(t,u,f,a),n.state=t.memoizedState),typeof u.getDerivedStateFromProps=="function"||typeof n.getSnapshotBeforeUpdate=="function"||typeof n.UNSAFE_componentWillMount!="function"&&typeof n.componentWillMount!="function"||(f=n.state,typeof n.componentWillMount=="function"&&n.componentWillMount(),typeof n.UNSAFE_componentWillMount=="function"&&n.UNSAFE_componentWillMount(),f!==n.state&&Ff.enqueueReplaceState(n,n.state,null),Va(t,a,n,e),Za(),n.state=t.memoizedState),typeof n.componentDidMount=="function"&&(t.flags|=4194308),a=!0}else if(l===null){n=t.stateNode;var c=t.memoizedProps,i=Uu(u,c);n.props=i;var h=n.context,g=u.contextType;f=Wu,typeof g=="object"&&g!==null&&(f=xl(g));var E=u.getDerivedStateFromProps;g=typeof E=="function"||typeof n.getSnapshotBeforeUpdate=="function",c=t.pendingProps!==c,g||typeof n.UNSAFE_componentWillReceiveProps!="function"&&typeof n.componentWillReceiveProps!="function"||(c||h!==f)&&v0(t,n,a,f),Jt=!1;var r=t.memoizedState;n.state=r,Va(t,a,n,e),Za(),h=t.memoizedState,c||r!==h||Jt?(typeof E=="function"&&(kf(t,u,E,a),h=t.memoizedState),(i=Jt||d0(t,u,i,a,r,h,f))?(g||typeof n.UNSAFE_componentWillMount!="function"&&typeof n.componentWillMount!="function"||(typeof n.componentWillMount=="function"&&n.componentWillMount(),typeof n.UNSAFE_componentWillMount=="function"&&n.UNSAFE_componentWillMount()),typeof n.componentDidMount=="function"&&(t.flags|=4194308)):(typeof n.componentDidMount=="function"&&(t.flags|=4194308),t.memoizedProps=a,t.memoizedState=h),n.props=a,n.state=h,n.context=f,a=i):(typeof n.componentDidMount=="function"&&(t.flags|=4194308),a=!1)}else{n=t.stateNode,Rf(l,t),f=t.memoizedProps,g=Uu(u,f),n.props=g,E=t.pendingProps,r=n.context,h=u.contextType,i=Wu,typeof h=="object"&&h!==null&&(i=xl(h)),c=u.getDerivedStateFromProps,(h=typeof c=="function"||typeof n.getSnapshotBeforeUpdate=="function")||typeof n.UNSAFE_componentWillReceiveProps!="function"&&typeof n.componentWillReceiveProps!="function"||(f!==E||r!==i)&&v0(t,n,a,i),Jt=!1,r=t.memoizedState,n.state=r,Va(t,a,n,e),Za();var m=t.memoizedState;f!==E||r!==m||Jt||l!==null&&l.dependencies!==null&&je(l.dependencies)?(typeof c=="function"&&(kf(t,u,c,a),m=t.memoizedState),(g=Jt||d0(t,u,g,a,r,m,i)||l!==null&&l.dependencies!==null&&je(l.dependencies))?(h||typeof n.UNSAFE_componentWillUpdate!="function"&&typeof n.componentWillUpdate!="function"||(typeof n.componentWillUpdate=="function"&&n.componentWillUpdate(a,m,i),typeof n.UNSAFE_componentWillUpdate=="function"&&n.UNSAFE_componentWillUpdate(a,m,i)),typeof n.componentDidUpdate=="function"&&(t.flags|=4),typeof n.getSnapshotBeforeUpdate=="function"&&(t.flags|=1024)):(typeof n.componentDidUpdate!="function"||f===l.memoizedProps&&r===l.memoizedState||(t.flags|=4),typeof n.getSnapshotBeforeUpdate!="function"||f===l.memoizedProps&&r===l.memoizedState||(t.flags|=1024),t.memoizedProps=a,t.memoizedState=m),n.props=a,n.state=m,n.context=i,a=g):(typeof n.componentDidUpdate!="function"||f===l.memoizedProps&&r===l.memoizedState||(t.flags|=4),typeof n.getSnapshotBeforeUpdate!="function"||f===l.memoizedProps&&r===l.memoizedState||(t.flags|=1024),a=!1)}return n=a,nn(l,t),a=(t.flags&128)!==0,n||a?(n=t.stateNode,u=a&&typeof u.getDerivedStateFromError!="function"?null:n.render(),t.flags|=1,l!==null&&a?(t.child=ea(t,l.child,null,e),t.child=ea(t,null,u,e)):Hl(l,t,u,e),t.memoizedState=n.state,l=t.child):l=Bt(l,t,e),l}function _0(l,t,u,a){return Ya(),t.flags|=256,Hl(l,t,u,a),t.child}var lc={dehydrated:null,treeContext:null,retryLane:0,hydrationErrors:null};function tc(l){return{baseLanes:l,cachePool:ys()}}function uc(l,t,u){return l=l!==null?l.childLanes&~u:0,t&&(l|=ot),l}function D0(l,t,u){var a=t.pendingProps,e=!1,n=(t.flags&128)!==0,f;if((f=n)||(f=l!==null&&l.memoizedState===null?!1:(Al.current&2)!==0),f&&(e=!0,t.flags&=-129),f=(t.flags&32)!==0,t.flags&=-33,l===null){if(P){if(e?kt(t):Ft(),P){var c=yl,i;if(i=c){l:{for(i=c,c=Tt;i.nodeType!==8;){if(!c){c=null;break l}if(i=gt(i.nextSibling),i===null){c=null;break l}}c=i}c!==null?(t.memoizedState={dehydrated:c,treeContext:Tu!==null?{id:Rt,overflow:Nt}:null,retryLane:536870912,hydrationErrors:null},i=Pl(18,null,null,0),i.stateNode=c,i.return=t,t.child=i,jl=t,yl=null,i=!0):i=!1}i||zu(t)}if(c=t.memoizedState,c!==null&&(c=c.dehydrated,c!==null))return Qc(c)?t.lanes=32:t.lanes=536870912,null;Yt(t)}return c=a.children,a=a.fallback,e?(Ft(),e=t.mode,c=fn({mode:"hidden",children:c},e),a=bu(a,e,u,null),c.return=t,a.return=t,c.sibling=a,t.child=c,e=t.child,e.memoizedState=tc(u),e.childLanes=uc(l,f,u),t.memoizedState=lc,a):(kt(t),ac(t,c))}if(i=l.memoizedState,i!==null&&(c=i.dehydrated,c!==null)){if(n)t.flags&256?(kt(t),t.flags&=-257,t=ec(l,t,u)):t.memoizedState!==null?(Ft(),t.child=l.child,t.flags|=128,t=null):(Ft(),e=a.fallback,c=t.mode,a=fn({mode:"visible",children:a.children},c),e=bu(e,c,u,null),e.flags|=2,a.return=t,e.return=t,a.sibling=e,t.child=a,ea(t,l.child,null,u),a=t.child,a.memoizedState=tc(u),a.childLanes=uc(l,f,u),t.memoizedState=lc,t=e);else if(kt(t),Qc(c)){if(f=c.nextSibling&&c.nextSibling.dataset,f)var h=f.dgst;f=h,a=Error(v(419)),a.stack="",a.digest=f,Ba({value:a,source:null,stack:null}),t=ec(l,t,u)}else if(_l||Ga(l,t,u,!1),f=(u&l.childLanes)!==0,_l||f){if(f=il,f!==null&&(a=u&-u,a=(a&42)!==0?1:Qn(a),a=(a&(f.suspendedLanes|u))!==0?0:a,a!==0&&a!==i.retryLane))throw i.retryLane=a,wu(l,a),et(f,l,a),S0;c.data==="$?"||Ac(),t=ec(l,t,u)}else c.data==="$?"?(t.flags|=192,t.child=l.child,t=null):(l=i.treeContext,yl=gt(c.nextSibling),jl=t,P=!0,Au=null,Tt=!1,l!==null&&(st[dt++]=Rt,st[dt++]=Nt,st[dt++]=Tu,Rt=l.id,Nt=l.overflow,Tu=t),t=ac(t,a.children),t.flags|=4096);return t}return e?(Ft(),e=a.fallback,c=t.mode,i=l.child,h=i.sibling,a=Ut(i,{mode:"hidden",children:a.children}),a.subtreeFlags=i.subtreeFlags&65011712,h!==null?e=Ut(h,e):(e=bu(e,c,u,null),e.flags|=2),e.return=t,a.return=t,a.sibling=e,t.child=a,a=e,e=t.child,c=l.child.memoizedState,c===null?c=tc(u):(i=c.cachePool,i!==null?(h=El._currentValue,i=i.parent!==h?{parent:h,pool:h}:i):i=ys(),c={baseLanes:c.baseLanes|u,cachePool:i}),e.memoizedState=c,e.childLanes=uc(l,f,u),t.memoizedState=lc,a):(kt(t),u=l.child,l=u.sibling,u=Ut(u,{mode:"visible",children:a.children}),u.return=t,u.sibling=null,l!==null&&(f=t.deletions,f===null?(t.deletions=[l],t.flags|=16):f.push(l)),t.child=u,t.memoizedState=null,u)}function ac(l,t){return t=fn({mode:"visible",children:t},l.mode),t.return=l,l.child=t}function fn(l,t){return l=Pl(22,l,null,t),l.lanes=0,l.stateNode={_visibility:1,_pendingMarkers:null,_retryCache:null,_transitions:null},l}function ec(l,t,u){return ea(t,l.child,null,u),l=ac(t,t.pendingProps.children),l.flags|=2,t.memoizedState=null,l}function U0(l,t,u){l.lanes|=t;var a=l.alternate;a!==null&&(a.lanes|=t),Af(l.return,t,u)}function nc(l,t,u,a,e){var n=l.memoizedState;n===null?l.memoizedState={isBackwards:t,rendering:null,renderingStartTime:0,last:a,tail:u,tailMode:e}:(n.isBackwards=t,n.rendering=null,n.renderingStartTime=0,n.last=a,n.tail=u,n.tailMode=e)}function R0(l,t,u){var a=t.pendingProps,e=a.revealOrder,n=a.tail;if(Hl(l,t,a.children,u),a=Al.current,(a&2)!==0)a=a&1|2,t.flags|=128;else{if(l!==null&&(l.flags&128)!==0)l:for(l=t.child;l!==null;){if(l.tag===13)l.memoizedState!==null&&U0(l,u,t);else if(l.tag===19)U0(l,u,t);else if(l.child!==null){l.child.return=l,l=l.child;continue}if(l===t)break l;for(;l.sibling===null;){if(l.return===null||l.return===t)break l;l=l.return}l.sibling.return=l.return,l=l.sibling}a&=1}switch(M(Al,a),e){case"forwards":for(u=t.child,e=null;u!==null;)l=u.alternate,l!==null&&un(l)===null&&(e=u),u=u.sibling;u=e,u===null?(e=t.child,t.child=null):(e=u.sibling,u.sibling=null),nc(t,!1,e,u,n);break;case"backwards":for(u=null,e=t.child,t.child=null;e!==null;){if(l=e.alternate,l!==null&&un(l)===null){t.child=e;break}l=e.sibling,e.sibling=u,u=e,e=l}nc(t,!0,u,null,n);break;case"together":nc(t,!1,null,null,void 0);break;default:t.memoizedState=null}return t.child}function Bt(l,t,u){if(l!==null&&(t.dependencies=l.dependencies),uu|=t.lanes,(u&t.childLanes)===0)if(l!==null){if(Ga(l,t,u,!1),(u&t.childLanes)===0)return null}else return null;if(l!==null&&t.child!==l.child)throw Error(v(153));if(t.child!==null){for(l=t.child,u=Ut(l,l.pendingProps),t.child=u,u.return=t;l.sibling!==null;)l=l.sibling,u=u.sibling=Ut(l,l.pendingProps),u.return=t;u.sibling=null}return t.child}function fc(l,t){return(l.lanes&t)!==0?!0:(l=l.dependencies,!!(l!==null&&je(l)))}function qo(l,t,u){switch(t.tag){case 3:sl(t,t.stateNode.containerInfo),Kt(t,El,l.memoizedState.cache),Ya();break;case 27:case 5:Yn(t);break;case 4:sl(t,t.stateNode.containerInfo);break;case 10:Kt(t,t.type,t.memoizedProps.value);break;case 13:var a=t.memoizedState;if(a!==null)return a.dehydrated!==null?(kt(t),t.flags|=128,null):(u&t.child.childLanes)!==0?D0(l,t,u):(kt(t),l=Bt(l,t,u),l!==null?l.sibling:null);kt(t);break;case 19:var e=(l.flags&128)!==0;if(a=(u&t.childLanes)!==0,a||(Ga(l,t,u,!1),a=(u&t.childLanes)!==0),e){if(a)return R0(l,t,u);t.flags|=128}if(e=t.memoizedState,e!==null&&(e.rendering=null,e.tail=null,e.lastEffect=null),M(Al,Al.current),a)break;return null;case 22:case 23:return t.lanes=0,A0(l,t,u);case 24:Kt(t,El,l.memoizedState.cache)}return Bt(l,t,u)}function N0(l,t,u){if(l!==null)if(l.memoizedProps!==t.pendingProps)_l=!0;else{if(!fc(l,u)&&(t.flags&128)===0)return _l=!1,qo(l,t,u);_l=(l.flags&131072)!==0}else _l=!1,P&&(t.flags&1048576)!==0&&fs(t,Qe,t.index);switch(t.lanes=0,t.tag){case 16:l:{l=t.pendingProps;var a=t.elementType,e=a._init;if(a=e(a._payload),t.type=a,typeof a=="function")rf(a)?(l=Uu(a,l),t.tag=1,t=M0(null,t,a,l,u)):(t.tag=0,t=Pf(null,t,a,l,u));else{if(a!=null){if(e=a.$$typeof,e===Wl){t.tag=11,t=b0(null,t,a,l,u);break l}else if(e===Ol){t.tag=14,t=T0(null,t,a,l,u);break l}}throw t=yu(a)||a,Error(v(306,t,""))}}return t;case 0:return Pf(l,t,t.type,t.pendingProps,u);case 1:return a=t.type,e=Uu(a,t.pendingProps),M0(l,t,a,e,u);case 3:l:{if(sl(t,t.stateNode.containerInfo),l===null)throw Error(v(387));a=t.pendingProps;var n=t.memoizedState;e=n.element,Rf(l,t),Va(t,a,null,u);var f=t.memoizedState;if(a=f.cache,Kt(t,El,a),a!==n.cache&&zf(t,[El],u,!0),Za(),a=f.element,n.isDehydrated)if(n={element:a,isDehydrated:!1,cache:f.cache},t.updateQueue.baseState=n,t.memoizedState=n,t.flags&256){t=_0(l,t,a,u);break l}else if(a!==e){e=ct(Error(v(424)),t),Ba(e),t=_0(l,t,a,u);break l}else{switch(l=t.stateNode.containerInfo,l.nodeType){case 9:l=l.body;break;default:l=l.nodeName==="HTML"?l.ownerDocument.body:l}for(yl=gt(l.firstChild),jl=t,P=!0,Au=null,Tt=!0,u=i0(t,null,a,u),t.child=u;u;)u.flags=u.flags&-3|4096,u=u.sibling}else{if(Ya(),a===e){t=Bt(l,t,u);break l}Hl(l,t,a,u)}t=t.child}return t;case 26:return nn(l,t),l===null?(u=Yd(t.type,null,t.pendingProps,null))?t.memoizedState=u:P||(u=t.type,l=t.pendingProps,a=En(C.current).createElement(u),a[Gl]=t,a[Zl]=l,ql(a,u,l),Ml(a),t.stateNode=a):t.memoizedState=Yd(t.type,l.memoizedProps,t.pendingProps,l.memoizedState),null;case 27:return Yn(t),l===null&&P&&(a=t.stateNode=Hd(t.type,t.pendingProps,C.current),jl=t,Tt=!0,e=yl,fu(t.type)?(jc=e,yl=gt(a.firstChild)):yl=e),Hl(l,t,t.pendingProps.children,u),nn(l,t),l===null&&(t.flags|=4194304),t.child;case 5:return l===null&&P&&((e=a=yl)&&(a=cy(a,t.type,t.pendingProps,Tt),a!==null?(t.stateNode=a,jl=t,yl=gt(a.firstChild),Tt=!1,e=!0):e=!1),e||zu(t)),Yn(t),e=t.type,n=t.pendingProps,f=l!==null?l.memoizedProps:null,a=n.children,Gc(e,n)?a=null:f!==null&&Gc(e,f)&&(t.flags|=32),t.memoizedState!==null&&(e=Bf(l,t,Mo,null,null,u),ve._currentValue=e),nn(l,t),Hl(l,t,a,u),t.child;case 6:return l===null&&P&&((l=u=yl)&&(u=iy(u,t.pendingProps,Tt),u!==null?(t.stateNode=u,jl=t,yl=null,l=!0):l=!1),l||zu(t)),null;case 13:return D0(l,t,u);case 4:return sl(t,t.stateNode.containerInfo),a=t.pendingProps,l===null?t.child=ea(t,null,a,u):Hl(l,t,a,u),t.child;case 11:return b0(l,t,t.type,t.pendingProps,u);case 7:return Hl(l,t,t.pendingProps,u),t.child;case 8:return Hl(l,t,t.pendingProps.children,u),t.child;case 12:return Hl(l,t,t.pendingProps.children,u),t.child;case 10:return a=t.pendingProps,Kt(t,t.type,a.value),Hl(l,t,a.children,u),t.child;case 9:return e=t.type._context,a=t.pendingProps.children,Mu(t),e=xl(e),a=a(e),t.flags|=1,Hl(l,t,a,u),t.child;case 14:return T0(l,t,t.type,t.pendingProps,u);case 15:return E0(l,t,t.type,t.pendingProps,u);case 19:return R0(l,t,u);case 31:return a=t.pendingProps,u=t.mode,a={mode:a.mode,children:a.children},l===null?(u=fn(a,u),u.ref=t.ref,t.child=u,u.return=t,t=u):(u=Ut(l.child,a),u.ref=t.ref,t.child=u,u.return=t,t=u),t;case 22:return A0(l,t,u);case 24:return Mu(t),a=xl(El),l===null?(e=_f(),e===null&&(e=il,n=Of(),e.pooledCache=n,n.refCount++,n!==null&&(e.pooledCacheLanes|=u),e=n),t.memoizedState={parent:a,cache:e},Uf(t),Kt(t,El,e)):((l.lanes&u)!==0&&(Rf(l,t),Va(t,null,null,u),Za()),e=l.memoizedState,n=t.memoizedState,e.parent!==a?(e={parent:a,cache:a},t.memoizedState=e,t.lanes===0&&(t.memoizedState=t.updateQueue.baseState=e),Kt(t,El,a)):(a=n.cache,Kt(t,El,a),a!==e.cache&&zf(t,[El],u,!0))),Hl(l,t,t.pendingProps.children,u),t.child;case 29:throw t.pendingProps}throw Error(v(156,t.tag))}function Gt(l){l.flags|=4}function H0(l,t){if(t.type!=="stylesheet"||(t.state.loading&4)!==0)l.flags&=-16777217;else if(l.flags|=16777216,!Qd(t)){if(t=vt.current,t!==null&&((W&4194048)===W?Et!==null:(W&62914560)!==W&&(W&536870912)===0||t!==Et))throw ja=Df,hs;l.flags|=8192}}function cn(l,t){t!==null&&(l.flags|=4),l.flags&16384&&(t=l.tag!==22?si():536870912,l.lanes|=t,ia|=t)}function ka(l,t){if(!P)switch(l.tailMode){case"hidden":t=l.tail;for(var u=null;t!==null;)t.alternate!==null&&(u=t),t=t.sibling;u===null?l.tail=null:u.sibling=null;break;case"collapsed":u=l.tail;for(var a=null;u!==null;)u.alternate!==null&&(a=u),u=u.sibling;a===null?t||l.tail===null?l.tail=null:l.tail.sibling=null:a.sibling=null}}function vl(l){var t=l.alternate!==null&&l.alternate.child===l.child,u=0,a=0;if(t)for(var e=l.child;e!==null;)u|=e.lanes|e.childLanes,a|=e.subtreeFlags&65011712,a|=e.flags&65011712,e.return=l,e=e.sibling;else for(e=l.child;e!==null;)u|=e.lanes|e.childLanes,a|=e.subtreeFlags,a|=e.flags,e.return=l,e=e.sibling;return l.subtreeFlags|=a,l.childLanes=u,t}function Yo(l,t,u){var a=t.pendingProps;switch(bf(t),t.tag){case 31:case 16:case 15:case 0:case 11:case 7:case 8:case 12:case 9:case 14:return vl(t),null;case 1:return vl(t),null;case 3:return u=t.stateNode,a=null,l!==null&&(a=l.memoizedState.cache),t.memoizedState.cache!==a&&(t.flags|=2048),pt(El),Ct(),u.pendingContext&&(u.context=u.pendingContext,u.pendingContext=null),(l===null||l.child===null)&&(qa(t)?Gt(t):l===null||l.memoizedState.isDehydrated&&(t.flags&256)===0||(t.flags|=1024,ss())),vl(t),null;case 26:return u=t.memoizedState,l===null?(Gt(t),u!==null?(vl(t),H0(t,u)):(vl(t),t.flags&=-16777217)):u?u!==l.memoizedState?(Gt(t),vl(t),H0(t,u)):(vl(t),t.flags&=-16777217):(l.memoizedProps!==a&&Gt(t),vl(t),t.flags&=-16777217),null;case 27:Se(t),u=C.current;var e=t.type;if(l!==null&&t.stateNode!=null)l.memoizedProps!==a&&Gt(t);else{if(!a){if(t.stateNode===null)throw Error(v(166));return vl(t),null}l=Y.current,qa(t)?cs(t):(l=Hd(e,a,u),t.stateNode=l,Gt(t))}return vl(t),null;case 5:if(Se(t),u=t.type,l!==null&&t.stateNode!=null)l.memoizedProps!==a&&Gt(t);else{if(!a){if(t.stateNode===null)throw Error(v(166));return vl(t),null}if(l=Y.current,qa(t))cs(t);else{switch(e=En(C.current),l){case 1:l=e.createElementNS("http://www.w3.org/2000/svg",u);break;case 2:l=e.createElementNS("http://www.w3.org/1998/Math/MathML",u);break;default:switch(u){case"svg":l=e.createElementNS("http://www.w3.org/2000/svg",u);break;case"math":l=e.createElementNS("http://www.w3.org/1998/Math/MathML",u);break;case"script":l=e.createElement("div"),l.innerHTML="<script><\/script>",l=l.removeChild(l.firstChild);break;case"select":l=typeof a.is=="string"?e.createElement("select",{is:a.is}):e.createElement("select"),a.multiple?l.multiple=!0:a.size&&(l.size=a.size);break;default:l=typeof a.is=="string"?e.createElement(u,{is:a.is}):e.createElement(u)}}l[Gl]=t,l[Zl]=a;l:for(e=t.child;e!==null;){if(e.tag===5||e.tag===6)l.appendChild(e.stateNode);else if(e.tag!==4&&e.tag!==27&&e.child!==null){e.child.return=e,e=e.child;continue}if(e===t)break l;for(;e.sibling===null;){if(e.return===null||e.return===t)break l;e=e.return}e.sibling.return=e.return,e=e.sibling}t.stateNode=l;l:switch(ql(l,u,a),u){case"button":case"input":case"select":case"textarea":l=!!a.autoFocus;break l;case"img":l=!0;break l;default:l=!1}l&&Gt(t)}}return vl(t),t.flags&=-16777217,null;case 6:if(l&&t.stateNode!=null)l.memoizedProps!==a&&Gt(t);else{if(typeof a!="string"&&t.stateNode===null)throw Error(v(166));if(l=C.current,qa(t)){if(l=t.stateNode,u=t.memoizedProps,a=null,e=jl,e!==null)switch(e.tag){case 27:case 5:a=e.memoizedProps}l[Gl]=t,l=!!(l.nodeValue===u||a!==null&&a.suppressHydrationWarning===!0||Od(l.nodeValue,u)),l||zu(t)}else l=En(l).createTextNode(a),l[Gl]=t,t.stateNode=l}return vl(t),null;case 13:if(a=t.memoizedState,l===null||l.memoizedState!==null&&l.memoizedState.dehydrated!==null){if(e=qa(t),a!==null&&a.dehydrated!==null){if(l===null){if(!e)throw Error(v(318));if(e=t.memoizedState,e=e!==null?e.dehydrated:null,!e)throw Error(v(317));e[Gl]=t}else Ya(),(t.flags&128)===0&&(t.memoizedState=null),t.flags|=4;vl(t),e=!1}else e=ss(),l!==null&&l.memoizedState!==null&&(l.memoizedState.hydrationErrors=e),e=!0;if(!e)return t.flags&256?(Yt(t),t):(Yt(t),null)}if(Yt(t),(t.flags&128)!==0)return t.lanes=u,t;if(u=a!==null,l=l!==null&&l.memoizedState!==null,u){a=t.child,e=null,a.alternate!==null&&a.alternate.memoizedState!==null&&a.alternate.memoizedState.cachePool!==null&&(e=a.alternate.memoizedState.cachePool.pool);var n=null;a.memoizedState!==null&&a.memoizedState.cachePool!==null&&(n=a.memoizedState.cachePool.pool),n!==e&&(a.flags|=2048)}return u!==l&&u&&(t.child.flags|=8192),cn(t,t.updateQueue),vl(t),null;case 4:return Ct(),l===null&&Hc(t.stateNode.containerInfo),vl(t),null;case 10:return pt(t.type),vl(t),null;case 19:if(R(Al),e=t.memoizedState,e===null)return vl(t),null;if(a=(t.flags&128)!==0,n=e.rendering,n===null)if(a)ka(e,!1);else{if(hl!==0||l!==null&&(l.flags&128)!==0)for(l=t.child;l!==null;){if(n=un(l),n!==null){for(t.flags|=128,ka(e,!1),l=n.updateQueue,t.updateQueue=l,cn(t,l),t.subtreeFlags=0,l=u,u=t.child;u!==null;)ns(u,l),u=u.sibling;return M(Al,Al.current&1|2),t.child}l=l.sibling}e.tail!==null&&bt()>vn&&(t.flags|=128,a=!0,ka(e,!1),t.lanes=4194304)}else{if(!a)if(l=un(n),l!==null){if(t.flags|=128,a=!0,l=l.updateQueue,t.updateQueue=l,cn(t,l),ka(e,!0),e.tail===null&&e.tailMode==="hidden"&&!n.alternate&&!P)return vl(t),null}else 2*bt()-e.renderingStartTime>vn&&u!==536870912&&(t.flags|=128,a=!0,ka(e,!1),t.lanes=4194304);e.isBackwards?(n.sibling=t.child,t.child=n):(l=e.last,l!==null?l.sibling=n:t.child=n,e.last=n)}return e.tail!==null?(t=e.tail,e.rendering=t,e.tail=t.sibling,e.renderingStartTime=bt(),t.sibling=null,l=Al.current,M(Al,a?l&1|2:l&1),t):(vl(t),null);case 22:case 23:return Yt(t),qf(),a=t.memoizedState!==null,l!==null?l.memoizedState!==null!==a&&(t.flags|=8192):a&&(t.flags|=8192),a?(u&536870912)!==0&&(t.flags&128)===0&&(vl(t),t.subtreeFlags&6&&(t.flags|=8192)):vl(t),u=t.updateQueue,u!==null&&cn(t,u.retryQueue),u=null,l!==null&&l.memoizedState!==null&&l.memoizedState.cachePool!==null&&(u=l.memoizedState.cachePool.pool),a=null,t.memoizedState!==null&&t.memoizedState.cachePool!==null&&(a=t.memoizedState.cachePool.pool),a!==u&&(t.flags|=2048),l!==null&&R(_u),null;case 24:return u=null,l!==null&&(u=l.memoizedState.cache),t.memoizedState.cache!==u&&(t.flags|=2048),pt(El),vl(t),null;case 25:return null;case 30:return null}throw Error(v(156,t.tag))}function Bo(l,t){switch(bf(t),t.tag){case 1:return l=t.flags,l&65536?(t.flags=l&-65537|128,t):null;case 3:return pt(El),Ct(),l=t.flags,(l&65536)!==0&&(l&128)===0?(t.flags=l&-65537|128,t):null;case 26:case 27:case 5:return Se(t),null;case 13:if(Yt(t),l=t.memoizedState,l!==null&&l.dehydrated!==null){if(t.alternate===null)throw Error(v(340));Ya()}return l=t.flags,l&65536?(t.flags=l&-65537|128,t):null;case 19:return R(Al),null;case 4:return Ct(),null;case 10:return pt(t.type),null;case 22:case 23:return Yt(t),qf(),l!==null&&R(_u),l=t.flags,l&65536?(t.flags=l&-65537|128,t):null;case 24:return pt(El),null;case 25:return null;default:return null}}function p0(l,t){switch(bf(t),t.tag){case 3:pt(El),Ct();break;case 26:case 27:case 5:Se(t);break;case 4:Ct();break;case 13:Yt(t);break;case 19:R(Al);break;case 10:pt(t.type);break;case 22:case 23:Yt(t),qf(),l!==null&&R(_u);break;case 24:pt(El)}}function Fa(l,t){try{var u=t.updateQueue,a=u!==null?u.lastEffect:null;if(a!==null){var e=a.next;u=e;do{if((u.tag&l)===l){a=void 0;var n=u.create,f=u.inst;a=n(),f.destroy=a}u=u.next}while(u!==e)}}catch(c){cl(t,t.return,c)}}function It(l,t,u){try{var a=t.updateQueue,e=a!==null?a.lastEffect:null;if(e!==null){var n=e.next;a=n;do{if((a.tag&l)===l){var f=a.inst,c=f.destroy;if(c!==void 0){f.destroy=void 0,e=t;var i=u,h=c;try{h()}catch(g){cl(e,i,g)}}}a=a.next}while(a!==n)}}catch(g){cl(t,t.return,g)}}function q0(l){var t=l.updateQueue;if(t!==null){var u=l.stateNode;try{Ts(t,u)}catch(a){cl(l,l.return,a)}}}function Y0(l,t,u){u.props=Uu(l.type,l.memoizedProps),u.state=l.memoizedState;try{u.componentWillUnmount()}catch(a){cl(l,t,a)}}function Ia(l,t){try{var u=l.ref;if(u!==null){switch(l.tag){case 26:case 27:case 5:var a=l.stateNode;break;case 30:a=l.stateNode;break;default:a=l.stateNode}typeof u=="function"?l.refCleanup=u(a):u.current=a}}catch(e){cl(l,t,e)}}function At(l,t){var u=l.ref,a=l.refCleanup;if(u!==null)if(typeof a=="function")try{a()}catch(e){cl(l,t,e)}finally{l.refCleanup=null,l=l.alternate,l!=null&&(l.refCleanup=null)}else if(typeof u=="function")try{u(null)}catch(e){cl(l,t,e)}else u.current=null}function B0(l){var t=l.type,u=l.memoizedProps,a=l.stateNode;try{l:switch(t){case"button":case"input":case"select":case"textarea":u.autoFocus&&a.focus();break l;case"img":u.src?a.src=u.src:u.srcSet&&(a.srcset=u.srcSet)}}catch(e){cl(l,l.return,e)}}function cc(l,t,u){try{var a=l.stateNode;uy(a,l.type,u,t),a[Zl]=t}catch(e){cl(l,l.return,e)}}function G0(l){return l.tag===5||l.tag===3||l.tag===26||l.tag===27&&fu(l.type)||l.tag===4}function ic(l){l:for(;;){for(;l.sibling===null;){if(l.return===null||G0(l.return))return null;l=l.return}for(l.sibling.return=l.return,l=l.sibling;l.tag!==5&&l.tag!==6&&l.tag!==18;){if(l.tag===27&&fu(l.type)||l.flags&2||l.child===null||l.tag===4)continue l;l.child.return=l,l=l.child}if(!(l.flags&2))return l.stateNode}}function sc(l,t,u){var a=l.tag;if(a===5||a===6)l=l.stateNode,t?(u.nodeType===9?u.body:u.nodeName==="HTML"?u.ownerDocument.body:u).insertBefore(l,t):(t=u.nodeType===9?u.body:u.nodeName==="HTML"?u.ownerDocument.body:u,t.appendChild(l),u=u._reactRootContainer,u!=null||t.onclick!==null||(t.onclick=Tn));else if(a!==4&&(a===27&&fu(l.type)&&(u=l.stateNode,t=null),l=l.child,l!==null))for(sc(l,t,u),l=l.sibling;l!==null;)sc(l,t,u),l=l.sibling}function sn(l,t,u){var a=l.tag;if(a===5||a===6)l=l.stateNode,t?u.insertBefore(l,t):u.appendChild(l);else if(a!==4&&(a===27&&fu(l.type)&&(u=l.stateNode),l=l.child,l!==null))for(sn(l,t,u),l=l.sibling;l!==null;)sn(l,t,u),l=l.sibling}function x0(l){var t=l.stateNode,u=l.memoizedProps;try{for(var a=l.type,e=t.attributes;e.length;)t.removeAttributeNode(e[0]);ql(t,a,u),t[Gl]=l,t[Zl]=u}catch(n){cl(l,l.return,n)}}var xt=!1,Sl=!1,dc=!1,X0=typeof WeakSet=="function"?WeakSet:Set,Dl=null;function Go(l,t){if(l=l.containerInfo,Yc=Dn,l=$i(l),cf(l)){if("selectionStart"in l)var u={start:l.selectionStart,end:l.selectionEnd};else l:{u=(u=l.ownerDocument)&&u.defaultView||window;var a=u.getSelection&&u.getSelection();if(a&&a.rangeCount!==0){u=a.anchorNode;var e=a.anchorOffset,n=a.focusNode;a=a.focusOffset;try{u.nodeType,n.nodeType}catch{u=null;break l}var f=0,c=-1,i=-1,h=0,g=0,E=l,r=null;t:for(;;){for(var m;E!==u||e!==0&&E.nodeType!==3||(c=f+e),E!==n||a!==0&&E.nodeType!==3||(i=f+a),E.nodeType===3&&(f+=E.nodeValue.length),(m=E.firstChild)!==null;)r=E,E=m;for(;;){if(E===l)break t;if(r===u&&++h===e&&(c=f),r===n&&++g===a&&(i=f),(m=E.nextSibling)!==null)break;E=r,r=E.parentNode}E=m}u=c===-1||i===-1?null:{start:c,end:i}}else u=null}u=u||{start:0,end:0}}else u=null;for(Bc={focusedElem:l,selectionRange:u},Dn=!1,Dl=t;Dl!==null;)if(t=Dl,l=t.child,(t.subtreeFlags&1024)!==0&&l!==null)l.return=t,Dl=l;else for(;Dl!==null;){switch(t=Dl,n=t.alternate,l=t.flags,t.tag){case 0:break;case 11:case 15:break;case 1:if((l&1024)!==0&&n!==null){l=void 0,u=t,e=n.memoizedProps,n=n.memoizedState,a=u.stateNode;try{var Q=Uu(u.type,e,u.elementType===u.type);l=a.getSnapshotBeforeUpdate(Q,n),a.__reactInternalSnapshotBeforeUpdate=l}catch(B){cl(u,u.return,B)}}break;case 3:if((l&1024)!==0){if(l=t.stateNode.containerInfo,u=l.nodeType,u===9)Xc(l);else if(u===1)switch(l.nodeName){case"HEAD":case"HTML":case"BODY":Xc(l);break;default:l.textContent=""}}break;case 5:case 26:case 27:case 6:case 4:case 17:break;default:if((l&1024)!==0)throw Error(v(163))}if(l=t.sibling,l!==null){l.return=t.return,Dl=l;break}Dl=t.return}}function Q0(l,t,u){var a=u.flags;switch(u.tag){case 0:case 11:case 15:Pt(l,u),a&4&&Fa(5,u);break;case 1:if(Pt(l,u),a&4)if(l=u.stateNode,t===null)try{l.componentDidMount()}catch(f){cl(u,u.return,f)}else{var e=Uu(u.type,t.memoizedProps);t=t.memoizedState;try{l.componentDidUpdate(e,t,l.__reactInternalSnapshotBeforeUpdate)}catch(f){cl(u,u.return,f)}}a&64&&q0(u),a&512&&Ia(u,u.return);break;case 3:if(Pt(l,u),a&64&&(l=u.updateQueue,l!==null)){if(t=null,u.child!==null)switch(u.child.tag){case 27:case 5:t=u.child.stateNode;break;case 1:t=u.child.stateNode}try{Ts(l,t)}catch(f){cl(u,u.return,f)}}break;case 27:t===null&&a&4&&x0(u);case 26:case 5:Pt(l,u),t===null&&a&4&&B0(u),a&512&&Ia(u,u.return);break;case 12:Pt(l,u);break;case 13:Pt(l,u),a&4&&Z0(l,u),a&64&&(l=u.memoizedState,l!==null&&(l=l.dehydrated,l!==null&&(u=Ko.bind(null,u),sy(l,u))));break;case 22:if(a=u.memoizedState!==null||xt,!a){t=t!==null&&t.memoizedState!==null||Sl,e=xt;var n=Sl;xt=a,(Sl=t)&&!n?lu(l,u,(u.subtreeFlags&8772)!==0):Pt(l,u),xt=e,Sl=n}break;case 30:break;default:Pt(l,u)}}function j0(l){var t=l.alternate;t!==null&&(l.alternate=null,j0(t)),l.child=null,l.deletions=null,l.sibling=null,l.tag===5&&(t=l.stateNode,t!==null&&Zn(t)),l.stateNode=null,l.return=null,l.dependencies=null,l.memoizedProps=null,l.memoizedState=null,l.pendingProps=null,l.stateNode=null,l.updateQueue=null}var dl=null,Kl=!1;function Xt(l,t,u){for(u=u.child;u!==null;)C0(l,t,u),u=u.sibling}function C0(l,t,u){if(kl&&typeof kl.onCommitFiberUnmount=="function")try{kl.onCommitFiberUnmount(ba,u)}catch{}switch(u.tag){case 26:Sl||At(u,t),Xt(l,t,u),u.memoizedState?u.memoizedState.count--:u.stateNode&&(u=u.stateNode,u.parentNode.removeChild(u));break;case 27:Sl||At(u,t);var a=dl,e=Kl;fu(u.type)&&(dl=u.stateNode,Kl=!1),Xt(l,t,u),ce(u.stateNode),dl=a,Kl=e;break;case 5:Sl||At(u,t);case 6:if(a=dl,e=Kl,dl=null,Xt(l,t,u),dl=a,Kl=e,dl!==null)if(Kl)try{(dl.nodeType===9?dl.body:dl.nodeName==="HTML"?dl.ownerDocument.body:dl).removeChild(u.stateNode)}catch(n){cl(u,t,n)}else try{dl.removeChild(u.stateNode)}catch(n){cl(u,t,n)}break;case 18:dl!==null&&(Kl?(l=dl,Rd(l.nodeType===9?l.body:l.nodeName==="HTML"?l.ownerDocument.body:l,u.stateNode),re(l)):Rd(dl,u.stateNode));break;case 4:a=dl,e=Kl,dl=u.stateNode.containerInfo,Kl=!0,Xt(l,t,u),dl=a,Kl=e;break;case 0:case 11:case 14:case 15:Sl||It(2,u,t),Sl||It(4,u,t),Xt(l,t,u);break;case 1:Sl||(At(u,t),a=u.stateNode,typeof a.componentWillUnmount=="function"&&Y0(u,t,a)),Xt(l,t,u);break;case 21:Xt(l,t,u);break;case 22:Sl=(a=Sl)||u.memoizedState!==null,Xt(l,t,u),Sl=a;break;default:Xt(l,t,u)}}function Z0(l,t){if(t.memoizedState===null&&(l=t.alternate,l!==null&&(l=l.memoizedState,l!==null&&(l=l.dehydrated,l!==null))))try{re(l)}catch(u){cl(t,t.return,u)}}function xo(l){switch(l.tag){case 13:case 19:var t=l.stateNode;return t===null&&(t=l.stateNode=new X0),t;case 22:return l=l.stateNode,t=l._retryCache,t===null&&(t=l._retryCache=new X0),t;default:throw Error(v(435,l.tag))}}function vc(l,t){var u=xo(l);t.forEach(function(a){var e=Jo.bind(null,l,a);u.has(a)||(u.add(a),a.then(e,e))})}function lt(l,t){var u=t.deletions;if(u!==null)for(var a=0;a<u.length;a++){var e=u[a],n=l,f=t,c=f;l:for(;c!==null;){switch(c.tag){case 27:if(fu(c.type)){dl=c.stateNode,Kl=!1;break l}break;case 5:dl=c.stateNode,Kl=!1;break l;case 3:case 4:dl=c.stateNode.containerInfo,Kl=!0;break l}c=c.return}if(dl===null)throw Error(v(160));C0(n,f,e),dl=null,Kl=!1,n=e.alternate,n!==null&&(n.return=null),e.return=null}if(t.subtreeFlags&13878)for(t=t.child;t!==null;)V0(t,l),t=t.sibling}var mt=null;function V0(l,t){var u=l.alternate,a=l.flags;switch(l.tag){case 0:case 11:case 14:case 15:lt(t,l),tt(l),a&4&&(It(3,l,l.return),Fa(3,l),It(5,l,l.return));break;case 1:lt(t,l),tt(l),a&512&&(Sl||u===null||At(u,u.return)),a&64&&xt&&(l=l.updateQueue,l!==null&&(a=l.callbacks,a!==null&&(u=l.shared.hiddenCallbacks,l.shared.hiddenCallbacks=u===null?a:u.concat(a))));break;case 26:var e=mt;if(lt(t,l),tt(l),a&512&&(Sl||u===null||At(u,u.return)),a&4){var n=u!==null?u.memoizedState:null;if(a=l.memoizedState,u===null)if(a===null)if(l.stateNode===null){l:{a=l.type,u=l.memoizedProps,e=e.ownerDocument||e;t:switch(a){case"title":n=e.getElementsByTagName("title")[0],(!n||n[Aa]||n[Gl]||n.namespaceURI==="http://www.w3.org/2000/svg"||n.hasAttribute("itemprop"))&&(n=e.createElement(a),e.head.insertBefore(n,e.querySelector("head > title"))),ql(n,a,u),n[Gl]=l,Ml(n),a=n;break l;case"link":var f=xd("link","href",e).get(a+(u.href||""));if(f){for(var c=0;c<f.length;c++)if(n=f[c],n.getAttribute("href")===(u.href==null||u.href===""?null:u.href)&&n.getAttribute("rel")===(u.rel==null?null:u.rel)&&n.getAttribute("title")===(u.title==null?null:u.title)&&n.getAttribute("crossorigin")===(u.crossOrigin==null?null:u.crossOrigin)){f.splice(c,1);break t}}n=e.createElement(a),ql(n,a,u),e.head.appendChild(n);break;case"meta":if(f=xd("meta","content",e).get(a+(u.content||""))){for(c=0;c<f.length;c++)if(n=f[c],n.getAttribute("content")===(u.content==null?null:""+u.content)&&n.getAttribute("name")===(u.name==null?null:u.name)&&n.getAttribute("property")===(u.property==null?null:u.property)&&n.getAttribute("http-equiv")===(u.httpEquiv==null?null:u.httpEquiv)&&n.getAttribute("charset")===(u.charSet==null?null:u.charSet)){f.splice(c,1);break t}}n=e.createElement(a),ql(n,a,u),e.head.appendChild(n);break;default:throw Error(v(468,a))}n[Gl]=l,Ml(n),a=n}l.stateNode=a}else Xd(e,l.type,l.stateNode);else l.stateNode=Gd(e,a,l.memoizedProps);else n!==a?(n===null?u.stateNode!==null&&(u=u.stateNode,u.parentNode.removeChild(u)):n.count--,a===null?Xd(e,l.type,l.stateNode):Gd(e,a,l.memoizedProps)):a===null&&l.stateNode!==null&&cc(l,l.memoizedProps,u.memoizedProps)}break;case 27:lt(t,l),tt(l),a&512&&(Sl||u===null||At(u,u.return)),u!==null&&a&4&&cc(l,l.memoizedProps,u.memoizedProps);break;case 5:if(lt(t,l),tt(l),a&512&&(Sl||u===null||At(u,u.return)),l.flags&32){e=l.stateNode;try{ju(e,"")}catch(m){cl(l,l.return,m)}}a&4&&l.stateNode!=null&&(e=l.memoizedProps,cc(l,e,u!==null?u.memoizedProps:e)),a&1024&&(dc=!0);break;case 6:if(lt(t,l),tt(l),a&4){if(l.stateNode===null)throw Error(v(162));a=l.memoizedProps,u=l.stateNode;try{u.nodeValue=a}catch(m){cl(l,l.return,m)}}break;case 3:if(On=null,e=mt,mt=An(t.containerInfo),lt(t,l),mt=e,tt(l),a&4&&u!==null&&u.memoizedState.isDehydrated)try{re(t.containerInfo)}catch(m){cl(l,l.return,m)}dc&&(dc=!1,L0(l));break;case 4:a=mt,mt=An(l.stateNode.containerInfo),lt(t,l),tt(l),mt=a;break;case 12:lt(t,l),tt(l);break;case 13:lt(t,l),tt(l),l.child.flags&8192&&l.memoizedState!==null!=(u!==null&&u.memoizedState!==null)&&(gc=bt()),a&4&&(a=l.updateQueue,a!==null&&(l.updateQueue=null,vc(l,a)));break;case 22:e=l.memoizedState!==null;var i=u!==null&&u.memoizedState!==null,h=xt,g=Sl;if(xt=h||e,Sl=g||i,lt(t,l),Sl=g,xt=h,tt(l),a&8192)l:for(t=l.stateNode,t._visibility=e?t._visibility&-2:t._visibility|1,e&&(u===null||i||xt||Sl||Ru(l)),u=null,t=l;;){if(t.tag===5||t.tag===26){if(u===null){i=u=t;try{if(n=i.stateNode,e)f=n.style,typeof f.setProperty=="function"?f.setProperty("display","none","important"):f.display="none";else{c=i.stateNode;var E=i.memoizedProps.style,r=E!=null&&E.hasOwnProperty("display")?E.display:null;c.style.display=r==null||typeof r=="boolean"?"":(""+r).trim()}}catch(m){cl(i,i.return,m)}}}else if(t.tag===6){if(u===null){i=t;try{i.stateNode.nodeValue=e?"":i.memoizedProps}catch(m){cl(i,i.return,m)}}}else if((t.tag!==22&&t.tag!==23||t.memoizedState===null||t===l)&&t.child!==null){t.child.return=t,t=t.child;continue}if(t===l)break l;for(;t.sibling===null;){if(t.return===null||t.return===l)break l;u===t&&(u=null),t=t.return}u===t&&(u=null),t.sibling.return=t.return,t=t.sibling}a&4&&(a=l.updateQueue,a!==null&&(u=a.retryQueue,u!==null&&(a.retryQueue=null,vc(l,u))));break;case 19:lt(t,l),tt(l),a&4&&(a=l.updateQueue,a!==null&&(l.updateQueue=null,vc(l,a)));break;case 30:break;case 21:break;default:lt(t,l),tt(l)}}function tt(l){var t=l.flags;if(t&2){try{for(var u,a=l.return;a!==null;){if(G0(a)){u=a;break}a=a.return}if(u==null)throw Error(v(160));switch(u.tag){case 27:var e=u.stateNode,n=ic(l);sn(l,n,e);break;case 5:var f=u.stateNode;u.flags&32&&(ju(f,""),u.flags&=-33);var c=ic(l);sn(l,c,f);break;case 3:case 4:var i=u.stateNode.containerInfo,h=ic(l);sc(l,h,i);break;default:throw Error(v(161))}}catch(g){cl(l,l.return,g)}l.flags&=-3}t&4096&&(l.flags&=-4097)}function L0(l){if(l.subtreeFlags&1024)for(l=l.child;l!==null;){var t=l;L0(t),t.tag===5&&t.flags&1024&&t.stateNode.reset(),l=l.sibling}}function Pt(l,t){if(t.subtreeFlags&8772)for(t=t.child;t!==null;)Q0(l,t.alternate,t),t=t.sibling}function Ru(l){for(l=l.child;l!==null;){var t=l;switch(t.tag){case 0:case 11:case 14:case 15:It(4,t,t.return),Ru(t);break;case 1:At(t,t.return);var u=t.stateNode;typeof u.componentWillUnmount=="function"&&Y0(t,t.return,u),Ru(t);break;case 27:ce(t.stateNode);case 26:case 5:At(t,t.return),Ru(t);break;case 22:t.memoizedState===null&&Ru(t);break;case 30:Ru(t);break;default:Ru(t)}l=l.sibling}}function lu(l,t,u){for(u=u&&(t.subtreeFlags&8772)!==0,t=t.child;t!==null;){var a=t.alternate,e=l,n=t,f=n.flags;switch(n.tag){case 0:case 11:case 15:lu(e,n,u),Fa(4,n);break;case 1:if(lu(e,n,u),a=n,e=a.stateNode,typeof e.componentDidMount=="function")try{e.componentDidMount()}catch(h){cl(a,a.return,h)}if(a=n,e=a.updateQueue,e!==null){var c=a.stateNode;try{var i=e.shared.hiddenCallbacks;if(i!==null)for(e.shared.hiddenCallbacks=null,e=0;e<i.length;e++)bs(i[e],c)}catch(h){cl(a,a.return,h)}}u&&f&64&&q0(n),Ia(n,n.return);break;case 27:x0(n);case 26:case 5:lu(e,n,u),u&&a===null&&f&4&&B0(n),Ia(n,n.return);break;case 12:lu(e,n,u);break;case 13:lu(e,n,u),u&&f&4&&Z0(e,n);break;case 22:n.memoizedState===null&&lu(e,n,u),Ia(n,n.return);break;case 30:break;default:lu(e,n,u)}t=t.sibling}}function oc(l,t){var u=null;l!==null&&l.memoizedState!==null&&l.memoizedState.cachePool!==null&&(u=l.memoizedState.cachePool.pool),l=null,t.memoizedState!==null&&t.memoizedState.cachePool!==null&&(l=t.memoizedState.cachePool.pool),l!==u&&(l!=null&&l.refCount++,u!=null&&xa(u))}function yc(l,t){l=null,t.alternate!==null&&(l=t.alternate.memoizedState.cache),t=t.memoizedState.cache,t!==l&&(t.refCount++,l!=null&&xa(l))}function zt(l,t,u,a){if(t.subtreeFlags&10256)for(t=t.child;t!==null;)K0(l,t,u,a),t=t.sibling}function K0(l,t,u,a){var e=t.flags;switch(t.tag){case 0:case 11:case 15:zt(l,t,u,a),e&2048&&Fa(9,t);break;case 1:zt(l,t,u,a);break;case 3:zt(l,t,u,a),e&2048&&(l=null,t.alternate!==null&&(l=t.alternate.memoizedState.cache),t=t.memoizedState.cache,t!==l&&(t.refCount++,l!=null&&xa(l)));break;case 12:if(e&2048){zt(l,t,u,a),l=t.stateNode;try{var n=t.memoizedProps,f=n.id,c=n.onPostCommit;typeof c=="function"&&c(f,t.alternate===null?"mount":"update",l.passiveEffectDuration,-0)}catch(i){cl(t,t.return,i)}}else zt(l,t,u,a);break;case 13:zt(l,t,u,a);break;case 23:break;case 22:n=t.stateNode,f=t.alternate,t.memoizedState!==null?n._visibility&2?zt(l,t,u,a):Pa(l,t):n._visibility&2?zt(l,t,u,a):(n._visibility|=2,na(l,t,u,a,(t.subtreeFlags&10256)!==0)),e&2048&&oc(f,t);break;case 24:zt(l,t,u,a),e&2048&&yc(t.alternate,t);break;default:zt(l,t,u,a)}}function na(l,t,u,a,e){for(e=e&&(t.subtreeFlags&10256)!==0,t=t.child;t!==null;){var n=l,f=t,c=u,i=a,h=f.flags;switch(f.tag){case 0:case 11:case 15:na(n,f,c,i,e),Fa(8,f);break;case 23:break;case 22:var g=f.stateNode;f.memoizedState!==null?g._visibility&2?na(n,f,c,i,e):Pa(n,f):(g._visibility|=2,na(n,f,c,i,e)),e&&h&2048&&oc(f.alternate,f);break;case 24:na(n,f,c,i,e),e&&h&2048&&yc(f.alternate,f);break;default:na(n,f,c,i,e)}t=t.sibling}}function Pa(l,t){if(t.subtreeFlags&10256)for(t=t.child;t!==null;){var u=l,a=t,e=a.flags;switch(a.tag){case 22:Pa(u,a),e&2048&&oc(a.alternate,a);break;case 24:Pa(u,a),e&2048&&yc(a.alternate,a);break;default:Pa(u,a)}t=t.sibling}}var le=8192;function fa(l){if(l.subtreeFlags&le)for(l=l.child;l!==null;)J0(l),l=l.sibling}function J0(l){switch(l.tag){case 26:fa(l),l.flags&le&&l.memoizedState!==null&&Ay(mt,l.memoizedState,l.memoizedProps);break;case 5:fa(l);break;case 3:case 4:var t=mt;mt=An(l.stateNode.containerInfo),fa(l),mt=t;break;case 22:l.memoizedState===null&&(t=l.alternate,t!==null&&t.memoizedState!==null?(t=le,le=16777216,fa(l),le=t):fa(l));break;default:fa(l)}}function w0(l){var t=l.alternate;if(t!==null&&(l=t.child,l!==null)){t.child=null;do t=l.sibling,l.sibling=null,l=t;while(l!==null)}}function te(l){var t=l.deletions;if((l.flags&16)!==0){if(t!==null)for(var u=0;u<t.length;u++){var a=t[u];Dl=a,$0(a,l)}w0(l)}if(l.subtreeFlags&10256)for(l=l.child;l!==null;)W0(l),l=l.sibling}function W0(l){switch(l.tag){case 0:case 11:case 15:te(l),l.flags&2048&&It(9,l,l.return);break;case 3:te(l);break;case 12:te(l);break;case 22:var t=l.stateNode;l.memoizedState!==null&&t._visibility&2&&(l.return===null||l.return.tag!==13)?(t._visibility&=-3,dn(l)):te(l);break;default:te(l)}}function dn(l){var t=l.deletions;if((l.flags&16)!==0){if(t!==null)for(var u=0;u<t.length;u++){var a=t[u];Dl=a,$0(a,l)}w0(l)}for(l=l.child;l!==null;){switch(t=l,t.tag){case 0:case 11:case 15:It(8,t,t.return),dn(t);break;case 22:u=t.stateNode,u._visibility&2&&(u._visibility&=-3,dn(t));break;default:dn(t)}l=l.sibling}}function $0(l,t){for(;Dl!==null;){var u=Dl;switch(u.tag){case 0:case 11:case 15:It(8,u,t);break;case 23:case 22:if(u.memoizedState!==null&&u.memoizedState.cachePool!==null){var a=u.memoizedState.cachePool.pool;a!=null&&a.refCount++}break;case 24:xa(u.memoizedState.cache)}if(a=u.child,a!==null)a.return=u,Dl=a;else l:for(u=l;Dl!==null;){a=Dl;var e=a.sibling,n=a.return;if(j0(a),a===u){Dl=null;break l}if(e!==null){e.return=n,Dl=e;break l}Dl=n}}}var Xo={getCacheForType:function(l){var t=xl(El),u=t.data.get(l);return u===void 0&&(u=l(),t.data.set(l,u)),u}},Qo=typeof WeakMap=="function"?WeakMap:Map,ll=0,il=null,K=null,W=0,tl=0,ut=null,tu=!1,ca=!1,hc=!1,Qt=0,hl=0,uu=0,Nu=0,rc=0,ot=0,ia=0,ue=null,Jl=null,mc=!1,gc=0,vn=1/0,on=null,au=null,pl=0,eu=null,sa=null,da=0,Sc=0,bc=null,k0=null,ae=0,Tc=null;function at(){if((ll&2)!==0&&W!==0)return W&-W;if(S.T!==null){var l=Fu;return l!==0?l:Dc()}return oi()}function F0(){ot===0&&(ot=(W&536870912)===0||P?ii():536870912);var l=vt.current;return l!==null&&(l.flags|=32),ot}function et(l,t,u){(l===il&&(tl===2||tl===9)||l.cancelPendingCommit!==null)&&(va(l,0),nu(l,W,ot,!1)),Ea(l,u),((ll&2)===0||l!==il)&&(l===il&&((ll&2)===0&&(Nu|=u),hl===4&&nu(l,W,ot,!1)),Ot(l))}function I0(l,t,u){if((ll&6)!==0)throw Error(v(327));var a=!u&&(t&124)===0&&(t&l.expiredLanes)===0||Ta(l,t),e=a?Zo(l,t):zc(l,t,!0),n=a;do{if(e===0){ca&&!a&&nu(l,t,0,!1);break}else{if(u=l.current.alternate,n&&!jo(u)){e=zc(l,t,!1),n=!1;continue}if(e===2){if(n=t,l.errorRecoveryDisabledLanes&n)var f=0;else f=l.pendingLanes&-536870913,f=f!==0?f:f&536870912?536870912:0;if(f!==0){t=f;l:{var c=l;e=ue;var i=c.current.memoizedState.isDehydrated;if(i&&(va(c,f).flags|=256),f=zc(c,f,!1),f!==2){if(hc&&!i){c.errorRecoveryDisabledLanes|=n,Nu|=n,e=4;break l}n=Jl,Jl=e,n!==null&&(Jl===null?Jl=n:Jl.push.apply(Jl,n))}e=f}if(n=!1,e!==2)continue}}if(e===1){va(l,0),nu(l,t,0,!0);break}l:{switch(a=l,n=e,n){case 0:case 1:throw Error(v(345));case 4:if((t&4194048)!==t)break;case 6:nu(a,t,ot,!tu);break l;case 2:Jl=null;break;case 3:case 5:break;default:throw Error(v(329))}if((t&62914560)===t&&(e=gc+300-bt(),10<e)){if(nu(a,t,ot,!tu),Ae(a,0,!0)!==0)break l;a.timeoutHandle=Dd(P0.bind(null,a,u,Jl,on,mc,t,ot,Nu,ia,tu,n,2,-0,0),e);break l}P0(a,u,Jl,on,mc,t,ot,Nu,ia,tu,n,0,-0,0)}}break}while(!0);Ot(l)}function P0(l,t,u,a,e,n,f,c,i,h,g,E,r,m){if(l.timeoutHandle=-1,E=t.subtreeFlags,(E&8192||(E&16785408)===16785408)&&(de={stylesheets:null,count:0,unsuspend:Ey},J0(t),E=zy(),E!==null)){l.cancelPendingCommit=E(fd.bind(null,l,t,n,u,a,e,f,c,i,g,1,r,m)),nu(l,n,f,!h);return}fd(l,t,n,u,a,e,f,c,i)}function jo(l){for(var t=l;;){var u=t.tag;if((u===0||u===11||u===15)&&t.flags&16384&&(u=t.updateQueue,u!==null&&(u=u.stores,u!==null)))for(var a=0;a<u.length;a++){var e=u[a],n=e.getSnapshot;e=e.value;try{if(!Il(n(),e))return!1}catch{return!1}}if(u=t.child,t.subtreeFlags&16384&&u!==null)u.return=t,t=u;else{if(t===l)break;for(;t.sibling===null;){if(t.return===null||t.return===l)return!0;t=t.return}t.sibling.return=t.return,t=t.sibling}}return!0}function nu(l,t,u,a){t&=~rc,t&=~Nu,l.suspendedLanes|=t,l.pingedLanes&=~t,a&&(l.warmLanes|=t),a=l.expirationTimes;for(var e=t;0<e;){var n=31-Fl(e),f=1<<n;a[n]=-1,e&=~f}u!==0&&di(l,u,t)}function yn(){return(ll&6)===0?(ee(0),!1):!0}function Ec(){if(K!==null){if(tl===0)var l=K.return;else l=K,Ht=Ou=null,Xf(l),aa=null,Wa=0,l=K;for(;l!==null;)p0(l.alternate,l),l=l.return;K=null}}function va(l,t){var u=l.timeoutHandle;u!==-1&&(l.timeoutHandle=-1,ey(u)),u=l.cancelPendingCommit,u!==null&&(l.cancelPendingCommit=null,u()),Ec(),il=l,K=u=Ut(l.current,null),W=t,tl=0,ut=null,tu=!1,ca=Ta(l,t),hc=!1,ia=ot=rc=Nu=uu=hl=0,Jl=ue=null,mc=!1,(t&8)!==0&&(t|=t&32);var a=l.entangledLanes;if(a!==0)for(l=l.entanglements,a&=t;0<a;){var e=31-Fl(a),n=1<<e;t|=l[e],a&=~n}return Qt=t,Ye(),u}function ld(l,t){V=null,S.H=Pe,t===Qa||t===Ve?(t=gs(),tl=3):t===hs?(t=gs(),tl=4):tl=t===S0?8:t!==null&&typeof t=="object"&&typeof t.then=="function"?6:1,ut=t,K===null&&(hl=1,en(l,ct(t,l.current)))}function td(){var l=S.H;return S.H=Pe,l===null?Pe:l}function ud(){var l=S.A;return S.A=Xo,l}function Ac(){hl=4,tu||(W&4194048)!==W&&vt.current!==null||(ca=!0),(uu&134217727)===0&&(Nu&134217727)===0||il===null||nu(il,W,ot,!1)}function zc(l,t,u){var a=ll;ll|=2;var e=td(),n=ud();(il!==l||W!==t)&&(on=null,va(l,t)),t=!1;var f=hl;l:do try{if(tl!==0&&K!==null){var c=K,i=ut;switch(tl){case 8:Ec(),f=6;break l;case 3:case 2:case 9:case 6:vt.current===null&&(t=!0);var h=tl;if(tl=0,ut=null,oa(l,c,i,h),u&&ca){f=0;break l}break;default:h=tl,tl=0,ut=null,oa(l,c,i,h)}}Co(),f=hl;break}catch(g){ld(l,g)}while(!0);return t&&l.shellSuspendCounter++,Ht=Ou=null,ll=a,S.H=e,S.A=n,K===null&&(il=null,W=0,Ye()),f}function Co(){for(;K!==null;)ad(K)}function Zo(l,t){var u=ll;ll|=2;var a=td(),e=ud();il!==l||W!==t?(on=null,vn=bt()+500,va(l,t)):ca=Ta(l,t);l:do try{if(tl!==0&&K!==null){t=K;var n=ut;t:switch(tl){case 1:tl=0,ut=null,oa(l,t,n,1);break;case 2:case 9:if(rs(n)){tl=0,ut=null,ed(t);break}t=function(){tl!==2&&tl!==9||il!==l||(tl=7),Ot(l)},n.then(t,t);break l;case 3:tl=7;break l;case 4:tl=5;break l;case 7:rs(n)?(tl=0,ut=null,ed(t)):(tl=0,ut=null,oa(l,t,n,7));break;case 5:var f=null;switch(K.tag){case 26:f=K.memoizedState;case 5:case 27:var c=K;if(!f||Qd(f)){tl=0,ut=null;var i=c.sibling;if(i!==null)K=i;else{var h=c.return;h!==null?(K=h,hn(h)):K=null}break t}}tl=0,ut=null,oa(l,t,n,5);break;case 6:tl=0,ut=null,oa(l,t,n,6);break;case 8:Ec(),hl=6;break l;default:throw Error(v(462))}}Vo();break}catch(g){ld(l,g)}while(!0);return Ht=Ou=null,S.H=a,S.A=e,ll=u,K!==null?0:(il=null,W=0,Ye(),hl)}function Vo(){for(;K!==null&&!dv();)ad(K)}function ad(l){var t=N0(l.alternate,l,Qt);l.memoizedProps=l.pendingProps,t===null?hn(l):K=t}function ed(l){var t=l,u=t.alternate;switch(t.tag){case 15:case 0:t=O0(u,t,t.pendingProps,t.type,void 0,W);break;case 11:t=O0(u,t,t.pendingProps,t.type.render,t.ref,W);break;case 5:Xf(t);default:p0(u,t),t=K=ns(t,Qt),t=N0(u,t,Qt)}l.memoizedProps=l.pendingProps,t===null?hn(l):K=t}function oa(l,t,u,a){Ht=Ou=null,Xf(t),aa=null,Wa=0;var e=t.return;try{if(po(l,e,t,u,W)){hl=1,en(l,ct(u,l.current)),K=null;return}}catch(n){if(e!==null)throw K=e,n;hl=1,en(l,ct(u,l.current)),K=null;return}t.flags&32768?(P||a===1?l=!0:ca||(W&536870912)!==0?l=!1:(tu=l=!0,(a===2||a===9||a===3||a===6)&&(a=vt.current,a!==null&&a.tag===13&&(a.flags|=16384))),nd(t,l)):hn(t)}function hn(l){var t=l;do{if((t.flags&32768)!==0){nd(t,tu);return}l=t.return;var u=Yo(t.alternate,t,Qt);if(u!==null){K=u;return}if(t=t.sibling,t!==null){K=t;return}K=t=l}while(t!==null);hl===0&&(hl=5)}function nd(l,t){do{var u=Bo(l.alternate,l);if(u!==null){u.flags&=32767,K=u;return}if(u=l.return,u!==null&&(u.flags|=32768,u.subtreeFlags=0,u.deletions=null),!t&&(l=l.sibling,l!==null)){K=l;return}K=l=u}while(l!==null);hl=6,K=null}function fd(l,t,u,a,e,n,f,c,i){l.cancelPendingCommit=null;do rn();while(pl!==0);if((ll&6)!==0)throw Error(v(327));if(t!==null){if(t===l.current)throw Error(v(177));if(n=t.lanes|t.childLanes,n|=yf,Tv(l,u,n,f,c,i),l===il&&(K=il=null,W=0),sa=t,eu=l,da=u,Sc=n,bc=e,k0=a,(t.subtreeFlags&10256)!==0||(t.flags&10256)!==0?(l.callbackNode=null,l.callbackPriority=0,wo(be,function(){return vd(),null})):(l.callbackNode=null,l.callbackPriority=0),a=(t.flags&13878)!==0,(t.subtreeFlags&13878)!==0||a){a=S.T,S.T=null,e=_.p,_.p=2,f=ll,ll|=4;try{Go(l,t,u)}finally{ll=f,_.p=e,S.T=a}}pl=1,cd(),id(),sd()}}function cd(){if(pl===1){pl=0;var l=eu,t=sa,u=(t.flags&13878)!==0;if((t.subtreeFlags&13878)!==0||u){u=S.T,S.T=null;var a=_.p;_.p=2;var e=ll;ll|=4;try{V0(t,l);var n=Bc,f=$i(l.containerInfo),c=n.focusedElem,i=n.selectionRange;if(f!==c&&c&&c.ownerDocument&&Wi(c.ownerDocument.documentElement,c)){if(i!==null&&cf(c)){var h=i.start,g=i.end;if(g===void 0&&(g=h),"selectionStart"in c)c.selectionStart=h,c.selectionEnd=Math.min(g,c.value.length);else{var E=c.ownerDocument||document,r=E&&E.defaultView||window;if(r.getSelection){var m=r.getSelection(),Q=c.textContent.length,B=Math.min(i.start,Q),nl=i.end===void 0?B:Math.min(i.end,Q);!m.extend&&B>nl&&(f=nl,nl=B,B=f);var o=wi(c,B),d=wi(c,nl);if(o&&d&&(m.rangeCount!==1||m.anchorNode!==o.node||m.anchorOffset!==o.offset||m.focusNode!==d.node||m.focusOffset!==d.offset)){var y=E.createRange();y.setStart(o.node,o.offset),m.removeAllRanges(),B>nl?(m.addRange(y),m.extend(d.node,d.offset)):(y.setEnd(d.node,d.offset),m.addRange(y))}}}}for(E=[],m=c;m=m.parentNode;)m.nodeType===1&&E.push({element:m,left:m.scrollLeft,top:m.scrollTop});for(typeof c.focus=="function"&&c.focus(),c=0;c<E.length;c++){var b=E[c];b.element.scrollLeft=b.left,b.element.scrollTop=b.top}}Dn=!!Yc,Bc=Yc=null}finally{ll=e,_.p=a,S.T=u}}l.current=t,pl=2}}function id(){if(pl===2){pl=0;var l=eu,t=sa,u=(t.flags&8772)!==0;if((t.subtreeFlags&8772)!==0||u){u=S.T,S.T=null;var a=_.p;_.p=2;var e=ll;ll|=4;try{Q0(l,t.alternate,t)}finally{ll=e,_.p=a,S.T=u}}pl=3}}function sd(){if(pl===4||pl===3){pl=0,vv();var l=eu,t=sa,u=da,a=k0;(t.subtreeFlags&10256)!==0||(t.flags&10256)!==0?pl=5:(pl=0,sa=eu=null,dd(l,l.pendingLanes));var e=l.pendingLanes;if(e===0&&(au=null),jn(u),t=t.stateNode,kl&&typeof kl.onCommitFiberRoot=="function")try{kl.onCommitFiberRoot(ba,t,void 0,(t.current.flags&128)===128)}catch{}if(a!==null){t=S.T,e=_.p,_.p=2,S.T=null;try{for(var n=l.onRecoverableError,f=0;f<a.length;f++){var c=a[f];n(c.value,{componentStack:c.stack})}}finally{S.T=t,_.p=e}}(da&3)!==0&&rn(),Ot(l),e=l.pendingLanes,(u&4194090)!==0&&(e&42)!==0?l===Tc?ae++:(ae=0,Tc=l):ae=0,ee(0)}}function dd(l,t){(l.pooledCacheLanes&=t)===0&&(t=l.pooledCache,t!=null&&(l.pooledCache=null,xa(t)))}function rn(l){return cd(),id(),sd(),vd()}function vd(){if(pl!==5)return!1;var l=eu,t=Sc;Sc=0;var u=jn(da),a=S.T,e=_.p;try{_.p=32>u?32:u,S.T=null,u=bc,bc=null;var n=eu,f=da;if(pl=0,sa=eu=null,da=0,(ll&6)!==0)throw Error(v(331));var c=ll;if(ll|=4,W0(n.current),K0(n,n.current,f,u),ll=c,ee(0,!1),kl&&typeof kl.onPostCommitFiberRoot=="function")try{kl.onPostCommitFiberRoot(ba,n)}catch{}return!0}finally{_.p=e,S.T=a,dd(l,t)}}function od(l,t,u){t=ct(u,t),t=If(l.stateNode,t,2),l=Wt(l,t,2),l!==null&&(Ea(l,2),Ot(l))}function cl(l,t,u){if(l.tag===3)od(l,l,u);else for(;t!==null;){if(t.tag===3){od(t,l,u);break}else if(t.tag===1){var a=t.stateNode;if(typeof t.type.getDerivedStateFromError=="function"||typeof a.componentDidCatch=="function"&&(au===null||!au.has(a))){l=ct(u,l),u=m0(2),a=Wt(t,u,2),a!==null&&(g0(u,a,t,l),Ea(a,2),Ot(a));break}}t=t.return}}function Oc(l,t,u){var a=l.pingCache;if(a===null){a=l.pingCache=new Qo;var e=new Set;a.set(t,e)}else e=a.get(t),e===void 0&&(e=new Set,a.set(t,e));e.has(u)||(hc=!0,e.add(u),l=Lo.bind(null,l,t,u),t.then(l,l))}function Lo(l,t,u){var a=l.pingCache;a!==null&&a.delete(t),l.pingedLanes|=l.suspendedLanes&u,l.warmLanes&=~u,il===l&&(W&u)===u&&(hl===4||hl===3&&(W&62914560)===W&&300>bt()-gc?(ll&2)===0&&va(l,0):rc|=u,ia===W&&(ia=0)),Ot(l)}function yd(l,t){t===0&&(t=si()),l=wu(l,t),l!==null&&(Ea(l,t),Ot(l))}function Ko(l){var t=l.memoizedState,u=0;t!==null&&(u=t.retryLane),yd(l,u)}function Jo(l,t){var u=0;switch(l.tag){case 13:var a=l.stateNode,e=l.memoizedState;e!==null&&(u=e.retryLane);break;case 19:a=l.stateNode;break;case 22:a=l.stateNode._retryCache;break;default:throw Error(v(314))}a!==null&&a.delete(t),yd(l,u)}function wo(l,t){return Gn(l,t)}var mn=null,ya=null,Mc=!1,gn=!1,_c=!1,Hu=0;function Ot(l){l!==ya&&l.next===null&&(ya===null?mn=ya=l:ya=ya.next=l),gn=!0,Mc||(Mc=!0,$o())}function ee(l,t){if(!_c&&gn){_c=!0;do for(var u=!1,a=mn;a!==null;){if(l!==0){var e=a.pendingLanes;if(e===0)var n=0;else{var f=a.suspendedLanes,c=a.pingedLanes;n=(1<<31-Fl(42|l)+1)-1,n&=e&~(f&~c),n=n&201326741?n&201326741|1:n?n|2:0}n!==0&&(u=!0,gd(a,n))}else n=W,n=Ae(a,a===il?n:0,a.cancelPendingCommit!==null||a.timeoutHandle!==-1),(n&3)===0||Ta(a,n)||(u=!0,gd(a,n));a=a.next}while(u);_c=!1}}function Wo(){hd()}function hd(){gn=Mc=!1;var l=0;Hu!==0&&(ay()&&(l=Hu),Hu=0);for(var t=bt(),u=null,a=mn;a!==null;){var e=a.next,n=rd(a,t);n===0?(a.next=null,u===null?mn=e:u.next=e,e===null&&(ya=u)):(u=a,(l!==0||(n&3)!==0)&&(gn=!0)),a=e}ee(l)}function rd(l,t){for(var u=l.suspendedLanes,a=l.pingedLanes,e=l.expirationTimes,n=l.pendingLanes&-62914561;0<n;){var f=31-Fl(n),c=1<<f,i=e[f];i===-1?((c&u)===0||(c&a)!==0)&&(e[f]=bv(c,t)):i<=t&&(l.expiredLanes|=c),n&=~c}if(t=il,u=W,u=Ae(l,l===t?u:0,l.cancelPendingCommit!==null||l.timeoutHandle!==-1),a=l.callbackNode,u===0||l===t&&(tl===2||tl===9)||l.cancelPendingCommit!==null)return a!==null&&a!==null&&xn(a),l.callbackNode=null,l.callbackPriority=0;if((u&3)===0||Ta(l,u)){if(t=u&-u,t===l.callbackPriority)return t;switch(a!==null&&xn(a),jn(u)){case 2:case 8:u=fi;break;case 32:u=be;break;case 268435456:u=ci;break;default:u=be}return a=md.bind(null,l),u=Gn(u,a),l.callbackPriority=t,l.callbackNode=u,t}return a!==null&&a!==null&&xn(a),l.callbackPriority=2,l.callbackNode=null,2}function md(l,t){if(pl!==0&&pl!==5)return l.callbackNode=null,l.callbackPriority=0,null;var u=l.callbackNode;if(rn()&&l.callbackNode!==u)return null;var a=W;return a=Ae(l,l===il?a:0,l.cancelPendingCommit!==null||l.timeoutHandle!==-1),a===0?null:(I0(l,a,t),rd(l,bt()),l.callbackNode!=null&&l.callbackNode===u?md.bind(null,l):null)}function gd(l,t){if(rn())return null;I0(l,t,!0)}function $o(){ny(function(){(ll&6)!==0?Gn(ni,Wo):hd()})}function Dc(){return Hu===0&&(Hu=ii()),Hu}function Sd(l){return l==null||typeof l=="symbol"||typeof l=="boolean"?null:typeof l=="function"?l:De(""+l)}function bd(l,t){var u=t.ownerDocument.createElement("input");return u.name=t.name,u.value=t.value,l.id&&u.setAttribute("form",l.id),t.parentNode.insertBefore(u,t),l=new FormData(l),u.parentNode.removeChild(u),l}function ko(l,t,u,a,e){if(t==="submit"&&u&&u.stateNode===e){var n=Sd((e[Zl]||null).action),f=a.submitter;f&&(t=(t=f[Zl]||null)?Sd(t.formAction):f.getAttribute("formAction"),t!==null&&(n=t,f=null));var c=new He("action","action",null,a,e);l.push({event:c,listeners:[{instance:null,listener:function(){if(a.defaultPrevented){if(Hu!==0){var i=f?bd(e,f):new FormData(e);wf(u,{pending:!0,data:i,method:e.method,action:n},null,i)}}else typeof n=="function"&&(c.preventDefault(),i=f?bd(e,f):new FormData(e),wf(u,{pending:!0,data:i,method:e.method,action:n},n,i))},currentTarget:e}]})}}for(var Uc=0;Uc<of.length;Uc++){var Rc=of[Uc],Fo=Rc.toLowerCase(),Io=Rc[0].toUpperCase()+Rc.slice(1);rt(Fo,"on"+Io)}rt(Ii,"onAnimationEnd"),rt(Pi,"onAnimationIteration"),rt(ls,"onAnimationStart"),rt("dblclick","onDoubleClick"),rt("focusin","onFocus"),rt("focusout","onBlur"),rt(ro,"onTransitionRun"),rt(mo,"onTransitionStart"),rt(go,"onTransitionCancel"),rt(ts,"onTransitionEnd"),xu("onMouseEnter",["mouseout","mouseover"]),xu("onMouseLeave",["mouseout","mouseover"]),xu("onPointerEnter",["pointerout","pointerover"]),xu("onPointerLeave",["pointerout","pointerover"]),ru("onChange","change click focusin focusout input keydown keyup selectionchange".split(" ")),ru("onSelect","focusout contextmenu dragend focusin keydown keyup mousedown mouseup selectionchange".split(" ")),ru("onBeforeInput",["compositionend","keypress","textInput","paste"]),ru("onCompositionEnd","compositionend focusout keydown keypress keyup mousedown".split(" ")),ru("onCompositionStart","compositionstart focusout keydown keypress keyup mousedown".split(" ")),ru("onCompositionUpdate","compositionupdate focusout keydown keypress keyup mousedown".split(" "));var ne="abort canplay canplaythrough durationchange emptied encrypted ended error loadeddata loadedmetadata loadstart pause play playing progress ratechange resize seeked seeking stalled suspend timeupdate volumechange waiting".split(" "),Po=new Set("beforetoggle cancel close invalid load scroll scrollend toggle".split(" ").concat(ne));function Td(l,t){t=(t&4)!==0;for(var u=0;u<l.length;u++){var a=l[u],e=a.event;a=a.listeners;l:{var n=void 0;if(t)for(var f=a.length-1;0<=f;f--){var c=a[f],i=c.instance,h=c.currentTarget;if(c=c.listener,i!==n&&e.isPropagationStopped())break l;n=c,e.currentTarget=h;try{n(e)}catch(g){an(g)}e.currentTarget=null,n=i}else for(f=0;f<a.length;f++){if(c=a[f],i=c.instance,h=c.currentTarget,c=c.listener,i!==n&&e.isPropagationStopped())break l;n=c,e.currentTarget=h;try{n(e)}catch(g){an(g)}e.currentTarget=null,n=i}}}}function J(l,t){var u=t[Cn];u===void 0&&(u=t[Cn]=new Set);var a=l+"__bubble";u.has(a)||(Ed(t,l,2,!1),u.add(a))}function Nc(l,t,u){var a=0;t&&(a|=4),Ed(u,l,a,t)}var Sn="_reactListening"+Math.random().toString(36).slice(2);function Hc(l){if(!l[Sn]){l[Sn]=!0,hi.forEach(function(u){u!=="selectionchange"&&(Po.has(u)||Nc(u,!1,l),Nc(u,!0,l))});var t=l.nodeType===9?l:l.ownerDocument;t===null||t[Sn]||(t[Sn]=!0,Nc("selectionchange",!1,t))}}function Ed(l,t,u,a){switch(Kd(t)){case 2:var e=_y;break;case 8:e=Dy;break;default:e=Kc}u=e.bind(null,t,u,l),e=void 0,!In||t!=="touchstart"&&t!=="touchmove"&&t!=="wheel"||(e=!0),a?e!==void 0?l.addEventListener(t,u,{capture:!0,passive:e}):l.addEventListener(t,u,!0):e!==void 0?l.addEventListener(t,u,{passive:e}):l.addEventListener(t,u,!1)}function pc(l,t,u,a,e){var n=a;if((t&1)===0&&(t&2)===0&&a!==null)l:for(;;){if(a===null)return;var f=a.tag;if(f===3||f===4){var c=a.stateNode.containerInfo;if(c===e)break;if(f===4)for(f=a.return;f!==null;){var i=f.tag;if((i===3||i===4)&&f.stateNode.containerInfo===e)return;f=f.return}for(;c!==null;){if(f=Yu(c),f===null)return;if(i=f.tag,i===5||i===6||i===26||i===27){a=n=f;continue l}c=c.parentNode}}a=a.return}Ui(function(){var h=n,g=kn(u),E=[];l:{var r=us.get(l);if(r!==void 0){var m=He,Q=l;switch(l){case"keypress":if(Re(u)===0)break l;case"keydown":case"keyup":m=Jv;break;case"focusin":Q="focus",m=uf;break;case"focusout":Q="blur",m=uf;break;case"beforeblur":case"afterblur":m=uf;break;case"click":if(u.button===2)break l;case"auxclick":case"dblclick":case"mousedown":case"mousemove":case"mouseup":case"mouseout":case"mouseover":case"contextmenu":m=Hi;break;case"drag":case"dragend":case"dragenter":case"dragexit":case"dragleave":case"dragover":case"dragstart":case"drop":m=Yv;break;case"touchcancel":case"touchend":case"touchmove":case"touchstart":m=$v;break;case Ii:case Pi:case ls:m=xv;break;case ts:m=Fv;break;case"scroll":case"scrollend":m=pv;break;case"wheel":m=Pv;break;case"copy":case"cut":case"paste":m=Qv;break;case"gotpointercapture":case"lostpointercapture":case"pointercancel":case"pointerdown":case"pointermove":case"pointerout":case"pointerover":case"pointerup":m=qi;break;case"toggle":case"beforetoggle":m=to}var B=(t&4)!==0,nl=!B&&(l==="scroll"||l==="scrollend"),o=B?r!==null?r+"Capture":null:r;B=[];for(var d=h,y;d!==null;){var b=d;if(y=b.stateNode,b=b.tag,b!==5&&b!==26&&b!==27||y===null||o===null||(b=Oa(d,o),b!=null&&B.push(fe(d,b,y))),nl)break;d=d.return}0<B.length&&(r=new m(r,Q,null,u,g),E.push({event:r,listeners:B}))}}if((t&7)===0){l:{if(r=l==="mouseover"||l==="pointerover",m=l==="mouseout"||l==="pointerout",r&&u!==$n&&(Q=u.relatedTarget||u.fromElement)&&(Yu(Q)||Q[qu]))break l;if((m||r)&&(r=g.window===g?g:(r=g.ownerDocument)?r.defaultView||r.parentWindow:window,m?(Q=u.relatedTarget||u.toElement,m=h,Q=Q?Yu(Q):null,Q!==null&&(nl=X(Q),B=Q.tag,Q!==nl||B!==5&&B!==27&&B!==6)&&(Q=null)):(m=null,Q=h),m!==Q)){if(B=Hi,b="onMouseLeave",o="onMouseEnter",d="mouse",(l==="pointerout"||l==="pointerover")&&(B=qi,b="onPointerLeave",o="onPointerEnter",d="pointer"),nl=m==null?r:za(m),y=Q==null?r:za(Q),r=new B(b,d+"leave",m,u,g),r.target=nl,r.relatedTarget=y,b=null,Yu(g)===h&&(B=new B(o,d+"enter",Q,u,g),B.target=y,B.relatedTarget=nl,b=B),nl=b,m&&Q)t:{for(B=m,o=Q,d=0,y=B;y;y=ha(y))d++;for(y=0,b=o;b;b=ha(b))y++;for(;0<d-y;)B=ha(B),d--;for(;0<y-d;)o=ha(o),y--;for(;d--;){if(B===o||o!==null&&B===o.alternate)break t;B=ha(B),o=ha(o)}B=null}else B=null;m!==null&&Ad(E,r,m,B,!1),Q!==null&&nl!==null&&Ad(E,nl,Q,B,!0)}}l:{if(r=h?za(h):window,m=r.nodeName&&r.nodeName.toLowerCase(),m==="select"||m==="input"&&r.type==="file")var H=Ci;else if(Qi(r))if(Zi)H=oo;else{H=so;var L=io}else m=r.nodeName,!m||m.toLowerCase()!=="input"||r.type!=="checkbox"&&r.type!=="radio"?h&&Wn(h.elementType)&&(H=Ci):H=vo;if(H&&(H=H(l,h))){ji(E,H,u,g);break l}L&&L(l,r,h),l==="focusout"&&h&&r.type==="number"&&h.memoizedProps.value!=null&&wn(r,"number",r.value)}switch(L=h?za(h):window,l){case"focusin":(Qi(L)||L.contentEditable==="true")&&(Lu=L,sf=h,pa=null);break;case"focusout":pa=sf=Lu=null;break;case"mousedown":df=!0;break;case"contextmenu":case"mouseup":case"dragend":df=!1,ki(E,u,g);break;case"selectionchange":if(ho)break;case"keydown":case"keyup":ki(E,u,g)}var q;if(ef)l:{switch(l){case"compositionstart":var G="onCompositionStart";break l;case"compositionend":G="onCompositionEnd";break l;case"compositionupdate":G="onCompositionUpdate";break l}G=void 0}else Vu?xi(l,u)&&(G="onCompositionEnd"):l==="keydown"&&u.keyCode===229&&(G="onCompositionStart");G&&(Yi&&u.locale!=="ko"&&(Vu||G!=="onCompositionStart"?G==="onCompositionEnd"&&Vu&&(q=Ri()):(Lt=g,Pn="value"in Lt?Lt.value:Lt.textContent,Vu=!0)),L=bn(h,G),0<L.length&&(G=new pi(G,l,null,u,g),E.push({event:G,listeners:L}),q?G.data=q:(q=Xi(u),q!==null&&(G.data=q)))),(q=ao?eo(l,u):no(l,u))&&(G=bn(h,"onBeforeInput"),0<G.length&&(L=new pi("onBeforeInput","beforeinput",null,u,g),E.push({event:L,listeners:G}),L.data=q)),ko(E,l,h,u,g)}Td(E,t)})}function fe(l,t,u){return{instance:l,listener:t,currentTarget:u}}function bn(l,t){for(var u=t+"Capture",a=[];l!==null;){var e=l,n=e.stateNode;if(e=e.tag,e!==5&&e!==26&&e!==27||n===null||(e=Oa(l,u),e!=null&&a.unshift(fe(l,e,n)),e=Oa(l,t),e!=null&&a.push(fe(l,e,n))),l.tag===3)return a;l=l.return}return[]}function ha(l){if(l===null)return null;do l=l.return;while(l&&l.tag!==5&&l.tag!==27);return l||null}function Ad(l,t,u,a,e){for(var n=t._reactName,f=[];u!==null&&u!==a;){var c=u,i=c.alternate,h=c.stateNode;if(c=c.tag,i!==null&&i===a)break;c!==5&&c!==26&&c!==27||h===null||(i=h,e?(h=Oa(u,n),h!=null&&f.unshift(fe(u,h,i))):e||(h=Oa(u,n),h!=null&&f.push(fe(u,h,i)))),u=u.return}f.length!==0&&l.push({event:t,listeners:f})}var ly=/\r\n?/g,ty=/\u0000|\uFFFD/g;function zd(l){return(typeof l=="string"?l:""+l).replace(ly,`
 `).replace(ty,"")}function Od(l,t){return t=zd(t),zd(l)===t}function Tn(){}function el(l,t,u,a,e,n){switch(u){case"children":typeof a=="string"?t==="body"||t==="textarea"&&a===""||ju(l,a):(typeof a=="number"||typeof a=="bigint")&&t!=="body"&&ju(l,""+a);break;case"className":Oe(l,"class",a);break;case"tabIndex":Oe(l,"tabindex",a);break;case"dir":case"role":case"viewBox":case"width":case"height":Oe(l,u,a);break;case"style":_i(l,a,n);break;case"data":if(t!=="object"){Oe(l,"data",a);break}case"src":case"href":if(a===""&&(t!=="a"||u!=="href")){l.removeAttribute(u);break}if(a==null||typeof a=="function"||typeof a=="symbol"||typeof a=="boolean"){l.removeAttribute(u);break}a=De(""+a),l.setAttribute(u,a);break;case"action":case"formAction":if(typeof a=="function"){l.setAttribute(u,"javascript:throw new Error('A React form was unexpectedly submitted. If you called form.submit() manually, consider using form.requestSubmit() instead. If you\\'re trying to use event.stopPropagation() in a submit event handler, consider also calling event.preventDefault().')");break}else typeof n=="function"&&(u==="formAction"?(t!=="input"&&el(l,t,"name",e.name,e,null),el(l,t,"formEncType",e.formEncType,e,null),el(l,t,"formMethod",e.formMethod,e,null),el(l,t,"formTarget",e.formTarget,e,null)):(el(l,t,"encType",e.encType,e,null),el(l,t,"method",e.method,e,null),el(l,t,"target",e.target,e,null)));if(a==null||typeof a=="symbol"||typeof a=="boolean"){l.removeAttribute(u);break}a=De(""+a),l.setAttribute(u,a);break;case"onClick":a!=null&&(l.onclick=Tn);break;case"onScroll":a!=null&&J("scroll",l);break;case"onScrollEnd":a!=null&&J("scrollend",l);break;case"dangerouslySetInnerHTML":if(a!=null){if(typeof a!="object"||!("__html"in a))throw Error(v(61));if(u=a.__html,u!=null){if(e.children!=null)throw Error(v(60));l.innerHTML=u}}break;case"multiple":l.multiple=a&&typeof a!="function"&&typeof a!="symbol";break;case"muted":l.muted=a&&typeof a!="function"&&typeof a!="symbol";break;case"suppressContentEditableWarning":case"suppressHydrationWarning":case"defaultValue":case"defaultChecked":case"innerHTML":case"ref":break;case"autoFocus":break;case"xlinkHref":if(a==null||typeof a=="function"||typeof a=="boolean"||typeof a=="symbol"){l.removeAttribute("xlink:href");break}u=De(""+a),l.setAttributeNS("http://www.w3.org/1999/xlink","xlink:href",u);break;case"contentEditable":case"spellCheck":case"draggable":case"value":case"autoReverse":case"externalResourcesRequired":case"focusable":case"preserveAlpha":a!=null&&typeof a!="function"&&typeof a!="symbol"?l.setAttribute(u,""+a):l.removeAttribute(u);break;case"inert":case"allowFullScreen":case"async":case"autoPlay":case"controls":case"default":case"defer":case"disabled":case"disablePictureInPicture":case"disableRemotePlayback":case"formNoValidate":case"hidden":case"loop":case"noModule":case"noValidate":case"open":case"playsInline":case"readOnly":case"required":case"reversed":case"scoped":case"seamless":case"itemScope":a&&typeof a!="function"&&typeof a!="symbol"?l.setAttribute(u,""):l.removeAttribute(u);break;case"capture":case"download":a===!0?l.setAttribute(u,""):a!==!1&&a!=null&&typeof a!="function"&&typeof a!="symbol"?l.setAttribute(u,a):l.removeAttribute(u);break;case"cols":case"rows":case"size":case"span":a!=null&&typeof a!="function"&&typeof a!="symbol"&&!isNaN(a)&&1<=a?l.setAttribute(u,a):l.removeAttribute(u);break;case"rowSpan":case"start":a==null||typeof a=="function"||typeof a=="symbol"||isNaN(a)?l.removeAttribute(u):l.setAttribute(u,a);break;case"popover":J("beforetoggle",l),J("toggle",l),ze(l,"popover",a);break;case"xlinkActuate":_t(l,"http://www.w3.org/1999/xlink","xlink:actuate",a);break;case"xlinkArcrole":_t(l,"http://www.w3.org/1999/xlink","xlink:arcrole",a);break;case"xlinkRole":_t(l,"http://www.w3.org/1999/xlink","xlink:role",a);break;case"xlinkShow":_t(l,"http://www.w3.org/1999/xlink","xlink:show",a);break;case"xlinkTitle":_t(l,"http://www.w3.org/1999/xlink","xlink:title",a);break;case"xlinkType":_t(l,"http://www.w3.org/1999/xlink","xlink:type",a);break;case"xmlBase":_t(l,"http://www.w3.org/XML/1998/namespace","xml:base",a);break;case"xmlLang":_t(l,"http://www.w3.org/XML/1998/namespace","xml:lang",a);break;case"xmlSpace":_t(l,"http://www.w3.org/XML/1998/namespace","xml:space",a);break;case"is":ze(l,"is",a);break;case"innerText":case"textContent":break;default:(!(2<u.length)||u[0]!=="o"&&u[0]!=="O"||u[1]!=="n"&&u[1]!=="N")&&(u=Nv.get(u)||u,ze(l,u,a))}}function qc(l,t,u,a,e,n){switch(u){case"style":_i(l,a,n);break;case"dangerouslySetInnerHTML":if(a!=null){if(typeof a!="object"||!("__html"in a))throw Error(v(61));if(u=a.__html,u!=null){if(e.children!=null)throw Error(v(60));l.innerHTML=u}}break;case"children":typeof a=="string"?ju(l,a):(typeof a=="number"||typeof a=="bigint")&&ju(l,""+a);break;case"onScroll":a!=null&&J("scroll",l);break;case"onScrollEnd":a!=null&&J("scrollend",l);break;case"onClick":a!=null&&(l.onclick=Tn);break;case"suppressContentEditableWarning":case"suppressHydrationWarning":case"innerHTML":case"ref":break;case"innerText":case"textContent":break;default:if(!ri.hasOwnProperty(u))l:{if(u[0]==="o"&&u[1]==="n"&&(e=u.endsWith("Capture"),t=u.slice(2,e?u.length-7:void 0),n=l[Zl]||null,n=n!=null?n[u]:null,typeof n=="function"&&l.removeEventListener(t,n,e),typeof a=="function")){typeof n!="function"&&n!==null&&(u in l?l[u]=null:l.hasAttribute(u)&&l.removeAttribute(u)),l.addEventListener(t,a,e);break l}u in l?l[u]=a:a===!0?l.setAttribute(u,""):ze(l,u,a)}}}function ql(l,t,u){switch(t){case"div":case"span":case"svg":case"path":case"a":case"g":case"p":case"li":break;case"img":J("error",l),J("load",l);var a=!1,e=!1,n;for(n in u)if(u.hasOwnProperty(n)){var f=u[n];if(f!=null)switch(n){case"src":a=!0;break;case"srcSet":e=!0;break;case"children":case"dangerouslySetInnerHTML":throw Error(v(137,t));default:el(l,t,n,f,u,null)}}e&&el(l,t,"srcSet",u.srcSet,u,null),a&&el(l,t,"src",u.src,u,null);return;case"input":J("invalid",l);var c=n=f=e=null,i=null,h=null;for(a in u)if(u.hasOwnProperty(a)){var g=u[a];if(g!=null)switch(a){case"name":e=g;break;case"type":f=g;break;case"checked":i=g;break;case"defaultChecked":h=g;break;case"value":n=g;break;case"defaultValue":c=g;break;case"children":case"dangerouslySetInnerHTML":if(g!=null)throw Error(v(137,t));break;default:el(l,t,a,g,u,null)}}Ai(l,n,c,i,h,f,e,!1),Me(l);return;case"select":J("invalid",l),a=f=n=null;for(e in u)if(u.hasOwnProperty(e)&&(c=u[e],c!=null))switch(e){case"value":n=c;break;case"defaultValue":f=c;break;case"multiple":a=c;default:el(l,t,e,c,u,null)}t=n,u=f,l.multiple=!!a,t!=null?Qu(l,!!a,t,!1):u!=null&&Qu(l,!!a,u,!0);return;case"textarea":J("invalid",l),n=e=a=null;for(f in u)if(u.hasOwnProperty(f)&&(c=u[f],c!=null))switch(f){case"value":a=c;break;case"defaultValue":e=c;break;case"children":n=c;break;case"dangerouslySetInnerHTML":if(c!=null)throw Error(v(91));break;default:el(l,t,f,c,u,null)}Oi(l,a,e,n),Me(l);return;case"option":for(i in u)if(u.hasOwnProperty(i)&&(a=u[i],a!=null))switch(i){case"selected":l.selected=a&&typeof a!="function"&&typeof a!="symbol";break;default:el(l,t,i,a,u,null)}return;case"dialog":J("beforetoggle",l),J("toggle",l),J("cancel",l),J("close",l);break;case"iframe":case"object":J("load",l);break;case"video":case"audio":for(a=0;a<ne.length;a++)J(ne[a],l);break;case"image":J("error",l),J("load",l);break;case"details":J("toggle",l);break;case"embed":case"source":case"link":J("error",l),J("load",l);case"area":case"base":case"br":case"col":case"hr":case"keygen":case"meta":case"param":case"track":case"wbr":case"menuitem":for(h in u)if(u.hasOwnProperty(h)&&(a=u[h],a!=null))switch(h){case"children":case"dangerouslySetInnerHTML":throw Error(v(137,t));default:el(l,t,h,a,u,null)}return;default:if(Wn(t)){for(g in u)u.hasOwnProperty(g)&&(a=u[g],a!==void 0&&qc(l,t,g,a,u,void 0));return}}for(c in u)u.hasOwnProperty(c)&&(a=u[c],a!=null&&el(l,t,c,a,u,null))}function uy(l,t,u,a){switch(t){case"div":case"span":case"svg":case"path":case"a":case"g":case"p":case"li":break;case"input":var e=null,n=null,f=null,c=null,i=null,h=null,g=null;for(m in u){var E=u[m];if(u.hasOwnProperty(m)&&E!=null)switch(m){case"checked":break;case"value":break;case"defaultValue":i=E;default:a.hasOwnProperty(m)||el(l,t,m,null,a,E)}}for(var r in a){var m=a[r];if(E=u[r],a.hasOwnProperty(r)&&(m!=null||E!=null))switch(r){case"type":n=m;break;case"name":e=m;break;case"checked":h=m;break;case"defaultChecked":g=m;break;case"value":f=m;break;case"defaultValue":c=m;break;case"children":case"dangerouslySetInnerHTML":if(m!=null)throw Error(v(137,t));break;default:m!==E&&el(l,t,r,m,a,E)}}Jn(l,f,c,i,h,g,n,e);return;case"select":m=f=c=r=null;for(n in u)if(i=u[n],u.hasOwnProperty(n)&&i!=null)switch(n){case"value":break;case"multiple":m=i;default:a.hasOwnProperty(n)||el(l,t,n,null,a,i)}for(e in a)if(n=a[e],i=u[e],a.hasOwnProperty(e)&&(n!=null||i!=null))switch(e){case"value":r=n;break;case"defaultValue":c=n;break;case"multiple":f=n;default:n!==i&&el(l,t,e,n,a,i)}t=c,u=f,a=m,r!=null?Qu(l,!!u,r,!1):!!a!=!!u&&(t!=null?Qu(l,!!u,t,!0):Qu(l,!!u,u?[]:"",!1));return;case"textarea":m=r=null;for(c in u)if(e=u[c],u.hasOwnProperty(c)&&e!=null&&!a.hasOwnProperty(c))switch(c){case"value":break;case"children":break;default:el(l,t,c,null,a,e)}for(f in a)if(e=a[f],n=u[f],a.hasOwnProperty(f)&&(e!=null||n!=null))switch(f){case"value":r=e;break;case"defaultValue":m=e;break;case"children":break;case"dangerouslySetInnerHTML":if(e!=null)throw Error(v(91));break;default:e!==n&&el(l,t,f,e,a,n)}zi(l,r,m);return;case"option":for(var Q in u)if(r=u[Q],u.hasOwnProperty(Q)&&r!=null&&!a.hasOwnProperty(Q))switch(Q){case"selected":l.selected=!1;break;default:el(l,t,Q,null,a,r)}for(i in a)if(r=a[i],m=u[i],a.hasOwnProperty(i)&&r!==m&&(r!=null||m!=null))switch(i){case"selected":l.selected=r&&typeof r!="function"&&typeof r!="symbol";break;default:el(l,t,i,r,a,m)}return;case"img":case"link":case"area":case"base":case"br":case"col":case"embed":case"hr":case"keygen":case"meta":case"param":case"source":case"track":case"wbr":case"menuitem":for(var B in u)r=u[B],u.hasOwnProperty(B)&&r!=null&&!a.hasOwnProperty(B)&&el(l,t,B,null,a,r);for(h in a)if(r=a[h],m=u[h],a.hasOwnProperty(h)&&r!==m&&(r!=null||m!=null))switch(h){case"children":case"dangerouslySetInnerHTML":if(r!=null)throw Error(v(137,t));break;default:el(l,t,h,r,a,m)}return;default:if(Wn(t)){for(var nl in u)r=u[nl],u.hasOwnProperty(nl)&&r!==void 0&&!a.hasOwnProperty(nl)&&qc(l,t,nl,void 0,a,r);for(g in a)r=a[g],m=u[g],!a.hasOwnProperty(g)||r===m||r===void 0&&m===void 0||qc(l,t,g,r,a,m);return}}for(var o in u)r=u[o],u.hasOwnProperty(o)&&r!=null&&!a.hasOwnProperty(o)&&el(l,t,o,null,a,r);for(E in a)r=a[E],m=u[E],!a.hasOwnProperty(E)||r===m||r==null&&m==null||el(l,t,E,r,a,m)}var Yc=null,Bc=null;function En(l){return l.nodeType===9?l:l.ownerDocument}function Md(l){switch(l){case"http://www.w3.org/2000/svg":return 1;case"http://www.w3.org/1998/Math/MathML":return 2;default:return 0}}function _d(l,t){if(l===0)switch(t){case"svg":return 1;case"math":return 2;default:return 0}return l===1&&t==="foreignObject"?0:l}function Gc(l,t){return l==="textarea"||l==="noscript"||typeof t.children=="string"||typeof t.children=="number"||typeof t.children=="bigint"||typeof t.dangerouslySetInnerHTML=="object"&&t.dangerouslySetInnerHTML!==null&&t.dangerouslySetInnerHTML.__html!=null}var xc=null;function ay(){var l=window.event;return l&&l.type==="popstate"?l===xc?!1:(xc=l,!0):(xc=null,!1)}var Dd=typeof setTimeout=="function"?setTimeout:void 0,ey=typeof clearTimeout=="function"?clearTimeout:void 0,Ud=typeof Promise=="function"?Promise:void 0,ny=typeof queueMicrotask=="function"?queueMicrotask:typeof Ud<"u"?function(l){return Ud.resolve(null).then(l).catch(fy)}:Dd;function fy(l){setTimeout(function(){throw l})}function fu(l){return l==="head"}function Rd(l,t){var u=t,a=0,e=0;do{var n=u.nextSibling;if(l.removeChild(u),n&&n.nodeType===8)if(u=n.data,u==="/$"){if(0<a&&8>a){u=a;var f=l.ownerDocument;if(u&1&&ce(f.documentElement),u&2&&ce(f.body),u&4)for(u=f.head,ce(u),f=u.firstChild;f;){var c=f.nextSibling,i=f.nodeName;f[Aa]||i==="SCRIPT"||i==="STYLE"||i==="LINK"&&f.rel.toLowerCase()==="stylesheet"||u.removeChild(f),f=c}}if(e===0){l.removeChild(n),re(t);return}e--}else u==="$"||u==="$?"||u==="$!"?e++:a=u.charCodeAt(0)-48;else a=0;u=n}while(u);re(t)}function Xc(l){var t=l.firstChild;for(t&&t.nodeType===10&&(t=t.nextSibling);t;){var u=t;switch(t=t.nextSibling,u.nodeName){case"HTML":case"HEAD":case"BODY":Xc(u),Zn(u);continue;case"SCRIPT":case"STYLE":continue;case"LINK":if(u.rel.toLowerCase()==="stylesheet")continue}l.removeChild(u)}}function cy(l,t,u,a){for(;l.nodeType===1;){var e=u;if(l.nodeName.toLowerCase()!==t.toLowerCase()){if(!a&&(l.nodeName!=="INPUT"||l.type!=="hidden"))break}else if(a){if(!l[Aa])switch(t){case"meta":if(!l.hasAttribute("itemprop"))break;return l;case"link":if(n=l.getAttribute("rel"),n==="stylesheet"&&l.hasAttribute("data-precedence"))break;if(n!==e.rel||l.getAttribute("href")!==(e.href==null||e.href===""?null:e.href)||l.getAttribute("crossorigin")!==(e.crossOrigin==null?null:e.crossOrigin)||l.getAttribute("title")!==(e.title==null?null:e.title))break;return l;case"style":if(l.hasAttribute("data-precedence"))break;return l;case"script":if(n=l.getAttribute("src"),(n!==(e.src==null?null:e.src)||l.getAttribute("type")!==(e.type==null?null:e.type)||l.getAttribute("crossorigin")!==(e.crossOrigin==null?null:e.crossOrigin))&&n&&l.hasAttribute("async")&&!l.hasAttribute("itemprop"))break;return l;default:return l}}else if(t==="input"&&l.type==="hidden"){var n=e.name==null?null:""+e.name;if(e.type==="hidden"&&l.getAttribute("name")===n)return l}else return l;if(l=gt(l.nextSibling),l===null)break}return null}function iy(l,t,u){if(t==="")return null;for(;l.nodeType!==3;)if((l.nodeType!==1||l.nodeName!=="INPUT"||l.type!=="hidden")&&!u||(l=gt(l.nextSibling),l===null))return null;return l}function Qc(l){return l.data==="$!"||l.data==="$?"&&l.ownerDocument.readyState==="complete"}function sy(l,t){var u=l.ownerDocument;if(l.data!=="$?"||u.readyState==="complete")t();else{var a=function(){t(),u.removeEventListener("DOMContentLoaded",a)};u.addEventListener("DOMContentLoaded",a),l._reactRetry=a}}function gt(l){for(;l!=null;l=l.nextSibling){var t=l.nodeType;if(t===1||t===3)break;if(t===8){if(t=l.data,t==="$"||t==="$!"||t==="$?"||t==="F!"||t==="F")break;if(t==="/$")return null}}return l}var jc=null;function Nd(l){l=l.previousSibling;for(var t=0;l;){if(l.nodeType===8){var u=l.data;if(u==="$"||u==="$!"||u==="$?"){if(t===0)return l;t--}else u==="/$"&&t++}l=l.previousSibling}return null}function Hd(l,t,u){switch(t=En(u),l){case"html":if(l=t.documentElement,!l)throw Error(v(452));return l;case"head":if(l=t.head,!l)throw Error(v(453));return l;case"body":if(l=t.body,!l)throw Error(v(454));return l;default:throw Error(v(451))}}function ce(l){for(var t=l.attributes;t.length;)l.removeAttributeNode(t[0]);Zn(l)}var yt=new Map,pd=new Set;function An(l){return typeof l.getRootNode=="function"?l.getRootNode():l.nodeType===9?l:l.ownerDocument}var jt=_.d;_.d={f:dy,r:vy,D:oy,C:yy,L:hy,m:ry,X:gy,S:my,M:Sy};function dy(){var l=jt.f(),t=yn();return l||t}function vy(l){var t=Bu(l);t!==null&&t.tag===5&&t.type==="form"?Is(t):jt.r(l)}var ra=typeof document>"u"?null:document;function qd(l,t,u){var a=ra;if(a&&typeof t=="string"&&t){var e=ft(t);e='link[rel="'+l+'"][href="'+e+'"]',typeof u=="string"&&(e+='[crossorigin="'+u+'"]'),pd.has(e)||(pd.add(e),l={rel:l,crossOrigin:u,href:t},a.querySelector(e)===null&&(t=a.createElement("link"),ql(t,"link",l),Ml(t),a.head.appendChild(t)))}}function oy(l){jt.D(l),qd("dns-prefetch",l,null)}function yy(l,t){jt.C(l,t),qd("preconnect",l,t)}function hy(l,t,u){jt.L(l,t,u);var a=ra;if(a&&l&&t){var e='link[rel="preload"][as="'+ft(t)+'"]';t==="image"&&u&&u.imageSrcSet?(e+='[imagesrcset="'+ft(u.imageSrcSet)+'"]',typeof u.imageSizes=="string"&&(e+='[imagesizes="'+ft(u.imageSizes)+'"]')):e+='[href="'+ft(l)+'"]';var n=e;switch(t){case"style":n=ma(l);break;case"script":n=ga(l)}yt.has(n)||(l=p({rel:"preload",href:t==="image"&&u&&u.imageSrcSet?void 0:l,as:t},u),yt.set(n,l),a.querySelector(e)!==null||t==="style"&&a.querySelector(ie(n))||t==="script"&&a.querySelector(se(n))||(t=a.createElement("link"),ql(t,"link",l),Ml(t),a.head.appendChild(t)))}}function ry(l,t){jt.m(l,t);var u=ra;if(u&&l){var a=t&&typeof t.as=="string"?t.as:"script",e='link[rel="modulepreload"][as="'+ft(a)+'"][href="'+ft(l)+'"]',n=e;switch(a){case"audioworklet":case"paintworklet":case"serviceworker":case"sharedworker":case"worker":case"script":n=ga(l)}if(!yt.has(n)&&(l=p({rel:"modulepreload",href:l},t),yt.set(n,l),u.querySelector(e)===null)){switch(a){case"audioworklet":case"paintworklet":case"serviceworker":case"sharedworker":case"worker":case"script":if(u.querySelector(se(n)))return}a=u.createElement("link"),ql(a,"link",l),Ml(a),u.head.appendChild(a)}}}function my(l,t,u){jt.S(l,t,u);var a=ra;if(a&&l){var e=Gu(a).hoistableStyles,n=ma(l);t=t||"default";var f=e.get(n);if(!f){var c={loading:0,preload:null};if(f=a.querySelector(ie(n)))c.loading=5;else{l=p({rel:"stylesheet",href:l,"data-precedence":t},u),(u=yt.get(n))&&Cc(l,u);var i=f=a.createElement("link");Ml(i),ql(i,"link",l),i._p=new Promise(function(h,g){i.onload=h,i.onerror=g}),i.addEventListener("load",function(){c.loading|=1}),i.addEventListener("error",function(){c.loading|=2}),c.loading|=4,zn(f,t,a)}f={type:"stylesheet",instance:f,count:1,state:c},e.set(n,f)}}}function gy(l,t){jt.X(l,t);var u=ra;if(u&&l){var a=Gu(u).hoistableScripts,e=ga(l),n=a.get(e);n||(n=u.querySelector(se(e)),n||(l=p({src:l,async:!0},t),(t=yt.get(e))&&Zc(l,t),n=u.createElement("script"),Ml(n),ql(n,"link",l),u.head.appendChild(n)),n={type:"script",instance:n,count:1,state:null},a.set(e,n))}}function Sy(l,t){jt.M(l,t);var u=ra;if(u&&l){var a=Gu(u).hoistableScripts,e=ga(l),n=a.get(e);n||(n=u.querySelector(se(e)),n||(l=p({src:l,async:!0,type:"module"},t),(t=yt.get(e))&&Zc(l,t),n=u.createElement("script"),Ml(n),ql(n,"link",l),u.head.appendChild(n)),n={type:"script",instance:n,count:1,state:null},a.set(e,n))}}function Yd(l,t,u,a){var e=(e=C.current)?An(e):null;if(!e)throw Error(v(446));switch(l){case"meta":case"title":return null;case"style":return typeof u.precedence=="string"&&typeof u.href=="string"?(t=ma(u.href),u=Gu(e).hoistableStyles,a=u.get(t),a||(a={type:"style",instance:null,count:0,state:null},u.set(t,a)),a):{type:"void",instance:null,count:0,state:null};case"link":if(u.rel==="stylesheet"&&typeof u.href=="string"&&typeof u.precedence=="string"){l=ma(u.href);var n=Gu(e).hoistableStyles,f=n.get(l);if(f||(e=e.ownerDocument||e,f={type:"stylesheet",instance:null,count:0,state:{loading:0,preload:null}},n.set(l,f),(n=e.querySelector(ie(l)))&&!n._p&&(f.instance=n,f.state.loading=5),yt.has(l)||(u={rel:"preload",as:"style",href:u.href,crossOrigin:u.crossOrigin,integrity:u.integrity,media:u.media,hrefLang:u.hrefLang,referrerPolicy:u.referrerPolicy},yt.set(l,u),n||by(e,l,u,f.state))),t&&a===null)throw Error(v(528,""));return f}if(t&&a!==null)throw Error(v(529,""));return null;case"script":return t=u.async,u=u.src,typeof u=="string"&&t&&typeof t!="function"&&typeof t!="symbol"?(t=ga(u),u=Gu(e).hoistableScripts,a=u.get(t),a||(a={type:"script",instance:null,count:0,state:null},u.set(t,a)),a):{type:"void",instance:null,count:0,state:null};default:throw Error(v(444,l))}}function ma(l){return'href="'+ft(l)+'"'}function ie(l){return'link[rel="stylesheet"]['+l+"]"}function Bd(l){return p({},l,{"data-precedence":l.precedence,precedence:null})}function by(l,t,u,a){l.querySelector('link[rel="preload"][as="style"]['+t+"]")?a.loading=1:(t=l.createElement("link"),a.preload=t,t.addEventListener("load",function(){return a.loading|=1}),t.addEventListener("error",function(){return a.loading|=2}),ql(t,"link",u),Ml(t),l.head.appendChild(t))}function ga(l){return'[src="'+ft(l)+'"]'}function se(l){return"script[async]"+l}function Gd(l,t,u){if(t.count++,t.instance===null)switch(t.type){case"style":var a=l.querySelector('style[data-href~="'+ft(u.href)+'"]');if(a)return t.instance=a,Ml(a),a;var e=p({},u,{"data-href":u.href,"data-precedence":u.precedence,href:null,precedence:null});return a=(l.ownerDocument||l).createElement("style"),Ml(a),ql(a,"style",e),zn(a,u.precedence,l),t.instance=a;case"stylesheet":e=ma(u.href);var n=l.querySelector(ie(e));if(n)return t.state.loading|=4,t.instance=n,Ml(n),n;a=Bd(u),(e=yt.get(e))&&Cc(a,e),n=(l.ownerDocument||l).createElement("link"),Ml(n);var f=n;return f._p=new Promise(function(c,i){f.onload=c,f.onerror=i}),ql(n,"link",a),t.state.loading|=4,zn(n,u.precedence,l),t.instance=n;case"script":return n=ga(u.src),(e=l.querySelector(se(n)))?(t.instance=e,Ml(e),e):(a=u,(e=yt.get(n))&&(a=p({},u),Zc(a,e)),l=l.ownerDocument||l,e=l.createElement("script"),Ml(e),ql(e,"link",a),l.head.appendChild(e),t.instance=e);case"void":return null;default:throw Error(v(443,t.type))}else t.type==="stylesheet"&&(t.state.loading&4)===0&&(a=t.instance,t.state.loading|=4,zn(a,u.precedence,l));return t.instance}function zn(l,t,u){for(var a=u.querySelectorAll('link[rel="stylesheet"][data-precedence],style[data-precedence]'),e=a.length?a[a.length-1]:null,n=e,f=0;f<a.length;f++){var c=a[f];if(c.dataset.precedence===t)n=c;else if(n!==e)break}n?n.parentNode.insertBefore(l,n.nextSibling):(t=u.nodeType===9?u.head:u,t.insertBefore(l,t.firstChild))}function Cc(l,t){l.crossOrigin==null&&(l.crossOrigin=t.crossOrigin),l.referrerPolicy==null&&(l.referrerPolicy=t.referrerPolicy),l.title==null&&(l.title=t.title)}function Zc(l,t){l.crossOrigin==null&&(l.crossOrigin=t.crossOrigin),l.referrerPolicy==null&&(l.referrerPolicy=t.referrerPolicy),l.integrity==null&&(l.integrity=t.integrity)}var On=null;function xd(l,t,u){if(On===null){var a=new Map,e=On=new Map;e.set(u,a)}else e=On,a=e.get(u),a||(a=new Map,e.set(u,a));if(a.has(l))return a;for(a.set(l,null),u=u.getElementsByTagName(l),e=0;e<u.length;e++){var n=u[e];if(!(n[Aa]||n[Gl]||l==="link"&&n.getAttribute("rel")==="stylesheet")&&n.namespaceURI!=="http://www.w3.org/2000/svg"){var f=n.getAttribute(t)||"";f=l+f;var c=a.get(f);c?c.push(n):a.set(f,[n])}}return a}function Xd(l,t,u){l=l.ownerDocument||l,l.head.insertBefore(u,t==="title"?l.querySelector("head > title"):null)}function Ty(l,t,u){if(u===1||t.itemProp!=null)return!1;switch(l){case"meta":case"title":return!0;case"style":if(typeof t.precedence!="string"||typeof t.href!="string"||t.href==="")break;return!0;case"link":if(typeof t.rel!="string"||typeof t.href!="string"||t.href===""||t.onLoad||t.onError)break;switch(t.rel){case"stylesheet":return l=t.disabled,typeof t.precedence=="string"&&l==null;default:return!0}case"script":if(t.async&&typeof t.async!="function"&&typeof t.async!="symbol"&&!t.onLoad&&!t.onError&&t.src&&typeof t.src=="string")return!0}return!1}function Qd(l){return!(l.type==="stylesheet"&&(l.state.loading&3)===0)}var de=null;function Ey(){}function Ay(l,t,u){if(de===null)throw Error(v(475));var a=de;if(t.type==="stylesheet"&&(typeof u.media!="string"||matchMedia(u.media).matches!==!1)&&(t.state.loading&4)===0){if(t.instance===null){var e=ma(u.href),n=l.querySelector(ie(e));if(n){l=n._p,l!==null&&typeof l=="object"&&typeof l.then=="function"&&(a.count++,a=Mn.bind(a),l.then(a,a)),t.state.loading|=4,t.instance=n,Ml(n);return}n=l.ownerDocument||l,u=Bd(u),(e=yt.get(e))&&Cc(u,e),n=n.createElement("link"),Ml(n);var f=n;f._p=new Promise(function(c,i){f.onload=c,f.onerror=i}),ql(n,"link",u),t.instance=n}a.stylesheets===null&&(a.stylesheets=new Map),a.stylesheets.set(t,l),(l=t.state.preload)&&(t.state.loading&3)===0&&(a.count++,t=Mn.bind(a),l.addEventListener("load",t),l.addEventListener("error",t))}}function zy(){if(de===null)throw Error(v(475));var l=de;return l.stylesheets&&l.count===0&&Vc(l,l.stylesheets),0<l.count?function(t){var u=setTimeout(function(){if(l.stylesheets&&Vc(l,l.stylesheets),l.unsuspend){var a=l.unsuspend;l.unsuspend=null,a()}},6e4);return l.unsuspend=t,function(){l.unsuspend=null,clearTimeout(u)}}:null}function Mn(){if(this.count--,this.count===0){if(this.stylesheets)Vc(this,this.stylesheets);else if(this.unsuspend){var l=this.unsuspend;this.unsuspend=null,l()}}}var _n=null;function Vc(l,t){l.stylesheets=null,l.unsuspend!==null&&(l.count++,_n=new Map,t.forEach(Oy,l),_n=null,Mn.call(l))}function Oy(l,t){if(!(t.state.loading&4)){var u=_n.get(l);if(u)var a=u.get(null);else{u=new Map,_n.set(l,u);for(var e=l.querySelectorAll("link[data-precedence],style[data-precedence]"),n=0;n<e.length;n++){var f=e[n];(f.nodeName==="LINK"||f.getAttribute("media")!=="not all")&&(u.set(f.dataset.precedence,f),a=f)}a&&u.set(null,a)}e=t.instance,f=e.getAttribute("data-precedence"),n=u.get(f)||a,n===a&&u.set(null,e),u.set(f,e),this.count++,a=Mn.bind(this),e.addEventListener("load",a),e.addEventListener("error",a),n?n.parentNode.insertBefore(e,n.nextSibling):(l=l.nodeType===9?l.head:l,l.insertBefore(e,l.firstChild)),t.state.loading|=4}}var ve={$$typeof:zl,Provider:null,Consumer:null,_currentValue:x,_currentValue2:x,_threadCount:0};function My(l,t,u,a,e,n,f,c){this.tag=1,this.containerInfo=l,this.pingCache=this.current=this.pendingChildren=null,this.timeoutHandle=-1,this.callbackNode=this.next=this.pendingContext=this.context=this.cancelPendingCommit=null,this.callbackPriority=0,this.expirationTimes=Xn(-1),this.entangledLanes=this.shellSuspendCounter=this.errorRecoveryDisabledLanes=this.expiredLanes=this.warmLanes=this.pingedLanes=this.suspendedLanes=this.pendingLanes=0,this.entanglements=Xn(0),this.hiddenUpdates=Xn(null),this.identifierPrefix=a,this.onUncaughtError=e,this.onCaughtError=n,this.onRecoverableError=f,this.pooledCache=null,this.pooledCacheLanes=0,this.formState=c,this.incompleteTransitions=new Map}function jd(l,t,u,a,e,n,f,c,i,h,g,E){return l=new My(l,t,u,f,c,i,h,E),t=1,n===!0&&(t|=24),n=Pl(3,null,null,t),l.current=n,n.stateNode=l,t=Of(),t.refCount++,l.pooledCache=t,t.refCount++,n.memoizedState={element:a,isDehydrated:u,cache:t},Uf(n),l}function Cd(l){return l?(l=Wu,l):Wu}function Zd(l,t,u,a,e,n){e=Cd(e),a.context===null?a.context=e:a.pendingContext=e,a=wt(t),a.payload={element:u},n=n===void 0?null:n,n!==null&&(a.callback=n),u=Wt(l,a,t),u!==null&&(et(u,l,t),Ca(u,l,t))}function Vd(l,t){if(l=l.memoizedState,l!==null&&l.dehydrated!==null){var u=l.retryLane;l.retryLane=u!==0&&u<t?u:t}}function Lc(l,t){Vd(l,t),(l=l.alternate)&&Vd(l,t)}function Ld(l){if(l.tag===13){var t=wu(l,67108864);t!==null&&et(t,l,67108864),Lc(l,67108864)}}var Dn=!0;function _y(l,t,u,a){var e=S.T;S.T=null;var n=_.p;try{_.p=2,Kc(l,t,u,a)}finally{_.p=n,S.T=e}}function Dy(l,t,u,a){var e=S.T;S.T=null;var n=_.p;try{_.p=8,Kc(l,t,u,a)}finally{_.p=n,S.T=e}}function Kc(l,t,u,a){if(Dn){var e=Jc(a);if(e===null)pc(l,t,a,Un,u),Jd(l,a);else if(Ry(e,l,t,u,a))a.stopPropagation();else if(Jd(l,a),t&4&&-1<Uy.indexOf(l)){for(;e!==null;){var n=Bu(e);if(n!==null)switch(n.tag){case 3:if(n=n.stateNode,n.current.memoizedState.isDehydrated){var f=hu(n.pendingLanes);if(f!==0){var c=n;for(c.pendingLanes|=2,c.entangledLanes|=2;f;){var i=1<<31-Fl(f);c.entanglements[1]|=i,f&=~i}Ot(n),(ll&6)===0&&(vn=bt()+500,ee(0))}}break;case 13:c=wu(n,2),c!==null&&et(c,n,2),yn(),Lc(n,2)}if(n=Jc(a),n===null&&pc(l,t,a,Un,u),n===e)break;e=n}e!==null&&a.stopPropagation()}else pc(l,t,a,null,u)}}function Jc(l){return l=kn(l),wc(l)}var Un=null;function wc(l){if(Un=null,l=Yu(l),l!==null){var t=X(l);if(t===null)l=null;else{var u=t.tag;if(u===13){if(l=j(t),l!==null)return l;l=null}else if(u===3){if(t.stateNode.current.memoizedState.isDehydrated)return t.tag===3?t.stateNode.containerInfo:null;l=null}else t!==l&&(l=null)}}return Un=l,null}function Kd(l){switch(l){case"beforetoggle":case"cancel":case"click":case"close":case"contextmenu":case"copy":case"cut":case"auxclick":case"dblclick":case"dragend":case"dragstart":case"drop":case"focusin":case"focusout":case"input":case"invalid":case"keydown":case"keypress":case"keyup":case"mousedown":case"mouseup":case"paste":case"pause":case"play":case"pointercancel":case"pointerdown":case"pointerup":case"ratechange":case"reset":case"resize":case"seeked":case"submit":case"toggle":case"touchcancel":case"touchend":case"touchstart":case"volumechange":case"change":case"selectionchange":case"textInput":case"compositionstart":case"compositionend":case"compositionupdate":case"beforeblur":case"afterblur":case"beforeinput":case"blur":case"fullscreenchange":case"focus":case"hashchange":case"popstate":case"select":case"selectstart":return 2;case"drag":case"dragenter":case"dragexit":case"dragleave":case"dragover":case"mousemove":case"mouseout":case"mouseover":case"pointermove":case"pointerout":case"pointerover":case"scroll":case"touchmove":case"wheel":case"mouseenter":case"mouseleave":case"pointerenter":case"pointerleave":return 8;case"message":switch(ov()){case ni:return 2;case fi:return 8;case be:case yv:return 32;case ci:return 268435456;default:return 32}default:return 32}}var Wc=!1,cu=null,iu=null,su=null,oe=new Map,ye=new Map,du=[],Uy="mousedown mouseup touchcancel touchend touchstart auxclick dblclick pointercancel pointerdown pointerup dragend dragstart drop compositionend compositionstart keydown keypress keyup input textInput copy cut paste click change contextmenu reset".split(" ");function Jd(l,t){switch(l){case"focusin":case"focusout":cu=null;break;case"dragenter":case"dragleave":iu=null;break;case"mouseover":case"mouseout":su=null;break;case"pointerover":case"pointerout":oe.delete(t.pointerId);break;case"gotpointercapture":case"lostpointercapture":ye.delete(t.pointerId)}}function he(l,t,u,a,e,n){return l===null||l.nativeEvent!==n?(l={blockedOn:t,domEventName:u,eventSystemFlags:a,nativeEvent:n,targetContainers:[e]},t!==null&&(t=Bu(t),t!==null&&Ld(t)),l):(l.eventSystemFlags|=a,t=l.targetContainers,e!==null&&t.indexOf(e)===-1&&t.push(e),l)}function Ry(l,t,u,a,e){switch(t){case"focusin":return cu=he(cu,l,t,u,a,e),!0;case"dragenter":return iu=he(iu,l,t,u,a,e),!0;case"mouseover":return su=he(su,l,t,u,a,e),!0;case"pointerover":var n=e.pointerId;return oe.set(n,he(oe.get(n)||null,l,t,u,a,e)),!0;case"gotpointercapture":return n=e.pointerId,ye.set(n,he(ye.get(n)||null,l,t,u,a,e)),!0}return!1}function wd(l){var t=Yu(l.target);if(t!==null){var u=X(t);if(u!==null){if(t=u.tag,t===13){if(t=j(u),t!==null){l.blockedOn=t,Ev(l.priority,function(){if(u.tag===13){var a=at();a=Qn(a);var e=wu(u,a);e!==null&&et(e,u,a),Lc(u,a)}});return}}else if(t===3&&u.stateNode.current.memoizedState.isDehydrated){l.blockedOn=u.tag===3?u.stateNode.containerInfo:null;return}}}l.blockedOn=null}function Rn(l){if(l.blockedOn!==null)return!1;for(var t=l.targetContainers;0<t.length;){var u=Jc(l.nativeEvent);if(u===null){u=l.nativeEvent;var a=new u.constructor(u.type,u);$n=a,u.target.dispatchEvent(a),$n=null}else return t=Bu(u),t!==null&&Ld(t),l.blockedOn=u,!1;t.shift()}return!0}function Wd(l,t,u){Rn(l)&&u.delete(t)}function Ny(){Wc=!1,cu!==null&&Rn(cu)&&(cu=null),iu!==null&&Rn(iu)&&(iu=null),su!==null&&Rn(su)&&(su=null),oe.forEach(Wd),ye.forEach(Wd)}function Nn(l,t){l.blockedOn===t&&(l.blockedOn=null,Wc||(Wc=!0,z.unstable_scheduleCallback(z.unstable_NormalPriority,Ny)))}var Hn=null;function $d(l){Hn!==l&&(Hn=l,z.unstable_scheduleCallback(z.unstable_NormalPriority,function(){Hn===l&&(Hn=null);for(var t=0;t<l.length;t+=3){var u=l[t],a=l[t+1],e=l[t+2];if(typeof a!="function"){if(wc(a||u)===null)continue;break}var n=Bu(u);n!==null&&(l.splice(t,3),t-=3,wf(n,{pending:!0,data:e,method:u.method,action:a},a,e))}}))}function re(l){function t(i){return Nn(i,l)}cu!==null&&Nn(cu,l),iu!==null&&Nn(iu,l),su!==null&&Nn(su,l),oe.forEach(t),ye.forEach(t);for(var u=0;u<du.length;u++){var a=du[u];a.blockedOn===l&&(a.blockedOn=null)}for(;0<du.length&&(u=du[0],u.blockedOn===null);)wd(u),u.blockedOn===null&&du.shift();if(u=(l.ownerDocument||l).$$reactFormReplay,u!=null)for(a=0;a<u.length;a+=3){var e=u[a],n=u[a+1],f=e[Zl]||null;if(typeof n=="function")f||$d(u);else if(f){var c=null;if(n&&n.hasAttribute("formAction")){if(e=n,f=n[Zl]||null)c=f.formAction;else if(wc(e)!==null)continue}else c=f.action;typeof c=="function"?u[a+1]=c:(u.splice(a,3),a-=3),$d(u)}}}function $c(l){this._internalRoot=l}pn.prototype.render=$c.prototype.render=function(l){var t=this._internalRoot;if(t===null)throw Error(v(409));var u=t.current,a=at();Zd(u,a,l,t,null,null)},pn.prototype.unmount=$c.prototype.unmount=function(){var l=this._internalRoot;if(l!==null){this._internalRoot=null;var t=l.containerInfo;Zd(l.current,2,null,l,null,null),yn(),t[qu]=null}};function pn(l){this._internalRoot=l}pn.prototype.unstable_scheduleHydration=function(l){if(l){var t=oi();l={blockedOn:null,target:l,priority:t};for(var u=0;u<du.length&&t!==0&&t<du[u].priority;u++);du.splice(u,0,l),u===0&&wd(l)}};var kd=N.version;if(kd!=="19.1.0")throw Error(v(527,kd,"19.1.0"));_.findDOMNode=function(l){var t=l._reactInternals;if(t===void 0)throw typeof l.render=="function"?Error(v(188)):(l=Object.keys(l).join(","),Error(v(268,l)));return l=U(t),l=l!==null?T(l):null,l=l===null?null:l.stateNode,l};var Hy={bundleType:0,version:"19.1.0",rendererPackageName:"react-dom",currentDispatcherRef:S,reconcilerVersion:"19.1.0"};if(typeof __REACT_DEVTOOLS_GLOBAL_HOOK__<"u"){var qn=__REACT_DEVTOOLS_GLOBAL_HOOK__;if(!qn.isDisabled&&qn.supportsFiber)try{ba=qn.inject(Hy),kl=qn}catch{}}return ge.createRoot=function(l,t){if(!D(l))throw Error(v(299));var u=!1,a="",e=o0,n=y0,f=h0,c=null;return t!=null&&(t.unstable_strictMode===!0&&(u=!0),t.identifierPrefix!==void 0&&(a=t.identifierPrefix),t.onUncaughtError!==void 0&&(e=t.onUncaughtError),t.onCaughtError!==void 0&&(n=t.onCaughtError),t.onRecoverableError!==void 0&&(f=t.onRecoverableError),t.unstable_transitionCallbacks!==void 0&&(c=t.unstable_transitionCallbacks)),t=jd(l,1,!1,null,null,u,a,e,n,f,c,null),l[qu]=t.current,Hc(l),new $c(t)},ge.hydrateRoot=function(l,t,u){if(!D(l))throw Error(v(299));var a=!1,e="",n=o0,f=y0,c=h0,i=null,h=null;return u!=null&&(u.unstable_strictMode===!0&&(a=!0),u.identifierPrefix!==void 0&&(e=u.identifierPrefix),u.onUncaughtError!==void 0&&(n=u.onUncaughtError),u.onCaughtError!==void 0&&(f=u.onCaughtError),u.onRecoverableError!==void 0&&(c=u.onRecoverableError),u.unstable_transitionCallbacks!==void 0&&(i=u.unstable_transitionCallbacks),u.formState!==void 0&&(h=u.formState)),t=jd(l,1,!0,t,u??null,a,e,n,f,c,i,h),t.context=Cd(null),u=t.current,a=at(),a=Qn(a),e=wt(a),e.callback=null,Wt(u,e,a),u=a,t.current.lanes=u,Ea(t,u),Ot(t),l[qu]=t.current,Hc(l),new pn(t)},ge.version="19.1.0",ge}var fv;function Cy(){if(fv)return Ic.exports;fv=1;function z(){if(!(typeof __REACT_DEVTOOLS_GLOBAL_HOOK__>"u"||typeof __REACT_DEVTOOLS_GLOBAL_HOOK__.checkDCE!="function"))try{__REACT_DEVTOOLS_GLOBAL_HOOK__.checkDCE(z)}catch(N){console.error(N)}}return z(),Ic.exports=jy(),Ic.exports}var Zy=Cy();async function Vy(z,N){const O=z.getReader();let v;for(;!(v=await O.read()).done;)N(v.value)}function Ly(z){let N,O,v,D=!1;return function(j){N===void 0?(N=j,O=0,v=-1):N=Jy(N,j);const $=N.length;let U=0;for(;O<$;){D&&(N[O]===10&&(U=++O),D=!1);let T=-1;for(;O<$&&T===-1;++O)switch(N[O]){case 58:v===-1&&(v=O-U);break;case 13:D=!0;case 10:T=O;break}if(T===-1)break;z(N.subarray(U,T),v),U=O,v=-1}U===$?N=void 0:U!==0&&(N=N.subarray(U),O-=U)}}function Ky(z,N,O){let v=cv();const D=new TextDecoder;return function(j,$){if(j.length===0)O?.(v),v=cv();else if($>0){const U=D.decode(j.subarray(0,$)),T=$+(j[$+1]===32?2:1),p=D.decode(j.subarray(T));switch(U){case"data":v.data=v.data?v.data+`
-`+p:p;break;case"event":v.event=p;break;case"id":z(v.id=p);break;case"retry":const I=parseInt(p,10);isNaN(I)||N(v.retry=I);break}}}}function Jy(z,N){const O=new Uint8Array(z.length+N.length);return O.set(z),O.set(N,z.length),O}function cv(){return{data:"",event:"",id:"",retry:void 0}}var wy=function(z,N){var O={};for(var v in z)Object.prototype.hasOwnProperty.call(z,v)&&N.indexOf(v)<0&&(O[v]=z[v]);if(z!=null&&typeof Object.getOwnPropertySymbols=="function")for(var D=0,v=Object.getOwnPropertySymbols(z);D<v.length;D++)N.indexOf(v[D])<0&&Object.prototype.propertyIsEnumerable.call(z,v[D])&&(O[v[D]]=z[v[D]]);return O};const ui="text/event-stream",Wy=1e3,iv="last-event-id";function $y(z,N){var{signal:O,headers:v,onopen:D,onmessage:X,onclose:j,onerror:$,openWhenHidden:U,fetch:T}=N,p=wy(N,["signal","headers","onopen","onmessage","onclose","onerror","openWhenHidden","fetch"]);return new Promise((I,ul)=>{const rl=Object.assign({},v);rl.accept||(rl.accept=ui);let ml;function Cl(){ml.abort(),document.hidden||w()}U||document.addEventListener("visibilitychange",Cl);let Rl=Wy,ht=0;function wl(){document.removeEventListener("visibilitychange",Cl),window.clearTimeout(ht),ml.abort()}O?.addEventListener("abort",()=>{wl(),I()});const zl=T??window.fetch,Wl=D??ky;async function w(){var Nl;ml=new AbortController;try{const Ol=await zl(z,Object.assign(Object.assign({},p),{headers:rl,signal:ml.signal}));await Wl(Ol),await Vy(Ol.body,Ly(Ky(ol=>{ol?rl[iv]=ol:delete rl[iv]},ol=>{Rl=ol},X))),j?.(),wl(),I()}catch(Ol){if(!ml.signal.aborted)try{const ol=(Nl=$?.(Ol))!==null&&Nl!==void 0?Nl:Rl;window.clearTimeout(ht),ht=window.setTimeout(w,ol)}catch(ol){wl(),ul(ol)}}}w()})}function ky(z){const N=z.headers.get("content-type");if(!N?.startsWith(ui))throw new Error(`Expected content-type to be ${ui}, Actual: ${N}`)}const Fy=z=>({content:z,role:"user",uuid:crypto.randomUUID()}),ei=z=>({content:z,role:"system",uuid:crypto.randomUUID()}),Iy=(z,N)=>({...z,content:z.content+N}),Py=({initialMessages:z}={})=>{const[N,O]=Sa.useState(z??[]),[v,D]=Sa.useState(!1);return{messages:N,sendMessage:async(j,$={})=>{const U=[...N,j];return O(U),D(!0),($.stream?t1:l1)(U,O,D).finally(()=>D(!1))},loading:v}},l1=async(z,N)=>fetch("/api/chat",{method:"POST",body:JSON.stringify({messages:z}),headers:{"Content-Type":"application/json"}}).then(O=>O.json()).then(O=>N([...z,ei(O.response)])),t1=async(z,N,O)=>(N([...z,ei("")]),$y("/api/chat",{method:"POST",body:JSON.stringify({messages:z,streaming:!0}),headers:{"Content-Type":"application/json"},onmessage:v=>{console.log("onmessage",{message:v});let D;v.data||(D={response:" ",done:!1});try{D=JSON.parse(v.data)}catch(X){console.log("Could not parse part of message",X);return}O(!1),!D.done&&N(X=>{const j=X[X.length-1];return[...X.slice(0,X.length-1),Iy(j,D.response??" ")]})}})),u1=()=>{const{messages:z,sendMessage:N,loading:O}=Py({initialMessages:[ei("How can I help you?")]}),[v,D]=Sa.useState(""),X=Sa.useRef(null);return Sa.useEffect(()=>{X.current?.scrollIntoView({behavior:"smooth"})},[z]),Ql.jsxs("div",{className:"chat-root",children:[Ql.jsxs("div",{className:"chat-messages",children:[z.map((j,$)=>Ql.jsx(sv,{position:j.role==="system"?"left":"right",content:j.content,ref:$===z.length-1?X:void 0},j.uuid)),O&&Ql.jsx(sv,{position:"left",loading:!0})]}),Ql.jsxs("form",{className:"chat-form",onSubmit:j=>{j.preventDefault(),v&&(N(Fy(v),{stream:!0}),D(""))},children:[Ql.jsx("input",{name:"userinput",type:"text",className:"chat-input",placeholder:"Ask a question...",value:v,onChange:j=>D(j.currentTarget.value)}),Ql.jsx("button",{type:"submit",disabled:!v||O,className:"submit-button",children:Ql.jsx(a1,{})})]})]})},sv=({position:z,content:N,ref:O,loading:v})=>{const D=z==="left"?"system-message":"user-message",X=v?"message-shimmer":"";return Ql.jsx("div",{ref:O,className:`message ${D} ${X}`,children:!v&&Ql.jsx("p",{children:N})})},a1=()=>Ql.jsx("svg",{width:"24px",height:"24px",viewBox:"0 0 24 24",fill:"none",xmlns:"http://www.w3.org/2000/svg",children:Ql.jsx("path",{fillRule:"evenodd",clipRule:"evenodd",d:"M3.3938 2.20468C3.70395 1.96828 4.12324 1.93374 4.4679 2.1162L21.4679 11.1162C21.7953 11.2895 22 11.6296 22 12C22 12.3704 21.7953 12.7105 21.4679 12.8838L4.4679 21.8838C4.12324 22.0662 3.70395 22.0317 3.3938 21.7953C3.08365 21.5589 2.93922 21.1637 3.02382 20.7831L4.97561 12L3.02382 3.21692C2.93922 2.83623 3.08365 2.44109 3.3938 2.20468ZM6.80218 13L5.44596 19.103L16.9739 13H6.80218ZM16.9739 11H6.80218L5.44596 4.89699L16.9739 11Z",fill:"#000000"})});function e1(){return Ql.jsx("div",{className:"app-root",children:Ql.jsx(u1,{})})}Zy.createRoot(document.getElementById("root")).render(Ql.jsx(Sa.StrictMode,{children:Ql.jsx(e1,{})}));
+`+p:p;break;case"event":v.event=p;break;case"id":z(v.id=p);break;case"retry":const I=parseInt(p,10);isNaN(I)||N(v.retry=I);break}}}}function Jy(z,N){const O=new Uint8Array(z.length+N.length);return O.set(z),O.set(N,z.length),O}function cv(){return{data:"",event:"",id:"",retry:void 0}}var wy=function(z,N){var O={};for(var v in z)Object.prototype.hasOwnProperty.call(z,v)&&N.indexOf(v)<0&&(O[v]=z[v]);if(z!=null&&typeof Object.getOwnPropertySymbols=="function")for(var D=0,v=Object.getOwnPropertySymbols(z);D<v.length;D++)N.indexOf(v[D])<0&&Object.prototype.propertyIsEnumerable.call(z,v[D])&&(O[v[D]]=z[v[D]]);return O};const ui="text/event-stream",Wy=1e3,iv="last-event-id";function $y(z,N){var{signal:O,headers:v,onopen:D,onmessage:X,onclose:j,onerror:$,openWhenHidden:U,fetch:T}=N,p=wy(N,["signal","headers","onopen","onmessage","onclose","onerror","openWhenHidden","fetch"]);return new Promise((I,ul)=>{const rl=Object.assign({},v);rl.accept||(rl.accept=ui);let ml;function Cl(){ml.abort(),document.hidden||w()}U||document.addEventListener("visibilitychange",Cl);let Rl=Wy,ht=0;function wl(){document.removeEventListener("visibilitychange",Cl),window.clearTimeout(ht),ml.abort()}O?.addEventListener("abort",()=>{wl(),I()});const zl=T??window.fetch,Wl=D??ky;async function w(){var Nl;ml=new AbortController;try{const Ol=await zl(z,Object.assign(Object.assign({},p),{headers:rl,signal:ml.signal}));await Wl(Ol),await Vy(Ol.body,Ly(Ky(ol=>{ol?rl[iv]=ol:delete rl[iv]},ol=>{Rl=ol},X))),j?.(),wl(),I()}catch(Ol){if(!ml.signal.aborted)try{const ol=(Nl=$?.(Ol))!==null&&Nl!==void 0?Nl:Rl;window.clearTimeout(ht),ht=window.setTimeout(w,ol)}catch(ol){wl(),ul(ol)}}}w()})}function ky(z){const N=z.headers.get("content-type");if(!N?.startsWith(ui))throw new Error(`Expected content-type to be ${ui}, Actual: ${N}`)}const Fy=z=>({content:z,role:"user",uuid:crypto.randomUUID()}),ei=z=>({content:z,role:"assistant",uuid:crypto.randomUUID()}),Iy=(z,N)=>({...z,content:z.content+N}),Py=({initialMessages:z}={})=>{const[N,O]=Sa.useState(z??[]),[v,D]=Sa.useState(!1);return{messages:N,sendMessage:async(j,$={})=>{const U=[...N,j];return O(U),D(!0),($.stream?t1:l1)(U,O,D).finally(()=>D(!1))},loading:v}},l1=async(z,N)=>fetch("/api/chat",{method:"POST",body:JSON.stringify({messages:z}),headers:{"Content-Type":"application/json"}}).then(O=>O.json()).then(O=>N([...z,ei(O.response)])),t1=async(z,N,O)=>(N([...z,ei("")]),$y("/api/chat",{method:"POST",body:JSON.stringify({messages:z,streaming:!0}),headers:{"Content-Type":"application/json"},onmessage:v=>{console.log("onmessage",{message:v});let D;v.data||(D={response:" ",done:!1});try{D=JSON.parse(v.data)}catch(X){console.log("Could not parse part of message",X);return}O(!1),!D.done&&N(X=>{const j=X[X.length-1];return[...X.slice(0,X.length-1),Iy(j,D.response??" ")]})}})),u1=()=>{const{messages:z,sendMessage:N,loading:O}=Py({initialMessages:[ei("How can I help you?")]}),[v,D]=Sa.useState(""),X=Sa.useRef(null);return Sa.useEffect(()=>{X.current?.scrollIntoView({behavior:"smooth"})},[z]),Ql.jsxs("div",{className:"chat-root",children:[Ql.jsxs("div",{className:"chat-messages",children:[z.map((j,$)=>Ql.jsx(sv,{position:j.role==="assistant"?"left":"right",content:j.content,ref:$===z.length-1?X:void 0},j.uuid)),O&&Ql.jsx(sv,{position:"left",loading:!0})]}),Ql.jsxs("form",{className:"chat-form",onSubmit:j=>{j.preventDefault(),v&&(N(Fy(v),{stream:!0}),D(""))},children:[Ql.jsx("input",{name:"userinput",type:"text",className:"chat-input",placeholder:"Ask a question...",value:v,onChange:j=>D(j.currentTarget.value)}),Ql.jsx("button",{type:"submit",disabled:!v||O,className:"submit-button",children:Ql.jsx(a1,{})})]})]})},sv=({position:z,content:N,ref:O,loading:v})=>{const D=z==="left"?"system-message":"user-message",X=v?"message-shimmer":"";return Ql.jsx("div",{ref:O,className:`message ${D} ${X}`,children:!v&&Ql.jsx("p",{children:N})})},a1=()=>Ql.jsx("svg",{width:"24px",height:"24px",viewBox:"0 0 24 24",fill:"none",xmlns:"http://www.w3.org/2000/svg",children:Ql.jsx("path",{fillRule:"evenodd",clipRule:"evenodd",d:"M3.3938 2.20468C3.70395 1.96828 4.12324 1.93374 4.4679 2.1162L21.4679 11.1162C21.7953 11.2895 22 11.6296 22 12C22 12.3704 21.7953 12.7105 21.4679 12.8838L4.4679 21.8838C4.12324 22.0662 3.70395 22.0317 3.3938 21.7953C3.08365 21.5589 2.93922 21.1637 3.02382 20.7831L4.97561 12L3.02382 3.21692C2.93922 2.83623 3.08365 2.44109 3.3938 2.20468ZM6.80218 13L5.44596 19.103L16.9739 13H6.80218ZM16.9739 11H6.80218L5.44596 4.89699L16.9739 11Z",fill:"#000000"})});function e1(){return Ql.jsx("div",{className:"app-root",children:Ql.jsx(u1,{})})}Zy.createRoot(document.getElementById("root")).render(Ql.jsx(Sa.StrictMode,{children:Ql.jsx(e1,{})}));
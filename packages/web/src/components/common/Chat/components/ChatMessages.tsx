@@ -23,7 +23,7 @@ export const ChatMessages = ({ messages, loading }: ChatMessagesProps) => {
             {messages.map(
                 (msg, idx) => <Message
                     key={msg.uuid}
-                    position={msg.role === "system" ? "left" : "right"}
+                    position={msg.role === "assistant" ? "left" : "right"}
                     content={msg.content}
                     ref={idx === messages.length - 1 ? messageRef : undefined}
                 />
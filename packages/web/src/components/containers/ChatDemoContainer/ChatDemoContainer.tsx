@@ -1,5 +1,5 @@
 import { Chat } from "../../common/Chat/Chat"
-import { systemMessage, useChat, userMessage } from "../../../hooks/useChat"
+import { assistantMessage, useChat, userMessage } from "../../../hooks/useChat"
 import { shouldEnableStreaming } from "../../../utils/chatConfig"
 import "./ChatDemoContainer.css"
 
@@ -9,7 +9,7 @@ import "./ChatDemoContainer.css"
 export const ChatDemoContainer = () => {
     const { messages, sendMessage, loading } = useChat({
         initialMessages: [
-            systemMessage("How can I help you?"),
+            assistantMessage("How can I help you?"),
         ],
     });
 
@@ -2,7 +2,7 @@ import { useState } from "react";
 import { fetchEventSource } from '@microsoft/fetch-event-source';
 
 export type ChatMessage = {
-  role: "user" | "system"
+  role: "user" | "assistant"
   content: string,
   uuid: string
 }
@@ -11,8 +11,8 @@ export type UserMessage = ChatMessage & {
   role: "user"
 }
 
-export type SystemMessage = ChatMessage & {
-  role: "system"
+export type AssistantMessage = ChatMessage & {
+  role: "assistant"
 }
 
 export type StreamResponse = {
@@ -30,11 +30,11 @@ export const userMessage = (content: string): UserMessage => ({
 });
 
 /**
- * Create a system message object
+ * Create an assistant message object
  */
-export const systemMessage = (content: string): SystemMessage => ({
+export const assistantMessage = (content: string): AssistantMessage => ({
   content,
-  role: "system",
+  role: "assistant",
   uuid: crypto.randomUUID()
 });
 
@@ -89,7 +89,7 @@ const sendMessageNonStreaming = async (
     .then(res => res.json())
     .then(json => setMessages([
       ...messages,
-      systemMessage(json.response)
+      assistantMessage(json.response)
     ]))
 }
 
@@ -100,7 +100,7 @@ const sendMessageStreaming = async (
 ) => {
   setMessages([
     ...messages,
-    systemMessage("") // <-- start a new message that we will be adding to with each chunk
+    assistantMessage("") // <-- start a new message that we will be adding to with each chunk
   ]);
   // fetch-event-source allows us to use a fetch-like api function for 
   // getting a stream of data from our backend